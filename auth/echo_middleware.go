@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/irvankadhafi/go-point-of-sales/cacher"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/rbac"
@@ -76,9 +77,15 @@ func (a *AuthenticationMiddleware) authenticateAccessToken(c echo.Context, next
 		if session == nil || perm == nil {
 			break // fallback
 		}
+		if session.UserStatus == "" {
+			break // cached before the status is cached along with the session, fallback
+		}
 		if session.IsAccessTokenExpired() {
 			return errorResp(http.StatusUnauthorized, "token expired")
 		}
+		if err := session.UserStatus.Err(); err != nil {
+			return userStatusErrorResp(err)
+		}
 
 		ctx := SetUserToCtx(c.Request().Context(), NewUserFromSession(*session, perm))
 		c.SetRequest(c.Request().WithContext(ctx))
@@ -86,6 +93,10 @@ func (a *AuthenticationMiddleware) authenticateAccessToken(c echo.Context, next
 	}
 
 	userSession, err := a.userAuthenticator.AuthenticateToken(ctx, token)
+	if errors.Is(err, model.ErrUserPending) || errors.Is(err, model.ErrUserInactive) {
+		return userStatusErrorResp(err)
+	}
+
 	// fallback to rpc
 	switch status.Code(err) {
 	case codes.OK:
@@ -185,3 +196,13 @@ func getAccessToken(req *http.Request) (accessToken string) {
 func errorResp(code int, message string) error {
 	return echo.NewHTTPError(code, echo.Map{"message": message})
 }
+
+// userStatusErrorResp the error of a user which is not active, the code tells apart a pending user from a deactivated one
+func userStatusErrorResp(err error) error {
+	code := ErrCodeUserInactive
+	if errors.Is(err, model.ErrUserPending) {
+		code = ErrCodeUserPending
+	}
+
+	return echo.NewHTTPError(http.StatusForbidden, echo.Map{"message": err.Error(), "code": code})
+}
@@ -5,3 +5,9 @@ import "errors"
 var (
 	ErrAccessDenied = errors.New("access denied")
 )
+
+// error codes of the users which are not allowed to use their tokens
+const (
+	ErrCodeUserPending  = "USER_PENDING"
+	ErrCodeUserInactive = "USER_INACTIVE"
+)
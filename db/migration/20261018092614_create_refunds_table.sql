@@ -0,0 +1,38 @@
+-- +migrate Up notransaction
+CREATE TYPE "refund_type" AS ENUM (
+    'VOID',
+    'REFUND'
+);
+
+CREATE TABLE IF NOT EXISTS "refunds" (
+    "id" BIGINT PRIMARY KEY,
+    "transaction_id" BIGINT NOT NULL,
+    "type" refund_type NOT NULL,
+    "total_amount" DECIMAL(20,0) NOT NULL,
+    "reason" TEXT NOT NULL,
+    "approved_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+
+ALTER TABLE "refunds" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "refunds" ADD FOREIGN KEY ("approved_by") REFERENCES "users" ("id");
+CREATE INDEX "refunds_transaction_id_idx" ON "refunds" ("transaction_id");
+CREATE INDEX "refunds_created_at_idx" ON "refunds" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "refund_details" (
+    "refund_id" BIGINT NOT NULL,
+    "transaction_id" BIGINT NOT NULL,
+    "product_id" BIGINT NOT NULL,
+    "quantity" BIGINT NOT NULL,
+    "subtotal" DECIMAL(20,0) NOT NULL
+);
+
+ALTER TABLE "refund_details" ADD FOREIGN KEY ("refund_id") REFERENCES "refunds" ("id");
+ALTER TABLE "refund_details" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "refund_details" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
+CREATE INDEX "refund_details_transaction_id_idx" ON "refund_details" ("transaction_id");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "refund_details";
+DROP TABLE IF EXISTS "refunds";
+DROP TYPE IF EXISTS "refund_type";
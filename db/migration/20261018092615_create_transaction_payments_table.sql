@@ -0,0 +1,27 @@
+-- +migrate Up notransaction
+CREATE TYPE "payment_method" AS ENUM (
+    'CASH',
+    'DEBIT_CARD',
+    'CREDIT_CARD',
+    'BANK_TRANSFER',
+    'E_WALLET',
+    'QRIS'
+);
+
+CREATE TABLE IF NOT EXISTS "transaction_payments" (
+    "transaction_id" BIGINT NOT NULL,
+    "method" payment_method NOT NULL,
+    "amount" DECIMAL(20,0) NOT NULL,
+    "reference" TEXT NOT NULL DEFAULT ''
+);
+
+ALTER TABLE "transaction_payments" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+CREATE INDEX "transaction_payments_transaction_id_idx" ON "transaction_payments" ("transaction_id");
+
+-- existing transactions were always paid in cash
+INSERT INTO "transaction_payments" ("transaction_id", "method", "amount")
+SELECT "id", 'CASH', "amount_paid" FROM "transactions" WHERE "amount_paid" > 0;
+
+-- +migrate Down
+DROP TABLE IF EXISTS "transaction_payments";
+DROP TYPE IF EXISTS "payment_method";
@@ -0,0 +1,19 @@
+-- +migrate Up notransaction
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "product_name" TEXT NOT NULL DEFAULT '';
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "product_slug" TEXT NOT NULL DEFAULT '';
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "unit_price" DECIMAL(20,0) NOT NULL DEFAULT 0;
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "discount" DECIMAL(20,0) NOT NULL DEFAULT 0;
+
+-- backfill from the sold subtotal and the product row, soft deleted products are included
+UPDATE "transaction_details" td
+SET "product_name" = p."name",
+    "product_slug" = p."slug",
+    "unit_price" = CASE WHEN td."quantity" > 0 THEN td."subtotal" / td."quantity" ELSE 0 END
+FROM "products" p
+WHERE p."id" = td."product_id";
+
+-- +migrate Down
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "product_name";
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "product_slug";
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "unit_price";
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "discount";
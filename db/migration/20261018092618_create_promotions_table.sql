@@ -0,0 +1,52 @@
+-- +migrate Up notransaction
+CREATE TYPE "promotion_type" AS ENUM (
+    'LINE_PERCENTAGE',
+    'LINE_FIXED',
+    'CART_PERCENTAGE',
+    'CART_FIXED',
+    'BUY_X_GET_Y'
+);
+
+CREATE TABLE IF NOT EXISTS "promotions" (
+    "id" BIGINT PRIMARY KEY,
+    "name" TEXT NOT NULL,
+    "type" promotion_type NOT NULL,
+    "product_id" BIGINT NOT NULL DEFAULT 0,
+    "value" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "buy_quantity" BIGINT NOT NULL DEFAULT 0,
+    "get_quantity" BIGINT NOT NULL DEFAULT 0,
+    "minimum_spend" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "start_at" TIMESTAMP NOT NULL,
+    "end_at" TIMESTAMP NOT NULL,
+    "daily_start_time" TEXT NOT NULL DEFAULT '',
+    "daily_end_time" TEXT NOT NULL DEFAULT '',
+    "is_active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "updated_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "deleted_at" TIMESTAMP
+);
+
+CREATE INDEX "promotions_is_active_end_at_idx" ON "promotions" ("is_active", "end_at") WHERE "deleted_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "transaction_promotions" (
+    "transaction_id" BIGINT NOT NULL,
+    "promotion_id" BIGINT NOT NULL,
+    "promotion_name" TEXT NOT NULL,
+    "product_id" BIGINT NOT NULL DEFAULT 0,
+    "discount" DECIMAL(20,0) NOT NULL
+);
+
+ALTER TABLE "transaction_promotions" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "transaction_promotions" ADD FOREIGN KEY ("promotion_id") REFERENCES "promotions" ("id");
+CREATE INDEX "transaction_promotions_transaction_id_idx" ON "transaction_promotions" ("transaction_id");
+CREATE INDEX "transaction_promotions_promotion_id_idx" ON "transaction_promotions" ("promotion_id");
+
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "discount" DECIMAL(20,0) NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "manual_discount" DECIMAL(20,0) NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "manual_discount";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "discount";
+DROP TABLE IF EXISTS "transaction_promotions";
+DROP TABLE IF EXISTS "promotions";
+DROP TYPE IF EXISTS "promotion_type";
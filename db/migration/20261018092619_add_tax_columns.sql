@@ -0,0 +1,17 @@
+-- +migrate Up notransaction
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "tax_category" TEXT NOT NULL DEFAULT 'STANDARD';
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "tax_category" TEXT NOT NULL DEFAULT 'STANDARD';
+
+-- the existing transactions were not taxed, so their tax columns stay zero
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "tax_base" DECIMAL(20,0) NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "tax_amount" DECIMAL(20,0) NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "service_charge" DECIMAL(20,0) NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "price_include_tax" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE "products" DROP COLUMN IF EXISTS "tax_category";
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "tax_category";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "tax_base";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "tax_amount";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "service_charge";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "price_include_tax";
@@ -0,0 +1,5 @@
+-- +migrate Up notransaction
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "receipt_prints" INT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "receipt_prints";
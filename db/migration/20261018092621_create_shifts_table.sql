@@ -0,0 +1,67 @@
+-- +migrate Up notransaction
+CREATE TYPE "shift_status" AS ENUM (
+    'OPEN',
+    'CLOSED'
+);
+
+CREATE TABLE IF NOT EXISTS "shifts" (
+    "id" BIGINT PRIMARY KEY,
+    "cashier_id" BIGINT NOT NULL,
+    "status" shift_status NOT NULL DEFAULT 'OPEN',
+    "opening_float" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "cash_sales" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "cash_in" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "cash_out" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "expected_cash" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "closing_cash" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "variance" DECIMAL(20,0) NOT NULL DEFAULT 0,
+    "opening_note" TEXT NOT NULL DEFAULT '',
+    "closing_note" TEXT NOT NULL DEFAULT '',
+    "opened_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "closed_at" TIMESTAMP
+);
+
+ALTER TABLE "shifts" ADD FOREIGN KEY ("cashier_id") REFERENCES "users" ("id");
+CREATE INDEX "shifts_opened_at_idx" ON "shifts" ("opened_at");
+-- a cashier can only have one open shift
+CREATE UNIQUE INDEX "shifts_open_cashier_id_idx" ON "shifts" ("cashier_id") WHERE "status" = 'OPEN';
+
+CREATE TYPE "cash_movement_type" AS ENUM (
+    'CASH_IN',
+    'CASH_OUT'
+);
+
+CREATE TABLE IF NOT EXISTS "cash_movements" (
+    "id" BIGINT PRIMARY KEY,
+    "shift_id" BIGINT NOT NULL,
+    "type" cash_movement_type NOT NULL,
+    "amount" DECIMAL(20,0) NOT NULL,
+    "reason" TEXT NOT NULL,
+    "created_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("shift_id") REFERENCES "shifts" ("id");
+ALTER TABLE "cash_movements" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+CREATE INDEX "cash_movements_shift_id_idx" ON "cash_movements" ("shift_id");
+
+-- zero means the transaction was made outside of any shift
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "shift_id" BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "transactions_shift_id_idx" ON "transactions" ("shift_id");
+
+-- zero means the refund was paid outside of any shift
+ALTER TABLE "refunds" ADD COLUMN IF NOT EXISTS "shift_id" BIGINT NOT NULL DEFAULT 0;
+-- the part of the refund paid back in cash, the existing refunds were never paid from a shift drawer
+ALTER TABLE "refunds" ADD COLUMN IF NOT EXISTS "cash_amount" DECIMAL(20,0) NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "refunds_shift_id_idx" ON "refunds" ("shift_id");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "refunds_shift_id_idx";
+ALTER TABLE "refunds" DROP COLUMN IF EXISTS "cash_amount";
+ALTER TABLE "refunds" DROP COLUMN IF EXISTS "shift_id";
+DROP INDEX IF EXISTS "transactions_shift_id_idx";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "shift_id";
+DROP TABLE IF EXISTS "cash_movements";
+DROP TYPE IF EXISTS "cash_movement_type";
+DROP TABLE IF EXISTS "shifts";
+DROP TYPE IF EXISTS "shift_status";
@@ -0,0 +1,40 @@
+-- +migrate Up notransaction
+CREATE TABLE IF NOT EXISTS "z_reports" (
+    "id" BIGINT PRIMARY KEY,
+    "number" BIGINT NOT NULL,
+    "period_start" TIMESTAMP NOT NULL,
+    "period_end" TIMESTAMP NOT NULL,
+    "summary" JSONB NOT NULL,
+    "hourly" JSONB,
+    "products" JSONB,
+    "cashiers" JSONB,
+    "payment_methods" JSONB,
+    "created_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+
+ALTER TABLE "z_reports" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+CREATE UNIQUE INDEX "z_reports_number_idx" ON "z_reports" ("number");
+
+-- a Z report is immutable once stored
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION "reject_z_report_change"() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'z report % is immutable', OLD.number;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER "z_reports_immutable" BEFORE UPDATE OR DELETE ON "z_reports"
+    FOR EACH ROW EXECUTE PROCEDURE "reject_z_report_change"();
+
+-- the reports aggregate the sales by their creation time
+CREATE INDEX IF NOT EXISTS "transactions_created_at_idx" ON "transactions" ("created_at");
+CREATE INDEX IF NOT EXISTS "transaction_details_transaction_id_idx" ON "transaction_details" ("transaction_id");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "transaction_details_transaction_id_idx";
+DROP INDEX IF EXISTS "transactions_created_at_idx";
+DROP TRIGGER IF EXISTS "z_reports_immutable" ON "z_reports";
+DROP FUNCTION IF EXISTS "reject_z_report_change"();
+DROP TABLE IF EXISTS "z_reports";
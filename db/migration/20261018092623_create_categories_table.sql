@@ -0,0 +1,25 @@
+-- +migrate Up notransaction
+CREATE TABLE IF NOT EXISTS "categories" (
+    "id" BIGINT PRIMARY KEY,
+    "parent_id" BIGINT NOT NULL DEFAULT 0,
+    "name" TEXT NOT NULL,
+    "slug" TEXT NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "sort_order" INT NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "updated_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "deleted_at" TIMESTAMP
+);
+
+-- zero parent id means a root category
+CREATE INDEX "categories_parent_id_idx" ON "categories" ("parent_id") WHERE "deleted_at" IS NULL;
+CREATE UNIQUE INDEX "categories_slug_idx" ON "categories" ("slug") WHERE "deleted_at" IS NULL;
+
+-- zero means the product is not categorized
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "category_id" BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "products_category_id_idx" ON "products" ("category_id");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "products_category_id_idx";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "category_id";
+DROP TABLE IF EXISTS "categories";
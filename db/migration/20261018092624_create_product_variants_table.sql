@@ -0,0 +1,36 @@
+-- +migrate Up notransaction
+-- the option groups of the variants, an empty list means the product has no variants
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "options" JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE IF NOT EXISTS "product_variants" (
+    "id" BIGINT PRIMARY KEY,
+    "product_id" BIGINT NOT NULL,
+    "name" TEXT NOT NULL,
+    "sku" TEXT NOT NULL,
+    "barcode" TEXT NOT NULL DEFAULT '',
+    "options" JSONB NOT NULL DEFAULT '{}',
+    "price" DECIMAL(20,0) NOT NULL,
+    "quantity" BIGINT NOT NULL DEFAULT 0 CHECK ("quantity" >= 0),
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "updated_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "deleted_at" TIMESTAMP
+);
+
+ALTER TABLE "product_variants" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
+CREATE INDEX "product_variants_product_id_idx" ON "product_variants" ("product_id") WHERE "deleted_at" IS NULL;
+CREATE UNIQUE INDEX "product_variants_sku_idx" ON "product_variants" ("sku") WHERE "deleted_at" IS NULL;
+CREATE UNIQUE INDEX "product_variants_barcode_idx" ON "product_variants" ("barcode") WHERE "deleted_at" IS NULL AND "barcode" <> '';
+
+-- zero means the sold product has no variants
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "variant_id" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "variant_name" TEXT NOT NULL DEFAULT '';
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "sku" TEXT NOT NULL DEFAULT '';
+ALTER TABLE "refund_details" ADD COLUMN IF NOT EXISTS "variant_id" BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE "refund_details" DROP COLUMN IF EXISTS "variant_id";
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "sku";
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "variant_name";
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "variant_id";
+DROP TABLE IF EXISTS "product_variants";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "options";
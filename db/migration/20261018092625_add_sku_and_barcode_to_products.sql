@@ -0,0 +1,13 @@
+-- +migrate Up notransaction
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "sku" TEXT NOT NULL DEFAULT '';
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "barcode" TEXT NOT NULL DEFAULT '';
+
+-- the codes are unique among the active products, an empty code means the product has none
+CREATE UNIQUE INDEX IF NOT EXISTS "products_sku_idx" ON "products" ("sku") WHERE "deleted_at" IS NULL AND "sku" <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS "products_barcode_idx" ON "products" ("barcode") WHERE "deleted_at" IS NULL AND "barcode" <> '';
+
+-- +migrate Down
+DROP INDEX IF EXISTS "products_barcode_idx";
+DROP INDEX IF EXISTS "products_sku_idx";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "barcode";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "sku";
@@ -0,0 +1,44 @@
+-- +migrate Up notransaction
+CREATE TYPE "stock_movement_type" AS ENUM (
+    'SALE',
+    'REFUND',
+    'ADJUSTMENT',
+    'RECEIPT',
+    'TRANSFER',
+    'WASTE'
+);
+
+-- the append-only stock ledger, the quantity is positive for an incoming stock and negative for an outgoing stock
+CREATE TABLE IF NOT EXISTS "stock_movements" (
+    "id" BIGINT PRIMARY KEY,
+    "product_id" BIGINT NOT NULL,
+    -- zero means the product has no variants
+    "variant_id" BIGINT NOT NULL DEFAULT 0,
+    "type" stock_movement_type NOT NULL,
+    "quantity" BIGINT NOT NULL,
+    "reference_type" TEXT NOT NULL,
+    "reference_id" BIGINT NOT NULL DEFAULT 0,
+    "note" TEXT NOT NULL DEFAULT '',
+    "created_by" BIGINT NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
+CREATE INDEX "stock_movements_product_id_created_at_idx" ON "stock_movements" ("product_id", "created_at");
+CREATE INDEX "stock_movements_variant_id_created_at_idx" ON "stock_movements" ("variant_id", "created_at") WHERE "variant_id" <> 0;
+CREATE INDEX "stock_movements_reference_idx" ON "stock_movements" ("reference_type", "reference_id");
+
+-- open the ledger with the current stock, so the stock is consistent with the sum of the movements
+INSERT INTO "stock_movements" ("id", "product_id", "variant_id", "type", "quantity", "reference_type", "reference_id", "note")
+SELECT (EXTRACT(EPOCH FROM NOW()) * 1000000000)::BIGINT + ROW_NUMBER() OVER (), p.id, 0, 'ADJUSTMENT', p.quantity, 'product', p.id, 'opening balance'
+FROM "products" p
+WHERE p.deleted_at IS NULL AND p.quantity <> 0 AND p.options = '[]'::JSONB;
+
+INSERT INTO "stock_movements" ("id", "product_id", "variant_id", "type", "quantity", "reference_type", "reference_id", "note")
+SELECT (EXTRACT(EPOCH FROM NOW()) * 1000000000)::BIGINT + 1000000 + ROW_NUMBER() OVER (), v.product_id, v.id, 'ADJUSTMENT', v.quantity, 'product', v.product_id, 'opening balance'
+FROM "product_variants" v
+WHERE v.deleted_at IS NULL AND v.quantity <> 0;
+
+-- +migrate Down
+DROP TABLE IF EXISTS "stock_movements";
+DROP TYPE IF EXISTS "stock_movement_type";
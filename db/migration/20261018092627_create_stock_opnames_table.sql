@@ -0,0 +1,39 @@
+-- +migrate Up notransaction
+CREATE TYPE "stock_opname_status" AS ENUM (
+    'OPEN',
+    'APPROVED'
+);
+
+CREATE TABLE IF NOT EXISTS "stock_opnames" (
+    "id" BIGINT PRIMARY KEY,
+    "status" stock_opname_status NOT NULL DEFAULT 'OPEN',
+    "note" TEXT NOT NULL DEFAULT '',
+    "started_by" BIGINT NOT NULL,
+    "approved_by" BIGINT NOT NULL DEFAULT 0,
+    "approved_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE INDEX "stock_opnames_created_at_idx" ON "stock_opnames" ("created_at");
+
+-- the count of every user is kept, the counts of the same product are summed on approval
+CREATE TABLE IF NOT EXISTS "stock_opname_counts" (
+    "stock_opname_id" BIGINT NOT NULL,
+    "product_id" BIGINT NOT NULL,
+    -- zero means the product has no variants
+    "variant_id" BIGINT NOT NULL DEFAULT 0,
+    "counted_by" BIGINT NOT NULL,
+    "quantity" BIGINT NOT NULL,
+    -- the stock when the product was counted
+    "system_quantity" BIGINT NOT NULL,
+    "counted_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    PRIMARY KEY ("stock_opname_id", "product_id", "variant_id", "counted_by")
+);
+
+ALTER TABLE "stock_opname_counts" ADD FOREIGN KEY ("stock_opname_id") REFERENCES "stock_opnames" ("id");
+ALTER TABLE "stock_opname_counts" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "stock_opname_counts";
+DROP TABLE IF EXISTS "stock_opnames";
+DROP TYPE IF EXISTS "stock_opname_status";
@@ -0,0 +1,11 @@
+-- +migrate Up notransaction
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "min_stock" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "reorder_quantity" BIGINT NOT NULL DEFAULT 0;
+
+-- a zero min stock means the product has no threshold
+CREATE INDEX IF NOT EXISTS "products_low_stock_idx" ON "products" ("min_stock", "quantity") WHERE "deleted_at" IS NULL AND "min_stock" > 0;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "products_low_stock_idx";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "reorder_quantity";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "min_stock";
@@ -0,0 +1,91 @@
+-- +migrate Up notransaction
+CREATE TABLE IF NOT EXISTS "suppliers" (
+    "id" BIGINT PRIMARY KEY,
+    "name" TEXT NOT NULL,
+    "contact_name" TEXT NOT NULL DEFAULT '',
+    "phone" TEXT NOT NULL DEFAULT '',
+    "email" TEXT NOT NULL DEFAULT '',
+    "address" TEXT NOT NULL DEFAULT '',
+    "note" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "updated_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "deleted_at" TIMESTAMP
+);
+
+CREATE INDEX "suppliers_name_idx" ON "suppliers" ("name") WHERE "deleted_at" IS NULL;
+
+CREATE TYPE "purchase_order_status" AS ENUM (
+    'DRAFT',
+    'SENT',
+    'PARTIALLY_RECEIVED',
+    'RECEIVED',
+    'CLOSED'
+);
+
+CREATE TABLE IF NOT EXISTS "purchase_orders" (
+    "id" BIGINT PRIMARY KEY,
+    "supplier_id" BIGINT NOT NULL,
+    "status" purchase_order_status NOT NULL DEFAULT 'DRAFT',
+    "note" TEXT NOT NULL DEFAULT '',
+    "total_cost" NUMERIC(20,0) NOT NULL DEFAULT 0,
+    "created_by" BIGINT NOT NULL,
+    "sent_at" TIMESTAMP,
+    "closed_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "updated_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE INDEX "purchase_orders_supplier_id_idx" ON "purchase_orders" ("supplier_id");
+CREATE INDEX "purchase_orders_created_at_idx" ON "purchase_orders" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "purchase_order_items" (
+    "id" BIGINT PRIMARY KEY,
+    "purchase_order_id" BIGINT NOT NULL,
+    "product_id" BIGINT NOT NULL,
+    -- zero means the product has no variants
+    "variant_id" BIGINT NOT NULL DEFAULT 0,
+    "quantity" BIGINT NOT NULL,
+    "received_quantity" BIGINT NOT NULL DEFAULT 0,
+    "cost_price" NUMERIC(20,0) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX "purchase_order_items_purchase_order_id_idx" ON "purchase_order_items" ("purchase_order_id");
+
+CREATE TABLE IF NOT EXISTS "goods_receipts" (
+    "id" BIGINT PRIMARY KEY,
+    "purchase_order_id" BIGINT NOT NULL,
+    "note" TEXT NOT NULL DEFAULT '',
+    "received_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE INDEX "goods_receipts_purchase_order_id_idx" ON "goods_receipts" ("purchase_order_id");
+
+CREATE TABLE IF NOT EXISTS "goods_receipt_items" (
+    "goods_receipt_id" BIGINT NOT NULL,
+    "purchase_order_item_id" BIGINT NOT NULL,
+    "product_id" BIGINT NOT NULL,
+    "variant_id" BIGINT NOT NULL DEFAULT 0,
+    "quantity" BIGINT NOT NULL,
+    "cost_price" NUMERIC(20,0) NOT NULL DEFAULT 0,
+    PRIMARY KEY ("goods_receipt_id", "purchase_order_item_id")
+);
+
+ALTER TABLE "purchase_orders" ADD FOREIGN KEY ("supplier_id") REFERENCES "suppliers" ("id");
+ALTER TABLE "purchase_order_items" ADD FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders" ("id");
+ALTER TABLE "purchase_order_items" ADD FOREIGN KEY ("product_id") REFERENCES "products" ("id");
+ALTER TABLE "goods_receipts" ADD FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders" ("id");
+ALTER TABLE "goods_receipt_items" ADD FOREIGN KEY ("goods_receipt_id") REFERENCES "goods_receipts" ("id");
+ALTER TABLE "goods_receipt_items" ADD FOREIGN KEY ("purchase_order_item_id") REFERENCES "purchase_order_items" ("id");
+
+-- the cost price of the last goods receipt
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "cost_price" NUMERIC(20,0) NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE "products" DROP COLUMN IF EXISTS "cost_price";
+DROP TABLE IF EXISTS "goods_receipt_items";
+DROP TABLE IF EXISTS "goods_receipts";
+DROP TABLE IF EXISTS "purchase_order_items";
+DROP TABLE IF EXISTS "purchase_orders";
+DROP TYPE IF EXISTS "purchase_order_status";
+DROP TABLE IF EXISTS "suppliers";
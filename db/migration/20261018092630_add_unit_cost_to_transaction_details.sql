@@ -0,0 +1,6 @@
+-- +migrate Up notransaction
+-- NULL means the cost of the sale is unknown, the sales made before the snapshot are left out of the profit reports
+ALTER TABLE "transaction_details" ADD COLUMN IF NOT EXISTS "unit_cost" NUMERIC(20,0);
+
+-- +migrate Down
+ALTER TABLE "transaction_details" DROP COLUMN IF EXISTS "unit_cost";
@@ -0,0 +1,9 @@
+-- +migrate Up notransaction
+-- the users created before the status is enforced could log in, keep them active
+UPDATE "users" SET "status" = 'ACTIVE' WHERE "status" IS NULL;
+ALTER TABLE "users" ALTER COLUMN "status" SET DEFAULT 'PENDING';
+ALTER TABLE "users" ALTER COLUMN "status" SET NOT NULL;
+
+-- +migrate Down
+ALTER TABLE "users" ALTER COLUMN "status" DROP NOT NULL;
+ALTER TABLE "users" ALTER COLUMN "status" DROP DEFAULT;
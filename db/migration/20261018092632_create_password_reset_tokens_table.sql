@@ -0,0 +1,16 @@
+-- +migrate Up notransaction
+CREATE TABLE IF NOT EXISTS "password_reset_tokens" (
+    "id" BIGINT PRIMARY KEY,
+    "user_id" BIGINT NOT NULL,
+    "token_hash" TEXT NOT NULL,
+    "expired_at" TIMESTAMP NOT NULL,
+    "used_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+ALTER TABLE "password_reset_tokens" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE UNIQUE INDEX "password_reset_tokens_token_hash_idx" ON "password_reset_tokens" ("token_hash");
+CREATE INDEX "password_reset_tokens_user_id_idx" ON "password_reset_tokens" ("user_id") WHERE "used_at" IS NULL;
+
+-- +migrate Down
+DROP TABLE IF EXISTS "password_reset_tokens";
@@ -0,0 +1,7 @@
+-- +migrate Up notransaction
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "pin" TEXT;
+ALTER TABLE "app_clients" ADD COLUMN IF NOT EXISTS "is_pos_terminal" BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE "app_clients" DROP COLUMN IF EXISTS "is_pos_terminal";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "pin";
@@ -0,0 +1,42 @@
+-- +migrate Up notransaction
+CREATE TABLE IF NOT EXISTS "user_two_factors" (
+    "user_id" BIGINT PRIMARY KEY,
+    "secret" TEXT NOT NULL,
+    "last_used_step" BIGINT NOT NULL DEFAULT 0,
+    "enabled_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()',
+    "updated_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+ALTER TABLE "user_two_factors" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" (
+    "id" BIGINT PRIMARY KEY,
+    "user_id" BIGINT NOT NULL,
+    "code_hash" TEXT NOT NULL,
+    "used_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE INDEX "recovery_codes_user_id_code_hash_idx" ON "recovery_codes" ("user_id", "code_hash");
+
+CREATE TABLE IF NOT EXISTS "two_factor_challenges" (
+    "id" BIGINT PRIMARY KEY,
+    "user_id" BIGINT NOT NULL,
+    "app_id" BIGINT NOT NULL,
+    "token_hash" TEXT NOT NULL,
+    "enrollment" BOOLEAN NOT NULL DEFAULT false,
+    "user_agent" TEXT,
+    "ip_address" TEXT,
+    "expired_at" TIMESTAMP NOT NULL,
+    "used_at" TIMESTAMP,
+    "created_at" TIMESTAMP NOT NULL DEFAULT 'NOW()'
+);
+ALTER TABLE "two_factor_challenges" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE UNIQUE INDEX "two_factor_challenges_token_hash_idx" ON "two_factor_challenges" ("token_hash");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "two_factor_challenges";
+DROP TABLE IF EXISTS "recovery_codes";
+DROP TABLE IF EXISTS "user_two_factors";
@@ -0,0 +1,6 @@
+-- +migrate Up notransaction
+-- a zero unit cost was snapshotted for the products without a cost price, the cost of those sales is unknown
+UPDATE "transaction_details" SET "unit_cost" = NULL WHERE "unit_cost" = 0;
+
+-- +migrate Down
+-- the unknown costs can not be told apart from the sales made before the snapshot, nothing to revert
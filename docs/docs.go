@@ -17,8 +17,8 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/products": {
-            "get": {
+        "/auth/2fa/enroll": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -26,86 +26,158 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Auth"
                 ],
-                "summary": "Endpoint for get list pagination of products",
+                "summary": "Endpoint for start the two factor enrollment of a login whose role requires two factor authentication",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Accept",
-                        "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Use Token: Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Content-Type",
-                        "in": "header"
-                    },
+                        "description": "payload, only the challenge token is used",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.twoFactorChallengeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.twoFactorEnrollmentResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/verify": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Endpoint for answer the two factor challenge of a login with a code or a recovery code",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query"
-                    },
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.twoFactorChallengeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.twoFactorLoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Endpoint for mail a password reset token, the response is the same whether the email is registered or not",
+                "parameters": [
                     {
-                        "type": "string",
-                        "name": "query",
-                        "in": "query"
-                    },
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ForgotPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Endpoint for reset the password with the mailed token, all of the sessions of the user are revoked",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "name": "size",
-                        "in": "query"
-                    },
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ResetPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Category"
+                ],
+                "summary": "Endpoint for get all categories, the subcategories are nested under their parent",
+                "parameters": [
                     {
-                        "enum": [
-                            "CREATED_AT_ASC",
-                            "CREATED_AT_DESC",
-                            "PRICE_ASC",
-                            "PRICE_DESC",
-                            "NAME_ASC",
-                            "NAME_DESC"
-                        ],
                         "type": "string",
-                        "x-enum-varnames": [
-                            "ProductSortTypeCreatedAtAsc",
-                            "ProductSortTypeCreatedAtDesc",
-                            "ProductSortTypePriceAsc",
-                            "ProductSortTypePriceDesc",
-                            "ProductSortTypeNameAsc",
-                            "ProductSortTypeNameDesc"
-                        ],
-                        "name": "sort_type",
-                        "in": "query"
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_ProductResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "items": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/model.ProductResponse"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CategoryResponse"
+                            }
                         }
                     }
                 }
@@ -118,16 +190,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Category"
                 ],
-                "summary": "Store a product",
+                "summary": "Store a category, give a parent id to store it as a subcategory",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Accept",
-                        "in": "header"
-                    },
                     {
                         "type": "string",
                         "description": "Use Token: Bearer {token}",
@@ -135,33 +201,27 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Content-Type",
-                        "in": "header"
-                    },
                     {
                         "description": "payload",
                         "name": "Body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateProductInput"
+                            "$ref": "#/definitions/model.CreateCategoryInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.ProductResponse"
+                            "$ref": "#/definitions/model.CategoryResponse"
                         }
                     }
                 }
             }
         },
-        "/products/{id}": {
+        "/categories/{id}": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -170,9 +230,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Category"
                 ],
-                "summary": "Endpoint for get detail product by id",
+                "summary": "Endpoint for get detail category by id along with its subcategories",
                 "parameters": [
                     {
                         "type": "string",
@@ -181,18 +241,6 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Accept",
-                        "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Content-Type",
-                        "in": "header"
-                    },
                     {
                         "type": "integer",
                         "description": "Example: 1",
@@ -205,7 +253,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ProductResponse"
+                            "$ref": "#/definitions/model.CategoryResponse"
                         }
                     }
                 }
@@ -218,9 +266,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Category"
                 ],
-                "summary": "Endpoint for update product by ID",
+                "summary": "Endpoint for update category by ID, a zero parent id moves the category to the root",
                 "parameters": [
                     {
                         "type": "string",
@@ -229,18 +277,6 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Accept",
-                        "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Content-Type",
-                        "in": "header"
-                    },
                     {
                         "type": "integer",
                         "description": "Example: 1",
@@ -254,7 +290,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateProductInput"
+                            "$ref": "#/definitions/model.UpdateCategoryInput"
                         }
                     }
                 ],
@@ -262,7 +298,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ProductResponse"
+                            "$ref": "#/definitions/model.CategoryResponse"
                         }
                     }
                 }
@@ -275,9 +311,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Category"
                 ],
-                "summary": "Endpoint for delete product by ID",
+                "summary": "Endpoint for delete category by ID, only a category without subcategories and products can be deleted",
                 "parameters": [
                     {
                         "type": "string",
@@ -286,18 +322,6 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Accept",
-                        "in": "header"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Example: application/json",
-                        "name": "Content-Type",
-                        "in": "header"
-                    },
                     {
                         "type": "integer",
                         "description": "Example: 1",
@@ -315,59 +339,5736 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "httpsvc.metaInfo": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "count_page": {
+        },
+        "/products": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for get list pagination of products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CREATED_AT_ASC",
+                            "CREATED_AT_DESC",
+                            "PRICE_ASC",
+                            "PRICE_DESC",
+                            "NAME_ASC",
+                            "NAME_DESC"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "ProductSortTypeCreatedAtAsc",
+                            "ProductSortTypeCreatedAtDesc",
+                            "ProductSortTypePriceAsc",
+                            "ProductSortTypePriceDesc",
+                            "ProductSortTypeNameAsc",
+                            "ProductSortTypeNameDesc"
+                        ],
+                        "name": "sort_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_ProductResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ProductResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Store a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateProductInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/barcode/{code}": {
+            "get": {
+                "description": "The variant_id is set when the code belongs to a variant of the product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for find the product of a scanned barcode or sku",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: 8991234567891",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScannedProductResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/export": {
+            "get": {
+                "description": "A product with variants is exported with its lowest variant price and its total stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for export the whole catalog as a CSV or XLSX file with the columns of the import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV or XLSX, default to CSV",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "description": "The first row is the header, only the name column is required: name, sku, barcode, description, price, cost_price, quantity, min_stock, reorder_quantity, tax_category, category_id.\nAn existing product only takes the columns of the file and keeps its stock \u0026 cost price. The failed rows are reported by their line number, nothing is stored on a dry run.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for import products from a CSV or XLSX file, a row is matched to an existing product by its sku or by its name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "the CSV or XLSX file, at most 10MB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV or XLSX, default to the file extension",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "check the rows without storing them",
+                        "name": "dryRun",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductImportResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/labels": {
+            "post": {
+                "description": "A product with variants prints the copies for every variant unless the variant is chosen",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "image/svg+xml",
+                    "image/png",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for print the shelf labels of products as SVG, PNG or PDF sheets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "format SVG, PNG or PDF default to PDF, layout A4_GRID or ROLL_40X30 default to A4_GRID",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PrintLabelsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/low-stock": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Endpoint for get the products which stock has reached their minimum stock level, the lowest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_ProductResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ProductResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for get detail product by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for update product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateProductInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for delete product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/cost-price": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Endpoint for correct the moving average cost price of the product manually, it is averaged again by the next goods receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateProductCostPriceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProductResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/stock-card": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Endpoint for get the stock movements of a product over a period along with the running balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-09-30",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-09-01",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "variant_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockCardResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/stock-movements": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Endpoint for post a waste or transfer of a product stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateStockMovementInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockMovementResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Endpoint for get list pagination of promotions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_PromotionResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.PromotionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Store a promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreatePromotionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PromotionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Endpoint for get detail promotion by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PromotionResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Endpoint for update promotion by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdatePromotionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PromotionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Endpoint for delete promotion by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order"
+                ],
+                "summary": "Endpoint for get list pagination of purchase orders, the newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "DRAFT",
+                            "SENT",
+                            "PARTIALLY_RECEIVED",
+                            "RECEIVED",
+                            "CLOSED"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "PurchaseOrderStatusDraft",
+                            "PurchaseOrderStatusSent",
+                            "PurchaseOrderStatusPartiallyReceived",
+                            "PurchaseOrderStatusReceived",
+                            "PurchaseOrderStatusClosed"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "supplier_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_PurchaseOrderResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.PurchaseOrderResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order"
+                ],
+                "summary": "Store a draft purchase order, the items can be changed until it is sent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreatePurchaseOrderInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order"
+                ],
+                "summary": "Endpoint for get detail purchase order by id along with its items \u0026 goods receipts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrderResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order"
+                ],
+                "summary": "Endpoint for update a draft purchase order by ID, the items are replaced",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdatePurchaseOrderInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/close": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order"
+                ],
+                "summary": "Close a sent purchase order, the items which are not received yet are given up",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/receive": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order"
+                ],
+                "summary": "Record a goods receipt of the purchase order, the received goods are added to the stock and the cost price of the products is averaged",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReceivePurchaseOrderInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/purchase-orders/{id}/send": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Order"
+                ],
+                "summary": "Mark the draft purchase order as sent to the supplier, its goods can be received afterwards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrderResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/profit": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Endpoint for get the gross profit \u0026 margin of the sales made within the date range, grouped by product, category, cashier or day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-09-30",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PRODUCT",
+                            "CATEGORY",
+                            "CASHIER",
+                            "DAY"
+                        ],
+                        "type": "string",
+                        "example": "PRODUCT",
+                        "x-enum-varnames": [
+                            "ProfitReportGroupProduct",
+                            "ProfitReportGroupCategory",
+                            "ProfitReportGroupCashier",
+                            "ProfitReportGroupDay"
+                        ],
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-09-01",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON or CSV, default to JSON",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProfitReportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/x": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Endpoint for get the mid-day X report, it covers the sales since the last Z report and does not close the period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON or CSV, default to JSON",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SalesReportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/z": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Endpoint for get list pagination of Z reports ordered by the newest number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_SalesReportResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.SalesReportResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Endpoint for close the period since the last Z report and store it as the next numbered Z report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.SalesReportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/z/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Endpoint for get detail Z report by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON or CSV, default to JSON",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SalesReportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shift"
+                ],
+                "summary": "Endpoint for get list pagination of shifts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "cashier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "OPEN",
+                            "CLOSED"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "ShiftStatusOpen",
+                            "ShiftStatusClosed"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_ShiftResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ShiftResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/current": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shift"
+                ],
+                "summary": "Endpoint for get the open shift of the current login cashier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ShiftResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/open": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shift"
+                ],
+                "summary": "Open a cashier shift with the opening float",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OpenShiftInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ShiftResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shift"
+                ],
+                "summary": "Endpoint for get detail shift by id along with its cash movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ShiftResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}/cash-movements": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shift"
+                ],
+                "summary": "Record a cash in or cash out of an open shift, e.g. petty cash or a safe drop",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CashMovementInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.CashMovementResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}/close": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shift"
+                ],
+                "summary": "Close an open shift with the counted closing cash and record the variance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CloseShiftInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ShiftResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Endpoint for get list pagination of stock opnames",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "OPEN",
+                            "APPROVED"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "StockOpnameStatusOpen",
+                            "StockOpnameStatusApproved"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_StockOpnameResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.StockOpnameResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Start a stock opname, the products can be counted until it is approved",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StartStockOpnameInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockOpnameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Endpoint for get detail stock opname by id along with the variances of the counted products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockOpnameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames/{id}/approve": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Approve the stock opname, the variances are posted as adjustment stock movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockOpnameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stock-opnames/{id}/counts": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Submit the counted quantities of the current login user, a recount of the same product replaces the previous count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SubmitStockOpnameCountsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockOpnameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppliers": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier"
+                ],
+                "summary": "Endpoint for get list pagination of suppliers ordered by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_SupplierResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.SupplierResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier"
+                ],
+                "summary": "Store a supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateSupplierInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.SupplierResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppliers/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier"
+                ],
+                "summary": "Endpoint for get detail supplier by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SupplierResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier"
+                ],
+                "summary": "Endpoint for update supplier by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateSupplierInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SupplierResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier"
+                ],
+                "summary": "Endpoint for delete supplier by ID, the purchase orders of the supplier are kept",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Endpoint for get list pagination of transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "created_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-09-30",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "max_total_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "min_total_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CREATED_AT_ASC",
+                            "CREATED_AT_DESC",
+                            "TOTAL_PRICE_ASC",
+                            "TOTAL_PRICE_DESC"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "TransactionSortTypeCreatedAtAsc",
+                            "TransactionSortTypeCreatedAtDesc",
+                            "TransactionSortTypeTotalPriceAsc",
+                            "TransactionSortTypeTotalPriceDesc"
+                        ],
+                        "name": "sort_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-09-25",
+                        "name": "start_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_TransactionResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.TransactionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Endpoint for get detail transaction by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/receipt": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/octet-stream",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Endpoint for print the receipt of a transaction, every print after the first one is audited as reprint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "TEXT_58, TEXT_80, ESCPOS_58, ESCPOS_80 or PDF, default to TEXT_80",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/refunds": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Endpoint for refund some lines of a transaction and restock the returned products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RefundTransactionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.RefundResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/void": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Endpoint for void a transaction and restock all of its products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token from Auth Service : Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Example: application/json",
+                        "name": "Content-Type",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.VoidTransactionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.RefundResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for get list pagination of users ordered by name, the query matches the name or the email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ADMIN",
+                            "PRODUCT_MANAGER",
+                            "CASHIER",
+                            "FINANCIAL_AUDITOR",
+                            "INTERNAL_SERVICE"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "RoleAdmin",
+                            "RoleProductManager",
+                            "RoleCashiers",
+                            "RoleFinancialAuditor",
+                            "RoleInternalService"
+                        ],
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PENDING",
+                            "ACTIVE",
+                            "INACTIVE"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "StatusPending",
+                            "StatusActive",
+                            "StatusInactive"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/httpsvc.paginationResponse-array_model_UserResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.UserResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Store a user with the given role, the user is active right away",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/2fa": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for start the two factor enrollment of the current login user, the secret is added to the authenticator app by scanning the QR code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.twoFactorEnrollmentResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/2fa/confirm": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for enable the two factor of the current login user with a code of the authenticator app, the recovery codes are only shown once",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TwoFactorCodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.recoveryCodesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/2fa/disable": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for disable the two factor of the current login user, not allowed when the role requires it",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DisableTwoFactorInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/2fa/recovery-codes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for replace the recovery codes of the current login user, the old codes can no longer be used",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TwoFactorCodeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.recoveryCodesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/password": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for change the password of the current login user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/pin": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for set the PIN of the current login user, the PIN is used to log in on the POS terminals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangePINInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/httpsvc.successResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{userID}/activate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for activate a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{userID}/deactivate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for deactivate a user, all of the sessions of the user are revoked",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{userID}/role": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Endpoint for change the role of a user, the user must log in again to use the new role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Use Token: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Example: 1",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "payload",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangeUserRoleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "httpsvc.metaInfo": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "count_page": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_page": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "size": {
                     "type": "integer"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_ProductResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.ProductResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_PromotionResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.PromotionResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_PurchaseOrderResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.PurchaseOrderResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_SalesReportResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.SalesReportResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_ShiftResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.ShiftResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_StockOpnameResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.StockOpnameResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_SupplierResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.SupplierResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.TransactionResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.paginationResponse-array_model_UserResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.UserResponse"
+                        }
+                    }
+                },
+                "meta_info": {
+                    "$ref": "#/definitions/httpsvc.metaInfo"
+                }
+            }
+        },
+        "httpsvc.recoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "httpsvc.successResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "httpsvc.twoFactorChallengeRequest": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "recovery_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpsvc.twoFactorEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string"
+                },
+                "qr_code": {
+                    "description": "QRCode the PNG image of the otpauth URI as data URI",
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpsvc.twoFactorLoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "access_token_expires_at": {
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "description": "RecoveryCodes only returned when the login completes the enrollment",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "refresh_token_expires_at": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CashMovementInput": {
+            "type": "object",
+            "required": [
+                "reason",
+                "type"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 500000
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "setor ke brankas"
+                },
+                "type": {
+                    "enum": [
+                        "CASH_IN",
+                        "CASH_OUT"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CashMovementType"
+                        }
+                    ],
+                    "example": "CASH_OUT"
+                }
+            }
+        },
+        "model.CashMovementResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "Rp500.000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "setor ke brankas"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "CASH_OUT"
+                }
+            }
+        },
+        "model.CashMovementType": {
+            "type": "string",
+            "enum": [
+                "CASH_IN",
+                "CASH_OUT"
+            ],
+            "x-enum-varnames": [
+                "CashMovementTypeIn",
+                "CashMovementTypeOut"
+            ]
+        },
+        "model.CashierSalesResponse": {
+            "type": "object",
+            "properties": {
+                "cashier_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "cashier_name": {
+                    "type": "string",
+                    "example": "Budi"
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "Rp100.000"
+                },
+                "total_sales": {
+                    "type": "string",
+                    "example": "Rp2.750.000"
+                },
+                "transaction_count": {
+                    "type": "string",
+                    "example": "60"
+                }
+            }
+        },
+        "model.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CategoryResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Aneka kopi panas \u0026 dingin"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kopi"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "kopi"
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                }
+            }
+        },
+        "model.ChangePINInput": {
+            "type": "object",
+            "required": [
+                "password",
+                "pin",
+                "pin_confirmation"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "pin": {
+                    "type": "string",
+                    "maxLength": 6,
+                    "minLength": 4,
+                    "example": "1234"
+                },
+                "pin_confirmation": {
+                    "type": "string",
+                    "example": "1234"
+                }
+            }
+        },
+        "model.ChangePasswordInput": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "new_password_confirmation",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "new_password_confirmation": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "old_password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "model.ChangeUserRoleInput": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/rbac.Role"
+                        }
+                    ],
+                    "example": "PRODUCT_MANAGER"
+                }
+            }
+        },
+        "model.CloseShiftInput": {
+            "type": "object",
+            "properties": {
+                "closing_cash": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 750000
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "uang pas"
+                }
+            }
+        },
+        "model.CreateCategoryInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 80,
+                    "example": "Aneka kopi panas \u0026 dingin"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "minLength": 2,
+                    "example": "Kopi"
+                },
+                "parent_id": {
+                    "description": "ParentID zero for a root category",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1695599921375543118
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                }
+            }
+        },
+        "model.CreateProductInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "barcode": {
+                    "description": "Barcode an EAN-13 or UPC-A with a valid check digit, or a Code128",
+                    "type": "string",
+                    "example": "8991234567891"
+                },
+                "category_id": {
+                    "description": "CategoryID zero when the product is not categorized",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1695599921375543118
+                },
+                "cost_price": {
+                    "description": "CostPrice the initial cost price, later averaged by the goods receipts",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3000
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 80,
+                    "example": "Pisang goreng gurih"
+                },
+                "min_stock": {
+                    "description": "MinStock the stock level which triggers a low stock alert, zero disables the alert",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "minLength": 3,
+                    "example": "Pisang Goreng"
+                },
+                "options": {
+                    "description": "Options the option groups of the variants, empty when the product has no variants",
+                    "type": "array",
+                    "maxItems": 3,
+                    "items": {
+                        "$ref": "#/definitions/model.ProductOption"
+                    }
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5000
+                },
+                "quantity": {
+                    "description": "Quantity is required for a product without variants, a product with variants is stocked per variant",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "reorder_quantity": {
+                    "description": "ReorderQuantity the suggested quantity to order when the stock is low",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 20
+                },
+                "sku": {
+                    "description": "SKU generated from the product name when empty",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "PISANG-GORENG"
+                },
+                "tax_category": {
+                    "description": "TaxCategory default to STANDARD when empty",
+                    "maxLength": 30,
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TaxCategory"
+                        }
+                    ],
+                    "example": "STANDARD"
+                },
+                "variants": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/model.CreateProductVariantInput"
+                    }
+                }
+            }
+        },
+        "model.CreateProductVariantInput": {
+            "type": "object",
+            "required": [
+                "options"
+            ],
+            "properties": {
+                "barcode": {
+                    "type": "string",
+                    "example": "8991234567891"
+                },
+                "id": {
+                    "description": "ID the existing variant on update, empty for a new variant",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1695599921375543118
+                },
+                "options": {
+                    "description": "Options the chosen value of every option group, keyed by the option group name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 8000
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                },
+                "sku": {
+                    "description": "SKU generated from the product \u0026 variant name when empty",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "ES-TEH-L"
+                }
+            }
+        },
+        "model.CreatePromotionInput": {
+            "type": "object",
+            "required": [
+                "end_at",
+                "name",
+                "start_at",
+                "type"
+            ],
+            "properties": {
+                "buy_quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "daily_end_time": {
+                    "type": "string",
+                    "example": "17:00"
+                },
+                "daily_start_time": {
+                    "type": "string",
+                    "example": "15:00"
+                },
+                "end_at": {
+                    "type": "string",
+                    "example": "2023-10-25T00:00:00+07:00"
+                },
+                "get_quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "minimum_spend": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "minLength": 3,
+                    "example": "Happy Hour Pisang Goreng"
+                },
+                "product_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1695599921375543118
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "2023-09-25T00:00:00+07:00"
+                },
+                "type": {
+                    "enum": [
+                        "LINE_PERCENTAGE",
+                        "LINE_FIXED",
+                        "CART_PERCENTAGE",
+                        "CART_FIXED",
+                        "BUY_X_GET_Y"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PromotionType"
+                        }
+                    ],
+                    "example": "LINE_PERCENTAGE"
+                },
+                "value": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
+                }
+            }
+        },
+        "model.CreatePurchaseOrderInput": {
+            "type": "object",
+            "required": [
+                "items",
+                "supplier_id"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.PurchaseOrderItemInput"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "stok mingguan"
+                },
+                "supplier_id": {
+                    "type": "integer",
+                    "example": 1695599921375543118
+                }
+            }
+        },
+        "model.CreateStockMovementInput": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "type"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "expired"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": -2
+                },
+                "type": {
+                    "enum": [
+                        "WASTE",
+                        "TRANSFER"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StockMovementType"
+                        }
+                    ],
+                    "example": "WASTE"
+                },
+                "variant_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "model.CreateSupplierInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Jl. Kaliurang KM 5, Sleman"
+                },
+                "contact_name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "example": "Budi"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "order@sumberpangan.co.id"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "minLength": 2,
+                    "example": "CV Sumber Pangan"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "pengiriman setiap senin"
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "081234567890"
+                }
+            }
+        },
+        "model.CreateUserInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "password_confirmation",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "budi@mail.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Budi Santoso"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "123456"
+                },
+                "password_confirmation": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "123456"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/rbac.Role"
+                        }
+                    ],
+                    "example": "CASHIER"
+                }
+            }
+        },
+        "model.DisableTwoFactorInput": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "recovery_code": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "model.ForgotPasswordInput": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "budi@mail.com"
+                }
+            }
+        },
+        "model.GoodsReceiptItemInput": {
+            "type": "object",
+            "required": [
+                "purchase_order_item_id",
+                "quantity"
+            ],
+            "properties": {
+                "purchase_order_item_id": {
+                    "type": "integer",
+                    "example": 1695599921375543118
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "model.GoodsReceiptItemResponse": {
+            "type": "object",
+            "properties": {
+                "cost_price": {
+                    "type": "string",
+                    "example": "Rp3.000"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "purchase_order_item_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "model.GoodsReceiptResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "27 September 2023 09:00 WIB"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GoodsReceiptItemResponse"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "example": "surat jalan 0042"
+                },
+                "received_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                }
+            }
+        },
+        "model.HourlySalesResponse": {
+            "type": "object",
+            "properties": {
+                "hour": {
+                    "type": "string",
+                    "example": "13:00"
+                },
+                "total_sales": {
+                    "type": "string",
+                    "example": "Rp520.000"
+                },
+                "transaction_count": {
+                    "type": "string",
+                    "example": "12"
+                }
+            }
+        },
+        "model.LabelFormat": {
+            "type": "string",
+            "enum": [
+                "SVG",
+                "PNG",
+                "PDF"
+            ],
+            "x-enum-varnames": [
+                "LabelFormatSVG",
+                "LabelFormatPNG",
+                "LabelFormatPDF"
+            ]
+        },
+        "model.LabelLayout": {
+            "type": "string",
+            "enum": [
+                "A4_GRID",
+                "ROLL_40X30"
+            ],
+            "x-enum-varnames": [
+                "LabelLayoutA4Grid",
+                "LabelLayoutRoll40x30"
+            ]
+        },
+        "model.OpenShiftInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "shift pagi"
+                },
+                "opening_float": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 200000
+                }
+            }
+        },
+        "model.PaymentMethod": {
+            "type": "string",
+            "enum": [
+                "CASH",
+                "DEBIT_CARD",
+                "CREDIT_CARD",
+                "BANK_TRANSFER",
+                "E_WALLET",
+                "QRIS"
+            ],
+            "x-enum-varnames": [
+                "PaymentMethodCash",
+                "PaymentMethodDebitCard",
+                "PaymentMethodCreditCard",
+                "PaymentMethodBankTransfer",
+                "PaymentMethodEWallet",
+                "PaymentMethodQRIS"
+            ]
+        },
+        "model.PaymentMethodSalesResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "Rp3.100.000"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "CASH"
+                },
+                "transaction_count": {
+                    "type": "string",
+                    "example": "80"
+                }
+            }
+        },
+        "model.PrintLabelInput": {
+            "type": "object",
+            "required": [
+                "copies",
+                "product_id"
+            ],
+            "properties": {
+                "copies": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "product_id": {
+                    "type": "integer",
+                    "example": 1695599921375543118
+                },
+                "variant_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "model.PrintLabelsInput": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "format": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.LabelFormat"
+                        }
+                    ],
+                    "example": "PDF"
+                },
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.PrintLabelInput"
+                    }
+                },
+                "layout": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.LabelLayout"
+                        }
+                    ],
+                    "example": "A4_GRID"
+                }
+            }
+        },
+        "model.ProductImportResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductImportRowError"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 18
+                }
+            }
+        },
+        "model.ProductImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "price: failed on the 'gte' tag"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.ProductOption": {
+            "type": "object",
+            "required": [
+                "name",
+                "values"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 30,
+                    "example": "Ukuran"
+                },
+                "values": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "S",
+                        "M",
+                        "L"
+                    ]
+                }
+            }
+        },
+        "model.ProductResponse": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string",
+                    "example": "8991234567891"
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "cost_price": {
+                    "type": "string",
+                    "example": "Rp3.000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Pisang goreng gurih"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "low_stock": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "min_stock": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Pisang Goreng"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductOption"
+                    }
+                },
+                "price": {
+                    "type": "string",
+                    "example": "Rp4.000"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "reorder_quantity": {
+                    "type": "string",
+                    "example": "20"
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "PISANG-GORENG"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "pisang-goreng"
+                },
+                "tax_category": {
+                    "type": "string",
+                    "example": "STANDARD"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductVariantResponse"
+                    }
+                }
+            }
+        },
+        "model.ProductSalesResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "string",
+                    "example": "Rp10.000"
+                },
+                "gross_sales": {
+                    "type": "string",
+                    "example": "Rp140.000"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Indomie Goreng"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "40"
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "Rp130.000"
+                }
+            }
+        },
+        "model.ProductSortType": {
+            "type": "string",
+            "enum": [
+                "CREATED_AT_ASC",
+                "CREATED_AT_DESC",
+                "PRICE_ASC",
+                "PRICE_DESC",
+                "NAME_ASC",
+                "NAME_DESC"
+            ],
+            "x-enum-varnames": [
+                "ProductSortTypeCreatedAtAsc",
+                "ProductSortTypeCreatedAtDesc",
+                "ProductSortTypePriceAsc",
+                "ProductSortTypePriceDesc",
+                "ProductSortTypeNameAsc",
+                "ProductSortTypeNameDesc"
+            ]
+        },
+        "model.ProductVariantInput": {
+            "type": "object",
+            "required": [
+                "options"
+            ],
+            "properties": {
+                "barcode": {
+                    "type": "string",
+                    "example": "8991234567891"
+                },
+                "id": {
+                    "description": "ID the existing variant on update, empty for a new variant",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1695599921375543118
+                },
+                "options": {
+                    "description": "Options the chosen value of every option group, keyed by the option group name",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 8000
+                },
+                "sku": {
+                    "description": "SKU generated from the product \u0026 variant name when empty",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "ES-TEH-L"
+                }
+            }
+        },
+        "model.ProductVariantResponse": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string",
+                    "example": "8991234567891"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "L / Dingin"
+                },
+                "options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "type": "string",
+                    "example": "Rp8.000"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "ES-TEH-L-DINGIN"
+                }
+            }
+        },
+        "model.ProfitLineResponse": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "string",
+                    "example": "Rp100.000"
+                },
+                "gross_profit": {
+                    "type": "string",
+                    "example": "Rp30.000"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "margin": {
+                    "type": "string",
+                    "example": "23.08%"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Indomie Goreng"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "40"
+                },
+                "revenue": {
+                    "type": "string",
+                    "example": "Rp130.000"
+                }
+            }
+        },
+        "model.ProfitReportGroup": {
+            "type": "string",
+            "enum": [
+                "PRODUCT",
+                "CATEGORY",
+                "CASHIER",
+                "DAY"
+            ],
+            "x-enum-varnames": [
+                "ProfitReportGroupProduct",
+                "ProfitReportGroupCategory",
+                "ProfitReportGroupCashier",
+                "ProfitReportGroupDay"
+            ]
+        },
+        "model.ProfitReportResponse": {
+            "type": "object",
+            "properties": {
+                "group_by": {
+                    "type": "string",
+                    "example": "PRODUCT"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProfitLineResponse"
+                    }
+                },
+                "period_end": {
+                    "type": "string",
+                    "example": "1 October 2023 00:00 WIB"
+                },
+                "period_start": {
+                    "type": "string",
+                    "example": "1 September 2023 00:00 WIB"
+                },
+                "summary": {
+                    "$ref": "#/definitions/model.ProfitLineResponse"
+                }
+            }
+        },
+        "model.PromotionResponse": {
+            "type": "object",
+            "properties": {
+                "buy_quantity": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "daily_end_time": {
+                    "type": "string",
+                    "example": "17:00"
+                },
+                "daily_start_time": {
+                    "type": "string",
+                    "example": "15:00"
+                },
+                "end_at": {
+                    "type": "string",
+                    "example": "25 Oktober 2023 00:00 WIB"
+                },
+                "get_quantity": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "minimum_spend": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Happy Hour Pisang Goreng"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "25 September 2023 00:00 WIB"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PromotionType"
+                        }
+                    ],
+                    "example": "LINE_PERCENTAGE"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "10"
+                }
+            }
+        },
+        "model.PromotionType": {
+            "type": "string",
+            "enum": [
+                "LINE_PERCENTAGE",
+                "LINE_FIXED",
+                "CART_PERCENTAGE",
+                "CART_FIXED",
+                "BUY_X_GET_Y"
+            ],
+            "x-enum-varnames": [
+                "PromotionTypeLinePercentage",
+                "PromotionTypeLineFixed",
+                "PromotionTypeCartPercentage",
+                "PromotionTypeCartFixed",
+                "PromotionTypeBuyXGetY"
+            ]
+        },
+        "model.PurchaseOrderItemInput": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "cost_price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3000
+                },
+                "product_id": {
+                    "type": "integer",
+                    "example": 1695599921375543118
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "variant_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "model.PurchaseOrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "cost_price": {
+                    "type": "string",
+                    "example": "Rp3.000"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "24"
+                },
+                "received_quantity": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "model.PurchaseOrderResponse": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string",
+                    "example": ""
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PurchaseOrderItemResponse"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "example": "stok mingguan"
+                },
+                "receipts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GoodsReceiptResponse"
+                    }
+                },
+                "sent_at": {
+                    "type": "string",
+                    "example": ""
+                },
+                "status": {
+                    "type": "string",
+                    "example": "DRAFT"
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "Rp72.000"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                }
+            }
+        },
+        "model.PurchaseOrderStatus": {
+            "type": "string",
+            "enum": [
+                "DRAFT",
+                "SENT",
+                "PARTIALLY_RECEIVED",
+                "RECEIVED",
+                "CLOSED"
+            ],
+            "x-enum-varnames": [
+                "PurchaseOrderStatusDraft",
+                "PurchaseOrderStatusSent",
+                "PurchaseOrderStatusPartiallyReceived",
+                "PurchaseOrderStatusReceived",
+                "PurchaseOrderStatusClosed"
+            ]
+        },
+        "model.ReceivePurchaseOrderInput": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.GoodsReceiptItemInput"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "surat jalan 0042"
+                }
+            }
+        },
+        "model.RefundDetailInput": {
+            "type": "object",
+            "required": [
+                "product_id"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer",
+                    "example": 1695599921375543118
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "variant_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "model.RefundDetailResponse": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "Rp5.000"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "model.RefundResponse": {
+            "type": "object",
+            "properties": {
+                "approved_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "cash_amount": {
+                    "type": "string",
+                    "example": "Rp5.000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "produk rusak"
+                },
+                "refund_details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RefundDetailResponse"
+                    }
+                },
+                "total_amount": {
+                    "type": "string",
+                    "example": "Rp5.000"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RefundType"
+                        }
+                    ],
+                    "example": "REFUND"
+                }
+            }
+        },
+        "model.RefundTransactionInput": {
+            "type": "object",
+            "required": [
+                "reason",
+                "refund_details"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "produk rusak"
+                },
+                "refund_details": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.RefundDetailInput"
+                    }
+                }
+            }
+        },
+        "model.RefundType": {
+            "type": "string",
+            "enum": [
+                "VOID",
+                "REFUND"
+            ],
+            "x-enum-varnames": [
+                "RefundTypeVoid",
+                "RefundTypeRefund"
+            ]
+        },
+        "model.ResetPasswordInput": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "new_password_confirmation",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "123456"
+                },
+                "new_password_confirmation": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "123456"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SalesReportResponse": {
+            "type": "object",
+            "properties": {
+                "cashiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CashierSalesResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "26 September 2023 22:00 WIB"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "hourly": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.HourlySalesResponse"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "payment_methods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PaymentMethodSalesResponse"
+                    }
+                },
+                "period_end": {
+                    "type": "string",
+                    "example": "26 September 2023 22:00 WIB"
+                },
+                "period_start": {
+                    "type": "string",
+                    "example": "25 September 2023 22:00 WIB"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductSalesResponse"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/model.SalesSummaryResponse"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Z"
+                }
+            }
+        },
+        "model.SalesSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "string",
+                    "example": "Rp200.000"
+                },
+                "gross_sales": {
+                    "type": "string",
+                    "example": "Rp5.200.000"
+                },
+                "manual_discount": {
+                    "type": "string",
+                    "example": "Rp50.000"
+                },
+                "net_sales": {
+                    "type": "string",
+                    "example": "Rp5.485.000"
+                },
+                "refund_amount": {
+                    "type": "string",
+                    "example": "Rp40.000"
+                },
+                "refund_count": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "service_charge": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "tax_amount": {
+                    "type": "string",
+                    "example": "Rp550.000"
+                },
+                "total_sales": {
+                    "type": "string",
+                    "example": "Rp5.550.000"
+                },
+                "transaction_count": {
+                    "type": "string",
+                    "example": "120"
+                },
+                "void_amount": {
+                    "type": "string",
+                    "example": "Rp25.000"
+                },
+                "void_count": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "model.ScannedProductResponse": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string",
+                    "example": "8991234567891"
+                },
+                "category_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "cost_price": {
+                    "type": "string",
+                    "example": "Rp3.000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Pisang goreng gurih"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "low_stock": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "min_stock": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Pisang Goreng"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductOption"
+                    }
+                },
+                "price": {
+                    "type": "string",
+                    "example": "Rp4.000"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "reorder_quantity": {
+                    "type": "string",
+                    "example": "20"
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "PISANG-GORENG"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "pisang-goreng"
+                },
+                "tax_category": {
+                    "type": "string",
+                    "example": "STANDARD"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductVariantResponse"
+                    }
+                }
+            }
+        },
+        "model.ShiftResponse": {
+            "type": "object",
+            "properties": {
+                "cash_in": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "cash_movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CashMovementResponse"
+                    }
+                },
+                "cash_out": {
+                    "type": "string",
+                    "example": "Rp500.000"
+                },
+                "cash_sales": {
+                    "type": "string",
+                    "example": "Rp1.050.000"
+                },
+                "cashier_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "closed_at": {
+                    "type": "string",
+                    "example": "25 September 2023 15:00 WIB"
+                },
+                "closing_cash": {
+                    "type": "string",
+                    "example": "Rp745.000"
+                },
+                "closing_note": {
+                    "type": "string",
+                    "example": "kurang 5 ribu"
+                },
+                "expected_cash": {
+                    "type": "string",
+                    "example": "Rp750.000"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "opened_at": {
+                    "type": "string",
+                    "example": "25 September 2023 07:00 WIB"
+                },
+                "opening_float": {
+                    "type": "string",
+                    "example": "Rp200.000"
+                },
+                "opening_note": {
+                    "type": "string",
+                    "example": "shift pagi"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "CLOSED"
+                },
+                "variance": {
+                    "type": "string",
+                    "example": "-Rp5.000"
+                }
+            }
+        },
+        "model.ShiftStatus": {
+            "type": "string",
+            "enum": [
+                "OPEN",
+                "CLOSED"
+            ],
+            "x-enum-varnames": [
+                "ShiftStatusOpen",
+                "ShiftStatusClosed"
+            ]
+        },
+        "model.StartStockOpnameInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "stock opname akhir bulan"
+                }
+            }
+        },
+        "model.StockCardEntryResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "8"
                 },
-                "has_more": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
                 },
-                "next_page": {
-                    "type": "integer"
+                "created_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "note": {
+                    "type": "string",
+                    "example": ""
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "-2"
+                },
+                "reference_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "reference_type": {
+                    "type": "string",
+                    "example": "transaction"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "SALE"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "model.StockCardResponse": {
+            "type": "object",
+            "properties": {
+                "closing_balance": {
+                    "type": "string",
+                    "example": "8"
+                },
+                "end_at": {
+                    "type": "string",
+                    "example": "01 October 2023 00:00 WIB"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockCardEntryResponse"
+                    }
+                },
+                "opening_balance": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "01 September 2023 00:00 WIB"
+                },
+                "total_in": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "total_out": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "model.StockMovementResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "note": {
+                    "type": "string",
+                    "example": ""
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "-2"
+                },
+                "reference_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "reference_type": {
+                    "type": "string",
+                    "example": "transaction"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "SALE"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "model.StockMovementType": {
+            "type": "string",
+            "enum": [
+                "SALE",
+                "REFUND",
+                "ADJUSTMENT",
+                "RECEIPT",
+                "TRANSFER",
+                "WASTE"
+            ],
+            "x-enum-varnames": [
+                "StockMovementTypeSale",
+                "StockMovementTypeRefund",
+                "StockMovementTypeAdjustment",
+                "StockMovementTypeReceipt",
+                "StockMovementTypeTransfer",
+                "StockMovementTypeWaste"
+            ]
+        },
+        "model.StockOpnameCountInput": {
+            "type": "object",
+            "required": [
+                "product_id"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer",
+                    "example": 1695599921375543118
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 8
+                },
+                "variant_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "model.StockOpnameResponse": {
+            "type": "object",
+            "properties": {
+                "approved_at": {
+                    "type": "string",
+                    "example": ""
+                },
+                "approved_by": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "30 September 2023 20:00 WIB"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "stock opname akhir bulan"
+                },
+                "started_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OPEN"
+                },
+                "variances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockOpnameVarianceResponse"
+                    }
+                }
+            }
+        },
+        "model.StockOpnameStatus": {
+            "type": "string",
+            "enum": [
+                "OPEN",
+                "APPROVED"
+            ],
+            "x-enum-varnames": [
+                "StockOpnameStatusOpen",
+                "StockOpnameStatusApproved"
+            ]
+        },
+        "model.StockOpnameVarianceResponse": {
+            "type": "object",
+            "properties": {
+                "counted_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "1695599921375543118"
+                    ]
+                },
+                "counted_quantity": {
+                    "type": "string",
+                    "example": "8"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "system_quantity": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "variance": {
+                    "type": "string",
+                    "example": "-2"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "model.SubmitStockOpnameCountsInput": {
+            "type": "object",
+            "required": [
+                "counts"
+            ],
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.StockOpnameCountInput"
+                    }
+                }
+            }
+        },
+        "model.SupplierResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Jl. Kaliurang KM 5, Sleman"
+                },
+                "contact_name": {
+                    "type": "string",
+                    "example": "Budi"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "order@sumberpangan.co.id"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "CV Sumber Pangan"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "pengiriman setiap senin"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "081234567890"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                }
+            }
+        },
+        "model.TaxCategory": {
+            "type": "string",
+            "enum": [
+                "STANDARD",
+                "EXEMPT"
+            ],
+            "x-enum-varnames": [
+                "TaxCategoryStandard",
+                "TaxCategoryExempt"
+            ]
+        },
+        "model.TransactionDetailResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "product_name": {
+                    "type": "string",
+                    "example": "Pisang Goreng"
+                },
+                "product_slug": {
+                    "type": "string",
+                    "example": "pisang-goreng"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "sku": {
+                    "type": "string",
+                    "example": ""
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "Rp10.000"
+                },
+                "tax_category": {
+                    "type": "string",
+                    "example": "STANDARD"
+                },
+                "unit_price": {
+                    "type": "string",
+                    "example": "Rp5.000"
+                },
+                "variant_id": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "variant_name": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "model.TransactionPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "Rp20.000"
+                },
+                "method": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PaymentMethod"
+                        }
+                    ],
+                    "example": "CASH"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "model.TransactionPromotionResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "string",
+                    "example": "Rp1.000"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
                 },
-                "page": {
-                    "type": "integer"
+                "promotion_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
                 },
-                "size": {
-                    "type": "integer"
+                "promotion_name": {
+                    "type": "string",
+                    "example": "Happy Hour Pisang Goreng"
                 }
             }
         },
-        "httpsvc.paginationResponse-array_model_ProductResponse": {
+        "model.TransactionResponse": {
             "type": "object",
             "properties": {
-                "items": {
+                "amount_paid": {
+                    "type": "string",
+                    "example": "Rp20.000"
+                },
+                "change": {
+                    "type": "string",
+                    "example": "Rp10.000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "25 September 2023 13:59 WIB"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "manual_discount": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "net_total_price": {
+                    "type": "string",
+                    "example": "Rp10.000"
+                },
+                "price_include_tax": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "refunded_amount": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "refunds": {
                     "type": "array",
                     "items": {
-                        "type": "array",
-                        "items": {
-                            "$ref": "#/definitions/model.ProductResponse"
-                        }
+                        "$ref": "#/definitions/model.RefundResponse"
                     }
                 },
-                "meta_info": {
-                    "$ref": "#/definitions/httpsvc.metaInfo"
+                "service_charge": {
+                    "type": "string",
+                    "example": "Rp0"
+                },
+                "shift_id": {
+                    "type": "string",
+                    "example": "1695599921375543118"
+                },
+                "tax_amount": {
+                    "type": "string",
+                    "example": "Rp1.100"
+                },
+                "tax_base": {
+                    "type": "string",
+                    "example": "Rp10.000"
+                },
+                "total_price": {
+                    "type": "string",
+                    "example": "Rp10.000"
+                },
+                "transaction_details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionDetailResponse"
+                    }
+                },
+                "transaction_payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionPaymentResponse"
+                    }
+                },
+                "transaction_promotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionPromotionResponse"
+                    }
                 }
             }
         },
-        "httpsvc.successResponse": {
+        "model.TransactionSortType": {
+            "type": "string",
+            "enum": [
+                "CREATED_AT_ASC",
+                "CREATED_AT_DESC",
+                "TOTAL_PRICE_ASC",
+                "TOTAL_PRICE_DESC"
+            ],
+            "x-enum-varnames": [
+                "TransactionSortTypeCreatedAtAsc",
+                "TransactionSortTypeCreatedAtDesc",
+                "TransactionSortTypeTotalPriceAsc",
+                "TransactionSortTypeTotalPriceDesc"
+            ]
+        },
+        "model.TwoFactorCodeInput": {
             "type": "object",
+            "required": [
+                "code"
+            ],
             "properties": {
-                "data": {},
-                "success": {
-                    "type": "boolean"
+                "code": {
+                    "type": "string",
+                    "example": "123456"
                 }
             }
         },
-        "model.CreateProductInput": {
+        "model.UpdateCategoryInput": {
             "type": "object",
             "required": [
                 "name"
@@ -376,108 +6077,338 @@ const docTemplate = `{
                 "description": {
                     "type": "string",
                     "maxLength": 80,
-                    "example": "Pisang goreng gurih"
+                    "example": "Aneka kopi panas \u0026 dingin"
                 },
                 "name": {
                     "type": "string",
                     "maxLength": 60,
-                    "minLength": 3,
-                    "example": "Pisang Goreng"
+                    "minLength": 2,
+                    "example": "Kopi"
                 },
-                "price": {
+                "parent_id": {
+                    "description": "ParentID zero for a root category",
                     "type": "integer",
                     "minimum": 0,
-                    "example": 5000
+                    "example": 1695599921375543118
                 },
-                "quantity": {
+                "sort_order": {
                     "type": "integer",
-                    "example": 10
+                    "minimum": 0,
+                    "example": 1
                 }
             }
         },
-        "model.ProductResponse": {
+        "model.UpdateProductCostPriceInput": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "cost_price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3000
+                }
+            }
+        },
+        "model.UpdateProductInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "barcode": {
+                    "description": "Barcode an EAN-13 or UPC-A with a valid check digit, or a Code128",
                     "type": "string",
-                    "example": "25 September 2023 13:59 WIB"
+                    "example": "8991234567891"
+                },
+                "category_id": {
+                    "description": "CategoryID zero when the product is not categorized",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1695599921375543118
                 },
                 "description": {
                     "type": "string",
+                    "maxLength": 80,
                     "example": "Pisang goreng gurih"
                 },
-                "id": {
-                    "type": "string",
-                    "example": "1695599921375543118"
+                "min_stock": {
+                    "description": "MinStock the stock level which triggers a low stock alert, zero disables the alert",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5
                 },
                 "name": {
                     "type": "string",
+                    "maxLength": 60,
+                    "minLength": 3,
                     "example": "Pisang Goreng"
                 },
+                "options": {
+                    "description": "Options the option groups of the variants, empty when the product has no variants",
+                    "type": "array",
+                    "maxItems": 3,
+                    "items": {
+                        "$ref": "#/definitions/model.ProductOption"
+                    }
+                },
                 "price": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5000
+                },
+                "reorder_quantity": {
+                    "description": "ReorderQuantity the suggested quantity to order when the stock is low",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 20
+                },
+                "sku": {
+                    "description": "SKU kept when empty",
                     "type": "string",
-                    "example": "Rp4.000"
+                    "maxLength": 64,
+                    "example": "PISANG-GORENG"
                 },
-                "quantity": {
+                "tax_category": {
+                    "description": "TaxCategory default to STANDARD when empty",
+                    "maxLength": 30,
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TaxCategory"
+                        }
+                    ],
+                    "example": "STANDARD"
+                },
+                "variants": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/model.ProductVariantInput"
+                    }
+                }
+            }
+        },
+        "model.UpdatePromotionInput": {
+            "type": "object",
+            "required": [
+                "end_at",
+                "name",
+                "start_at",
+                "type"
+            ],
+            "properties": {
+                "buy_quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "daily_end_time": {
                     "type": "string",
-                    "example": "10"
+                    "example": "17:00"
                 },
-                "slug": {
+                "daily_start_time": {
                     "type": "string",
-                    "example": "pisang-goreng"
+                    "example": "15:00"
                 },
-                "updated_at": {
+                "end_at": {
                     "type": "string",
-                    "example": "25 September 2023 13:59 WIB"
+                    "example": "2023-10-25T00:00:00+07:00"
+                },
+                "get_quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "minimum_spend": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "minLength": 3,
+                    "example": "Happy Hour Pisang Goreng"
+                },
+                "product_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1695599921375543118
+                },
+                "start_at": {
+                    "type": "string",
+                    "example": "2023-09-25T00:00:00+07:00"
+                },
+                "type": {
+                    "enum": [
+                        "LINE_PERCENTAGE",
+                        "LINE_FIXED",
+                        "CART_PERCENTAGE",
+                        "CART_FIXED",
+                        "BUY_X_GET_Y"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.PromotionType"
+                        }
+                    ],
+                    "example": "LINE_PERCENTAGE"
+                },
+                "value": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10
                 }
             }
         },
-        "model.ProductSortType": {
-            "type": "string",
-            "enum": [
-                "CREATED_AT_ASC",
-                "CREATED_AT_DESC",
-                "PRICE_ASC",
-                "PRICE_DESC",
-                "NAME_ASC",
-                "NAME_DESC"
+        "model.UpdatePurchaseOrderInput": {
+            "type": "object",
+            "required": [
+                "items",
+                "supplier_id"
             ],
-            "x-enum-varnames": [
-                "ProductSortTypeCreatedAtAsc",
-                "ProductSortTypeCreatedAtDesc",
-                "ProductSortTypePriceAsc",
-                "ProductSortTypePriceDesc",
-                "ProductSortTypeNameAsc",
-                "ProductSortTypeNameDesc"
-            ]
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.PurchaseOrderItemInput"
+                    }
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "stok mingguan"
+                },
+                "supplier_id": {
+                    "type": "integer",
+                    "example": 1695599921375543118
+                }
+            }
         },
-        "model.UpdateProductInput": {
+        "model.UpdateSupplierInput": {
             "type": "object",
             "required": [
                 "name"
             ],
             "properties": {
-                "description": {
+                "address": {
                     "type": "string",
-                    "maxLength": 80,
-                    "example": "Pisang goreng gurih"
+                    "maxLength": 200,
+                    "example": "Jl. Kaliurang KM 5, Sleman"
+                },
+                "contact_name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "example": "Budi"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "order@sumberpangan.co.id"
                 },
                 "name": {
                     "type": "string",
                     "maxLength": 60,
-                    "minLength": 3,
-                    "example": "Pisang Goreng"
+                    "minLength": 2,
+                    "example": "CV Sumber Pangan"
                 },
-                "price": {
-                    "type": "integer",
-                    "minimum": 0,
-                    "example": 5000
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "pengiriman setiap senin"
                 },
-                "quantity": {
+                "phone": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "081234567890"
+                }
+            }
+        },
+        "model.UserResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "budi@mail.com"
+                },
+                "id": {
                     "type": "integer",
-                    "example": 10
+                    "example": 1695599921375543118
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Budi Santoso"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/rbac.Role"
+                        }
+                    ],
+                    "example": "CASHIER"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.UserStatus"
+                        }
+                    ],
+                    "example": "ACTIVE"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UserStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "ACTIVE",
+                "INACTIVE"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusActive",
+                "StatusInactive"
+            ]
+        },
+        "model.VoidTransactionInput": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "salah input produk"
                 }
             }
+        },
+        "rbac.Role": {
+            "type": "string",
+            "enum": [
+                "ADMIN",
+                "PRODUCT_MANAGER",
+                "CASHIER",
+                "FINANCIAL_AUDITOR",
+                "INTERNAL_SERVICE"
+            ],
+            "x-enum-varnames": [
+                "RoleAdmin",
+                "RoleProductManager",
+                "RoleCashiers",
+                "RoleFinancialAuditor",
+                "RoleInternalService"
+            ]
         }
     },
     "securityDefinitions": {
@@ -15,6 +15,7 @@ require (
 	github.com/gomodule/redigo v1.8.9
 	github.com/goodsign/monday v1.0.1
 	github.com/gosimple/slug v1.13.1
+	github.com/jackc/pgx/v5 v5.3.1
 	github.com/jpillora/backoff v1.0.0
 	github.com/labstack/echo/v4 v4.9.0
 	github.com/rubenv/sql-migrate v1.5.2
@@ -57,7 +58,6 @@ require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
-	github.com/jackc/pgx/v5 v5.3.1 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
@@ -0,0 +1,107 @@
+// Package barcode validate the product barcodes read by the scanners and encode them for printing
+package barcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// Symbology the barcode type of a code
+type Symbology string
+
+const (
+	SymbologyEAN13   Symbology = "EAN_13"
+	SymbologyUPCA    Symbology = "UPC_A"
+	SymbologyCode128 Symbology = "CODE_128"
+)
+
+// Code128MaxLength the longest Code128 data accepted, longer codes do not fit a product label
+const Code128MaxLength = 48
+
+// barcode errors
+var (
+	ErrInvalidCheckDigit = errors.New("invalid barcode check digit")
+	ErrInvalidCharacter  = errors.New("invalid barcode character")
+	ErrInvalidLength     = errors.New("invalid barcode length")
+)
+
+// Detect the symbology of the code and validate it, a numeric code of 13 or 12 digits
+// is an EAN-13 or UPC-A and must carry a valid check digit, any other code is a Code128
+// which may only contain printable ASCII characters
+func Detect(code string) (Symbology, error) {
+	if code == "" || len(code) > Code128MaxLength {
+		return "", ErrInvalidLength
+	}
+
+	if isNumeric(code) {
+		switch len(code) {
+		case 13:
+			if !validCheckDigit(code) {
+				return "", ErrInvalidCheckDigit
+			}
+			return SymbologyEAN13, nil
+		case 12:
+			if !validCheckDigit(code) {
+				return "", ErrInvalidCheckDigit
+			}
+			return SymbologyUPCA, nil
+		}
+	}
+
+	for _, c := range code {
+		if c < ' ' || c > '~' {
+			return "", ErrInvalidCharacter
+		}
+	}
+
+	return SymbologyCode128, nil
+}
+
+// Validate check the code is a valid EAN-13, UPC-A or Code128
+func Validate(code string) error {
+	_, err := Detect(code)
+	return err
+}
+
+// CheckDigit calculate the GS1 check digit of the numeric data, used by EAN-13 & UPC-A.
+// The digits are weighted 3 and 1 alternately starting from the rightmost digit.
+func CheckDigit(data string) (byte, error) {
+	if data == "" || !isNumeric(data) {
+		return 0, ErrInvalidCharacter
+	}
+
+	sum := 0
+	for i := len(data) - 1; i >= 0; i-- {
+		digit := int(data[i] - '0')
+		if (len(data)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return byte('0' + (10-sum%10)%10), nil
+}
+
+// Normalize trim the whitespaces & line endings appended by the scanners
+func Normalize(code string) string {
+	return strings.TrimSpace(code)
+}
+
+func validCheckDigit(code string) bool {
+	digit, err := CheckDigit(code[:len(code)-1])
+	if err != nil {
+		return false
+	}
+
+	return digit == code[len(code)-1]
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
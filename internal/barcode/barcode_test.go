@@ -0,0 +1,122 @@
+package barcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		code      string
+		symbology Symbology
+		err       error
+	}{
+		{code: "8991234567891", symbology: SymbologyEAN13},
+		{code: "4006381333931", symbology: SymbologyEAN13},
+		{code: "4006381333932", err: ErrInvalidCheckDigit},
+		{code: "036000291452", symbology: SymbologyUPCA},
+		{code: "036000291453", err: ErrInvalidCheckDigit},
+		{code: "ES-TEH-L", symbology: SymbologyCode128},
+		{code: "12345", symbology: SymbologyCode128},
+		{code: "ES TEH\t", err: ErrInvalidCharacter},
+		{code: "kopi-susu-ñ", err: ErrInvalidCharacter},
+		{code: "", err: ErrInvalidLength},
+		{code: "0123456789012345678901234567890123456789012345678", err: ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			symbology, err := Detect(tt.code)
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.symbology, symbology)
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	digit, err := CheckDigit("899123456789")
+	require.NoError(t, err)
+	require.Equal(t, byte('1'), digit)
+
+	digit, err = CheckDigit("03600029145")
+	require.NoError(t, err)
+	require.Equal(t, byte('2'), digit)
+
+	_, err = CheckDigit("89912345678A")
+	require.ErrorIs(t, err, ErrInvalidCharacter)
+}
+
+func barsString(bars Bars) string {
+	s := make([]byte, 0, len(bars))
+	for _, bar := range bars {
+		if bar {
+			s = append(s, '1')
+		} else {
+			s = append(s, '0')
+		}
+	}
+
+	return string(s)
+}
+
+func TestEncodeEAN13(t *testing.T) {
+	bars, err := EncodeEAN13("4006381333931")
+	require.NoError(t, err)
+
+	s := barsString(bars)
+	require.Len(t, s, 95)
+	require.Equal(t, "101", s[:3])
+	require.Equal(t, "0001101", s[3:10])  // 0 on the odd parity, since the first digit 4 starts with L
+	require.Equal(t, "0100111", s[10:17]) // 0 on the even parity
+	require.Equal(t, "01010", s[45:50])
+	require.Equal(t, "1000010", s[50:57]) // 3 on the right side
+	require.Equal(t, "101", s[92:])
+
+	_, err = EncodeEAN13("4006381333932")
+	require.ErrorIs(t, err, ErrInvalidCheckDigit)
+}
+
+func TestEncodeCode128(t *testing.T) {
+	for _, pattern := range code128Patterns[:code128Stop] {
+		sum := 0
+		for _, width := range pattern {
+			sum += int(width - '0')
+		}
+		require.Equal(t, 11, sum, pattern)
+	}
+
+	bars, err := EncodeCode128("ES-TEH-L")
+	require.NoError(t, err)
+	s := barsString(bars)
+	require.Len(t, s, 11*(8+2)+13)
+	require.Equal(t, "11010010000", s[:11]) // start B
+	require.Equal(t, "1100011101011", s[len(s)-13:])
+
+	// the numeric data is encoded as digit pairs
+	bars, err = EncodeCode128("1695599921375543118")
+	require.NoError(t, err)
+	s = barsString(bars)
+	require.Len(t, s, 11*(3+9+1)+13)
+	require.Equal(t, "11010010000", s[:11])
+
+	bars, err = EncodeCode128("16955999")
+	require.NoError(t, err)
+	s = barsString(bars)
+	require.Len(t, s, 11*(1+4+1)+13)
+	require.Equal(t, "11010011100", s[:11]) // start C
+
+	_, err = EncodeCode128("kopi\n")
+	require.ErrorIs(t, err, ErrInvalidCharacter)
+}
+
+func TestEncode(t *testing.T) {
+	bars, symbology, err := Encode("036000291452")
+	require.NoError(t, err)
+	require.Equal(t, SymbologyUPCA, symbology)
+	require.Len(t, bars, 95)
+
+	_, symbology, err = Encode("PISANG-GORENG")
+	require.NoError(t, err)
+	require.Equal(t, SymbologyCode128, symbology)
+}
@@ -0,0 +1,162 @@
+package barcode
+
+// Bars the modules of an encoded barcode from left to right, true is a dark bar.
+// The quiet zones around the barcode are left to the renderer.
+type Bars []bool
+
+// QuietZone the modules kept blank on both sides of the barcode so the scanners can find it
+const QuietZone = 11
+
+var (
+	ean13LeftOdd = [10]string{
+		"0001101", "0011001", "0010011", "0111101", "0100011",
+		"0110001", "0101111", "0111011", "0110111", "0001011",
+	}
+	ean13LeftEven = [10]string{
+		"0100111", "0110011", "0011011", "0100001", "0011101",
+		"0111001", "0000101", "0010001", "0001001", "0010111",
+	}
+	ean13Right = [10]string{
+		"1110010", "1100110", "1101100", "1000010", "1011100",
+		"1001110", "1010000", "1000100", "1001000", "1110100",
+	}
+	// ean13Parity the odd (L) & even (G) encoding of the left digits, chosen by the first digit
+	ean13Parity = [10]string{
+		"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+		"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+	}
+)
+
+// code128Patterns the bar & space widths of every Code128 symbol value, the last one is the stop pattern
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// Encode the code on its detected symbology, an UPC-A is encoded as an EAN-13 with a leading zero
+func Encode(code string) (Bars, Symbology, error) {
+	symbology, err := Detect(code)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var bars Bars
+	switch symbology {
+	case SymbologyEAN13:
+		bars, err = EncodeEAN13(code)
+	case SymbologyUPCA:
+		bars, err = EncodeEAN13("0" + code)
+	default:
+		bars, err = EncodeCode128(code)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return bars, symbology, nil
+}
+
+// EncodeEAN13 encode the 13 digits including the check digit as 95 modules
+func EncodeEAN13(code string) (Bars, error) {
+	if len(code) != 13 || !isNumeric(code) {
+		return nil, ErrInvalidLength
+	}
+	if !validCheckDigit(code) {
+		return nil, ErrInvalidCheckDigit
+	}
+
+	pattern := "101"
+	parity := ean13Parity[code[0]-'0']
+	for i := 1; i <= 6; i++ {
+		digit := code[i] - '0'
+		if parity[i-1] == 'L' {
+			pattern += ean13LeftOdd[digit]
+		} else {
+			pattern += ean13LeftEven[digit]
+		}
+	}
+	pattern += "01010"
+	for i := 7; i <= 12; i++ {
+		pattern += ean13Right[code[i]-'0']
+	}
+	pattern += "101"
+
+	bars := make(Bars, 0, len(pattern))
+	for _, c := range pattern {
+		bars = append(bars, c == '1')
+	}
+
+	return bars, nil
+}
+
+// EncodeCode128 encode the printable ASCII data as Code128 along with its check symbol.
+// A numeric data of four digits or more is encoded as digit pairs on code set C to keep the barcode short,
+// the leading digit of an odd length number is encoded on code set B.
+func EncodeCode128(data string) (Bars, error) {
+	if data == "" || len(data) > Code128MaxLength {
+		return nil, ErrInvalidLength
+	}
+	for _, c := range data {
+		if c < ' ' || c > '~' {
+			return nil, ErrInvalidCharacter
+		}
+	}
+
+	var values []int
+	switch {
+	case isNumeric(data) && len(data) >= 4 && len(data)%2 == 0:
+		values = append(values, code128StartC)
+		values = append(values, digitPairs(data)...)
+	case isNumeric(data) && len(data) >= 4:
+		values = append(values, code128StartB, int(data[0]-' '), code128CodeC)
+		values = append(values, digitPairs(data[1:])...)
+	default:
+		values = append(values, code128StartB)
+		for i := 0; i < len(data); i++ {
+			values = append(values, int(data[i]-' '))
+		}
+	}
+
+	checksum := values[0]
+	for i := 1; i < len(values); i++ {
+		checksum += i * values[i]
+	}
+	values = append(values, checksum%103, code128Stop)
+
+	var bars Bars
+	for _, value := range values {
+		for i, width := range code128Patterns[value] {
+			// the patterns start with a bar and alternate with a space
+			for n := 0; n < int(width-'0'); n++ {
+				bars = append(bars, i%2 == 0)
+			}
+		}
+	}
+
+	return bars, nil
+}
+
+func digitPairs(digits string) []int {
+	values := make([]int, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		values = append(values, int(digits[i]-'0')*10+int(digits[i+1]-'0'))
+	}
+
+	return values
+}
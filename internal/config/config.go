@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"strings"
@@ -225,6 +226,191 @@ func SessionDeleteBatchSize() int {
 	return cfg
 }
 
+// TransactionVoidWindow get how long after its creation a transaction can still be voided
+func TransactionVoidWindow() time.Duration {
+	cfg := viper.GetString("transaction.void_window")
+	return parseDuration(cfg, DefaultTransactionVoidWindow)
+}
+
+// TransactionRequireOpenShift get whether selling is blocked when the cashier has no open shift
+func TransactionRequireOpenShift() bool {
+	return viper.GetBool("transaction.require_open_shift")
+}
+
+// TaxRates get the tax rate in percent of each tax category, keyed by the upper cased category.
+// The standard category is charged with the PPN rate and the exempt category is not taxed unless configured otherwise
+func TaxRates() map[string]float64 {
+	rates := map[string]float64{
+		"STANDARD": DefaultPPNRate,
+		"EXEMPT":   0,
+	}
+
+	for category := range viper.GetStringMap("tax.rates") {
+		rates[strings.ToUpper(category)] = viper.GetFloat64("tax.rates." + category)
+	}
+
+	return rates
+}
+
+// ServiceChargeRate get the service charge in percent of the net sales, zero means no service charge
+func ServiceChargeRate() float64 {
+	return viper.GetFloat64("tax.service_charge_rate")
+}
+
+// PriceIncludeTax get whether the product prices already include the tax
+func PriceIncludeTax() bool {
+	return viper.GetBool("tax.price_include_tax")
+}
+
+// ReceiptHeader get the lines printed on top of the receipt, e.g. the store name & address
+func ReceiptHeader() []string {
+	return viper.GetStringSlice("receipt.header")
+}
+
+// ReceiptFooter get the lines printed at the bottom of the receipt
+func ReceiptFooter() []string {
+	return viper.GetStringSlice("receipt.footer")
+}
+
+// StockAlertInterval get how often the low stock products are checked, zero disables the checker
+func StockAlertInterval() time.Duration {
+	if !viper.IsSet("stock_alert.interval") {
+		return DefaultStockAlertInterval
+	}
+
+	cfg := viper.GetString("stock_alert.interval")
+	return parseDuration(cfg, 0)
+}
+
+// StockAlertRepeatInterval get how long a low stock product is not alerted again after its alert
+func StockAlertRepeatInterval() time.Duration {
+	cfg := viper.GetString("stock_alert.repeat_interval")
+	return parseDuration(cfg, DefaultStockAlertRepeatInterval)
+}
+
+// StockAlertNotifier get where the low stock alerts are sent, either log, file or webhook
+func StockAlertNotifier() string {
+	if !viper.IsSet("stock_alert.notifier") {
+		return "log"
+	}
+
+	return viper.GetString("stock_alert.notifier")
+}
+
+// StockAlertFilePath get the file the low stock alerts are appended to by the file notifier
+func StockAlertFilePath() string {
+	return viper.GetString("stock_alert.file_path")
+}
+
+// StockAlertWebhookURL get the url the low stock alerts are posted to by the webhook notifier
+func StockAlertWebhookURL() string {
+	return viper.GetString("stock_alert.webhook_url")
+}
+
+// StockAlertWebhookTimeout get the timeout of posting a low stock alert to the webhook
+func StockAlertWebhookTimeout() time.Duration {
+	cfg := viper.GetString("stock_alert.webhook_timeout")
+	return parseDuration(cfg, DefaultStockAlertWebhookTimeout)
+}
+
+// ProductImportBatchSize get how many imported products are stored in a single db transaction
+func ProductImportBatchSize() int {
+	cfg := viper.GetInt("product_import.batch_size")
+
+	if cfg <= 0 {
+		return DefaultProductImportBatchSize
+	}
+
+	return cfg
+}
+
+// PasswordResetTokenDuration get how long a password reset token can be used
+func PasswordResetTokenDuration() time.Duration {
+	cfg := viper.GetString("password_reset.token_duration")
+	return parseDuration(cfg, DefaultPasswordResetTokenDuration)
+}
+
+// PasswordResetURL get the url of the reset password page, the token is appended as the token query param
+func PasswordResetURL() string {
+	return viper.GetString("password_reset.url")
+}
+
+// Mailer get how the emails are sent, either log, file or smtp
+func Mailer() string {
+	if !viper.IsSet("mailer.kind") {
+		return "log"
+	}
+
+	return viper.GetString("mailer.kind")
+}
+
+// MailerFilePath get the file the emails are appended to by the file mailer
+func MailerFilePath() string {
+	return viper.GetString("mailer.file_path")
+}
+
+// MailerSMTPHost get the host of the SMTP server
+func MailerSMTPHost() string {
+	return viper.GetString("mailer.smtp.host")
+}
+
+// MailerSMTPPort get the port of the SMTP server
+func MailerSMTPPort() int {
+	cfg := viper.GetInt("mailer.smtp.port")
+	if cfg <= 0 {
+		return DefaultMailerSMTPPort
+	}
+
+	return cfg
+}
+
+// MailerSMTPUsername get the username of the SMTP server, empty to skip the authentication
+func MailerSMTPUsername() string {
+	return viper.GetString("mailer.smtp.username")
+}
+
+// MailerSMTPPassword get the password of the SMTP server
+func MailerSMTPPassword() string {
+	return viper.GetString("mailer.smtp.password")
+}
+
+// MailerFrom get the sender address of the emails
+func MailerFrom() string {
+	return viper.GetString("mailer.from")
+}
+
+// MailerSendTimeout get the timeout of sending an email off the request
+func MailerSendTimeout() time.Duration {
+	cfg := viper.GetString("mailer.send_timeout")
+	return parseDuration(cfg, DefaultMailerSendTimeout)
+}
+
+// TwoFactorIssuer get the issuer shown by the authenticator apps next to the account
+func TwoFactorIssuer() string {
+	if !viper.IsSet("two_factor.issuer") {
+		return DefaultTwoFactorIssuer
+	}
+
+	return viper.GetString("two_factor.issuer")
+}
+
+// TwoFactorChallengeDuration get how long the two factor challenge of a login can be verified
+func TwoFactorChallengeDuration() time.Duration {
+	cfg := viper.GetString("two_factor.challenge_duration")
+	return parseDuration(cfg, DefaultTwoFactorChallengeDuration)
+}
+
+// TwoFactorRequiredRoles get the roles that must log in with two factor authentication,
+// the users of these roles without it have to enroll on their next login
+func TwoFactorRequiredRoles() []rbac.Role {
+	var roles []rbac.Role
+	for _, role := range viper.GetStringSlice("two_factor.required_roles") {
+		roles = append(roles, rbac.Role(strings.ToUpper(strings.TrimSpace(role))))
+	}
+
+	return roles
+}
+
 func parseDuration(in string, defaultDuration time.Duration) time.Duration {
 	dur, err := time.ParseDuration(in)
 	if err != nil {
@@ -20,4 +20,21 @@ const (
 	DefaultLoginRetryAttempts = 3
 	DefaultCacheTTL           = 15 * time.Minute
 	DefaultLoginLockTTL       = 5 * time.Minute
+
+	DefaultTransactionVoidWindow = 8 * time.Hour
+
+	DefaultPPNRate = 11
+
+	DefaultStockAlertInterval       = 15 * time.Minute
+	DefaultStockAlertRepeatInterval = 24 * time.Hour
+	DefaultStockAlertWebhookTimeout = 10 * time.Second
+
+	DefaultProductImportBatchSize = 100
+
+	DefaultPasswordResetTokenDuration = 1 * time.Hour
+	DefaultMailerSMTPPort             = 587
+	DefaultMailerSendTimeout          = 30 * time.Second
+
+	DefaultTwoFactorIssuer            = "Go Point of Sales"
+	DefaultTwoFactorChallengeDuration = 5 * time.Minute
 )
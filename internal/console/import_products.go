@@ -0,0 +1,125 @@
+package console
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/db"
+	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/repository"
+	"github.com/irvankadhafi/go-point-of-sales/internal/spreadsheet"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"time"
+)
+
+var importProductsCmd = &cobra.Command{
+	Use:   "import-products",
+	Short: "import the products of a CSV or XLSX file",
+	Long: `This subcommand create or update the products of a CSV or XLSX file on behalf of the user with --email,
+a row is matched to an existing product by its sku or by its name. Use --dry-run to only report the failed rows`,
+	Run: importProducts,
+}
+
+func init() {
+	importProductsCmd.Flags().String("file", "", "the CSV or XLSX file of the products")
+	importProductsCmd.Flags().String("format", "", "CSV or XLSX, default to the file extension")
+	importProductsCmd.Flags().String("email", "", "the email of the user importing the products")
+	importProductsCmd.Flags().Bool("dry-run", false, "check the rows without storing them")
+	_ = importProductsCmd.MarkFlagRequired("file")
+	_ = importProductsCmd.MarkFlagRequired("email")
+	RootCmd.AddCommand(importProductsCmd)
+}
+
+func importProducts(cmd *cobra.Command, args []string) {
+	filePath, _ := cmd.Flags().GetString("file")
+	formatFlag, _ := cmd.Flags().GetString("format")
+	email, _ := cmd.Flags().GetString("email")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	format := model.ProductFileFormat(strings.ToUpper(formatFlag))
+	if format == "" {
+		format = model.ProductFileFormatFromFilename(filePath)
+	}
+	if !format.IsValid() {
+		logrus.Fatal(model.ErrUnknownProductFileFormat)
+	}
+
+	file, err := os.Open(filePath)
+	continueOrFatal(err)
+	defer helper.WrapCloser(file.Close)
+
+	info, err := file.Stat()
+	continueOrFatal(err)
+
+	records, err := spreadsheet.ReadAll(file, info.Size(), format)
+	continueOrFatal(err)
+
+	input, err := model.ParseProductImportRecords(records)
+	continueOrFatal(err)
+	input.DryRun = dryRun
+
+	// Initiate all connection like db, redis, etc
+	db.InitializePostgresConn()
+	generalCacher := cacher.ConstructCacheManager()
+
+	redisOpts := &db.RedisConnectionPoolOptions{
+		DialTimeout:     config.RedisDialTimeout(),
+		ReadTimeout:     config.RedisReadTimeout(),
+		WriteTimeout:    config.RedisWriteTimeout(),
+		IdleCount:       config.RedisMaxIdleConn(),
+		PoolSize:        config.RedisMaxActiveConn(),
+		IdleTimeout:     240 * time.Second,
+		MaxConnLifetime: 1 * time.Minute,
+	}
+
+	redisConn, err := db.NewRedigoRedisConnectionPool(config.RedisCacheHost(), redisOpts)
+	continueOrFatal(err)
+	defer helper.WrapCloser(redisConn.Close)
+
+	redisLockConn, err := db.NewRedigoRedisConnectionPool(config.RedisLockHost(), redisOpts)
+	continueOrFatal(err)
+	defer helper.WrapCloser(redisLockConn.Close)
+
+	generalCacher.SetConnectionPool(redisConn)
+	generalCacher.SetLockConnectionPool(redisLockConn)
+	generalCacher.SetDefaultTTL(config.CacheTTL())
+
+	auditRepo := repository.NewAuditRepository()
+	rbacRepo := repository.NewRBACRepository(db.PostgreSQL, generalCacher)
+	userRepo := repository.NewUserRepository(db.PostgreSQL, generalCacher)
+	productVariantRepo := repository.NewProductVariantRepository(db.PostgreSQL, generalCacher)
+	stockMovementRepo := repository.NewStockMovementRepository(db.PostgreSQL)
+	productRepo := repository.NewProductRepository(db.PostgreSQL, generalCacher, productVariantRepo, stockMovementRepo, auditRepo)
+	categoryRepo := repository.NewCategoryRepository(db.PostgreSQL, generalCacher, auditRepo)
+	productUsecase := usecase.NewProductUsecase(productRepo, productVariantRepo, categoryRepo)
+
+	requester, err := userRepo.FindByEmail(cmd.Context(), email)
+	continueOrFatal(err)
+	if requester == nil {
+		logrus.Fatalf("user %s is not found", email)
+	}
+
+	perm, err := rbacRepo.LoadPermission(cmd.Context())
+	continueOrFatal(err)
+	requester.SetPermission(perm)
+
+	result, err := productUsecase.Import(cmd.Context(), requester, input)
+	continueOrFatal(err)
+
+	for _, rowErr := range result.Errors {
+		logrus.WithField("line", rowErr.Line).Warn(rowErr.Error)
+	}
+
+	if result.DryRun {
+		logrus.Infof("dry run of %d rows: %d would be created, %d would be updated, %d failed",
+			result.Total, result.Created, result.Updated, len(result.Errors))
+		return
+	}
+
+	logrus.Infof("imported %d rows: %d created, %d updated, %d failed",
+		result.Total, result.Created, result.Updated, len(result.Errors))
+}
@@ -0,0 +1,60 @@
+package console
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/db"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var reconcileStockCmd = &cobra.Command{
+	Use:   "reconcile-stock",
+	Short: "reconcile the product stock against the stock movements",
+	Long: `This subcommand report the products & variants which stock differs from the sum of their stock movements,
+use --fix to post adjustment movements which bring the ledger to the current stock`,
+	Run: reconcileStock,
+}
+
+func init() {
+	reconcileStockCmd.Flags().Bool("fix", false, "post the reconciling adjustment movements")
+	RootCmd.AddCommand(reconcileStockCmd)
+}
+
+func reconcileStock(cmd *cobra.Command, args []string) {
+	db.InitializePostgresConn()
+	stockMovementRepo := repository.NewStockMovementRepository(db.PostgreSQL)
+
+	discrepancies, err := stockMovementRepo.FindDiscrepancies(cmd.Context())
+	continueOrFatal(err)
+
+	for _, discrepancy := range discrepancies {
+		logrus.WithFields(logrus.Fields{
+			"productID":      discrepancy.ProductID,
+			"variantID":      discrepancy.VariantID,
+			"quantity":       discrepancy.Quantity,
+			"ledgerQuantity": discrepancy.LedgerQuantity,
+		}).Warn("stock differs from the stock movements")
+	}
+
+	if len(discrepancies) <= 0 {
+		logrus.Info("stock is consistent with the stock movements")
+		return
+	}
+
+	fix, _ := cmd.Flags().GetBool("fix")
+	if !fix {
+		logrus.Infof("found %d discrepancies, run with --fix to reconcile", len(discrepancies))
+		return
+	}
+
+	// the stock is kept as is, only the ledger is completed
+	movements := model.AnyStockDiscrepancies(discrepancies).ReconcilingMovements("stock reconciliation")
+	err = db.PostgreSQL.WithContext(cmd.Context()).Transaction(func(tx *gorm.DB) error {
+		return stockMovementRepo.Create(cmd.Context(), tx, movements)
+	})
+	continueOrFatal(err)
+
+	logrus.Infof("posted %d reconciling stock movements", len(movements))
+}
@@ -105,5 +105,27 @@ func seeder(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	appClientPOS := &model.AppClient{
+		ID:            utils.GenerateID(),
+		ClientID:      "pos-terminal-1",
+		ClientSecret:  "Vq8mRZt3LxN2cKw7PfHs9DyB4jUe6GaT",
+		IsPOSTerminal: true,
+	}
+
+	err = appClientRepo.Create(context.Background(), appClientPOS)
+	if err != nil {
+		return
+	}
+
+	userCashierCipherPIN, err := helper.HashString("1234")
+	if err != nil {
+		logrus.Error(err)
+	}
+
+	err = userRepo.UpdatePINByID(context.Background(), userCashier.ID, userCashierCipherPIN)
+	if err != nil {
+		return
+	}
+
 	logrus.Warn("DONE!")
 }
@@ -11,6 +11,9 @@ import (
 	"github.com/irvankadhafi/go-point-of-sales/internal/db"
 	"github.com/irvankadhafi/go-point-of-sales/internal/delivery/httpsvc"
 	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
+	"github.com/irvankadhafi/go-point-of-sales/internal/mailer"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/notifier"
 	"github.com/irvankadhafi/go-point-of-sales/internal/repository"
 	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
 	"github.com/labstack/echo/v4"
@@ -31,6 +34,9 @@ var runCmd = &cobra.Command{
 	Run:   run,
 }
 
+// stopStockAlertTickerCh signal for closing the low stock checker ticker
+var stopStockAlertTickerCh = make(chan bool, 1)
+
 func init() {
 	RootCmd.AddCommand(runCmd)
 }
@@ -83,16 +89,50 @@ func run(cmd *cobra.Command, args []string) {
 	userRepo := repository.NewUserRepository(db.PostgreSQL, generalCacher)
 	sessionRepo := repository.NewSessionRepository(db.PostgreSQL, authenticationCacher, userRepo)
 	appClientRepo := repository.NewAppClientRepository(db.PostgreSQL, authenticationCacher)
-	productRepo := repository.NewProductRepository(db.PostgreSQL, generalCacher, auditRepo)
+	productVariantRepo := repository.NewProductVariantRepository(db.PostgreSQL, generalCacher)
+	stockMovementRepo := repository.NewStockMovementRepository(db.PostgreSQL)
+	productRepo := repository.NewProductRepository(db.PostgreSQL, generalCacher, productVariantRepo, stockMovementRepo, auditRepo)
 	transactionDetailRepo := repository.NewTransactionDetailRepository(db.PostgreSQL, generalCacher)
-	transactionRepo := repository.NewTransactionRepository(db.PostgreSQL, generalCacher, transactionDetailRepo, auditRepo)
+	transactionPaymentRepo := repository.NewTransactionPaymentRepository(db.PostgreSQL, generalCacher)
+	transactionPromotionRepo := repository.NewTransactionPromotionRepository(db.PostgreSQL, generalCacher)
+	shiftRepo := repository.NewShiftRepository(db.PostgreSQL, generalCacher, auditRepo)
+	reportRepo := repository.NewReportRepository(db.PostgreSQL, generalCacher, auditRepo)
+	categoryRepo := repository.NewCategoryRepository(db.PostgreSQL, generalCacher, auditRepo)
+	transactionRepo := repository.NewTransactionRepository(db.PostgreSQL, generalCacher, transactionDetailRepo, transactionPaymentRepo, transactionPromotionRepo, productRepo, productVariantRepo, shiftRepo, stockMovementRepo, auditRepo)
+	promotionRepo := repository.NewPromotionRepository(db.PostgreSQL, generalCacher, auditRepo)
+	refundRepo := repository.NewRefundRepository(db.PostgreSQL, generalCacher, productRepo, productVariantRepo, stockMovementRepo, shiftRepo, auditRepo)
+	stockOpnameRepo := repository.NewStockOpnameRepository(db.PostgreSQL, generalCacher, productRepo, productVariantRepo, auditRepo)
+	stockAlertRepo := repository.NewStockAlertRepository(generalCacher)
+	supplierRepo := repository.NewSupplierRepository(db.PostgreSQL, generalCacher, auditRepo)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db.PostgreSQL, generalCacher, productRepo, productVariantRepo, auditRepo)
+	passwordResetTokenRepo := repository.NewPasswordResetTokenRepository(db.PostgreSQL)
+	twoFactorRepo := repository.NewTwoFactorRepository(db.PostgreSQL)
+
+	stockAlertNotifier, err := notifier.New(newStockAlertNotifierConfig())
+	continueOrFatal(err)
 
-	userUsecase := usecase.NewUserUsecase(userRepo)
-	authUsecase := usecase.NewAuthUsecase(userRepo, sessionRepo, rbacRepo)
+	userMailer, err := mailer.New(newMailerConfig())
+	continueOrFatal(err)
+
+	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo, passwordResetTokenRepo, userMailer)
+	authUsecase := usecase.NewAuthUsecase(userRepo, sessionRepo, rbacRepo, appClientRepo, twoFactorRepo)
 	userAuther := usecase.NewUserAutherAdapter(authUsecase)
 	appClientUsecase := usecase.NewAppClientUsecase(appClientRepo)
-	productUsecase := usecase.NewProductUsecase(productRepo)
-	transactionUsecase := usecase.NewTransactionUsecase(transactionRepo, productRepo)
+	productUsecase := usecase.NewProductUsecase(productRepo, productVariantRepo, categoryRepo)
+	transactionUsecase := usecase.NewTransactionUsecase(transactionRepo, productRepo, productVariantRepo, refundRepo, promotionRepo, userRepo, shiftRepo)
+	promotionUsecase := usecase.NewPromotionUsecase(promotionRepo, productRepo)
+	shiftUsecase := usecase.NewShiftUsecase(shiftRepo)
+	reportUsecase := usecase.NewReportUsecase(reportRepo)
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo)
+	stockMovementUsecase := usecase.NewStockMovementUsecase(stockMovementRepo, productRepo, productVariantRepo)
+	stockOpnameUsecase := usecase.NewStockOpnameUsecase(stockOpnameRepo, productRepo, productVariantRepo)
+	stockAlertUsecase := usecase.NewStockAlertUsecase(productRepo, stockAlertRepo, stockAlertNotifier)
+	supplierUsecase := usecase.NewSupplierUsecase(supplierRepo)
+	purchaseOrderUsecase := usecase.NewPurchaseOrderUsecase(purchaseOrderRepo, supplierRepo, productRepo, productVariantRepo)
+
+	if interval := config.StockAlertInterval(); interval > 0 {
+		go checkLowStock(time.NewTicker(interval), stockAlertUsecase)
+	}
 
 	httpServer := echo.New()
 	httpMiddleware := auth.NewAuthenticationMiddleware(userAuther, authenticationCacher)
@@ -104,7 +144,7 @@ func run(cmd *cobra.Command, args []string) {
 
 	httpServer.GET("/swagger/*", echoSwagger.WrapHandler, middleware.RemoveTrailingSlash())
 	apiGroup := httpServer.Group("/api")
-	httpsvc.RouteService(apiGroup, authUsecase, userUsecase, appClientUsecase, productUsecase, transactionUsecase, httpMiddleware)
+	httpsvc.RouteService(apiGroup, authUsecase, userUsecase, appClientUsecase, productUsecase, transactionUsecase, promotionUsecase, shiftUsecase, reportUsecase, categoryUsecase, stockMovementUsecase, stockOpnameUsecase, supplierUsecase, purchaseOrderUsecase, httpMiddleware)
 
 	sigCh := make(chan os.Signal, 1)
 	errCh := make(chan error, 1)
@@ -138,6 +178,7 @@ func run(cmd *cobra.Command, args []string) {
 
 func gracefulShutdown(httpSvr *echo.Echo) {
 	db.StopTickerCh <- true
+	stopStockAlertTickerCh <- true
 
 	if httpSvr != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -147,3 +188,52 @@ func gracefulShutdown(httpSvr *echo.Echo) {
 		}
 	}
 }
+
+func newStockAlertNotifierConfig() notifier.Config {
+	cfg := notifier.Config{
+		Kind:    config.StockAlertNotifier(),
+		Timeout: config.StockAlertWebhookTimeout(),
+	}
+
+	switch cfg.Kind {
+	case notifier.KindFile:
+		cfg.Target = config.StockAlertFilePath()
+	case notifier.KindWebhook:
+		cfg.Target = config.StockAlertWebhookURL()
+	}
+
+	return cfg
+}
+
+func newMailerConfig() mailer.Config {
+	return mailer.Config{
+		Kind:     config.Mailer(),
+		FilePath: config.MailerFilePath(),
+		SMTP: mailer.SMTPConfig{
+			Host:     config.MailerSMTPHost(),
+			Port:     config.MailerSMTPPort(),
+			Username: config.MailerSMTPUsername(),
+			Password: config.MailerSMTPPassword(),
+			From:     config.MailerFrom(),
+		},
+	}
+}
+
+func checkLowStock(ticker *time.Ticker, stockAlertUsecase model.StockAlertUsecase) {
+	for {
+		select {
+		case <-stopStockAlertTickerCh:
+			ticker.Stop()
+			return
+		case <-ticker.C:
+			sent, err := stockAlertUsecase.NotifyLowStock(context.Background())
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			if sent > 0 {
+				log.WithField("sent", sent).Info("low stock alerts sent")
+			}
+		}
+	}
+}
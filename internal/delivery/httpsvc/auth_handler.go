@@ -39,7 +39,7 @@ func (s *Service) handleLoginByEmailPassword() echo.HandlerFunc {
 			return err
 		}
 
-		session, err := s.authUsecase.LoginByEmailPassword(c.Request().Context(), model.LoginRequest{
+		session, challenge, err := s.authUsecase.LoginByEmailPassword(c.Request().Context(), model.LoginRequest{
 			AppID:         client.ID,
 			Email:         req.Email,
 			PlainPassword: req.Password,
@@ -52,6 +52,10 @@ func (s *Service) handleLoginByEmailPassword() echo.HandlerFunc {
 			return ErrEmailPasswordNotMatch
 		case usecase.ErrLoginByEmailPasswordLocked:
 			return ErrLoginByEmailPasswordLocked
+		case model.ErrUserPending:
+			return ErrUserPending
+		case model.ErrUserInactive:
+			return ErrUserInactive
 		case usecase.ErrPermissionDenied:
 			return ErrPermissionDenied
 		default:
@@ -59,15 +63,104 @@ func (s *Service) handleLoginByEmailPassword() echo.HandlerFunc {
 			return ErrInternal
 		}
 
-		res := loginResponse{
-			AccessToken:           session.AccessToken,
-			AccessTokenExpiresAt:  utils.FormatTimeRFC3339(&session.AccessTokenExpiredAt),
-			RefreshToken:          session.RefreshToken,
-			RefreshTokenExpiresAt: utils.FormatTimeRFC3339(&session.RefreshTokenExpiredAt),
-			TokenType:             "Bearer",
+		if challenge != nil {
+			return c.JSON(http.StatusOK, twoFactorChallengeResponse{
+				TwoFactorRequired:       true,
+				EnrollmentRequired:      challenge.Enrollment,
+				ChallengeToken:          challenge.Token,
+				ChallengeTokenExpiresAt: utils.FormatTimeRFC3339(&challenge.ExpiredAt),
+			})
 		}
 
-		return c.JSON(http.StatusOK, res)
+		return c.JSON(http.StatusOK, newLoginResponse(session))
+	}
+}
+
+type pinLoginRequest struct {
+	UserID int64  `json:"user_id"`
+	PIN    string `json:"pin"`
+}
+
+func (s *Service) handleLoginByPIN() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := pinLoginRequest{}
+		if err := c.Bind(&req); err != nil {
+			logrus.Error(err)
+			return ErrInvalidArgument
+		}
+
+		client, err := s.getAppClient(c)
+		if err != nil {
+			logrus.Error(err)
+			return err
+		}
+
+		session, err := s.authUsecase.LoginByPIN(c.Request().Context(), model.PINLoginRequest{
+			AppID:     client.ID,
+			UserID:    req.UserID,
+			PlainPIN:  req.PIN,
+			UserAgent: c.Request().UserAgent(),
+		})
+		if err != nil {
+			return pinLoginErr(err)
+		}
+
+		return c.JSON(http.StatusOK, newLoginResponse(session))
+	}
+}
+
+func (s *Service) handleSwitchOperator() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := pinLoginRequest{}
+		if err := c.Bind(&req); err != nil {
+			logrus.Error(err)
+			return ErrInvalidArgument
+		}
+
+		session, err := s.authUsecase.SwitchOperator(ctx, requester, model.PINLoginRequest{
+			UserID:    req.UserID,
+			PlainPIN:  req.PIN,
+			UserAgent: c.Request().UserAgent(),
+		})
+		if err != nil {
+			return pinLoginErr(err)
+		}
+
+		return c.JSON(http.StatusOK, newLoginResponse(session))
+	}
+}
+
+// pinLoginErr the http error of the PIN login & the operator switch
+func pinLoginErr(err error) error {
+	switch err {
+	case usecase.ErrNotFound, usecase.ErrUnauthorized:
+		return ErrUserPINNotMatch
+	case usecase.ErrLoginByPINLocked:
+		return ErrLoginByPINLocked
+	case usecase.ErrNotPOSTerminal:
+		return ErrNotPOSTerminal
+	case model.ErrUserPending:
+		return ErrUserPending
+	case model.ErrUserInactive:
+		return ErrUserInactive
+	case model.ErrTwoFactorRequired:
+		return ErrTwoFactorRequired
+	default:
+		logrus.Error(err)
+		return ErrInternal
+	}
+}
+
+func newLoginResponse(session *model.Session) loginResponse {
+	return loginResponse{
+		AccessToken:           session.AccessToken,
+		AccessTokenExpiresAt:  utils.FormatTimeRFC3339(&session.AccessTokenExpiredAt),
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiresAt: utils.FormatTimeRFC3339(&session.RefreshTokenExpiredAt),
+		TokenType:             "Bearer",
 	}
 }
 
@@ -91,6 +184,10 @@ func (s *Service) handleRefreshToken() echo.HandlerFunc {
 		case nil:
 		case usecase.ErrRefreshTokenExpired, usecase.ErrNotFound:
 			return ErrUnauthenticated
+		case model.ErrUserPending:
+			return ErrUserPending
+		case model.ErrUserInactive:
+			return ErrUserInactive
 		default:
 			logrus.Error(err)
 			return ErrInternal
@@ -127,6 +224,212 @@ func (s *Service) handleLogout() echo.HandlerFunc {
 	}
 }
 
+// Endpoint Forgot Password
+//
+//	@Summary	Endpoint for mail a password reset token, the response is the same whether the email is registered or not
+//	@Description
+//	@Tags		Auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		Body	body		model.ForgotPasswordInput	true	"payload"
+//	@Success	200		{object}	successResponse
+//	@Router		/auth/password/forgot [post]
+func (s *Service) handleForgotPassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		req := model.ForgotPasswordInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		err := s.userUsecase.ForgotPassword(ctx, req)
+		switch err {
+		case nil:
+			return c.JSON(http.StatusOK, setSuccessResponse(nil))
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+	}
+}
+
+// Endpoint Reset Password
+//
+//	@Summary	Endpoint for reset the password with the mailed token, all of the sessions of the user are revoked
+//	@Description
+//	@Tags		Auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		Body	body		model.ResetPasswordInput	true	"payload"
+//	@Success	200		{object}	successResponse
+//	@Router		/auth/password/reset [post]
+func (s *Service) handleResetPassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		req := model.ResetPasswordInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		err := s.userUsecase.ResetPassword(ctx, req)
+		switch err {
+		case nil:
+			return c.JSON(http.StatusOK, setSuccessResponse(nil))
+		case model.ErrInvalidPasswordResetToken:
+			return ErrInvalidPasswordResetToken
+		case model.ErrUserPending:
+			return ErrUserPending
+		case model.ErrUserInactive:
+			return ErrUserInactive
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+	}
+}
+
+// twoFactorChallengeResponse the response of a login to be continued with the two factor authentication,
+// the user has to enroll first when the enrollment is required
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired       bool   `json:"two_factor_required"`
+	EnrollmentRequired      bool   `json:"enrollment_required"`
+	ChallengeToken          string `json:"challenge_token"`
+	ChallengeTokenExpiresAt string `json:"challenge_token_expires_at"`
+}
+
+type twoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+type twoFactorLoginResponse struct {
+	loginResponse
+	// RecoveryCodes only returned when the login completes the enrollment
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type twoFactorEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	// QRCode the PNG image of the otpauth URI as data URI
+	QRCode string `json:"qr_code"`
+}
+
+func newTwoFactorEnrollmentResponse(enrollment *model.TwoFactorEnrollment) twoFactorEnrollmentResponse {
+	return twoFactorEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	}
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Endpoint Enroll Two Factor By Challenge
+//
+//	@Summary	Endpoint for start the two factor enrollment of a login whose role requires two factor authentication
+//	@Description
+//	@Tags		Auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		Body	body		twoFactorChallengeRequest	true	"payload, only the challenge token is used"
+//	@Success	200		{object}	twoFactorEnrollmentResponse
+//	@Router		/auth/2fa/enroll [post]
+func (s *Service) handleEnrollTwoFactorByChallenge() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := twoFactorChallengeRequest{}
+		if err := c.Bind(&req); err != nil {
+			logrus.Error(err)
+			return ErrInvalidArgument
+		}
+
+		enrollment, err := s.authUsecase.EnrollTwoFactorByChallenge(c.Request().Context(), req.ChallengeToken)
+		if err != nil {
+			return twoFactorErr(err)
+		}
+
+		return c.JSON(http.StatusOK, newTwoFactorEnrollmentResponse(enrollment))
+	}
+}
+
+// Endpoint Verify Two Factor
+//
+//	@Summary	Endpoint for answer the two factor challenge of a login with a code or a recovery code
+//	@Description
+//	@Tags		Auth
+//	@Accept		json
+//	@Produce	json
+//	@Param		Body	body		twoFactorChallengeRequest	true	"payload"
+//	@Success	200		{object}	twoFactorLoginResponse
+//	@Router		/auth/2fa/verify [post]
+func (s *Service) handleVerifyTwoFactor() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := twoFactorChallengeRequest{}
+		if err := c.Bind(&req); err != nil {
+			logrus.Error(err)
+			return ErrInvalidArgument
+		}
+
+		session, recoveryCodes, err := s.authUsecase.VerifyTwoFactor(c.Request().Context(), model.VerifyTwoFactorRequest{
+			ChallengeToken: req.ChallengeToken,
+			Code:           req.Code,
+			RecoveryCode:   req.RecoveryCode,
+		})
+		if err != nil {
+			return twoFactorErr(err)
+		}
+
+		return c.JSON(http.StatusOK, twoFactorLoginResponse{
+			loginResponse: newLoginResponse(session),
+			RecoveryCodes: recoveryCodes,
+		})
+	}
+}
+
+// twoFactorErr the http error of the two factor endpoints
+func twoFactorErr(err error) error {
+	switch err {
+	case model.ErrInvalidTwoFactorCode:
+		return ErrInvalidTwoFactorCode
+	case model.ErrInvalidTwoFactorChallenge:
+		return ErrInvalidTwoFactorChallenge
+	case model.ErrTwoFactorAlreadyEnabled:
+		return ErrTwoFactorAlreadyEnabled
+	case model.ErrTwoFactorNotEnabled:
+		return ErrTwoFactorNotEnabled
+	case model.ErrTwoFactorNotEnrolled:
+		return ErrTwoFactorNotEnrolled
+	case model.ErrTwoFactorRequired:
+		return ErrTwoFactorRequired
+	case usecase.ErrLoginByTwoFactorLocked:
+		return ErrLoginByTwoFactorLocked
+	case usecase.ErrPasswordMismatch:
+		return ErrOldPasswordMismatch
+	case usecase.ErrNotFound:
+		return ErrNotFound
+	case model.ErrUserPending:
+		return ErrUserPending
+	case model.ErrUserInactive:
+		return ErrUserInactive
+	default:
+		logrus.Error(err)
+		return httpValidationOrInternalErr(err)
+	}
+}
+
 func (s *Service) getAppClient(c echo.Context) (*model.AppClient, error) {
 	clientID, clientSecret, err := s.parseBasicAuth(c.Request())
 	if err != nil {
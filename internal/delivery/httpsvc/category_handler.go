@@ -0,0 +1,212 @@
+package httpsvc
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// Endpoint Create Category
+//
+//	@Summary	Store a category, give a parent id to store it as a subcategory
+//	@Description
+//	@Tags		Category
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.CreateCategoryInput	true	"payload"
+//	@Success	201				{object}	model.CategoryResponse
+//	@Router		/categories [post]
+func (s *Service) handleCreateCategory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CreateCategoryInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		category, err := s.categoryUsecase.Create(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrAlreadyExist:
+			return ErrCategoryAlreadyExist
+		case model.ErrInvalidCategoryParent:
+			return ErrInvalidCategoryParent
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(category.ToCategoryResponse()))
+	}
+}
+
+// Endpoint Get Category Tree
+//
+//	@Summary	Endpoint for get all categories, the subcategories are nested under their parent
+//	@Description
+//	@Tags		Category
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Success	200				{object}	[]model.CategoryResponse
+//	@Router		/categories [get]
+func (s *Service) handleGetCategoryTree() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		categories, err := s.categoryUsecase.FindTree(ctx, requester)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(categories.ToListCategoryResponse()))
+	}
+}
+
+// Endpoint Get Detail Category By ID
+//
+//	@Summary	Endpoint for get detail category by id along with its subcategories
+//	@Description
+//	@Tags		Category
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.CategoryResponse
+//	@Router		/categories/{id} [get]
+func (s *Service) handleGetDetailCategoryByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		category, err := s.categoryUsecase.FindByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(category.ToCategoryResponse()))
+	}
+}
+
+// Endpoint Update Category By ID
+//
+//	@Summary	Endpoint for update category by ID, a zero parent id moves the category to the root
+//	@Description
+//	@Tags		Category
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int							true	"Example: 1"
+//	@Param		Body			body		model.UpdateCategoryInput	true	"payload"
+//	@Success	200				{object}	model.CategoryResponse
+//	@Router		/categories/{id} [put]
+func (s *Service) handleUpdateCategoryByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.UpdateCategoryInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		category, err := s.categoryUsecase.UpdateByID(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrAlreadyExist:
+			return ErrCategoryAlreadyExist
+		case model.ErrInvalidCategoryParent:
+			return ErrInvalidCategoryParent
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(category.ToCategoryResponse()))
+	}
+}
+
+// Endpoint Delete Category By ID
+//
+//	@Summary	Endpoint for delete category by ID, only a category without subcategories and products can be deleted
+//	@Description
+//	@Tags		Category
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	successResponse
+//	@Router		/categories/{id} [delete]
+func (s *Service) handleDeleteCategoryByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		err := s.categoryUsecase.DeleteByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			return c.JSON(http.StatusOK, setSuccessResponse(nil))
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrCategoryNotEmpty:
+			return ErrCategoryNotEmpty
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+	}
+}
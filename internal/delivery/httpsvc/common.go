@@ -67,8 +67,9 @@ func setSuccessResponse(data any) successResponse {
 }
 
 type errorResponse struct {
-	Success bool `json:"success"`
-	Message any  `json:"message"`
+	Success bool   `json:"success"`
+	Message any    `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 func setErrorMessage(msg any) errorResponse {
@@ -77,3 +78,12 @@ func setErrorMessage(msg any) errorResponse {
 		Message: msg,
 	}
 }
+
+// setErrorCodeMessage set the error message along with a code, so the clients can tell apart the errors of the same status
+func setErrorCodeMessage(code string, msg any) errorResponse {
+	return errorResponse{
+		Success: false,
+		Message: msg,
+		Code:    code,
+	}
+}
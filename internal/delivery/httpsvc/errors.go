@@ -3,6 +3,7 @@ package httpsvc
 import (
 	"fmt"
 	"github.com/go-playground/validator"
+	"github.com/irvankadhafi/go-point-of-sales/auth"
 	"github.com/labstack/echo/v4"
 	"net/http"
 )
@@ -11,11 +12,71 @@ var (
 	ErrInvalidArgument            = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid argument"))
 	ErrEmailPasswordNotMatch      = echo.NewHTTPError(http.StatusUnauthorized, setErrorMessage("email or password not match"))
 	ErrLoginByEmailPasswordLocked = echo.NewHTTPError(http.StatusLocked, setErrorMessage("user is locked from logging in using email and password"))
+	ErrUserPINNotMatch            = echo.NewHTTPError(http.StatusUnauthorized, setErrorMessage("user or PIN not match"))
+	ErrLoginByPINLocked           = echo.NewHTTPError(http.StatusLocked, setErrorMessage("user is locked from logging in using PIN on the terminal"))
+	ErrNotPOSTerminal             = echo.NewHTTPError(http.StatusForbidden, setErrorMessage("PIN login is only allowed on the POS terminals"))
+	ErrInvalidTwoFactorCode       = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid two factor code"))
+	ErrInvalidTwoFactorChallenge  = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid or expired two factor challenge"))
+	ErrTwoFactorAlreadyEnabled    = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("two factor authentication already enabled"))
+	ErrTwoFactorNotEnabled        = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("two factor authentication not enabled"))
+	ErrTwoFactorNotEnrolled       = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("two factor enrollment not started"))
+	ErrTwoFactorRequired          = echo.NewHTTPError(http.StatusForbidden, setErrorMessage("two factor authentication is required for the role"))
+	ErrLoginByTwoFactorLocked     = echo.NewHTTPError(http.StatusLocked, setErrorMessage("user is locked from verifying the two factor codes"))
 	ErrPermissionDenied           = echo.NewHTTPError(http.StatusForbidden, setErrorMessage("permission denied"))
 	ErrInternal                   = echo.NewHTTPError(http.StatusInternalServerError, setErrorMessage("internal system error"))
 	ErrUnauthenticated            = echo.NewHTTPError(http.StatusUnauthorized, setErrorMessage("unauthenticated"))
 	ErrNotFound                   = echo.NewHTTPError(http.StatusNotFound, setErrorMessage("record not found"))
 	ErrProductNameAlreadyExist    = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("product name already exist"))
+	ErrInvalidDateRange           = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid date range"))
+	ErrInvalidAmountRange         = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid amount range"))
+	ErrInsufficientStock          = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("insufficient stock for one or more products"))
+	ErrInsufficientPayment        = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("payment is less than the total price"))
+	ErrNonCashOverpayment         = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("non cash payment exceeds the total price"))
+	ErrManualDiscountExceeded     = echo.NewHTTPError(http.StatusForbidden, setErrorMessage("manual discount exceeds the allowed maximum"))
+	ErrInvalidPromotionPeriod     = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid promotion period"))
+	ErrInvalidPromotionRule       = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid promotion rule"))
+	ErrRefundQuantityExceeded     = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("refund quantity exceeds the remaining sold quantity"))
+	ErrTransactionAlreadyRefunded = echo.NewHTTPError(http.StatusConflict, setErrorMessage("transaction already has refund"))
+	ErrVoidWindowExpired          = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("transaction can no longer be voided"))
+	ErrUnknownTaxCategory         = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown tax category"))
+	ErrUnknownReceiptFormat       = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown receipt format"))
+	ErrShiftAlreadyOpen           = echo.NewHTTPError(http.StatusConflict, setErrorMessage("cashier already has an open shift"))
+	ErrShiftNotOpen               = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("open a shift before selling"))
+	ErrShiftClosed                = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("shift is already closed"))
+	ErrUnknownCategory            = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown category"))
+	ErrInvalidCategoryParent      = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("parent category does not exist or is the category itself or one of its subcategories"))
+	ErrCategoryNotEmpty           = echo.NewHTTPError(http.StatusConflict, setErrorMessage("category still has subcategories or products"))
+	ErrCategoryAlreadyExist       = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("category name already exist"))
+	ErrUnknownReportFormat        = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown report format"))
+	ErrUnknownProfitReportGroup   = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown profit report group, use PRODUCT, CATEGORY, CASHIER or DAY"))
+	ErrUnknownProductVariant      = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown product variant"))
+	ErrProductVariantRequired     = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("product has variants, the variant must be chosen"))
+	ErrInvalidProductVariant      = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("product variant options do not match the product options"))
+	ErrDuplicateProductVariant    = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("product variant options or sku already exist"))
+	ErrDuplicateProductCode       = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("sku or barcode is already used"))
+	ErrUnknownLabelFormat         = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown label format"))
+	ErrUnknownLabelLayout         = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown label layout"))
+	ErrTooManyLabels              = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("too many labels in a single print"))
+	ErrInvalidStockMovement       = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid stock movement quantity for the movement type"))
+	ErrStockOpnameNotOpen         = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("stock opname is already approved"))
+	ErrStockOpnameEmpty           = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("count at least one product before approving the stock opname"))
+	ErrUnknownSupplier            = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown supplier"))
+	ErrInvalidPurchaseOrderStatus = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("purchase order status does not allow the action"))
+	ErrUnknownPurchaseOrderItem   = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown purchase order item"))
+	ErrPurchaseOrderOverReceived  = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("received quantity exceeds the remaining ordered quantity"))
+	ErrUnknownProductFileFormat   = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown product file format, use CSV or XLSX"))
+	ErrInvalidProductImportFile   = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("product file can not be read"))
+	ErrInvalidProductImportHeader = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid product import header, the name column is required"))
+	ErrTooManyProductImportRows   = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("too many rows in a single product import"))
+	ErrProductImportFileTooLarge  = echo.NewHTTPError(http.StatusRequestEntityTooLarge, setErrorMessage("product file is too large"))
+	ErrEmailAlreadyExist          = echo.NewHTTPError(http.StatusConflict, setErrorMessage("email already exist"))
+	ErrUnknownRole                = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("unknown role, use ADMIN, PRODUCT_MANAGER, CASHIER or FINANCIAL_AUDITOR"))
+	ErrUserSelfChange             = echo.NewHTTPError(http.StatusUnprocessableEntity, setErrorMessage("can not change the role or status of your own user"))
+	ErrOldPasswordMismatch        = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("old password does not match"))
+	ErrPasswordConfirmation       = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("new password confirmation does not match"))
+	ErrInvalidPasswordResetToken  = echo.NewHTTPError(http.StatusBadRequest, setErrorMessage("invalid or expired password reset token"))
+	ErrUserPending                = echo.NewHTTPError(http.StatusForbidden, setErrorCodeMessage(auth.ErrCodeUserPending, "user is pending activation"))
+	ErrUserInactive               = echo.NewHTTPError(http.StatusForbidden, setErrorCodeMessage(auth.ErrCodeUserInactive, "user is deactivated"))
 )
 
 // httpValidationOrInternalErr return valdiation or internal error
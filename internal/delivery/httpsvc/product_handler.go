@@ -1,16 +1,23 @@
 package httpsvc
 
 import (
+	"fmt"
 	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/spreadsheet"
 	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Endpoint CreateProduct
+//
 //	@Summary	Store a product
 //	@Description
 //	@Tags		Product
@@ -47,6 +54,18 @@ func (s *Service) handleCreateProduct() echo.HandlerFunc {
 			break
 		case usecase.ErrAlreadyExist:
 			return ErrProductNameAlreadyExist
+		case model.ErrUnknownTaxCategory:
+			return ErrUnknownTaxCategory
+		case model.ErrUnknownCategory:
+			return ErrUnknownCategory
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrInvalidProductVariant:
+			return ErrInvalidProductVariant
+		case model.ErrDuplicateProductVariant:
+			return ErrDuplicateProductVariant
+		case model.ErrDuplicateProductCode:
+			return ErrDuplicateProductCode
 		default:
 			logger.Error(err)
 			return httpValidationOrInternalErr(err)
@@ -57,6 +76,7 @@ func (s *Service) handleCreateProduct() echo.HandlerFunc {
 }
 
 // Endpoint Get List Pagination of Products
+//
 //	@Summary	Endpoint for get list pagination of products
 //	@Description
 //	@Tags		Product
@@ -99,6 +119,7 @@ func (s *Service) handleGetListPaginationProducts() echo.HandlerFunc {
 }
 
 // Endpoint Get Detail Product By ID
+//
 //	@Summary	Endpoint for get detail product by id
 //	@Description
 //	@Tags		Product
@@ -134,7 +155,47 @@ func (s *Service) handleGetDetailProductByID() echo.HandlerFunc {
 	}
 }
 
+// Endpoint Get Product By Barcode
+//
+//	@Summary	Endpoint for find the product of a scanned barcode or sku
+//	@Description	The variant_id is set when the code belongs to a variant of the product
+//	@Tags		Product
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Accept			header		string	false	"Example: application/json"
+//	@Param		Content-Type	header		string	false	"Example: application/json"
+//	@Param		code			path		string	true	"Example: 8991234567891"
+//	@Success	200				{object}	model.ScannedProductResponse
+//	@Router		/products/barcode/{code} [get]
+func (s *Service) handleGetProductByBarcode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		product, variant, err := s.productUsecase.FindByCode(ctx, requester, c.Param("code"))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(product.ToScannedProductResponse(variant)))
+	}
+}
+
 // Endpoint Update Product By ID
+//
 //	@Summary	Endpoint for update product by ID
 //	@Description
 //	@Tags		Product
@@ -176,6 +237,18 @@ func (s *Service) handleUpdateProductByID() echo.HandlerFunc {
 			return ErrNotFound
 		case usecase.ErrAlreadyExist:
 			return ErrProductNameAlreadyExist
+		case model.ErrUnknownTaxCategory:
+			return ErrUnknownTaxCategory
+		case model.ErrUnknownCategory:
+			return ErrUnknownCategory
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrInvalidProductVariant:
+			return ErrInvalidProductVariant
+		case model.ErrDuplicateProductVariant:
+			return ErrDuplicateProductVariant
+		case model.ErrDuplicateProductCode:
+			return ErrDuplicateProductCode
 		default:
 			logger.Error(err)
 			return httpValidationOrInternalErr(err)
@@ -185,7 +258,52 @@ func (s *Service) handleUpdateProductByID() echo.HandlerFunc {
 	}
 }
 
+// Endpoint Update Product Cost Price By ID
+//
+//	@Summary	Endpoint for correct the moving average cost price of the product manually, it is averaged again by the next goods receipt
+//	@Description
+//	@Tags		Product
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string								true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int									true	"Example: 1"
+//	@Param		Body			body		model.UpdateProductCostPriceInput	true	"payload"
+//	@Success	200				{object}	model.ProductResponse
+//	@Router		/products/{id}/cost-price [put]
+func (s *Service) handleUpdateProductCostPriceByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.UpdateProductCostPriceInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		product, err := s.productUsecase.UpdateCostPriceByID(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(product.ToProductResponse()))
+	}
+}
+
 // Endpoint Delete Product By ID
+//
 //	@Summary	Endpoint for delete product by ID
 //	@Description
 //	@Tags		Product
@@ -220,3 +338,234 @@ func (s *Service) handleDeleteProductByID() echo.HandlerFunc {
 
 	}
 }
+
+// Endpoint Print Product Labels
+//
+//	@Summary	Endpoint for print the shelf labels of products as SVG, PNG or PDF sheets
+//	@Description	A product with variants prints the copies for every variant unless the variant is chosen
+//	@Tags		Product
+//	@Accept		json
+//	@Produce	image/svg+xml
+//	@Produce	image/png
+//	@Produce	application/pdf
+//	@Param		Authorization	header		string					true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Body			body		model.PrintLabelsInput	true	"format SVG, PNG or PDF default to PDF, layout A4_GRID or ROLL_40X30 default to A4_GRID"
+//	@Success	200				{file}		file
+//	@Router		/products/labels [post]
+func (s *Service) handlePrintProductLabels() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.PrintLabelsInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+		req.Format = model.LabelFormat(strings.ToUpper(string(req.Format)))
+		req.Layout = model.LabelLayout(strings.ToUpper(string(req.Layout)))
+
+		sheet, err := s.productUsecase.PrintLabels(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrUnknownLabelFormat:
+			return ErrUnknownLabelFormat
+		case model.ErrUnknownLabelLayout:
+			return ErrUnknownLabelLayout
+		case model.ErrTooManyLabels:
+			return ErrTooManyLabels
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", sheet.Filename))
+		return c.Blob(http.StatusOK, sheet.ContentType, sheet.Content)
+	}
+}
+
+// maxProductImportFileSize the largest product file accepted by the import
+const maxProductImportFileSize = 10 << 20
+
+// Endpoint Import Products
+//
+//	@Summary	Endpoint for import products from a CSV or XLSX file, a row is matched to an existing product by its sku or by its name
+//	@Description	The first row is the header, only the name column is required: name, sku, barcode, description, price, cost_price, quantity, min_stock, reorder_quantity, tax_category, category_id.
+//	@Description	An existing product only takes the columns of the file and keeps its stock & cost price. The failed rows are reported by their line number, nothing is stored on a dry run.
+//	@Tags		Product
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		file			formData	file	true	"the CSV or XLSX file, at most 10MB"
+//	@Param		format			formData	string	false	"CSV or XLSX, default to the file extension"
+//	@Param		dryRun			formData	bool	false	"check the rows without storing them"
+//	@Success	200				{object}	model.ProductImportResult
+//	@Router		/products/import [post]
+func (s *Service) handleImportProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+		if fileHeader.Size > maxProductImportFileSize {
+			return ErrProductImportFileTooLarge
+		}
+
+		format := model.ProductFileFormat(strings.ToUpper(c.FormValue("format")))
+		if format == "" {
+			format = model.ProductFileFormatFromFilename(fileHeader.Filename)
+		}
+		if !format.IsValid() {
+			return ErrUnknownProductFileFormat
+		}
+
+		dryRun := false
+		if val := c.FormValue("dryRun"); val != "" {
+			if dryRun, err = strconv.ParseBool(val); err != nil {
+				return ErrInvalidArgument
+			}
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			logger.Error(err)
+			return ErrInternal
+		}
+		defer helper.WrapCloser(file.Close)
+
+		records, err := spreadsheet.ReadAll(file, fileHeader.Size, format)
+		if err != nil {
+			logger.Error(err)
+			return ErrInvalidProductImportFile
+		}
+
+		input, err := model.ParseProductImportRecords(records)
+		switch err {
+		case nil:
+			break
+		case model.ErrInvalidProductImportHeader:
+			return ErrInvalidProductImportHeader
+		case model.ErrTooManyProductImportRows:
+			return ErrTooManyProductImportRows
+		default:
+			logger.Error(err)
+			return ErrInvalidProductImportFile
+		}
+		input.DryRun = dryRun
+
+		result, err := s.productUsecase.Import(ctx, requester, input)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(result))
+	}
+}
+
+// Endpoint Export Products
+//
+//	@Summary	Endpoint for export the whole catalog as a CSV or XLSX file with the columns of the import
+//	@Description	A product with variants is exported with its lowest variant price and its total stock
+//	@Tags		Product
+//	@Accept		json
+//	@Produce	text/csv
+//	@Produce	application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		format			query		string	false	"CSV or XLSX, default to CSV"
+//	@Success	200				{file}		file
+//	@Router		/products/export [get]
+func (s *Service) handleExportProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		format := model.ProductFileFormatCSV
+		if val := c.QueryParam("format"); val != "" {
+			format = model.ProductFileFormat(strings.ToUpper(val))
+		}
+		if !format.IsValid() {
+			return ErrUnknownProductFileFormat
+		}
+
+		// the response is only started by the first product, so a denied request still gets its error response
+		var writer spreadsheet.Writer
+		startResponse := func() error {
+			res := c.Response()
+			filename := fmt.Sprintf("products-%s.%s", time.Now().In(utils.WesternIndonesianLocation()).Format("20060102"), strings.ToLower(string(format)))
+			res.Header().Set(echo.HeaderContentType, spreadsheet.ContentType(format))
+			res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+			res.WriteHeader(http.StatusOK)
+
+			w, err := spreadsheet.NewWriter(res, format)
+			if err != nil {
+				return err
+			}
+			writer = w
+			return writer.Write(model.ProductFileColumns)
+		}
+
+		err := s.productUsecase.Export(ctx, requester, func(product *model.Product) error {
+			if writer == nil {
+				if err := startResponse(); err != nil {
+					return err
+				}
+			}
+			return writer.Write(product.ToProductFileRecord())
+		})
+		switch {
+		case err == nil:
+			break
+		case writer != nil:
+			// the rows are already sent, the file is left incomplete
+			logger.Error(err)
+			return nil
+		case err == usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		if writer == nil {
+			if err := startResponse(); err != nil {
+				logger.Error(err)
+				return nil
+			}
+		}
+
+		if err := writer.Close(); err != nil {
+			logger.Error(err)
+		}
+
+		return nil
+	}
+}
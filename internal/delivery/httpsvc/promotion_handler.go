@@ -0,0 +1,232 @@
+package httpsvc
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// Endpoint CreatePromotion
+//
+//	@Summary	Store a promotion
+//	@Description
+//	@Tags		Promotion
+//	@Accept		json
+//	@Produce	json
+//	@Param		Accept			header		string						false	"Example: application/json"
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Content-Type	header		string						false	"Example: application/json"
+//	@Param		Body			body		model.CreatePromotionInput	true	"payload"
+//	@Success	201				{object}	model.PromotionResponse
+//	@Router		/promotions [post]
+func (s *Service) handleCreatePromotion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CreatePromotionInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		promotion, err := s.promotionUsecase.Create(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case model.ErrInvalidPromotionPeriod:
+			return ErrInvalidPromotionPeriod
+		case model.ErrInvalidPromotionRule:
+			return ErrInvalidPromotionRule
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(promotion.ToPromotionResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Promotions
+//
+//	@Summary	Endpoint for get list pagination of promotions
+//	@Description
+//	@Tags		Promotion
+//	@Accept		json
+//	@Produce	json
+//	@Param		Accept			header		string							false	"Example: application/json"
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		Content-Type	header		string							false	"Example: application/json"
+//	@Param		request			query		model.PromotionSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.PromotionResponse]{items=[]model.PromotionResponse}
+//	@Router		/promotions [get]
+func (s *Service) handleGetListPaginationPromotions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.PromotionSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		promotions, count, err := s.promotionUsecase.Search(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, promotions.ToListPromotionResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Get Detail Promotion By ID
+//
+//	@Summary	Endpoint for get detail promotion by id
+//	@Description
+//	@Tags		Promotion
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Accept			header		string	false	"Example: application/json"
+//	@Param		Content-Type	header		string	false	"Example: application/json"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.PromotionResponse
+//	@Router		/promotions/{id} [get]
+func (s *Service) handleGetDetailPromotionByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		promotion, err := s.promotionUsecase.FindByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(promotion.ToPromotionResponse()))
+	}
+}
+
+// Endpoint Update Promotion By ID
+//
+//	@Summary	Endpoint for update promotion by ID
+//	@Description
+//	@Tags		Promotion
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Accept			header		string						false	"Example: application/json"
+//	@Param		Content-Type	header		string						false	"Example: application/json"
+//	@Param		id				path		int							true	"Example: 1"
+//	@Param		Body			body		model.UpdatePromotionInput	true	"payload"
+//	@Success	200				{object}	model.PromotionResponse
+//	@Router		/promotions/{id} [put]
+func (s *Service) handleUpdatePromotionByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.UpdatePromotionInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		promotion, err := s.promotionUsecase.UpdateByID(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case model.ErrInvalidPromotionPeriod:
+			return ErrInvalidPromotionPeriod
+		case model.ErrInvalidPromotionRule:
+			return ErrInvalidPromotionRule
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(promotion.ToPromotionResponse()))
+	}
+}
+
+// Endpoint Delete Promotion By ID
+//
+//	@Summary	Endpoint for delete promotion by ID
+//	@Description
+//	@Tags		Promotion
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Accept			header		string	false	"Example: application/json"
+//	@Param		Content-Type	header		string	false	"Example: application/json"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	successResponse
+//	@Router		/promotions/{id} [delete]
+func (s *Service) handleDeletePromotionByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		err := s.promotionUsecase.DeleteByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			return c.JSON(http.StatusOK, setSuccessResponse(nil))
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+	}
+}
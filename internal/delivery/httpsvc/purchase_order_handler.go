@@ -0,0 +1,327 @@
+package httpsvc
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// Endpoint Create Purchase Order
+//
+//	@Summary	Store a draft purchase order, the items can be changed until it is sent
+//	@Description
+//	@Tags		Purchase Order
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.CreatePurchaseOrderInput	true	"payload"
+//	@Success	201				{object}	model.PurchaseOrderResponse
+//	@Router		/purchase-orders [post]
+func (s *Service) handleCreatePurchaseOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CreatePurchaseOrderInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		order, err := s.purchaseOrderUsecase.Create(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrUnknownSupplier:
+			return ErrUnknownSupplier
+		case model.ErrDuplicateProductVariant:
+			return ErrDuplicateProductVariant
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrProductVariantRequired:
+			return ErrProductVariantRequired
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(order.ToPurchaseOrderResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Purchase Orders
+//
+//	@Summary	Endpoint for get list pagination of purchase orders, the newest first
+//	@Description
+//	@Tags		Purchase Order
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string								true	"Use Token: Bearer {token}"
+//	@Param		request			query		model.PurchaseOrderSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.PurchaseOrderResponse]{items=[]model.PurchaseOrderResponse}
+//	@Router		/purchase-orders [get]
+func (s *Service) handleGetListPaginationPurchaseOrders() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.PurchaseOrderSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		orders, count, err := s.purchaseOrderUsecase.Search(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, orders.ToListPurchaseOrderResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Get Detail Purchase Order By ID
+//
+//	@Summary	Endpoint for get detail purchase order by id along with its items & goods receipts
+//	@Description
+//	@Tags		Purchase Order
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.PurchaseOrderResponse
+//	@Router		/purchase-orders/{id} [get]
+func (s *Service) handleGetDetailPurchaseOrderByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		order, err := s.purchaseOrderUsecase.FindByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(order.ToPurchaseOrderResponse()))
+	}
+}
+
+// Endpoint Update Purchase Order By ID
+//
+//	@Summary	Endpoint for update a draft purchase order by ID, the items are replaced
+//	@Description
+//	@Tags		Purchase Order
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		id				path		int								true	"Example: 1"
+//	@Param		Body			body		model.UpdatePurchaseOrderInput	true	"payload"
+//	@Success	200				{object}	model.PurchaseOrderResponse
+//	@Router		/purchase-orders/{id} [put]
+func (s *Service) handleUpdatePurchaseOrderByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.UpdatePurchaseOrderInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		order, err := s.purchaseOrderUsecase.UpdateByID(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrInvalidPurchaseOrderStatus:
+			return ErrInvalidPurchaseOrderStatus
+		case model.ErrUnknownSupplier:
+			return ErrUnknownSupplier
+		case model.ErrDuplicateProductVariant:
+			return ErrDuplicateProductVariant
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrProductVariantRequired:
+			return ErrProductVariantRequired
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(order.ToPurchaseOrderResponse()))
+	}
+}
+
+// Endpoint Send Purchase Order
+//
+//	@Summary	Mark the draft purchase order as sent to the supplier, its goods can be received afterwards
+//	@Description
+//	@Tags		Purchase Order
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.PurchaseOrderResponse
+//	@Router		/purchase-orders/{id}/send [post]
+func (s *Service) handleSendPurchaseOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		order, err := s.purchaseOrderUsecase.Send(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrInvalidPurchaseOrderStatus:
+			return ErrInvalidPurchaseOrderStatus
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(order.ToPurchaseOrderResponse()))
+	}
+}
+
+// Endpoint Receive Purchase Order
+//
+//	@Summary	Record a goods receipt of the purchase order, the received goods are added to the stock and the cost price of the products is averaged
+//	@Description
+//	@Tags		Purchase Order
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		id				path		int								true	"Example: 1"
+//	@Param		Body			body		model.ReceivePurchaseOrderInput	true	"payload"
+//	@Success	200				{object}	model.PurchaseOrderResponse
+//	@Router		/purchase-orders/{id}/receive [post]
+func (s *Service) handleReceivePurchaseOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.ReceivePurchaseOrderInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		order, err := s.purchaseOrderUsecase.Receive(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrInvalidPurchaseOrderStatus:
+			return ErrInvalidPurchaseOrderStatus
+		case model.ErrDuplicateProductVariant:
+			return ErrDuplicateProductVariant
+		case model.ErrUnknownPurchaseOrderItem:
+			return ErrUnknownPurchaseOrderItem
+		case model.ErrPurchaseOrderOverReceived:
+			return ErrPurchaseOrderOverReceived
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(order.ToPurchaseOrderResponse()))
+	}
+}
+
+// Endpoint Close Purchase Order
+//
+//	@Summary	Close a sent purchase order, the items which are not received yet are given up
+//	@Description
+//	@Tags		Purchase Order
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.PurchaseOrderResponse
+//	@Router		/purchase-orders/{id}/close [post]
+func (s *Service) handleClosePurchaseOrder() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		order, err := s.purchaseOrderUsecase.Close(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrInvalidPurchaseOrderStatus:
+			return ErrInvalidPurchaseOrderStatus
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(order.ToPurchaseOrderResponse()))
+	}
+}
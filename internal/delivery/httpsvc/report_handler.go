@@ -0,0 +1,272 @@
+package httpsvc
+
+import (
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/report"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+// Endpoint Get X Report
+//
+//	@Summary	Endpoint for get the mid-day X report, it covers the sales since the last Z report and does not close the period
+//	@Description
+//	@Tags		Report
+//	@Accept		json
+//	@Produce	json
+//	@Produce	text/csv
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		format			query		string	false	"JSON or CSV, default to JSON"
+//	@Success	200				{object}	model.SalesReportResponse
+//	@Router		/reports/x [get]
+func (s *Service) handleGetXReport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		format, err := parseReportFormat(c.QueryParam("format"))
+		if err != nil {
+			return ErrUnknownReportFormat
+		}
+
+		salesReport, err := s.reportUsecase.GenerateXReport(ctx, requester)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return writeSalesReport(c, http.StatusOK, format, salesReport)
+	}
+}
+
+// Endpoint Create Z Report
+//
+//	@Summary	Endpoint for close the period since the last Z report and store it as the next numbered Z report
+//	@Description
+//	@Tags		Report
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Success	201				{object}	model.SalesReportResponse
+//	@Router		/reports/z [post]
+func (s *Service) handleCreateZReport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		salesReport, err := s.reportUsecase.CreateZReport(ctx, requester)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(salesReport.ToSalesReportResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Z Reports
+//
+//	@Summary	Endpoint for get list pagination of Z reports ordered by the newest number
+//	@Description
+//	@Tags		Report
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		request			query		model.ZReportSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.SalesReportResponse]{items=[]model.SalesReportResponse}
+//	@Router		/reports/z [get]
+func (s *Service) handleGetListPaginationZReports() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.ZReportSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		reports, count, err := s.reportUsecase.SearchZReports(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, reports.ToListSalesReportResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Get Detail Z Report By ID
+//
+//	@Summary	Endpoint for get detail Z report by id
+//	@Description
+//	@Tags		Report
+//	@Accept		json
+//	@Produce	json
+//	@Produce	text/csv
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Param		format			query		string	false	"JSON or CSV, default to JSON"
+//	@Success	200				{object}	model.SalesReportResponse
+//	@Router		/reports/z/{id} [get]
+func (s *Service) handleGetDetailZReportByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		format, err := parseReportFormat(c.QueryParam("format"))
+		if err != nil {
+			return ErrUnknownReportFormat
+		}
+
+		salesReport, err := s.reportUsecase.FindZReportByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return writeSalesReport(c, http.StatusOK, format, salesReport)
+	}
+}
+
+// Endpoint Get Profit Report
+//
+//	@Summary	Endpoint for get the gross profit & margin of the sales made within the date range, grouped by product, category, cashier or day
+//	@Description
+//	@Tags		Report
+//	@Accept		json
+//	@Produce	json
+//	@Produce	text/csv
+//	@Param		Authorization	header		string						true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		request			query		model.ProfitReportCriteria	false	"Query Params"
+//	@Param		format			query		string						false	"JSON or CSV, default to JSON"
+//	@Success	200				{object}	model.ProfitReportResponse
+//	@Router		/reports/profit [get]
+func (s *Service) handleGetProfitReport() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		format, err := parseReportFormat(c.QueryParam("format"))
+		if err != nil {
+			return ErrUnknownReportFormat
+		}
+
+		var criteria model.ProfitReportCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		profitReport, err := s.reportUsecase.GenerateProfitReport(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrUnknownProfitReportGroup:
+			return ErrUnknownProfitReportGroup
+		case model.ErrInvalidDateRange:
+			return ErrInvalidDateRange
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		if format != model.ReportFormatCSV {
+			return c.JSON(http.StatusOK, setSuccessResponse(profitReport.ToProfitReportResponse()))
+		}
+
+		content, err := report.ProfitCSV(profitReport)
+		if err != nil {
+			logger.Error(err)
+			return ErrInternal
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", report.ProfitCSVFilename(profitReport)))
+		return c.Blob(http.StatusOK, report.CSVContentType, content)
+	}
+}
+
+// parseReportFormat parse the report format query param, default to JSON
+func parseReportFormat(value string) (model.ReportFormat, error) {
+	if value == "" {
+		return model.ReportFormatJSON, nil
+	}
+
+	format := model.ReportFormat(strings.ToUpper(value))
+	if !format.IsValid() {
+		return "", model.ErrUnknownReportFormat
+	}
+
+	return format, nil
+}
+
+// writeSalesReport write the sales report as JSON response or as CSV attachment
+func writeSalesReport(c echo.Context, code int, format model.ReportFormat, salesReport *model.SalesReport) error {
+	if format != model.ReportFormatCSV {
+		return c.JSON(code, setSuccessResponse(salesReport.ToSalesReportResponse()))
+	}
+
+	content, err := report.CSV(salesReport)
+	if err != nil {
+		logrus.Error(err)
+		return ErrInternal
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", report.CSVFilename(salesReport)))
+	return c.Blob(code, report.CSVContentType, content)
+}
@@ -8,13 +8,21 @@ import (
 
 // Service http service
 type Service struct {
-	echo               *echo.Group
-	authUsecase        model.AuthUsecase
-	userUsecase        model.UserUsecase
-	appClientUsecase   model.AppClientUsecase
-	productUsecase     model.ProductUsecase
-	transactionUsecase model.TransactionUsecase
-	httpMiddleware     *auth.AuthenticationMiddleware
+	echo                 *echo.Group
+	authUsecase          model.AuthUsecase
+	userUsecase          model.UserUsecase
+	appClientUsecase     model.AppClientUsecase
+	productUsecase       model.ProductUsecase
+	transactionUsecase   model.TransactionUsecase
+	promotionUsecase     model.PromotionUsecase
+	shiftUsecase         model.ShiftUsecase
+	reportUsecase        model.ReportUsecase
+	categoryUsecase      model.CategoryUsecase
+	stockMovementUsecase model.StockMovementUsecase
+	stockOpnameUsecase   model.StockOpnameUsecase
+	supplierUsecase      model.SupplierUsecase
+	purchaseOrderUsecase model.PurchaseOrderUsecase
+	httpMiddleware       *auth.AuthenticationMiddleware
 }
 
 // RouteService add dependencies and use group for routing
@@ -25,16 +33,32 @@ func RouteService(
 	appClientUsecase model.AppClientUsecase,
 	productUsecase model.ProductUsecase,
 	transactionUsecase model.TransactionUsecase,
+	promotionUsecase model.PromotionUsecase,
+	shiftUsecase model.ShiftUsecase,
+	reportUsecase model.ReportUsecase,
+	categoryUsecase model.CategoryUsecase,
+	stockMovementUsecase model.StockMovementUsecase,
+	stockOpnameUsecase model.StockOpnameUsecase,
+	supplierUsecase model.SupplierUsecase,
+	purchaseOrderUsecase model.PurchaseOrderUsecase,
 	authMiddleware *auth.AuthenticationMiddleware,
 ) {
 	srv := &Service{
-		echo:               echo,
-		authUsecase:        authUsecase,
-		userUsecase:        userUsecase,
-		appClientUsecase:   appClientUsecase,
-		productUsecase:     productUsecase,
-		transactionUsecase: transactionUsecase,
-		httpMiddleware:     authMiddleware,
+		echo:                 echo,
+		authUsecase:          authUsecase,
+		userUsecase:          userUsecase,
+		appClientUsecase:     appClientUsecase,
+		productUsecase:       productUsecase,
+		transactionUsecase:   transactionUsecase,
+		promotionUsecase:     promotionUsecase,
+		shiftUsecase:         shiftUsecase,
+		reportUsecase:        reportUsecase,
+		categoryUsecase:      categoryUsecase,
+		stockMovementUsecase: stockMovementUsecase,
+		stockOpnameUsecase:   stockOpnameUsecase,
+		supplierUsecase:      supplierUsecase,
+		purchaseOrderUsecase: purchaseOrderUsecase,
+		httpMiddleware:       authMiddleware,
 	}
 
 	srv.initRoutes()
@@ -45,18 +69,43 @@ func (s *Service) initRoutes() {
 	authRoute := s.echo.Group("/auth")
 	{
 		authRoute.POST("/login/", s.handleLoginByEmailPassword())
+		authRoute.POST("/login/pin/", s.handleLoginByPIN())
+		authRoute.POST("/switch-operator/", s.handleSwitchOperator(), s.httpMiddleware.MustAuthenticateAccessToken())
 		authRoute.POST("/refresh/", s.handleRefreshToken())
 		authRoute.POST("/logout/", s.handleLogout(), s.httpMiddleware.MustAuthenticateAccessToken())
+		authRoute.POST("/password/forgot/", s.handleForgotPassword())
+		authRoute.POST("/password/reset/", s.handleResetPassword())
+		authRoute.POST("/2fa/enroll/", s.handleEnrollTwoFactorByChallenge())
+		authRoute.POST("/2fa/verify/", s.handleVerifyTwoFactor())
 	}
 
 	userRoute := s.echo.Group("/user")
 	{
+		userRoute.GET("/", s.handleGetListPaginationUsers(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.POST("/", s.handleCreateUser(), s.httpMiddleware.MustAuthenticateAccessToken())
 		userRoute.GET("/me/", s.handleGetCurrentLoginUser(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.PUT("/me/password/", s.handleChangePassword(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.PUT("/me/pin/", s.handleChangePIN(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.POST("/me/2fa/", s.handleEnrollTwoFactor(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.POST("/me/2fa/confirm/", s.handleConfirmTwoFactor(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.POST("/me/2fa/disable/", s.handleDisableTwoFactor(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.POST("/me/2fa/recovery-codes/", s.handleRegenerateRecoveryCodes(), s.httpMiddleware.MustAuthenticateAccessToken())
 		userRoute.GET("/:userID/", s.handleGetUserByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.PUT("/:userID/role/", s.handleChangeUserRole(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.POST("/:userID/activate/", s.handleActivateUser(), s.httpMiddleware.MustAuthenticateAccessToken())
+		userRoute.POST("/:userID/deactivate/", s.handleDeactivateUser(), s.httpMiddleware.MustAuthenticateAccessToken())
 	}
 
 	productRoute := s.echo.Group("/products")
 	{
+		productRoute.POST("/labels/", s.handlePrintProductLabels(), s.httpMiddleware.MustAuthenticateAccessToken())
+		productRoute.POST("/import/", s.handleImportProducts(), s.httpMiddleware.MustAuthenticateAccessToken())
+		productRoute.GET("/export/", s.handleExportProducts(), s.httpMiddleware.MustAuthenticateAccessToken())
+		productRoute.GET("/low-stock/", s.handleGetListPaginationLowStockProducts(), s.httpMiddleware.MustAuthenticateAccessToken())
+		productRoute.GET("/barcode/:code/", s.handleGetProductByBarcode(), s.httpMiddleware.MustAuthenticateAccessToken())
+		productRoute.GET("/:id/stock-card/", s.handleGetProductStockCard(), s.httpMiddleware.MustAuthenticateAccessToken())
+		productRoute.POST("/:id/stock-movements/", s.handleCreateProductStockMovement(), s.httpMiddleware.MustAuthenticateAccessToken())
+		productRoute.PUT("/:id/cost-price/", s.handleUpdateProductCostPriceByID(), s.httpMiddleware.MustAuthenticateAccessToken())
 		productRoute.GET("/:id/", s.handleGetDetailProductByID(), s.httpMiddleware.MustAuthenticateAccessToken())
 		productRoute.PUT("/:id/", s.handleUpdateProductByID(), s.httpMiddleware.MustAuthenticateAccessToken())
 		productRoute.DELETE("/:id/", s.handleDeleteProductByID(), s.httpMiddleware.MustAuthenticateAccessToken())
@@ -66,6 +115,77 @@ func (s *Service) initRoutes() {
 
 	transactionRoute := s.echo.Group("/transactions")
 	{
+		transactionRoute.GET("/:id/", s.handleGetDetailTransactionByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		transactionRoute.GET("/", s.handleGetListPaginationTransactions(), s.httpMiddleware.MustAuthenticateAccessToken())
 		transactionRoute.POST("/", s.handleCreateTransaction(), s.httpMiddleware.MustAuthenticateAccessToken())
+		transactionRoute.POST("/:id/void/", s.handleVoidTransaction(), s.httpMiddleware.MustAuthenticateAccessToken())
+		transactionRoute.POST("/:id/refunds/", s.handleRefundTransaction(), s.httpMiddleware.MustAuthenticateAccessToken())
+		transactionRoute.GET("/:id/receipt/", s.handlePrintTransactionReceipt(), s.httpMiddleware.MustAuthenticateAccessToken())
+	}
+
+	promotionRoute := s.echo.Group("/promotions")
+	{
+		promotionRoute.GET("/:id/", s.handleGetDetailPromotionByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		promotionRoute.PUT("/:id/", s.handleUpdatePromotionByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		promotionRoute.DELETE("/:id/", s.handleDeletePromotionByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		promotionRoute.GET("/", s.handleGetListPaginationPromotions(), s.httpMiddleware.MustAuthenticateAccessToken())
+		promotionRoute.POST("/", s.handleCreatePromotion(), s.httpMiddleware.MustAuthenticateAccessToken())
+	}
+
+	shiftRoute := s.echo.Group("/shifts")
+	{
+		shiftRoute.GET("/current/", s.handleGetCurrentShift(), s.httpMiddleware.MustAuthenticateAccessToken())
+		shiftRoute.POST("/open/", s.handleOpenShift(), s.httpMiddleware.MustAuthenticateAccessToken())
+		shiftRoute.GET("/:id/", s.handleGetDetailShiftByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		shiftRoute.POST("/:id/cash-movements/", s.handleAddShiftCashMovement(), s.httpMiddleware.MustAuthenticateAccessToken())
+		shiftRoute.POST("/:id/close/", s.handleCloseShift(), s.httpMiddleware.MustAuthenticateAccessToken())
+		shiftRoute.GET("/", s.handleGetListPaginationShifts(), s.httpMiddleware.MustAuthenticateAccessToken())
+	}
+
+	stockOpnameRoute := s.echo.Group("/stock-opnames")
+	{
+		stockOpnameRoute.GET("/:id/", s.handleGetDetailStockOpnameByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		stockOpnameRoute.POST("/:id/counts/", s.handleSubmitStockOpnameCounts(), s.httpMiddleware.MustAuthenticateAccessToken())
+		stockOpnameRoute.POST("/:id/approve/", s.handleApproveStockOpname(), s.httpMiddleware.MustAuthenticateAccessToken())
+		stockOpnameRoute.GET("/", s.handleGetListPaginationStockOpnames(), s.httpMiddleware.MustAuthenticateAccessToken())
+		stockOpnameRoute.POST("/", s.handleStartStockOpname(), s.httpMiddleware.MustAuthenticateAccessToken())
+	}
+
+	supplierRoute := s.echo.Group("/suppliers")
+	{
+		supplierRoute.GET("/:id/", s.handleGetDetailSupplierByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		supplierRoute.PUT("/:id/", s.handleUpdateSupplierByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		supplierRoute.DELETE("/:id/", s.handleDeleteSupplierByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		supplierRoute.GET("/", s.handleGetListPaginationSuppliers(), s.httpMiddleware.MustAuthenticateAccessToken())
+		supplierRoute.POST("/", s.handleCreateSupplier(), s.httpMiddleware.MustAuthenticateAccessToken())
+	}
+
+	purchaseOrderRoute := s.echo.Group("/purchase-orders")
+	{
+		purchaseOrderRoute.GET("/:id/", s.handleGetDetailPurchaseOrderByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		purchaseOrderRoute.PUT("/:id/", s.handleUpdatePurchaseOrderByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		purchaseOrderRoute.POST("/:id/send/", s.handleSendPurchaseOrder(), s.httpMiddleware.MustAuthenticateAccessToken())
+		purchaseOrderRoute.POST("/:id/receive/", s.handleReceivePurchaseOrder(), s.httpMiddleware.MustAuthenticateAccessToken())
+		purchaseOrderRoute.POST("/:id/close/", s.handleClosePurchaseOrder(), s.httpMiddleware.MustAuthenticateAccessToken())
+		purchaseOrderRoute.GET("/", s.handleGetListPaginationPurchaseOrders(), s.httpMiddleware.MustAuthenticateAccessToken())
+		purchaseOrderRoute.POST("/", s.handleCreatePurchaseOrder(), s.httpMiddleware.MustAuthenticateAccessToken())
+	}
+
+	categoryRoute := s.echo.Group("/categories")
+	{
+		categoryRoute.GET("/:id/", s.handleGetDetailCategoryByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		categoryRoute.PUT("/:id/", s.handleUpdateCategoryByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		categoryRoute.DELETE("/:id/", s.handleDeleteCategoryByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		categoryRoute.GET("/", s.handleGetCategoryTree(), s.httpMiddleware.MustAuthenticateAccessToken())
+		categoryRoute.POST("/", s.handleCreateCategory(), s.httpMiddleware.MustAuthenticateAccessToken())
+	}
+
+	reportRoute := s.echo.Group("/reports")
+	{
+		reportRoute.GET("/profit/", s.handleGetProfitReport(), s.httpMiddleware.MustAuthenticateAccessToken())
+		reportRoute.GET("/x/", s.handleGetXReport(), s.httpMiddleware.MustAuthenticateAccessToken())
+		reportRoute.POST("/z/", s.handleCreateZReport(), s.httpMiddleware.MustAuthenticateAccessToken())
+		reportRoute.GET("/z/:id/", s.handleGetDetailZReportByID(), s.httpMiddleware.MustAuthenticateAccessToken())
+		reportRoute.GET("/z/", s.handleGetListPaginationZReports(), s.httpMiddleware.MustAuthenticateAccessToken())
 	}
 }
@@ -0,0 +1,267 @@
+package httpsvc
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// Endpoint Open Shift
+//
+//	@Summary	Open a cashier shift with the opening float
+//	@Description
+//	@Tags		Shift
+//	@Accept		json
+//	@Produce	json
+//	@Param		Accept			header		string					false	"Example: application/json"
+//	@Param		Authorization	header		string					true	"Use Token: Bearer {token}"
+//	@Param		Content-Type	header		string					false	"Example: application/json"
+//	@Param		Body			body		model.OpenShiftInput	true	"payload"
+//	@Success	201				{object}	model.ShiftResponse
+//	@Router		/shifts/open [post]
+func (s *Service) handleOpenShift() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.OpenShiftInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		shift, err := s.shiftUsecase.Open(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrShiftAlreadyOpen:
+			return ErrShiftAlreadyOpen
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(shift.ToShiftResponse()))
+	}
+}
+
+// Endpoint Get Current Shift
+//
+//	@Summary	Endpoint for get the open shift of the current login cashier
+//	@Description
+//	@Tags		Shift
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Success	200				{object}	model.ShiftResponse
+//	@Router		/shifts/current [get]
+func (s *Service) handleGetCurrentShift() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		shift, err := s.shiftUsecase.FindCurrent(ctx, requester)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(shift.ToShiftResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Shifts
+//
+//	@Summary	Endpoint for get list pagination of shifts
+//	@Description
+//	@Tags		Shift
+//	@Accept		json
+//	@Produce	json
+//	@Param		Accept			header		string						false	"Example: application/json"
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Content-Type	header		string						false	"Example: application/json"
+//	@Param		request			query		model.ShiftSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.ShiftResponse]{items=[]model.ShiftResponse}
+//	@Router		/shifts [get]
+func (s *Service) handleGetListPaginationShifts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.ShiftSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		shifts, count, err := s.shiftUsecase.Search(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, shifts.ToListShiftResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Get Detail Shift By ID
+//
+//	@Summary	Endpoint for get detail shift by id along with its cash movements
+//	@Description
+//	@Tags		Shift
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.ShiftResponse
+//	@Router		/shifts/{id} [get]
+func (s *Service) handleGetDetailShiftByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		shift, err := s.shiftUsecase.FindByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(shift.ToShiftResponse()))
+	}
+}
+
+// Endpoint Add Shift Cash Movement
+//
+//	@Summary	Record a cash in or cash out of an open shift, e.g. petty cash or a safe drop
+//	@Description
+//	@Tags		Shift
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string					true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int						true	"Example: 1"
+//	@Param		Body			body		model.CashMovementInput	true	"payload"
+//	@Success	201				{object}	model.CashMovementResponse
+//	@Router		/shifts/{id}/cash-movements [post]
+func (s *Service) handleAddShiftCashMovement() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CashMovementInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		movement, err := s.shiftUsecase.AddCashMovement(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrShiftClosed:
+			return ErrShiftClosed
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(movement.ToCashMovementResponse()))
+	}
+}
+
+// Endpoint Close Shift
+//
+//	@Summary	Close an open shift with the counted closing cash and record the variance
+//	@Description
+//	@Tags		Shift
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string					true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int						true	"Example: 1"
+//	@Param		Body			body		model.CloseShiftInput	true	"payload"
+//	@Success	200				{object}	model.ShiftResponse
+//	@Router		/shifts/{id}/close [post]
+func (s *Service) handleCloseShift() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CloseShiftInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		shift, err := s.shiftUsecase.Close(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrShiftClosed:
+			return ErrShiftClosed
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(shift.ToShiftResponse()))
+	}
+}
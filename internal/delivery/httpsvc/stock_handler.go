@@ -0,0 +1,156 @@
+package httpsvc
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// Endpoint Get Stock Card of Product
+//
+//	@Summary	Endpoint for get the stock movements of a product over a period along with the running balance
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		id				path		int							true	"Example: 1"
+//	@Param		request			query		model.StockCardCriteria		false	"Query Params, the period default to the current month"
+//	@Success	200				{object}	model.StockCardResponse
+//	@Router		/products/{id}/stock-card [get]
+func (s *Service) handleGetProductStockCard() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.StockCardCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+		criteria.ProductID = utils.StringToInt64(c.Param("id"))
+
+		card, err := s.stockMovementUsecase.FindStockCard(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrInvalidDateRange:
+			return ErrInvalidDateRange
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(card.ToStockCardResponse()))
+	}
+}
+
+// Endpoint Create Stock Movement of Product
+//
+//	@Summary	Endpoint for post a waste or transfer of a product stock
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		id				path		int								true	"Example: 1"
+//	@Param		Body			body		model.CreateStockMovementInput	true	"payload"
+//	@Success	201				{object}	model.StockMovementResponse
+//	@Router		/products/{id}/stock-movements [post]
+func (s *Service) handleCreateProductStockMovement() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CreateStockMovementInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		movement, err := s.stockMovementUsecase.Create(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrInvalidStockMovement:
+			return ErrInvalidStockMovement
+		case model.ErrInsufficientStock:
+			return ErrInsufficientStock
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrProductVariantRequired:
+			return ErrProductVariantRequired
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(movement.ToStockMovementResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Low Stock Products
+//
+//	@Summary	Endpoint for get the products which stock has reached their minimum stock level, the lowest first
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		request			query		model.LowStockSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.ProductResponse]{items=[]model.ProductResponse}
+//	@Router		/products/low-stock [get]
+func (s *Service) handleGetListPaginationLowStockProducts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.LowStockSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		products, count, err := s.productUsecase.SearchLowStock(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, products.ToListProductResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
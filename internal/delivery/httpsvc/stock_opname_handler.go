@@ -0,0 +1,228 @@
+package httpsvc
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// Endpoint Start Stock Opname
+//
+//	@Summary	Start a stock opname, the products can be counted until it is approved
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.StartStockOpnameInput	true	"payload"
+//	@Success	201				{object}	model.StockOpnameResponse
+//	@Router		/stock-opnames [post]
+func (s *Service) handleStartStockOpname() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.StartStockOpnameInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		opname, err := s.stockOpnameUsecase.Start(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(opname.ToStockOpnameResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Stock Opnames
+//
+//	@Summary	Endpoint for get list pagination of stock opnames
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		request			query		model.StockOpnameSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.StockOpnameResponse]{items=[]model.StockOpnameResponse}
+//	@Router		/stock-opnames [get]
+func (s *Service) handleGetListPaginationStockOpnames() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.StockOpnameSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		opnames, count, err := s.stockOpnameUsecase.Search(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, opnames.ToListStockOpnameResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Get Detail Stock Opname By ID
+//
+//	@Summary	Endpoint for get detail stock opname by id along with the variances of the counted products
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.StockOpnameResponse
+//	@Router		/stock-opnames/{id} [get]
+func (s *Service) handleGetDetailStockOpnameByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		opname, err := s.stockOpnameUsecase.FindByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(opname.ToStockOpnameResponse()))
+	}
+}
+
+// Endpoint Submit Stock Opname Counts
+//
+//	@Summary	Submit the counted quantities of the current login user, a recount of the same product replaces the previous count
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string								true	"Use Token: Bearer {token}"
+//	@Param		id				path		int									true	"Example: 1"
+//	@Param		Body			body		model.SubmitStockOpnameCountsInput	true	"payload"
+//	@Success	200				{object}	model.StockOpnameResponse
+//	@Router		/stock-opnames/{id}/counts [post]
+func (s *Service) handleSubmitStockOpnameCounts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.SubmitStockOpnameCountsInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		opname, err := s.stockOpnameUsecase.SubmitCounts(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrStockOpnameNotOpen:
+			return ErrStockOpnameNotOpen
+		case model.ErrDuplicateProductVariant:
+			return ErrDuplicateProductVariant
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrProductVariantRequired:
+			return ErrProductVariantRequired
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(opname.ToStockOpnameResponse()))
+	}
+}
+
+// Endpoint Approve Stock Opname
+//
+//	@Summary	Approve the stock opname, the variances are posted as adjustment stock movements
+//	@Description
+//	@Tags		Stock
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.StockOpnameResponse
+//	@Router		/stock-opnames/{id}/approve [post]
+func (s *Service) handleApproveStockOpname() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		opname, err := s.stockOpnameUsecase.Approve(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrStockOpnameNotOpen:
+			return ErrStockOpnameNotOpen
+		case model.ErrStockOpnameEmpty:
+			return ErrStockOpnameEmpty
+		case model.ErrInsufficientStock:
+			return ErrInsufficientStock
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(opname.ToStockOpnameResponse()))
+	}
+}
@@ -0,0 +1,212 @@
+package httpsvc
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// Endpoint Create Supplier
+//
+//	@Summary	Store a supplier
+//	@Description
+//	@Tags		Supplier
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.CreateSupplierInput	true	"payload"
+//	@Success	201				{object}	model.SupplierResponse
+//	@Router		/suppliers [post]
+func (s *Service) handleCreateSupplier() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CreateSupplierInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		supplier, err := s.supplierUsecase.Create(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(supplier.ToSupplierResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Suppliers
+//
+//	@Summary	Endpoint for get list pagination of suppliers ordered by name
+//	@Description
+//	@Tags		Supplier
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token: Bearer {token}"
+//	@Param		request			query		model.SupplierSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.SupplierResponse]{items=[]model.SupplierResponse}
+//	@Router		/suppliers [get]
+func (s *Service) handleGetListPaginationSuppliers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.SupplierSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		suppliers, count, err := s.supplierUsecase.Search(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, suppliers.ToListSupplierResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Get Detail Supplier By ID
+//
+//	@Summary	Endpoint for get detail supplier by id
+//	@Description
+//	@Tags		Supplier
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.SupplierResponse
+//	@Router		/suppliers/{id} [get]
+func (s *Service) handleGetDetailSupplierByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		supplier, err := s.supplierUsecase.FindByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(supplier.ToSupplierResponse()))
+	}
+}
+
+// Endpoint Update Supplier By ID
+//
+//	@Summary	Endpoint for update supplier by ID
+//	@Description
+//	@Tags		Supplier
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		id				path		int							true	"Example: 1"
+//	@Param		Body			body		model.UpdateSupplierInput	true	"payload"
+//	@Success	200				{object}	model.SupplierResponse
+//	@Router		/suppliers/{id} [put]
+func (s *Service) handleUpdateSupplierByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.UpdateSupplierInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		supplier, err := s.supplierUsecase.UpdateByID(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(supplier.ToSupplierResponse()))
+	}
+}
+
+// Endpoint Delete Supplier By ID
+//
+//	@Summary	Endpoint for delete supplier by ID, the purchase orders of the supplier are kept
+//	@Description
+//	@Tags		Supplier
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	successResponse
+//	@Router		/suppliers/{id} [delete]
+func (s *Service) handleDeleteSupplierByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		err := s.supplierUsecase.DeleteByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			return c.JSON(http.StatusOK, setSuccessResponse(nil))
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package httpsvc
 
 import (
+	"fmt"
 	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
@@ -8,6 +9,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strings"
 )
 
 func (s *Service) handleCreateTransaction() echo.HandlerFunc {
@@ -31,11 +33,266 @@ func (s *Service) handleCreateTransaction() echo.HandlerFunc {
 			break
 		case usecase.ErrAlreadyExist:
 			return ErrProductNameAlreadyExist
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case model.ErrInsufficientStock:
+			return ErrInsufficientStock
+		case model.ErrInsufficientPayment:
+			return ErrInsufficientPayment
+		case model.ErrNonCashOverpayment:
+			return ErrNonCashOverpayment
+		case usecase.ErrManualDiscountExceeded:
+			return ErrManualDiscountExceeded
+		case model.ErrShiftNotOpen:
+			return ErrShiftNotOpen
+		case model.ErrShiftClosed:
+			return ErrShiftClosed
+		case model.ErrUnknownProductVariant:
+			return ErrUnknownProductVariant
+		case model.ErrProductVariantRequired:
+			return ErrProductVariantRequired
 		default:
 			logger.Error(err)
 			return httpValidationOrInternalErr(err)
 		}
 
-		return c.JSON(http.StatusCreated, setSuccessResponse(transaction))
+		return c.JSON(http.StatusCreated, setSuccessResponse(transaction.ToTransactionResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Transactions
+//
+//	@Summary	Endpoint for get list pagination of transactions
+//	@Description
+//	@Tags		Transaction
+//	@Accept		json
+//	@Produce	json
+//	@Param		Accept			header		string								false	"Example: application/json"
+//	@Param		Authorization	header		string								true	"Use Token: Bearer {token}"
+//	@Param		Content-Type	header		string								false	"Example: application/json"
+//	@Param		request			query		model.TransactionSearchCriteria		false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.TransactionResponse]{items=[]model.TransactionResponse}
+//	@Router		/transactions [get]
+func (s *Service) handleGetListPaginationTransactions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.TransactionSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		transactions, count, err := s.transactionUsecase.Search(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrInvalidDateRange:
+			return ErrInvalidDateRange
+		case model.ErrInvalidAmountRange:
+			return ErrInvalidAmountRange
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, transactions.ToListTransactionResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Get Detail Transaction By ID
+//
+//	@Summary	Endpoint for get detail transaction by id
+//	@Description
+//	@Tags		Transaction
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Accept			header		string	false	"Example: application/json"
+//	@Param		Content-Type	header		string	false	"Example: application/json"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Success	200				{object}	model.TransactionResponse
+//	@Router		/transactions/{id} [get]
+func (s *Service) handleGetDetailTransactionByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		transaction, err := s.transactionUsecase.FindByID(ctx, requester, utils.StringToInt64(c.Param("id")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(transaction.ToTransactionResponse()))
+	}
+}
+
+// Endpoint Void Transaction
+//
+//	@Summary	Endpoint for void a transaction and restock all of its products
+//	@Description
+//	@Tags		Transaction
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Accept			header		string						false	"Example: application/json"
+//	@Param		Content-Type	header		string						false	"Example: application/json"
+//	@Param		id				path		int							true	"Example: 1"
+//	@Param		request			body		model.VoidTransactionInput	true	"Request Body"
+//	@Success	201				{object}	model.RefundResponse
+//	@Router		/transactions/{id}/void [post]
+func (s *Service) handleVoidTransaction() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.VoidTransactionInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		refund, err := s.transactionUsecase.Void(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrVoidWindowExpired:
+			return ErrVoidWindowExpired
+		case model.ErrTransactionAlreadyRefunded:
+			return ErrTransactionAlreadyRefunded
+		case model.ErrShiftClosed:
+			return ErrShiftClosed
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(refund.ToRefundResponse()))
+	}
+}
+
+// Endpoint Refund Transaction
+//
+//	@Summary	Endpoint for refund some lines of a transaction and restock the returned products
+//	@Description
+//	@Tags		Transaction
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string							true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		Accept			header		string							false	"Example: application/json"
+//	@Param		Content-Type	header		string							false	"Example: application/json"
+//	@Param		id				path		int								true	"Example: 1"
+//	@Param		request			body		model.RefundTransactionInput	true	"Request Body"
+//	@Success	201				{object}	model.RefundResponse
+//	@Router		/transactions/{id}/refunds [post]
+func (s *Service) handleRefundTransaction() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.RefundTransactionInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		refund, err := s.transactionUsecase.Refund(ctx, requester, utils.StringToInt64(c.Param("id")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case model.ErrRefundQuantityExceeded:
+			return ErrRefundQuantityExceeded
+		case model.ErrShiftClosed:
+			return ErrShiftClosed
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(refund.ToRefundResponse()))
+	}
+}
+
+// Endpoint Print Transaction Receipt
+//
+//	@Summary	Endpoint for print the receipt of a transaction, every print after the first one is audited as reprint
+//	@Description
+//	@Tags		Transaction
+//	@Accept		json
+//	@Produce	plain
+//	@Produce	octet-stream
+//	@Produce	application/pdf
+//	@Param		Authorization	header		string	true	"Use Token from Auth Service : Bearer {token}"
+//	@Param		id				path		int		true	"Example: 1"
+//	@Param		format			query		string	false	"TEXT_58, TEXT_80, ESCPOS_58, ESCPOS_80 or PDF, default to TEXT_80"
+//	@Success	200				{file}		file
+//	@Router		/transactions/{id}/receipt [get]
+func (s *Service) handlePrintTransactionReceipt() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		format := model.ReceiptFormat(strings.ToUpper(c.QueryParam("format")))
+		receipt, err := s.transactionUsecase.PrintReceipt(ctx, requester, utils.StringToInt64(c.Param("id")), format)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrUnknownReceiptFormat:
+			return ErrUnknownReceiptFormat
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", receipt.Filename))
+		return c.Blob(http.StatusOK, receipt.ContentType, receipt.Content)
 	}
 }
@@ -2,6 +2,7 @@ package httpsvc
 
 import (
 	"github.com/irvankadhafi/go-point-of-sales/internal/delivery"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/internal/usecase"
 	"github.com/irvankadhafi/go-point-of-sales/rbac"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
@@ -90,3 +91,417 @@ func (s *Service) handleGetUserByID() echo.HandlerFunc {
 		return c.JSON(http.StatusOK, res)
 	}
 }
+
+// Endpoint Create User
+//
+//	@Summary	Store a user with the given role, the user is active right away
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string					true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.CreateUserInput	true	"payload"
+//	@Success	201				{object}	model.UserResponse
+//	@Router		/user [post]
+func (s *Service) handleCreateUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.CreateUserInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		user, err := s.userUsecase.Create(ctx, requester, req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case usecase.ErrAlreadyExist:
+			return ErrEmailAlreadyExist
+		case model.ErrUnknownRole:
+			return ErrUnknownRole
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusCreated, setSuccessResponse(user.ToUserResponse()))
+	}
+}
+
+// Endpoint Get List Pagination of Users
+//
+//	@Summary	Endpoint for get list pagination of users ordered by name, the query matches the name or the email
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		request			query		model.UserSearchCriteria	false	"Query Params"
+//	@Success	200				{object}	paginationResponse[[]model.UserResponse]{items=[]model.UserResponse}
+//	@Router		/user [get]
+func (s *Service) handleGetListPaginationUsers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		var criteria model.UserSearchCriteria
+		if err := c.Bind(&criteria); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		criteria.SetDefaultValue()
+
+		users, count, err := s.userUsecase.Search(ctx, requester, criteria)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		response := toResourcePaginationResponse(criteria.Page, criteria.Size, count, users.ToListUserResponse())
+		return c.JSON(http.StatusOK, setSuccessResponse(response))
+	}
+}
+
+// Endpoint Change User Role
+//
+//	@Summary	Endpoint for change the role of a user, the user must log in again to use the new role
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		userID			path		int							true	"Example: 1"
+//	@Param		Body			body		model.ChangeUserRoleInput	true	"payload"
+//	@Success	200				{object}	model.UserResponse
+//	@Router		/user/{userID}/role [put]
+func (s *Service) handleChangeUserRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.ChangeUserRoleInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		user, err := s.userUsecase.ChangeRoleByID(ctx, requester, utils.StringToInt64(c.Param("userID")), req)
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrUnknownRole:
+			return ErrUnknownRole
+		case model.ErrUserSelfChange:
+			return ErrUserSelfChange
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(user.ToUserResponse()))
+	}
+}
+
+// Endpoint Activate User
+//
+//	@Summary	Endpoint for activate a user
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		userID			path		int		true	"Example: 1"
+//	@Success	200				{object}	model.UserResponse
+//	@Router		/user/{userID}/activate [post]
+func (s *Service) handleActivateUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		user, err := s.userUsecase.ActivateByID(ctx, requester, utils.StringToInt64(c.Param("userID")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrUserSelfChange:
+			return ErrUserSelfChange
+		default:
+			logger.Error(err)
+			return ErrInternal
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(user.ToUserResponse()))
+	}
+}
+
+// Endpoint Deactivate User
+//
+//	@Summary	Endpoint for deactivate a user, all of the sessions of the user are revoked
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Param		userID			path		int		true	"Example: 1"
+//	@Success	200				{object}	model.UserResponse
+//	@Router		/user/{userID}/deactivate [post]
+func (s *Service) handleDeactivateUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		user, err := s.userUsecase.DeactivateByID(ctx, requester, utils.StringToInt64(c.Param("userID")))
+		switch err {
+		case nil:
+			break
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPermissionDenied:
+			return ErrPermissionDenied
+		case model.ErrUserSelfChange:
+			return ErrUserSelfChange
+		default:
+			logger.Error(err)
+			return ErrInternal
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(user.ToUserResponse()))
+	}
+}
+
+// Endpoint Change Password
+//
+//	@Summary	Endpoint for change the password of the current login user
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.ChangePasswordInput	true	"payload"
+//	@Success	200				{object}	successResponse
+//	@Router		/user/me/password [put]
+func (s *Service) handleChangePassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.ChangePasswordInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		_, err := s.userUsecase.ChangePassword(ctx, requester, req)
+		switch err {
+		case nil:
+			return c.JSON(http.StatusOK, setSuccessResponse(nil))
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPasswordMismatch:
+			return ErrOldPasswordMismatch
+		case model.ErrPasswordMismatch:
+			return ErrPasswordConfirmation
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+	}
+}
+
+// Endpoint Change PIN
+//
+//	@Summary	Endpoint for set the PIN of the current login user, the PIN is used to log in on the POS terminals
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string					true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.ChangePINInput	true	"payload"
+//	@Success	200				{object}	successResponse
+//	@Router		/user/me/pin [put]
+func (s *Service) handleChangePIN() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		logger := logrus.WithFields(logrus.Fields{
+			"ctx": utils.DumpIncomingContext(ctx),
+		})
+
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.ChangePINInput{}
+		if err := c.Bind(&req); err != nil {
+			logger.Error(err)
+			return ErrInvalidArgument
+		}
+
+		err := s.userUsecase.ChangePIN(ctx, requester, req)
+		switch err {
+		case nil:
+			return c.JSON(http.StatusOK, setSuccessResponse(nil))
+		case usecase.ErrNotFound:
+			return ErrNotFound
+		case usecase.ErrPasswordMismatch:
+			return ErrOldPasswordMismatch
+		default:
+			logger.Error(err)
+			return httpValidationOrInternalErr(err)
+		}
+	}
+}
+
+// Endpoint Enroll Two Factor
+//
+//	@Summary	Endpoint for start the two factor enrollment of the current login user, the secret is added to the authenticator app by scanning the QR code
+//	@Description
+//	@Tags		User
+//	@Produce	json
+//	@Param		Authorization	header		string	true	"Use Token: Bearer {token}"
+//	@Success	200				{object}	twoFactorEnrollmentResponse
+//	@Router		/user/me/2fa [post]
+func (s *Service) handleEnrollTwoFactor() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		enrollment, err := s.authUsecase.EnrollTwoFactor(ctx, requester)
+		if err != nil {
+			return twoFactorErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(newTwoFactorEnrollmentResponse(enrollment)))
+	}
+}
+
+// Endpoint Confirm Two Factor
+//
+//	@Summary	Endpoint for enable the two factor of the current login user with a code of the authenticator app, the recovery codes are only shown once
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.TwoFactorCodeInput	true	"payload"
+//	@Success	200				{object}	recoveryCodesResponse
+//	@Router		/user/me/2fa/confirm [post]
+func (s *Service) handleConfirmTwoFactor() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.TwoFactorCodeInput{}
+		if err := c.Bind(&req); err != nil {
+			logrus.Error(err)
+			return ErrInvalidArgument
+		}
+
+		recoveryCodes, err := s.authUsecase.ConfirmTwoFactor(ctx, requester, req)
+		if err != nil {
+			return twoFactorErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(recoveryCodesResponse{RecoveryCodes: recoveryCodes}))
+	}
+}
+
+// Endpoint Disable Two Factor
+//
+//	@Summary	Endpoint for disable the two factor of the current login user, not allowed when the role requires it
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.DisableTwoFactorInput	true	"payload"
+//	@Success	200				{object}	successResponse
+//	@Router		/user/me/2fa/disable [post]
+func (s *Service) handleDisableTwoFactor() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.DisableTwoFactorInput{}
+		if err := c.Bind(&req); err != nil {
+			logrus.Error(err)
+			return ErrInvalidArgument
+		}
+
+		if err := s.authUsecase.DisableTwoFactor(ctx, requester, req); err != nil {
+			return twoFactorErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(nil))
+	}
+}
+
+// Endpoint Regenerate Recovery Codes
+//
+//	@Summary	Endpoint for replace the recovery codes of the current login user, the old codes can no longer be used
+//	@Description
+//	@Tags		User
+//	@Accept		json
+//	@Produce	json
+//	@Param		Authorization	header		string						true	"Use Token: Bearer {token}"
+//	@Param		Body			body		model.TwoFactorCodeInput	true	"payload"
+//	@Success	200				{object}	recoveryCodesResponse
+//	@Router		/user/me/2fa/recovery-codes [post]
+func (s *Service) handleRegenerateRecoveryCodes() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		requester := delivery.GetAuthUserFromCtx(ctx)
+
+		req := model.TwoFactorCodeInput{}
+		if err := c.Bind(&req); err != nil {
+			logrus.Error(err)
+			return ErrInvalidArgument
+		}
+
+		recoveryCodes, err := s.authUsecase.RegenerateRecoveryCodes(ctx, requester, req)
+		if err != nil {
+			return twoFactorErr(err)
+		}
+
+		return c.JSON(http.StatusOK, setSuccessResponse(recoveryCodesResponse{RecoveryCodes: recoveryCodes}))
+	}
+}
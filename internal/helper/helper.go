@@ -1,6 +1,10 @@
 package helper
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"github.com/sirupsen/logrus"
 	"github.com/ttacon/libphonenumber"
@@ -77,3 +81,19 @@ func WrapCloser(close func() error) {
 		logrus.Error(err)
 	}
 }
+
+// GenerateRandomToken generate an url safe random token of n random bytes
+func GenerateRandomToken(n int) (string, error) {
+	bt := make([]byte, n)
+	if _, err := rand.Read(bt); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bt), nil
+}
+
+// HashToken hash the token with sha256, unlike HashString the hash is the same on every call
+// so the token can be looked up by its hash
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,225 @@
+// Package label render the product shelf labels as SVG, PNG or PDF sheets
+package label
+
+import (
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/barcode"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"math"
+)
+
+// Item a single printed label
+type Item struct {
+	Name  string
+	Price int64
+	// Code the barcode or sku of the product, printed as EAN-13 when it is an EAN-13 or UPC-A and as Code128 otherwise
+	Code string
+}
+
+// sheet the paper of a layout in millimeters, the labels are placed on a grid starting from the top left corner
+type sheet struct {
+	width, height         float64
+	labelWidth            float64
+	labelHeight           float64
+	columns, rows         int
+	marginTop, marginLeft float64
+}
+
+var sheets = map[model.LabelLayout]sheet{
+	model.LabelLayoutA4Grid: {
+		width: 210, height: 297,
+		labelWidth: 70, labelHeight: 37,
+		columns: 3, rows: 8,
+		marginTop: 0.5,
+	},
+	model.LabelLayoutRoll40x30: {
+		width: 40, height: 30,
+		labelWidth: 40, labelHeight: 30,
+		columns: 1, rows: 1,
+	},
+}
+
+// the label content in millimeters, the bars are snapped to the dots of a 203 dpi label printer
+const (
+	padding       = 1.5
+	nameSize      = 3
+	priceSize     = 4
+	codeSize      = 2.5
+	lineGap       = 1
+	dotsPerMM     = 8
+	minModuleSize = 1.0 / dotsPerMM
+	maxModuleSize = 3.0 / dotsPerMM
+)
+
+type font int
+
+const (
+	fontSans font = iota
+	fontSansBold
+	fontMono
+)
+
+// rect a filled black rectangle, the position is the top left corner in millimeters
+type rect struct {
+	x, y, width, height float64
+}
+
+// text a single line of ASCII text, the position is the top left corner in millimeters
+// or the top center when centered, size is the font size in millimeters
+type text struct {
+	x, y     float64
+	size     float64
+	font     font
+	centered bool
+	value    string
+}
+
+// page the shapes drawn on a single page of the sheet
+type page struct {
+	rects []rect
+	texts []text
+}
+
+// Render compose the labels on the layout and render the sheets on the given format
+func Render(format model.LabelFormat, layout model.LabelLayout, items []Item) (*model.LabelSheet, error) {
+	s, ok := sheets[layout]
+	if !ok {
+		return nil, model.ErrUnknownLabelLayout
+	}
+
+	pages, err := compose(s, items)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSheet := &model.LabelSheet{
+		Format: format,
+		Layout: layout,
+	}
+
+	switch format {
+	case model.LabelFormatSVG:
+		labelSheet.Content = SVG(s, pages)
+		labelSheet.ContentType = "image/svg+xml"
+		labelSheet.Filename = "labels.svg"
+	case model.LabelFormatPNG:
+		labelSheet.Content, err = PNG(s, pages)
+		labelSheet.ContentType = "image/png"
+		labelSheet.Filename = "labels.png"
+	case model.LabelFormatPDF:
+		labelSheet.Content = PDF(s, pages)
+		labelSheet.ContentType = "application/pdf"
+		labelSheet.Filename = "labels.pdf"
+	default:
+		return nil, model.ErrUnknownLabelFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return labelSheet, nil
+}
+
+// compose place the labels on the pages of the sheet
+func compose(s sheet, items []Item) ([]*page, error) {
+	perPage := s.columns * s.rows
+	var pages []*page
+	for i, item := range items {
+		if i%perPage == 0 {
+			pages = append(pages, &page{})
+		}
+
+		n := i % perPage
+		x := s.marginLeft + float64(n%s.columns)*s.labelWidth
+		y := s.marginTop + float64(n/s.columns)*s.labelHeight
+		if err := pages[len(pages)-1].drawLabel(x, y, s.labelWidth, s.labelHeight, item); err != nil {
+			return nil, fmt.Errorf("label %q: %w", item.Code, err)
+		}
+	}
+
+	return pages, nil
+}
+
+// drawLabel draw the name, the price and the barcode with its code below, from top to bottom
+func (p *page) drawLabel(x, y, width, height float64, item Item) error {
+	bars, _, err := barcode.Encode(item.Code)
+	if err != nil {
+		return err
+	}
+
+	contentWidth := width - 2*padding
+	top := y + padding
+	p.texts = append(p.texts, text{
+		x: x + padding, y: top, size: nameSize, font: fontSans,
+		value: fit(item.Name, contentWidth, nameSize),
+	})
+
+	top += nameSize + lineGap
+	p.texts = append(p.texts, text{
+		x: x + padding, y: top, size: priceSize, font: fontSansBold,
+		value: fit(utils.Int64ToRupiah(item.Price), contentWidth, priceSize),
+	})
+
+	top += priceSize + lineGap
+	bottom := y + height - padding - codeSize - lineGap/2
+	modules := float64(len(bars) + 2*barcode.QuietZone)
+	module := math.Floor(contentWidth/modules*dotsPerMM) / dotsPerMM
+	module = math.Max(minModuleSize, math.Min(module, maxModuleSize))
+
+	left := x + (width-float64(len(bars))*module)/2
+	for i := 0; i < len(bars); {
+		if !bars[i] {
+			i++
+			continue
+		}
+
+		// merge the adjacent dark modules into a single bar
+		j := i
+		for j < len(bars) && bars[j] {
+			j++
+		}
+		p.rects = append(p.rects, rect{
+			x:      left + float64(i)*module,
+			y:      top,
+			width:  float64(j-i) * module,
+			height: bottom - top,
+		})
+		i = j
+	}
+
+	p.texts = append(p.texts, text{
+		x: x + width/2, y: bottom + lineGap/2, size: codeSize, font: fontMono, centered: true,
+		value: fit(item.Code, contentWidth, codeSize),
+	})
+
+	return nil
+}
+
+// fit cut the text so it fits the width, the width is estimated from the widest glyph of the bitmap font
+func fit(value string, width, size float64) string {
+	value = toASCII(value)
+	maxChars := int(width / (glyphAdvance * size))
+	if len(value) <= maxChars {
+		return value
+	}
+	if maxChars <= 3 {
+		return value[:maxChars]
+	}
+
+	return value[:maxChars-3] + "..."
+}
+
+// toASCII replace the characters outside of the printable ASCII, the built-in fonts only cover ASCII
+func toASCII(value string) string {
+	out := make([]byte, 0, len(value))
+	for _, r := range value {
+		if r < 0x20 || r > 0x7e {
+			out = append(out, '?')
+			continue
+		}
+		out = append(out, byte(r))
+	}
+
+	return string(out)
+}
@@ -0,0 +1,75 @@
+package label
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestItems(n int) []Item {
+	items := make([]Item, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, Item{Name: "Pisang Goreng Keju Cokelat Spesial Jumbo", Price: 12500, Code: "8991234567891"})
+	}
+
+	return items
+}
+
+func TestRender(t *testing.T) {
+	t.Run("svg on the A4 grid", func(t *testing.T) {
+		sheet, err := Render(model.LabelFormatSVG, model.LabelLayoutA4Grid, newTestItems(25))
+		require.NoError(t, err)
+		require.Equal(t, "image/svg+xml", sheet.ContentType)
+
+		svg := string(sheet.Content)
+		require.Contains(t, svg, `height="594mm"`) // two pages
+		require.Contains(t, svg, "Rp12.500")
+		require.Contains(t, svg, ">8991234567891</text>")
+		require.Contains(t, svg, "Pisang Goreng Keju Cokelat...")
+	})
+
+	t.Run("pdf on the roll", func(t *testing.T) {
+		sheet, err := Render(model.LabelFormatPDF, model.LabelLayoutRoll40x30, []Item{
+			{Name: "Es Teh (L)", Price: 8000, Code: "ES-TEH-L"},
+			{Name: "Kopi", Price: 15000, Code: "1695599921375543118"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "application/pdf", sheet.ContentType)
+
+		pdf := string(sheet.Content)
+		require.True(t, strings.HasPrefix(pdf, "%PDF-1.4"))
+		require.Contains(t, pdf, "/Count 2")
+		require.Contains(t, pdf, "/MediaBox [0 0 113.39 85.04]")
+		require.Contains(t, pdf, `(Es Teh \(L\)) Tj`)
+		require.Contains(t, pdf, "(Rp15.000) Tj")
+	})
+
+	t.Run("png on the roll", func(t *testing.T) {
+		sheet, err := Render(model.LabelFormatPNG, model.LabelLayoutRoll40x30, newTestItems(3))
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(sheet.Content))
+		require.NoError(t, err)
+		require.Equal(t, 320, img.Bounds().Dx())
+		require.Equal(t, 3*240, img.Bounds().Dy())
+	})
+
+	t.Run("png too large", func(t *testing.T) {
+		_, err := Render(model.LabelFormatPNG, model.LabelLayoutA4Grid, newTestItems(24*6))
+		require.ErrorIs(t, err, model.ErrTooManyLabels)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		_, err := Render(model.LabelFormatSVG, model.LabelLayoutA4Grid, []Item{{Name: "Kopi", Code: "8991234567890"}})
+		require.Error(t, err)
+	})
+
+	t.Run("unknown layout", func(t *testing.T) {
+		_, err := Render(model.LabelFormatSVG, "A5", newTestItems(1))
+		require.ErrorIs(t, err, model.ErrUnknownLabelLayout)
+	})
+}
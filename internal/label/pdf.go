@@ -0,0 +1,93 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// the PDF uses the built-in fonts so no font has to be embedded, Courier glyphs are 0.6 of the font size wide
+const (
+	pointsPerMM      = 72 / 25.4
+	courierAdvance   = 0.6
+	pdfFirstPageObj  = 6
+	pdfObjectsByPage = 2
+)
+
+var pdfFonts = map[font]string{
+	fontSans:     "F1",
+	fontSansBold: "F2",
+	fontMono:     "F3",
+}
+
+// PDF render every page of the sheet as a PDF page
+func PDF(s sheet, pages []*page) []byte {
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", pdfFirstPageObj+i*pdfObjectsByPage))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	}
+
+	width, height := s.width*pointsPerMM, s.height*pointsPerMM
+	for i, p := range pages {
+		content := pdfContent(s, p)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+				width, height, pdfFirstPageObj+i*pdfObjectsByPage+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, 0, len(objects))
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfContent the drawing operators of the page, the PDF origin is the bottom left corner in points
+func pdfContent(s sheet, p *page) string {
+	var content bytes.Buffer
+	content.WriteString("0 g\n")
+	for _, r := range p.rects {
+		fmt.Fprintf(&content, "%.3f %.3f %.3f %.3f re f\n",
+			r.x*pointsPerMM, (s.height-r.y-r.height)*pointsPerMM, r.width*pointsPerMM, r.height*pointsPerMM)
+	}
+
+	for _, t := range p.texts {
+		x := t.x
+		if t.centered {
+			x -= courierAdvance * t.size * float64(len(t.value)) / 2
+		}
+		fmt.Fprintf(&content, "BT /%s %.2f Tf %.3f %.3f Td (%s) Tj ET\n",
+			pdfFonts[t.font], t.size*pointsPerMM, x*pointsPerMM, (s.height-t.y-fontAscent*t.size)*pointsPerMM,
+			escapePDFText(t.value))
+	}
+
+	return content.String()
+}
+
+// escapePDFText escape the PDF string delimiters
+func escapePDFText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+	return replacer.Replace(text)
+}
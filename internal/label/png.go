@@ -0,0 +1,85 @@
+package label
+
+import (
+	"bytes"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+// maxPNGDots the largest PNG rendered, about five A4 pages, a larger print should use the PDF format
+const maxPNGDots = 5 * 210 * 297 * dotsPerMM * dotsPerMM
+
+// PNG rasterize the pages on the dots of a 203 dpi label printer, every page is placed below the previous one
+func PNG(s sheet, pages []*page) ([]byte, error) {
+	width := dots(s.width)
+	pageHeight := dots(s.height)
+	if width*pageHeight*len(pages) > maxPNGDots {
+		return nil, model.ErrTooManyLabels
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, pageHeight*len(pages)))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	for i, p := range pages {
+		top := i * pageHeight
+		for _, r := range p.rects {
+			fill(img, dots(r.x), top+dots(r.y), dots(r.x+r.width), top+dots(r.y+r.height))
+		}
+
+		for _, t := range p.texts {
+			scale := int(math.Max(1, math.Floor(t.size*dotsPerMM/glyphHeight)))
+			x := dots(t.x)
+			if t.centered {
+				x -= (len(t.value)*(glyphWidth+1)*scale - scale) / 2
+			}
+			drawText(img, x, top+dots(t.y), scale, t.font == fontSansBold, t.value)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawText draw the text with the bitmap font enlarged by the scale, the bold text is drawn twice one dot apart
+func drawText(img *image.Gray, x, y, scale int, bold bool, value string) {
+	for i := 0; i < len(value); i++ {
+		columns := glyph(value[i])
+		left := x + i*(glyphWidth+1)*scale
+		for col, bits := range columns {
+			for row := 0; row < glyphHeight; row++ {
+				if bits&(1<<row) == 0 {
+					continue
+				}
+
+				x0, y0 := left+col*scale, y+row*scale
+				fill(img, x0, y0, x0+scale, y0+scale)
+				if bold {
+					fill(img, x0+1, y0, x0+scale+1, y0+scale)
+				}
+			}
+		}
+	}
+}
+
+func fill(img *image.Gray, x0, y0, x1, y1 int) {
+	r := image.Rect(x0, y0, x1, y1).Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetGray(x, y, color.Gray{})
+		}
+	}
+}
+
+// dots convert the millimeters to the printer dots
+func dots(v float64) int {
+	return int(math.Round(v * dotsPerMM))
+}
@@ -0,0 +1,54 @@
+package label
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// fontAscent the height above the baseline relative to the font size, used to place the text by its top
+const fontAscent = 0.8
+
+var svgFonts = map[font]string{
+	fontSans:     `font-family="Helvetica, Arial, sans-serif"`,
+	fontSansBold: `font-family="Helvetica, Arial, sans-serif" font-weight="bold"`,
+	fontMono:     `font-family="Courier, monospace"`,
+}
+
+// SVG render the pages as a single SVG in millimeters, every page is placed below the previous one
+func SVG(s sheet, pages []*page) []byte {
+	var buf bytes.Buffer
+	height := s.height * float64(len(pages))
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%smm" height="%smm" viewBox="0 0 %s %s">`+"\n",
+		mm(s.width), mm(height), mm(s.width), mm(height))
+
+	for i, p := range pages {
+		fmt.Fprintf(&buf, `<g transform="translate(0 %s)">`+"\n", mm(s.height*float64(i)))
+		fmt.Fprintf(&buf, `<rect width="%s" height="%s" fill="#fff"/>`+"\n", mm(s.width), mm(s.height))
+		for _, r := range p.rects {
+			fmt.Fprintf(&buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="#000"/>`+"\n",
+				mm(r.x), mm(r.y), mm(r.width), mm(r.height))
+		}
+		for _, t := range p.texts {
+			anchor := "start"
+			if t.centered {
+				anchor = "middle"
+			}
+			fmt.Fprintf(&buf, `<text x="%s" y="%s" font-size="%s" %s text-anchor="%s">`,
+				mm(t.x), mm(t.y+fontAscent*t.size), mm(t.size), svgFonts[t.font], anchor)
+			_ = xml.EscapeText(&buf, []byte(t.value))
+			buf.WriteString("</text>\n")
+		}
+		buf.WriteString("</g>\n")
+	}
+	buf.WriteString("</svg>\n")
+
+	return buf.Bytes()
+}
+
+// mm format the millimeters with up to three decimals
+func mm(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
@@ -0,0 +1,108 @@
+// Package mailer send the emails through a SMTP server, or to a log or a file on development
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// mailer kinds
+const (
+	KindLog  = "log"
+	KindFile = "file"
+	KindSMTP = "smtp"
+)
+
+// ErrUnknownKind error when the mailer kind is not supported
+var ErrUnknownKind = errors.New("unknown mailer kind")
+
+// Config how the emails are sent, FilePath is only used by the file mailer and SMTP by the smtp mailer
+type Config struct {
+	Kind     string
+	FilePath string
+	SMTP     SMTPConfig
+}
+
+// New create the mailer of the config kind
+func New(cfg Config) (model.Mailer, error) {
+	switch cfg.Kind {
+	case KindLog:
+		return NewLogMailer(), nil
+	case KindFile:
+		return NewFileMailer(cfg.FilePath)
+	case KindSMTP:
+		return NewSMTPMailer(cfg.SMTP)
+	default:
+		return nil, ErrUnknownKind
+	}
+}
+
+type logMailer struct{}
+
+// NewLogMailer create a mailer which writes the emails to the application log, the body is logged as is
+// so it must only be used on development
+func NewLogMailer() model.Mailer {
+	return &logMailer{}
+}
+
+// Send log the email
+func (l *logMailer) Send(_ context.Context, mail *model.Mail) error {
+	logrus.WithFields(logrus.Fields{
+		"to":      mail.To,
+		"subject": mail.Subject,
+	}).Info(mail.Body)
+
+	return nil
+}
+
+// sentMail the email written by the writer mailer
+type sentMail struct {
+	*model.Mail
+	SentAt time.Time `json:"sent_at"`
+}
+
+type writerMailer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterMailer create a mailer which writes every email as a JSON line to w
+func NewWriterMailer(w io.Writer) model.Mailer {
+	return &writerMailer{w: w}
+}
+
+// NewFileMailer create a mailer which appends every email as a JSON line to the file,
+// the file is created when it does not exist
+func NewFileMailer(path string) (model.Mailer, error) {
+	if path == "" {
+		return nil, errors.New("mailer file path is required")
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriterMailer(file), nil
+}
+
+// Send write the email as a JSON line
+func (w *writerMailer) Send(_ context.Context, mail *model.Mail) error {
+	line, err := json.Marshal(sentMail{Mail: mail, SentAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err = w.w.Write(append(line, '\n'))
+	return err
+}
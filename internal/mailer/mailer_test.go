@@ -0,0 +1,91 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+var testMail = &model.Mail{
+	To:      "budi@mail.com",
+	Subject: "Reset your password",
+	Body:    "Open the link below\nhttp://localhost/reset?token=abc",
+}
+
+func TestWriterMailer_Send(t *testing.T) {
+	buf := &bytes.Buffer{}
+	m := NewWriterMailer(buf)
+
+	require.NoError(t, m.Send(context.TODO(), testMail))
+	require.NoError(t, m.Send(context.TODO(), testMail))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	mail := &model.Mail{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), mail))
+	require.Equal(t, testMail, mail)
+}
+
+func TestNew(t *testing.T) {
+	t.Run("file mailer append to the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mails.log")
+		m, err := New(Config{Kind: KindFile, FilePath: path})
+		require.NoError(t, err)
+		require.NoError(t, m.Send(context.TODO(), testMail))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Contains(t, string(content), `"to":"budi@mail.com"`)
+	})
+
+	t.Run("file mailer require the path", func(t *testing.T) {
+		_, err := New(Config{Kind: KindFile})
+		require.Error(t, err)
+	})
+
+	t.Run("smtp mailer require the host and the sender", func(t *testing.T) {
+		_, err := New(Config{Kind: KindSMTP, SMTP: SMTPConfig{Port: 587, From: "pos@mail.com"}})
+		require.Error(t, err)
+
+		_, err = New(Config{Kind: KindSMTP, SMTP: SMTPConfig{Host: "smtp.mail.com", Port: 587}})
+		require.Error(t, err)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := New(Config{Kind: "sms"})
+		require.ErrorIs(t, err, ErrUnknownKind)
+	})
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	m, err := NewSMTPMailer(SMTPConfig{Host: "smtp.mail.com", Port: 587, Username: "pos", Password: "secret", From: "pos@mail.com"})
+	require.NoError(t, err)
+
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  string
+	)
+	m.(*smtpMailer).sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		require.NotNil(t, a)
+		require.Equal(t, "pos@mail.com", from)
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	require.NoError(t, m.Send(context.TODO(), testMail))
+	require.Equal(t, "smtp.mail.com:587", gotAddr)
+	require.Equal(t, []string{"budi@mail.com"}, gotTo)
+	require.Contains(t, gotMsg, "To: budi@mail.com\r\n")
+	require.Contains(t, gotMsg, "Subject: Reset your password\r\n")
+	require.True(t, strings.HasSuffix(gotMsg, "\r\n\r\nOpen the link below\r\nhttp://localhost/reset?token=abc"))
+}
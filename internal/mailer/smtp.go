@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig the SMTP server, the authentication is skipped when the username is empty
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+	// sendMail is replaced on the tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPMailer create a mailer which sends the emails through the SMTP server
+func NewSMTPMailer(cfg SMTPConfig) (model.Mailer, error) {
+	if cfg.Host == "" || cfg.Port <= 0 {
+		return nil, errors.New("mailer smtp host and port are required")
+	}
+	if cfg.From == "" {
+		return nil, errors.New("mailer smtp from address is required")
+	}
+
+	m := &smtpMailer{
+		addr:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from:     cfg.From,
+		sendMail: smtp.SendMail,
+	}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return m, nil
+}
+
+// Send send the email as plain text, the context is only checked before sending
+// since net/smtp does not support cancellation
+func (s *smtpMailer) Send(ctx context.Context, mail *model.Mail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.sendMail(s.addr, s.auth, s.from, []string{mail.To}, s.newMessage(mail))
+}
+
+func (s *smtpMailer) newMessage(mail *model.Mail) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", s.from)
+	fmt.Fprintf(&sb, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&sb, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&sb, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+
+	return []byte(sb.String())
+}
@@ -7,11 +7,13 @@ import (
 
 // AppClient the app clients
 type AppClient struct {
-	ID           int64     `json:"id" gorm:"primary_key;AUTO_INCREMENT"`
-	ClientID     string    `json:"client_id"`
-	ClientSecret string    `json:"client_secret"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int64  `json:"id" gorm:"primary_key;AUTO_INCREMENT"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	// IsPOSTerminal the app client is a shared POS terminal, the users may log in using PIN
+	IsPOSTerminal bool      `json:"is_pos_terminal"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // AppClientRepository repository
@@ -31,4 +31,6 @@ const (
 	AuditActionReset   AuditAction = "reset"
 	AuditActionUpsert  AuditAction = "upsert"
 	AuditActionRestore AuditAction = "restore"
+	AuditActionPrint   AuditAction = "print"
+	AuditActionReprint AuditAction = "reprint"
 )
@@ -11,6 +11,12 @@ type LoginRequest struct {
 	Email, PlainPassword, IPAddress, UserAgent string
 }
 
+// PINLoginRequest request, the user is usually picked from the operators of the terminal
+type PINLoginRequest struct {
+	AppID, UserID                  int64
+	PlainPIN, IPAddress, UserAgent string
+}
+
 // RefreshTokenRequest request
 type RefreshTokenRequest struct {
 	AppID                              int64
@@ -19,10 +25,28 @@ type RefreshTokenRequest struct {
 
 // AuthUsecase usecases
 type AuthUsecase interface {
-	LoginByEmailPassword(ctx context.Context, req LoginRequest) (*Session, error)
+	// LoginByEmailPassword log in using email & password, a challenge is returned instead of the session
+	// when the user has to continue with the two factor authentication
+	LoginByEmailPassword(ctx context.Context, req LoginRequest) (*Session, *TwoFactorChallenge, error)
+	// VerifyTwoFactor answer the two factor challenge of the login, the recovery codes are only returned
+	// when the challenge completes the enrollment
+	VerifyTwoFactor(ctx context.Context, req VerifyTwoFactorRequest) (*Session, []string, error)
+	// EnrollTwoFactorByChallenge start the enrollment of the user logging in whose role requires two factor authentication
+	EnrollTwoFactorByChallenge(ctx context.Context, challengeToken string) (*TwoFactorEnrollment, error)
+	// LoginByPIN log in using PIN, only allowed on the app clients flagged as POS terminal
+	LoginByPIN(ctx context.Context, req PINLoginRequest) (*Session, error)
+	// SwitchOperator swap the operator of the requester terminal session, the session of the requester
+	// is replaced by a session of the user on the same terminal
+	SwitchOperator(ctx context.Context, requester *User, req PINLoginRequest) (*Session, error)
 	// AuthenticateToken authenticate the given token
 	AuthenticateToken(ctx context.Context, accessToken string) (*User, error)
 	FindRolePermission(ctx context.Context, role rbac.Role) (*rbac.RolePermission, error)
 	RefreshToken(ctx context.Context, req RefreshTokenRequest) (*Session, error)
 	DeleteSessionByID(ctx context.Context, sessionID int64) error
+
+	EnrollTwoFactor(ctx context.Context, requester *User) (*TwoFactorEnrollment, error)
+	// ConfirmTwoFactor enable the pending two factor with a code of the authenticator app and return the recovery codes
+	ConfirmTwoFactor(ctx context.Context, requester *User, input TwoFactorCodeInput) ([]string, error)
+	DisableTwoFactor(ctx context.Context, requester *User, input DisableTwoFactorInput) error
+	RegenerateRecoveryCodes(ctx context.Context, requester *User, input TwoFactorCodeInput) ([]string, error)
 }
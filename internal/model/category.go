@@ -0,0 +1,181 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+// category errors
+var (
+	ErrUnknownCategory       = errors.New("unknown category")
+	ErrInvalidCategoryParent = errors.New("invalid category parent")
+	ErrCategoryNotEmpty      = errors.New("category still has subcategories or products")
+)
+
+// Category group of products, a category with zero ParentID is a root category.
+// The categories are ordered by SortOrder then by name within the same parent.
+type Category struct {
+	ID          int64          `json:"id" gorm:"primary_key"`
+	ParentID    int64          `json:"parent_id"`
+	Name        string         `json:"name"`
+	Slug        string         `json:"slug"`
+	Description string         `json:"description"`
+	SortOrder   int            `json:"sort_order"`
+	CreatedAt   time.Time      `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+	UpdatedAt   time.Time      `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at"`
+
+	Children []*Category `json:"children" gorm:"-"`
+}
+
+// CategoryRepository repository
+type CategoryRepository interface {
+	FindByID(ctx context.Context, id int64) (*Category, error)
+	FindBySlug(ctx context.Context, slug string) (*Category, error)
+	// FindAll find all categories ordered by the sort order & name, the categories are few so they are cached as a whole
+	FindAll(ctx context.Context) ([]*Category, error)
+	Create(ctx context.Context, userID int64, category *Category) error
+	Update(ctx context.Context, userID int64, category *Category) error
+	// Delete soft delete the category, return ErrCategoryNotEmpty when it still has subcategories or products
+	Delete(ctx context.Context, userID int64, category *Category) error
+}
+
+// CategoryUsecase usecase
+type CategoryUsecase interface {
+	FindByID(ctx context.Context, requester *User, id int64) (*Category, error)
+	// FindTree find the root categories with their subcategories nested
+	FindTree(ctx context.Context, requester *User) (AnyCategories, error)
+	Create(ctx context.Context, requester *User, input CreateCategoryInput) (*Category, error)
+	UpdateByID(ctx context.Context, requester *User, id int64, input UpdateCategoryInput) (*Category, error)
+	DeleteByID(ctx context.Context, requester *User, id int64) error
+}
+
+// CreateCategoryInput create category input
+type CreateCategoryInput struct {
+	// ParentID zero for a root category
+	ParentID    int64  `json:"parent_id" validate:"gte=0" example:"1695599921375543118"`
+	Name        string `json:"name" validate:"required,min=2,max=60" example:"Kopi"`
+	Description string `json:"description" validate:"max=80" example:"Aneka kopi panas & dingin"`
+	SortOrder   int    `json:"sort_order" validate:"gte=0" example:"1"`
+}
+
+type UpdateCategoryInput = CreateCategoryInput
+
+// Validate validate category input
+func (c *CreateCategoryInput) Validate() error {
+	return validate.Struct(c)
+}
+
+type AnyCategories []*Category
+
+// Tree nest the categories under their parent and return the roots,
+// a category whose parent is not in the list is treated as a root
+func (ac AnyCategories) Tree() AnyCategories {
+	nodes := make(map[int64]*Category, len(ac))
+	for _, category := range ac {
+		node := *category
+		node.Children = nil
+		nodes[node.ID] = &node
+	}
+
+	var roots AnyCategories
+	for _, category := range ac {
+		node := nodes[category.ID]
+		if parent, ok := nodes[node.ParentID]; ok && node.ParentID != node.ID {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+
+	sortCategories(roots)
+	return roots
+}
+
+// DescendantIDs find the ids of the subcategories of the category at any depth
+func (ac AnyCategories) DescendantIDs(id int64) []int64 {
+	children := make(map[int64][]int64)
+	for _, category := range ac {
+		children[category.ParentID] = append(children[category.ParentID], category.ID)
+	}
+
+	var ids []int64
+	visited := map[int64]bool{id: true}
+	queue := []int64{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, childID := range children[current] {
+			if visited[childID] {
+				continue
+			}
+			visited[childID] = true
+			ids = append(ids, childID)
+			queue = append(queue, childID)
+		}
+	}
+
+	return ids
+}
+
+// HasDescendant check if the descendantID is a subcategory of the category at any depth
+func (ac AnyCategories) HasDescendant(id, descendantID int64) bool {
+	for _, childID := range ac.DescendantIDs(id) {
+		if childID == descendantID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortCategories(categories []*Category) {
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].Name < categories[j].Name
+	})
+
+	for _, category := range categories {
+		sortCategories(category.Children)
+	}
+}
+
+type CategoryResponse struct {
+	ID          string             `json:"id" example:"1695599921375543118"`
+	ParentID    string             `json:"parent_id" example:"0"`
+	Name        string             `json:"name" example:"Kopi"`
+	Slug        string             `json:"slug" example:"kopi"`
+	Description string             `json:"description" example:"Aneka kopi panas & dingin"`
+	SortOrder   int                `json:"sort_order" example:"1"`
+	CreatedAt   string             `json:"created_at" example:"25 September 2023 13:59 WIB"`
+	UpdatedAt   string             `json:"updated_at" example:"25 September 2023 13:59 WIB"`
+	Children    []CategoryResponse `json:"children,omitempty"`
+}
+
+func (c Category) ToCategoryResponse() CategoryResponse {
+	return CategoryResponse{
+		ID:          utils.Int64ToString(c.ID),
+		ParentID:    utils.Int64ToString(c.ParentID),
+		Name:        c.Name,
+		Slug:        c.Slug,
+		Description: c.Description,
+		SortOrder:   c.SortOrder,
+		CreatedAt:   utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &c.CreatedAt),
+		UpdatedAt:   utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &c.UpdatedAt),
+		Children:    AnyCategories(c.Children).ToListCategoryResponse(),
+	}
+}
+
+func (ac AnyCategories) ToListCategoryResponse() (responses []CategoryResponse) {
+	for _, category := range ac {
+		responses = append(responses, category.ToCategoryResponse())
+	}
+
+	return responses
+}
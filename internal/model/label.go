@@ -0,0 +1,99 @@
+package model
+
+import "errors"
+
+// label errors
+var (
+	ErrUnknownLabelFormat = errors.New("unknown label format")
+	ErrUnknownLabelLayout = errors.New("unknown label layout")
+	ErrTooManyLabels      = errors.New("too many labels in a single print")
+)
+
+// MaxLabelsPerPrint the most labels rendered by a single print, including the copies & the variants
+const MaxLabelsPerPrint = 1000
+
+// LabelFormat output format of the label sheets
+type LabelFormat string
+
+const (
+	LabelFormatSVG LabelFormat = "SVG"
+	LabelFormatPNG LabelFormat = "PNG"
+	LabelFormatPDF LabelFormat = "PDF"
+)
+
+// IsValid check if the label format is supported
+func (f LabelFormat) IsValid() bool {
+	switch f {
+	case LabelFormatSVG, LabelFormatPNG, LabelFormatPDF:
+		return true
+	default:
+		return false
+	}
+}
+
+// LabelLayout the paper the labels are printed on
+type LabelLayout string
+
+// LabelLayout constants, the A4 grid holds 24 labels of 70x37mm and the roll holds a single 40x30mm label per page
+const (
+	LabelLayoutA4Grid    LabelLayout = "A4_GRID"
+	LabelLayoutRoll40x30 LabelLayout = "ROLL_40X30"
+)
+
+// IsValid check if the label layout is supported
+func (l LabelLayout) IsValid() bool {
+	switch l {
+	case LabelLayoutA4Grid, LabelLayoutRoll40x30:
+		return true
+	default:
+		return false
+	}
+}
+
+// LabelSheet the rendered label sheets
+type LabelSheet struct {
+	Format      LabelFormat
+	Layout      LabelLayout
+	ContentType string
+	Filename    string
+	Content     []byte
+}
+
+// PrintLabelsInput the products to print the shelf labels of,
+// the format default to PDF and the layout default to the A4 grid
+type PrintLabelsInput struct {
+	Format LabelFormat       `json:"format" example:"PDF"`
+	Layout LabelLayout       `json:"layout" example:"A4_GRID"`
+	Items  []PrintLabelInput `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// PrintLabelInput the copies printed for a product, a product with variants
+// prints the copies for every variant when the variant is not chosen
+type PrintLabelInput struct {
+	ProductID int64 `json:"product_id" validate:"required" example:"1695599921375543118"`
+	VariantID int64 `json:"variant_id" validate:"gte=0" example:"0"`
+	Copies    int64 `json:"copies" validate:"required,gte=1,lte=1000" example:"10"`
+}
+
+// Validate validate print labels input
+func (p *PrintLabelsInput) Validate() error {
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Format == "" {
+		p.Format = LabelFormatPDF
+	}
+	if !p.Format.IsValid() {
+		return ErrUnknownLabelFormat
+	}
+
+	if p.Layout == "" {
+		p.Layout = LabelLayoutA4Grid
+	}
+	if !p.Layout.IsValid() {
+		return ErrUnknownLabelLayout
+	}
+
+	return nil
+}
@@ -51,6 +51,21 @@ func (mr *MockAuthUsecaseMockRecorder) AuthenticateToken(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateToken", reflect.TypeOf((*MockAuthUsecase)(nil).AuthenticateToken), arg0, arg1)
 }
 
+// ConfirmTwoFactor mocks base method.
+func (m *MockAuthUsecase) ConfirmTwoFactor(arg0 context.Context, arg1 *model.User, arg2 model.TwoFactorCodeInput) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTwoFactor", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTwoFactor indicates an expected call of ConfirmTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) ConfirmTwoFactor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).ConfirmTwoFactor), arg0, arg1, arg2)
+}
+
 // DeleteSessionByID mocks base method.
 func (m *MockAuthUsecase) DeleteSessionByID(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
@@ -65,6 +80,50 @@ func (mr *MockAuthUsecaseMockRecorder) DeleteSessionByID(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionByID", reflect.TypeOf((*MockAuthUsecase)(nil).DeleteSessionByID), arg0, arg1)
 }
 
+// DisableTwoFactor mocks base method.
+func (m *MockAuthUsecase) DisableTwoFactor(arg0 context.Context, arg1 *model.User, arg2 model.DisableTwoFactorInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) DisableTwoFactor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).DisableTwoFactor), arg0, arg1, arg2)
+}
+
+// EnrollTwoFactor mocks base method.
+func (m *MockAuthUsecase) EnrollTwoFactor(arg0 context.Context, arg1 *model.User) (*model.TwoFactorEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(*model.TwoFactorEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTwoFactor indicates an expected call of EnrollTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) EnrollTwoFactor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).EnrollTwoFactor), arg0, arg1)
+}
+
+// EnrollTwoFactorByChallenge mocks base method.
+func (m *MockAuthUsecase) EnrollTwoFactorByChallenge(arg0 context.Context, arg1 string) (*model.TwoFactorEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTwoFactorByChallenge", arg0, arg1)
+	ret0, _ := ret[0].(*model.TwoFactorEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTwoFactorByChallenge indicates an expected call of EnrollTwoFactorByChallenge.
+func (mr *MockAuthUsecaseMockRecorder) EnrollTwoFactorByChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTwoFactorByChallenge", reflect.TypeOf((*MockAuthUsecase)(nil).EnrollTwoFactorByChallenge), arg0, arg1)
+}
+
 // FindRolePermission mocks base method.
 func (m *MockAuthUsecase) FindRolePermission(arg0 context.Context, arg1 rbac.Role) (*rbac.RolePermission, error) {
 	m.ctrl.T.Helper()
@@ -81,12 +140,13 @@ func (mr *MockAuthUsecaseMockRecorder) FindRolePermission(arg0, arg1 interface{}
 }
 
 // LoginByEmailPassword mocks base method.
-func (m *MockAuthUsecase) LoginByEmailPassword(arg0 context.Context, arg1 model.LoginRequest) (*model.Session, error) {
+func (m *MockAuthUsecase) LoginByEmailPassword(arg0 context.Context, arg1 model.LoginRequest) (*model.Session, *model.TwoFactorChallenge, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "LoginByEmailPassword", arg0, arg1)
 	ret0, _ := ret[0].(*model.Session)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(*model.TwoFactorChallenge)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // LoginByEmailPassword indicates an expected call of LoginByEmailPassword.
@@ -95,6 +155,21 @@ func (mr *MockAuthUsecaseMockRecorder) LoginByEmailPassword(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginByEmailPassword", reflect.TypeOf((*MockAuthUsecase)(nil).LoginByEmailPassword), arg0, arg1)
 }
 
+// LoginByPIN mocks base method.
+func (m *MockAuthUsecase) LoginByPIN(arg0 context.Context, arg1 model.PINLoginRequest) (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginByPIN", arg0, arg1)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginByPIN indicates an expected call of LoginByPIN.
+func (mr *MockAuthUsecaseMockRecorder) LoginByPIN(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginByPIN", reflect.TypeOf((*MockAuthUsecase)(nil).LoginByPIN), arg0, arg1)
+}
+
 // RefreshToken mocks base method.
 func (m *MockAuthUsecase) RefreshToken(arg0 context.Context, arg1 model.RefreshTokenRequest) (*model.Session, error) {
 	m.ctrl.T.Helper()
@@ -109,3 +184,49 @@ func (mr *MockAuthUsecaseMockRecorder) RefreshToken(arg0, arg1 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthUsecase)(nil).RefreshToken), arg0, arg1)
 }
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthUsecase) RegenerateRecoveryCodes(arg0 context.Context, arg1 *model.User, arg2 model.TwoFactorCodeInput) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthUsecaseMockRecorder) RegenerateRecoveryCodes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthUsecase)(nil).RegenerateRecoveryCodes), arg0, arg1, arg2)
+}
+
+// SwitchOperator mocks base method.
+func (m *MockAuthUsecase) SwitchOperator(arg0 context.Context, arg1 *model.User, arg2 model.PINLoginRequest) (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwitchOperator", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwitchOperator indicates an expected call of SwitchOperator.
+func (mr *MockAuthUsecaseMockRecorder) SwitchOperator(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchOperator", reflect.TypeOf((*MockAuthUsecase)(nil).SwitchOperator), arg0, arg1, arg2)
+}
+
+// VerifyTwoFactor mocks base method.
+func (m *MockAuthUsecase) VerifyTwoFactor(arg0 context.Context, arg1 model.VerifyTwoFactorRequest) (*model.Session, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) VerifyTwoFactor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).VerifyTwoFactor), arg0, arg1)
+}
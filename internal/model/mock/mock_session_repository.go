@@ -78,6 +78,20 @@ func (mr *MockSessionRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessionRepository)(nil).Delete), arg0, arg1)
 }
 
+// DeleteByUserID mocks base method.
+func (m *MockSessionRepository) DeleteByUserID(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUserID indicates an expected call of DeleteByUserID.
+func (mr *MockSessionRepositoryMockRecorder) DeleteByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserID", reflect.TypeOf((*MockSessionRepository)(nil).DeleteByUserID), arg0, arg1)
+}
+
 // DeleteByUserIDAndMaxRemainderSession mocks base method.
 func (m *MockSessionRepository) DeleteByUserIDAndMaxRemainderSession(arg0 context.Context, arg1 int64, arg2 int) error {
 	m.ctrl.T.Helper()
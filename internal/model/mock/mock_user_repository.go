@@ -79,6 +79,21 @@ func (mr *MockUserRepositoryMockRecorder) FindByID(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUserRepository)(nil).FindByID), arg0, arg1)
 }
 
+// FindPINByID mocks base method.
+func (m *MockUserRepository) FindPINByID(arg0 context.Context, arg1 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPINByID", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPINByID indicates an expected call of FindPINByID.
+func (mr *MockUserRepositoryMockRecorder) FindPINByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPINByID", reflect.TypeOf((*MockUserRepository)(nil).FindPINByID), arg0, arg1)
+}
+
 // FindPasswordByID mocks base method.
 func (m *MockUserRepository) FindPasswordByID(arg0 context.Context, arg1 int64) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +123,34 @@ func (mr *MockUserRepositoryMockRecorder) IncrementLoginByEmailPasswordRetryAtte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLoginByEmailPasswordRetryAttempts", reflect.TypeOf((*MockUserRepository)(nil).IncrementLoginByEmailPasswordRetryAttempts), arg0, arg1)
 }
 
+// IncrementLoginByPINRetryAttempts mocks base method.
+func (m *MockUserRepository) IncrementLoginByPINRetryAttempts(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementLoginByPINRetryAttempts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementLoginByPINRetryAttempts indicates an expected call of IncrementLoginByPINRetryAttempts.
+func (mr *MockUserRepositoryMockRecorder) IncrementLoginByPINRetryAttempts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLoginByPINRetryAttempts", reflect.TypeOf((*MockUserRepository)(nil).IncrementLoginByPINRetryAttempts), arg0, arg1, arg2)
+}
+
+// IncrementLoginByTwoFactorRetryAttempts mocks base method.
+func (m *MockUserRepository) IncrementLoginByTwoFactorRetryAttempts(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementLoginByTwoFactorRetryAttempts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementLoginByTwoFactorRetryAttempts indicates an expected call of IncrementLoginByTwoFactorRetryAttempts.
+func (mr *MockUserRepositoryMockRecorder) IncrementLoginByTwoFactorRetryAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementLoginByTwoFactorRetryAttempts", reflect.TypeOf((*MockUserRepository)(nil).IncrementLoginByTwoFactorRetryAttempts), arg0, arg1)
+}
+
 // IsLoginByEmailPasswordLocked mocks base method.
 func (m *MockUserRepository) IsLoginByEmailPasswordLocked(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +166,52 @@ func (mr *MockUserRepositoryMockRecorder) IsLoginByEmailPasswordLocked(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoginByEmailPasswordLocked", reflect.TypeOf((*MockUserRepository)(nil).IsLoginByEmailPasswordLocked), arg0, arg1)
 }
 
+// IsLoginByPINLocked mocks base method.
+func (m *MockUserRepository) IsLoginByPINLocked(arg0 context.Context, arg1, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLoginByPINLocked", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsLoginByPINLocked indicates an expected call of IsLoginByPINLocked.
+func (mr *MockUserRepositoryMockRecorder) IsLoginByPINLocked(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoginByPINLocked", reflect.TypeOf((*MockUserRepository)(nil).IsLoginByPINLocked), arg0, arg1, arg2)
+}
+
+// IsLoginByTwoFactorLocked mocks base method.
+func (m *MockUserRepository) IsLoginByTwoFactorLocked(arg0 context.Context, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLoginByTwoFactorLocked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsLoginByTwoFactorLocked indicates an expected call of IsLoginByTwoFactorLocked.
+func (mr *MockUserRepositoryMockRecorder) IsLoginByTwoFactorLocked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoginByTwoFactorLocked", reflect.TypeOf((*MockUserRepository)(nil).IsLoginByTwoFactorLocked), arg0, arg1)
+}
+
+// SearchByPage mocks base method.
+func (m *MockUserRepository) SearchByPage(arg0 context.Context, arg1 model.UserSearchCriteria) ([]int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchByPage", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchByPage indicates an expected call of SearchByPage.
+func (mr *MockUserRepositoryMockRecorder) SearchByPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByPage", reflect.TypeOf((*MockUserRepository)(nil).SearchByPage), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockUserRepository) Update(arg0 context.Context, arg1 int64, arg2 *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +227,20 @@ func (mr *MockUserRepositoryMockRecorder) Update(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), arg0, arg1, arg2)
 }
 
+// UpdatePINByID mocks base method.
+func (m *MockUserRepository) UpdatePINByID(arg0 context.Context, arg1 int64, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePINByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePINByID indicates an expected call of UpdatePINByID.
+func (mr *MockUserRepositoryMockRecorder) UpdatePINByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePINByID", reflect.TypeOf((*MockUserRepository)(nil).UpdatePINByID), arg0, arg1, arg2)
+}
+
 // UpdatePasswordByID mocks base method.
 func (m *MockUserRepository) UpdatePasswordByID(arg0 context.Context, arg1 int64, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -35,6 +35,35 @@ func (m *MockUserUsecase) EXPECT() *MockUserUsecaseMockRecorder {
 	return m.recorder
 }
 
+// ActivateByID mocks base method.
+func (m *MockUserUsecase) ActivateByID(arg0 context.Context, arg1 *model.User, arg2 int64) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateByID indicates an expected call of ActivateByID.
+func (mr *MockUserUsecaseMockRecorder) ActivateByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateByID", reflect.TypeOf((*MockUserUsecase)(nil).ActivateByID), arg0, arg1, arg2)
+}
+
+// ChangePIN mocks base method.
+func (m *MockUserUsecase) ChangePIN(arg0 context.Context, arg1 *model.User, arg2 model.ChangePINInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePIN", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePIN indicates an expected call of ChangePIN.
+func (mr *MockUserUsecaseMockRecorder) ChangePIN(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePIN", reflect.TypeOf((*MockUserUsecase)(nil).ChangePIN), arg0, arg1, arg2)
+}
+
 // ChangePassword mocks base method.
 func (m *MockUserUsecase) ChangePassword(arg0 context.Context, arg1 *model.User, arg2 model.ChangePasswordInput) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +79,21 @@ func (mr *MockUserUsecaseMockRecorder) ChangePassword(arg0, arg1, arg2 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserUsecase)(nil).ChangePassword), arg0, arg1, arg2)
 }
 
+// ChangeRoleByID mocks base method.
+func (m *MockUserUsecase) ChangeRoleByID(arg0 context.Context, arg1 *model.User, arg2 int64, arg3 model.ChangeUserRoleInput) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRoleByID", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeRoleByID indicates an expected call of ChangeRoleByID.
+func (mr *MockUserUsecaseMockRecorder) ChangeRoleByID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRoleByID", reflect.TypeOf((*MockUserUsecase)(nil).ChangeRoleByID), arg0, arg1, arg2, arg3)
+}
+
 // Create mocks base method.
 func (m *MockUserUsecase) Create(arg0 context.Context, arg1 *model.User, arg2 model.CreateUserInput) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +109,21 @@ func (mr *MockUserUsecaseMockRecorder) Create(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserUsecase)(nil).Create), arg0, arg1, arg2)
 }
 
+// DeactivateByID mocks base method.
+func (m *MockUserUsecase) DeactivateByID(arg0 context.Context, arg1 *model.User, arg2 int64) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateByID indicates an expected call of DeactivateByID.
+func (mr *MockUserUsecaseMockRecorder) DeactivateByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateByID", reflect.TypeOf((*MockUserUsecase)(nil).DeactivateByID), arg0, arg1, arg2)
+}
+
 // FindByID mocks base method.
 func (m *MockUserUsecase) FindByID(arg0 context.Context, arg1 *model.User, arg2 int64) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +139,50 @@ func (mr *MockUserUsecaseMockRecorder) FindByID(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockUserUsecase)(nil).FindByID), arg0, arg1, arg2)
 }
 
+// ForgotPassword mocks base method.
+func (m *MockUserUsecase) ForgotPassword(arg0 context.Context, arg1 model.ForgotPasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserUsecaseMockRecorder) ForgotPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserUsecase)(nil).ForgotPassword), arg0, arg1)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserUsecase) ResetPassword(arg0 context.Context, arg1 model.ResetPasswordInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserUsecaseMockRecorder) ResetPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserUsecase)(nil).ResetPassword), arg0, arg1)
+}
+
+// Search mocks base method.
+func (m *MockUserUsecase) Search(arg0 context.Context, arg1 *model.User, arg2 model.UserSearchCriteria) (model.AnyUsers, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.AnyUsers)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockUserUsecaseMockRecorder) Search(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUserUsecase)(nil).Search), arg0, arg1, arg2)
+}
+
 // UpdateProfile mocks base method.
 func (m *MockUserUsecase) UpdateProfile(arg0 context.Context, arg1 *model.User, arg2 model.UpdateProfileInput) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidPasswordResetToken error when the reset token does not exist, is expired or is already used
+var ErrInvalidPasswordResetToken = errors.New("invalid or expired password reset token")
+
+// Mail a plain text email
+type Mail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Mailer send the emails, e.g. through a SMTP server or to a local file
+type Mailer interface {
+	Send(ctx context.Context, mail *Mail) error
+}
+
+// PasswordResetToken a single use token to reset the password of the user, only the hash of the token is stored
+type PasswordResetToken struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	TokenHash string     `json:"token_hash"`
+	ExpiredAt time.Time  `json:"expired_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"->;<-:create"`
+}
+
+// IsExpired check expired at against now
+func (p *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(p.ExpiredAt)
+}
+
+// PasswordResetTokenRepository repository
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *PasswordResetToken) error
+	// FindByTokenHash find the token by its hash, return nil when not found
+	FindByTokenHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	// Use mark the token used along with the other unused tokens of the user,
+	// return false when the token is already used
+	Use(ctx context.Context, token *PasswordResetToken) (bool, error)
+}
+
+// ForgotPasswordInput request a password reset token to the email
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email" example:"budi@mail.com"`
+}
+
+// Validate validate the input
+func (f *ForgotPasswordInput) Validate() error {
+	return validate.Struct(f)
+}
+
+// ResetPasswordInput reset the password with the token sent to the email
+type ResetPasswordInput struct {
+	Token                   string `json:"token" validate:"required"`
+	NewPassword             string `json:"new_password" validate:"required,min=6" example:"123456"`
+	NewPasswordConfirmation string `json:"new_password_confirmation" validate:"required,min=6,eqfield=NewPassword" example:"123456"`
+}
+
+// Validate validate the input
+func (r *ResetPasswordInput) Validate() error {
+	return validate.Struct(r)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"errors"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"gorm.io/gorm"
 	"time"
@@ -9,17 +10,100 @@ import (
 
 var WesternIndonesiaLayout = "02 January 2006 15:04 WIB"
 
-// Product model
+// ErrInsufficientStock error when the product stock is less than requested quantity
+var ErrInsufficientStock = errors.New("insufficient stock for one or more products")
+
+// ErrDuplicateProductCode error when the sku or barcode is already used by another product or variant,
+// a code must point to a single item so a scan is never ambiguous
+var ErrDuplicateProductCode = errors.New("sku or barcode is already used")
+
+// Product model, a product with options is sold per variant.
+// The Price of such product is the lowest variant price and the Quantity is the total stock of its variants.
+// A low stock alert is sent when the Quantity reaches the MinStock, a zero MinStock disables the alert.
+// The CostPrice is the moving average cost of the stock, it is averaged by every goods receipt
+// and can be corrected manually, a zero CostPrice means the cost is not recorded yet.
 type Product struct {
-	ID          int64          `json:"id" gorm:"primary_key"`
-	Name        string         `json:"name"`
-	Slug        string         `json:"slug"`
-	Description string         `json:"description"`
-	Price       int64          `json:"price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
-	Quantity    int64          `json:"quantity"`
-	CreatedAt   time.Time      `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
-	UpdatedAt   time.Time      `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at"`
+	ID              int64          `json:"id" gorm:"primary_key"`
+	Name            string         `json:"name"`
+	Slug            string         `json:"slug"`
+	SKU             string         `json:"sku"`
+	Barcode         string         `json:"barcode"`
+	CategoryID      int64          `json:"category_id"`
+	Description     string         `json:"description"`
+	Price           int64          `json:"price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	CostPrice       int64          `json:"cost_price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Quantity        int64          `json:"quantity"`
+	MinStock        int64          `json:"min_stock"`
+	ReorderQuantity int64          `json:"reorder_quantity"`
+	TaxCategory     TaxCategory    `json:"tax_category"`
+	Options         ProductOptions `json:"options" gorm:"serializer:json"`
+	CreatedAt       time.Time      `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+	UpdatedAt       time.Time      `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at"`
+
+	Variants []*ProductVariant `json:"variants" gorm:"-"`
+}
+
+// HasVariants check if the product is sold per variant
+func (p Product) HasVariants() bool {
+	return len(p.Options) > 0
+}
+
+// IsLowStock check if the stock has reached the minimum stock level
+func (p Product) IsLowStock() bool {
+	return p.MinStock > 0 && p.Quantity <= p.MinStock
+}
+
+// UnitCost the cost price to snapshot on a sold line, nil when the cost is not recorded yet
+func (p Product) UnitCost() *int64 {
+	if p.CostPrice <= 0 {
+		return nil
+	}
+
+	cost := p.CostPrice
+	return &cost
+}
+
+// KeepStock keep the stored stock of the product and its variants, the stock is only changed by the stock movements.
+// A new variant starts without stock, and so does the product when it switches between having variants or not.
+func (p *Product) KeepStock(stored *Product, storedVariants []*ProductVariant) {
+	if !p.HasVariants() {
+		p.Quantity = 0
+		if !stored.HasVariants() {
+			p.Quantity = stored.Quantity
+		}
+		return
+	}
+
+	p.Quantity = 0
+	for _, variant := range p.Variants {
+		variant.Quantity = 0
+		if storedVariant := AnyProductVariants(storedVariants).FindByID(variant.ID); storedVariant != nil {
+			variant.Quantity = storedVariant.Quantity
+		}
+		p.Quantity += variant.Quantity
+	}
+}
+
+// MovingAverageCost the cost price after receiving quantity costing amount in total on top of the stock
+// valued at costPrice, rounded to the nearest rupiah. A stock of zero or below has no value to average with,
+// so the received cost is taken as is.
+func MovingAverageCost(stock, costPrice, quantity, amount int64) int64 {
+	if stock < 0 {
+		stock = 0
+	}
+
+	total := stock + quantity
+	if total <= 0 {
+		return costPrice
+	}
+
+	return (stock*costPrice + amount + total/2) / total
+}
+
+// HasCode check if the code is the sku or barcode of the product
+func (p Product) HasCode(code string) bool {
+	return code != "" && (p.SKU == code || p.Barcode == code)
 }
 
 // ProductRepository repository
@@ -27,18 +111,58 @@ type ProductRepository interface {
 	FindByID(ctx context.Context, id int64) (*Product, error)
 	SearchByPage(ctx context.Context, criteria ProductSearchCriteria) (ids []int64, count int64, err error)
 	FindBySlug(ctx context.Context, slug string) (*Product, error)
+	// FindByCode find the product with the sku or barcode
+	FindByCode(ctx context.Context, code string) (*Product, error)
 	Create(ctx context.Context, userID int64, product *Product) error
 	Update(ctx context.Context, userID int64, product *Product) error
 	Delete(ctx context.Context, userID int64, product *Product) error
+	// DecreaseStockByID decrease the stock within the given db transaction,
+	// return ErrInsufficientStock when the remaining stock is less than quantity
+	DecreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error
+	// IncreaseStockByID return the stock within the given db transaction
+	IncreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error
+	// ApplyStockMovements change the stock by the movements and append them to the ledger within the given db transaction
+	ApplyStockMovements(ctx context.Context, tx *gorm.DB, movements []*StockMovement) error
+	// PostStockMovements apply the movements in their own db transaction
+	PostStockMovements(ctx context.Context, movements []*StockMovement) error
+	DeleteCachesByIDs(ids []int64) error
+	// ApplyReceivedCostByID average the cost price with the received quantity costing amount in total within
+	// the given db transaction, it must be called before the received quantity is added to the stock.
+	// The caches are deleted by the caller.
+	ApplyReceivedCostByID(ctx context.Context, tx *gorm.DB, id, quantity, amount int64) error
+	// UpdateCostPrice set the cost price manually
+	UpdateCostPrice(ctx context.Context, userID int64, product *Product) error
+	// SearchLowStockByPage find the ids of the products which stock has reached their minimum stock level,
+	// ordered by the lowest stock relative to the minimum
+	SearchLowStockByPage(ctx context.Context, criteria LowStockSearchCriteria) (ids []int64, count int64, err error)
+	// Import store the imported products in a single db transaction, the existing products keep their stock & cost price
+	Import(ctx context.Context, userID int64, imports []*ProductImport) error
+	// StreamAll call fn with every product ordered by id without holding the whole catalog in memory,
+	// the variants are not loaded
+	StreamAll(ctx context.Context, fn func(product *Product) error) error
 }
 
 // ProductUsecase usecase
 type ProductUsecase interface {
 	FindByID(ctx context.Context, requester *User, id int64) (*Product, error)
+	// FindByCode find the product of a scanned sku or barcode, the variant is returned
+	// when the code belongs to a variant of the product
+	FindByCode(ctx context.Context, requester *User, code string) (*Product, *ProductVariant, error)
 	Search(ctx context.Context, requester *User, criteria ProductSearchCriteria) (products AnyProducts, count int64, err error)
 	Create(ctx context.Context, requester *User, input CreateProductInput) (*Product, error)
 	UpdateByID(ctx context.Context, requester *User, id int64, input UpdateProductInput) (*Product, error)
+	// UpdateCostPriceByID correct the moving average cost price manually
+	UpdateCostPriceByID(ctx context.Context, requester *User, id int64, input UpdateProductCostPriceInput) (*Product, error)
 	DeleteByID(ctx context.Context, requester *User, id int64) error
+	// PrintLabels render the shelf labels of the products, a label is printed for every copy
+	PrintLabels(ctx context.Context, requester *User, input PrintLabelsInput) (*LabelSheet, error)
+	// SearchLowStock find the products which stock has reached their minimum stock level
+	SearchLowStock(ctx context.Context, requester *User, criteria LowStockSearchCriteria) (products AnyProducts, count int64, err error)
+	// Import create or update the products of the imported rows, a row is matched to an existing product by its sku
+	// or by the slug of its name. The rows which fail are reported in the result instead of stopping the import.
+	Import(ctx context.Context, requester *User, input ImportProductsInput) (*ProductImportResult, error)
+	// Export call fn with every product of the catalog ordered by id
+	Export(ctx context.Context, requester *User, fn func(product *Product) error) error
 }
 
 // CreateProductInput create product input
@@ -46,14 +170,125 @@ type CreateProductInput struct {
 	Name        string `json:"name" validate:"required,min=3,max=60" example:"Pisang Goreng"`
 	Description string `json:"description" validate:"max=80" example:"Pisang goreng gurih"`
 	Price       int64  `json:"price" validate:"gte=0" example:"5000"`
-	Quantity    int64  `json:"quantity" validate:"gt=0" example:"10"`
+	// CostPrice the initial cost price, later averaged by the goods receipts
+	CostPrice int64 `json:"cost_price" validate:"gte=0" example:"3000"`
+	// SKU generated from the product name when empty
+	SKU string `json:"sku" validate:"max=64" example:"PISANG-GORENG"`
+	// Barcode an EAN-13 or UPC-A with a valid check digit, or a Code128
+	Barcode string `json:"barcode" validate:"omitempty,barcode" example:"8991234567891"`
+	// Quantity is required for a product without variants, a product with variants is stocked per variant
+	Quantity int64 `json:"quantity" validate:"required_without=Options,gte=0" example:"10"`
+	// MinStock the stock level which triggers a low stock alert, zero disables the alert
+	MinStock int64 `json:"min_stock" validate:"gte=0" example:"5"`
+	// ReorderQuantity the suggested quantity to order when the stock is low
+	ReorderQuantity int64 `json:"reorder_quantity" validate:"gte=0" example:"20"`
+	// TaxCategory default to STANDARD when empty
+	TaxCategory TaxCategory `json:"tax_category" validate:"max=30" example:"STANDARD"`
+	// CategoryID zero when the product is not categorized
+	CategoryID int64 `json:"category_id" validate:"gte=0" example:"1695599921375543118"`
+	// Options the option groups of the variants, empty when the product has no variants
+	Options  ProductOptions              `json:"options" validate:"omitempty,max=3,dive"`
+	Variants []CreateProductVariantInput `json:"variants" validate:"omitempty,max=100,dive"`
 }
 
-type UpdateProductInput = CreateProductInput
-
-// Validate validate product input
+// Validate validate product input, every variant must choose a value of every option group
+// and no two variants may have the same options or sku
 func (c *CreateProductInput) Validate() error {
-	return validate.Struct(c)
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+
+	variants := make([]ProductVariantInput, 0, len(c.Variants))
+	for _, variant := range c.Variants {
+		variants = append(variants, variant.ProductVariantInput)
+	}
+
+	return validateProductVariants(c.Options, variants)
+}
+
+// UpdateProductInput update product input, the stock is not updated here but through
+// the stock movements and the stock opname, so it is always recorded in the ledger.
+// The cost price is updated by the goods receipts or through UpdateProductCostPriceInput.
+type UpdateProductInput struct {
+	Name        string `json:"name" validate:"required,min=3,max=60" example:"Pisang Goreng"`
+	Description string `json:"description" validate:"max=80" example:"Pisang goreng gurih"`
+	Price       int64  `json:"price" validate:"gte=0" example:"5000"`
+	// SKU kept when empty
+	SKU string `json:"sku" validate:"max=64" example:"PISANG-GORENG"`
+	// Barcode an EAN-13 or UPC-A with a valid check digit, or a Code128
+	Barcode string `json:"barcode" validate:"omitempty,barcode" example:"8991234567891"`
+	// MinStock the stock level which triggers a low stock alert, zero disables the alert
+	MinStock int64 `json:"min_stock" validate:"gte=0" example:"5"`
+	// ReorderQuantity the suggested quantity to order when the stock is low
+	ReorderQuantity int64 `json:"reorder_quantity" validate:"gte=0" example:"20"`
+	// TaxCategory default to STANDARD when empty
+	TaxCategory TaxCategory `json:"tax_category" validate:"max=30" example:"STANDARD"`
+	// CategoryID zero when the product is not categorized
+	CategoryID int64 `json:"category_id" validate:"gte=0" example:"1695599921375543118"`
+	// Options the option groups of the variants, empty when the product has no variants
+	Options  ProductOptions        `json:"options" validate:"omitempty,max=3,dive"`
+	Variants []ProductVariantInput `json:"variants" validate:"omitempty,max=100,dive"`
+}
+
+// Validate validate update product input, with the same variant rules as CreateProductInput
+func (u *UpdateProductInput) Validate() error {
+	if err := validate.Struct(u); err != nil {
+		return err
+	}
+
+	return validateProductVariants(u.Options, u.Variants)
+}
+
+// UpdateProductCostPriceInput correct the moving average cost price manually,
+// e.g. when the initial cost price was wrong or the stock was received without a purchase order
+type UpdateProductCostPriceInput struct {
+	CostPrice int64 `json:"cost_price" validate:"gte=0" example:"3000"`
+}
+
+// Validate validate update product cost price input
+func (u *UpdateProductCostPriceInput) Validate() error {
+	return validate.Struct(u)
+}
+
+func validateProductVariants(options ProductOptions, variants []ProductVariantInput) error {
+	if len(options) <= 0 {
+		if len(variants) > 0 {
+			return ErrInvalidProductVariant
+		}
+		return nil
+	}
+
+	if len(variants) <= 0 {
+		return ErrInvalidProductVariant
+	}
+
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(variants))
+	skus := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		if !options.Matches(variant.Options) {
+			return ErrInvalidProductVariant
+		}
+
+		name := options.VariantName(variant.Options)
+		if names[name] {
+			return ErrDuplicateProductVariant
+		}
+		names[name] = true
+
+		if variant.SKU == "" {
+			continue
+		}
+		if skus[variant.SKU] {
+			return ErrDuplicateProductVariant
+		}
+		skus[variant.SKU] = true
+	}
+
+	return nil
 }
 
 // ProductSortType sort type for product search
@@ -78,12 +313,14 @@ var QueryProductSortByMap = map[ProductSortType]string{
 	ProductSortTypeNameDesc:      "name DESC",
 }
 
-// ProductSearchCriteria criteria for searching & sorting product
+// ProductSearchCriteria criteria for searching & sorting product,
+// the CategoryID filter includes the products of its subcategories
 type ProductSearchCriteria struct {
-	Query    string          `json:"query" query:"query"`
-	Page     int             `json:"page" query:"page"`
-	Size     int             `json:"size" query:"size"`
-	SortType ProductSortType `json:"sort_type" query:"sortBy"`
+	Query      string          `json:"query" query:"query"`
+	Page       int             `json:"page" query:"page"`
+	Size       int             `json:"size" query:"size"`
+	SortType   ProductSortType `json:"sort_type" query:"sortBy"`
+	CategoryID int64           `json:"category_id" query:"categoryID"`
 }
 
 // SetDefaultValue will set default value for page and size if zero
@@ -105,30 +342,90 @@ func (c *ProductSearchCriteria) SetDefaultValue() {
 	}
 }
 
+// LowStockSearchCriteria criteria for searching the products with low stock
+type LowStockSearchCriteria struct {
+	Page int `json:"page" query:"page"`
+	Size int `json:"size" query:"size"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *LowStockSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+}
+
 type ProductResponse struct {
-	ID          string `json:"id" example:"1695599921375543118"`
-	Name        string `json:"name" example:"Pisang Goreng"`
-	Slug        string `json:"slug" example:"pisang-goreng"`
-	Description string `json:"description" example:"Pisang goreng gurih"`
-	Price       string `json:"price" example:"Rp4.000"`
-	Quantity    string `json:"quantity" example:"10"`
-	CreatedAt   string `json:"created_at" example:"25 September 2023 13:59 WIB"`
-	UpdatedAt   string `json:"updated_at" example:"25 September 2023 13:59 WIB"`
+	ID              string `json:"id" example:"1695599921375543118"`
+	Name            string `json:"name" example:"Pisang Goreng"`
+	Slug            string `json:"slug" example:"pisang-goreng"`
+	SKU             string `json:"sku" example:"PISANG-GORENG"`
+	Barcode         string `json:"barcode" example:"8991234567891"`
+	CategoryID      string `json:"category_id" example:"1695599921375543118"`
+	Description     string `json:"description" example:"Pisang goreng gurih"`
+	Price           string `json:"price" example:"Rp4.000"`
+	CostPrice       string `json:"cost_price,omitempty" example:"Rp3.000"`
+	Quantity        string `json:"quantity" example:"10"`
+	MinStock        string `json:"min_stock" example:"5"`
+	ReorderQuantity string `json:"reorder_quantity" example:"20"`
+	LowStock        bool   `json:"low_stock" example:"false"`
+	TaxCategory     string `json:"tax_category" example:"STANDARD"`
+	CreatedAt       string `json:"created_at" example:"25 September 2023 13:59 WIB"`
+	UpdatedAt       string `json:"updated_at" example:"25 September 2023 13:59 WIB"`
+
+	Options  ProductOptions           `json:"options,omitempty"`
+	Variants []ProductVariantResponse `json:"variants,omitempty"`
 }
 
 func (p Product) ToProductResponse() ProductResponse {
 	return ProductResponse{
-		ID:          utils.Int64ToString(p.ID),
-		Name:        p.Name,
-		Slug:        p.Slug,
-		Description: p.Description,
-		Price:       utils.Int64ToRupiah(p.Price),
-		Quantity:    utils.Int64ToString(p.Quantity),
-		CreatedAt:   utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.CreatedAt),
-		UpdatedAt:   utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.UpdatedAt),
+		ID:              utils.Int64ToString(p.ID),
+		Name:            p.Name,
+		Slug:            p.Slug,
+		SKU:             p.SKU,
+		Barcode:         p.Barcode,
+		CategoryID:      utils.Int64ToString(p.CategoryID),
+		Description:     p.Description,
+		Price:           utils.Int64ToRupiah(p.Price),
+		CostPrice:       utils.Int64ToRupiah(p.CostPrice),
+		Quantity:        utils.Int64ToString(p.Quantity),
+		MinStock:        utils.Int64ToString(p.MinStock),
+		ReorderQuantity: utils.Int64ToString(p.ReorderQuantity),
+		LowStock:        p.IsLowStock(),
+		TaxCategory:     string(p.TaxCategory),
+		CreatedAt:       utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.CreatedAt),
+		UpdatedAt:       utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.UpdatedAt),
+		Options:         p.Options,
+		Variants:        AnyProductVariants(p.Variants).ToListProductVariantResponse(),
 	}
 }
 
+// ScannedProductResponse the product of a scanned code, VariantID is the scanned variant
+// and empty when the code belongs to the product itself. The cost price is left out since the code is scanned at the till.
+type ScannedProductResponse struct {
+	ProductResponse
+	VariantID string `json:"variant_id,omitempty" example:"1695599921375543118"`
+}
+
+// ToScannedProductResponse the product response along with the scanned variant
+func (p Product) ToScannedProductResponse(variant *ProductVariant) ScannedProductResponse {
+	response := ScannedProductResponse{ProductResponse: p.ToProductResponse()}
+	response.CostPrice = ""
+	if variant != nil {
+		response.VariantID = utils.Int64ToString(variant.ID)
+	}
+
+	return response
+}
+
 type AnyProducts []*Product
 
 func (ap AnyProducts) ToListProductResponse() (productResponses []ProductResponse) {
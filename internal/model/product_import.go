@@ -0,0 +1,286 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// product import errors
+var (
+	ErrUnknownProductFileFormat = errors.New("unknown product file format")
+	// ErrInvalidProductImportHeader error when the first row of the imported file has no name column
+	ErrInvalidProductImportHeader = errors.New("invalid product import header, the name column is required")
+	ErrTooManyProductImportRows   = errors.New("too many rows in a single product import")
+	// ErrDuplicateProductImportRow error when more rows of the imported file point to the same product
+	ErrDuplicateProductImportRow = errors.New("product is imported by another row")
+	// ErrProductImportHasVariants error when an imported row points to a product sold per variant,
+	// the variants can not be described by a single row so the product must be edited instead
+	ErrProductImportHasVariants = errors.New("product with variants can not be imported")
+	// ErrProductImportNameAlreadyExist error when the name of the row is already used by another product
+	ErrProductImportNameAlreadyExist = errors.New("product name already exist")
+	// ErrProductImportBatchFailed error of the rows which are not stored because their batch failed
+	ErrProductImportBatchFailed = errors.New("not stored because the batch of the row failed, retry the import")
+)
+
+// MaxProductImportRows the most product rows read from a single imported file
+const MaxProductImportRows = 5000
+
+// ProductFileFormat format of the imported & exported product files
+type ProductFileFormat string
+
+const (
+	ProductFileFormatCSV  ProductFileFormat = "CSV"
+	ProductFileFormatXLSX ProductFileFormat = "XLSX"
+)
+
+// IsValid check if the product file format is supported
+func (f ProductFileFormat) IsValid() bool {
+	switch f {
+	case ProductFileFormatCSV, ProductFileFormatXLSX:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProductFileFormatFromFilename the format of the file by its extension, e.g. products.xlsx is XLSX
+func ProductFileFormatFromFilename(filename string) ProductFileFormat {
+	return ProductFileFormat(strings.ToUpper(strings.TrimPrefix(filepath.Ext(filename), ".")))
+}
+
+// ProductFileColumns the columns of the product files, in the exported order.
+// The imported file may order the columns freely and leave out all but the name.
+var ProductFileColumns = []string{
+	"name",
+	"sku",
+	"barcode",
+	"description",
+	"price",
+	"cost_price",
+	"quantity",
+	"min_stock",
+	"reorder_quantity",
+	"tax_category",
+	"category_id",
+}
+
+// ToProductFileRecord the product as a row of the product file, in the order of ProductFileColumns
+func (p *Product) ToProductFileRecord() []string {
+	return []string{
+		p.Name,
+		p.SKU,
+		p.Barcode,
+		p.Description,
+		strconv.FormatInt(p.Price, 10),
+		strconv.FormatInt(p.CostPrice, 10),
+		strconv.FormatInt(p.Quantity, 10),
+		strconv.FormatInt(p.MinStock, 10),
+		strconv.FormatInt(p.ReorderQuantity, 10),
+		string(p.TaxCategory),
+		strconv.FormatInt(p.CategoryID, 10),
+	}
+}
+
+// ProductImportRow a product row of the imported file, Line is the line number on the file starting from 1.
+// Err is set when the row can not be parsed, such row is reported instead of imported.
+type ProductImportRow struct {
+	Line  int
+	Input CreateProductInput
+	Err   error
+}
+
+// ParseProductImportRecords parse the records of the imported file, the first non empty record is the header.
+// The header is matched case insensitively and the unknown columns are ignored, the empty rows are skipped.
+func ParseProductImportRecords(records [][]string) (ImportProductsInput, error) {
+	input := ImportProductsInput{}
+	headerLine := -1
+	for i, record := range records {
+		if !isEmptyRecord(record) {
+			headerLine = i
+			break
+		}
+	}
+	if headerLine < 0 {
+		return input, ErrInvalidProductImportHeader
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[headerLine] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return input, ErrInvalidProductImportHeader
+	}
+
+	for _, column := range ProductFileColumns {
+		if _, ok := columns[column]; ok {
+			input.Columns = append(input.Columns, column)
+		}
+	}
+
+	for i := headerLine + 1; i < len(records); i++ {
+		if isEmptyRecord(records[i]) {
+			continue
+		}
+		if len(input.Rows) >= MaxProductImportRows {
+			return input, ErrTooManyProductImportRows
+		}
+
+		input.Rows = append(input.Rows, parseProductImportRecord(i+1, columns, records[i]))
+	}
+
+	return input, nil
+}
+
+func parseProductImportRecord(line int, columns map[string]int, record []string) *ProductImportRow {
+	value := func(column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := &ProductImportRow{
+		Line: line,
+		Input: CreateProductInput{
+			Name:        value("name"),
+			SKU:         value("sku"),
+			Barcode:     value("barcode"),
+			Description: value("description"),
+			TaxCategory: TaxCategory(value("tax_category")),
+		},
+	}
+
+	numbers := []struct {
+		column string
+		dest   *int64
+	}{
+		{"price", &row.Input.Price},
+		{"cost_price", &row.Input.CostPrice},
+		{"quantity", &row.Input.Quantity},
+		{"min_stock", &row.Input.MinStock},
+		{"reorder_quantity", &row.Input.ReorderQuantity},
+		{"category_id", &row.Input.CategoryID},
+	}
+	for _, number := range numbers {
+		val := value(number.column)
+		if val == "" {
+			continue
+		}
+
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			row.Err = fmt.Errorf("%s: %q is not a whole number", number.column, val)
+			return row
+		}
+		*number.dest = n
+	}
+
+	return row
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, val := range record {
+		if strings.TrimSpace(val) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate validate the row with the CreateProductInput rules, the stock of an existing product is kept
+// so the quantity is only validated for a new product
+func (r *ProductImportRow) Validate(isNew bool) error {
+	if isNew {
+		return r.Input.Validate()
+	}
+
+	if err := validate.StructExcept(r.Input, "Quantity"); err != nil {
+		return err
+	}
+
+	return validateProductVariants(r.Input.Options, nil)
+}
+
+// ImportProductsInput the rows of the imported file, nothing is stored on a dry run but every row is checked the same way.
+// Columns are the known columns of the file, only these are updated on the existing products.
+type ImportProductsInput struct {
+	Columns []string
+	Rows    []*ProductImportRow
+	DryRun  bool
+}
+
+// HasColumn check if the imported file has the column
+func (i ImportProductsInput) HasColumn(column string) bool {
+	for _, c := range i.Columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProductImportRowError the reason a row of the imported file is not imported
+type ProductImportRowError struct {
+	Line  int    `json:"line" example:"3"`
+	Error string `json:"error" example:"price: failed on the 'gte' tag"`
+}
+
+// NewProductImportRowError describe the error of the row, the failed fields of a validation error
+// are named after their columns
+func NewProductImportRowError(line int, err error) *ProductImportRowError {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return &ProductImportRowError{Line: line, Error: err.Error()}
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		messages = append(messages, fmt.Sprintf("%s: failed on the '%s' tag", toSnakeCase(validationError.Field()), validationError.Tag()))
+	}
+
+	return &ProductImportRowError{Line: line, Error: strings.Join(messages, "; ")}
+}
+
+// ProductImportResult the outcome of the import, on a dry run Created & Updated count the rows which would be stored
+type ProductImportResult struct {
+	DryRun  bool                     `json:"dry_run"`
+	Total   int                      `json:"total" example:"120"`
+	Created int                      `json:"created" example:"100"`
+	Updated int                      `json:"updated" example:"18"`
+	Errors  []*ProductImportRowError `json:"errors"`
+}
+
+// ProductImport a product of the imported file to be stored, a new product is created along with its initial stock
+// while an existing product is updated keeping its stock & cost price
+type ProductImport struct {
+	Line    int
+	Product *Product
+	IsNew   bool
+}
+
+// toSnakeCase turn the field name into its column name, e.g. CostPrice into cost_price and CategoryID into category_id
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+
+	return sb.String()
+}
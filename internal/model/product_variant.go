@@ -0,0 +1,206 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// product variant errors
+var (
+	ErrUnknownProductVariant   = errors.New("unknown product variant")
+	ErrProductVariantRequired  = errors.New("product has variants, the variant must be chosen")
+	ErrInvalidProductVariant   = errors.New("product variant options do not match the product options")
+	ErrDuplicateProductVariant = errors.New("product variant options or sku already exist")
+)
+
+// ProductOption an option group of the product variants, such as size, color or temperature
+type ProductOption struct {
+	Name   string   `json:"name" validate:"required,max=30" example:"Ukuran"`
+	Values []string `json:"values" validate:"required,min=1,max=20,dive,required,max=30" example:"S,M,L"`
+}
+
+// HasValue check if the value is one of the option values
+func (p ProductOption) HasValue(value string) bool {
+	for _, v := range p.Values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProductOptions the option groups of a product, ordered as they are displayed
+type ProductOptions []ProductOption
+
+// VariantName join the chosen option values on the option groups order, e.g. "L / Dingin"
+func (po ProductOptions) VariantName(options map[string]string) string {
+	values := make([]string, 0, len(po))
+	for _, option := range po {
+		if value, ok := options[option.Name]; ok {
+			values = append(values, value)
+		}
+	}
+
+	return strings.Join(values, " / ")
+}
+
+// Validate make sure the option group names are unique and every group has unique values
+func (po ProductOptions) Validate() error {
+	names := make(map[string]bool, len(po))
+	for _, option := range po {
+		name := strings.ToLower(option.Name)
+		if names[name] {
+			return ErrInvalidProductVariant
+		}
+		names[name] = true
+
+		values := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			value = strings.ToLower(value)
+			if values[value] {
+				return ErrInvalidProductVariant
+			}
+			values[value] = true
+		}
+	}
+
+	return nil
+}
+
+// Matches check the options choose exactly one of the values of every option group
+func (po ProductOptions) Matches(options map[string]string) bool {
+	if len(options) != len(po) {
+		return false
+	}
+
+	for _, option := range po {
+		value, ok := options[option.Name]
+		if !ok || !option.HasValue(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ProductVariant a sellable combination of the product options with its own sku, barcode, price & stock
+type ProductVariant struct {
+	ID        int64             `json:"id" gorm:"primary_key"`
+	ProductID int64             `json:"product_id"`
+	Name      string            `json:"name"`
+	SKU       string            `json:"sku"`
+	Barcode   string            `json:"barcode"`
+	Options   map[string]string `json:"options" gorm:"serializer:json"`
+	Price     int64             `json:"price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Quantity  int64             `json:"quantity"`
+	CreatedAt time.Time         `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+	UpdatedAt time.Time         `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
+	DeletedAt gorm.DeletedAt    `json:"deleted_at"`
+}
+
+// HasCode check if the code is the sku or barcode of the variant
+func (p ProductVariant) HasCode(code string) bool {
+	return code != "" && (p.SKU == code || p.Barcode == code)
+}
+
+// ProductVariantRepository repository
+type ProductVariantRepository interface {
+	FindByID(ctx context.Context, id int64) (*ProductVariant, error)
+	// FindByCode find the variant with the sku or barcode
+	FindByCode(ctx context.Context, code string) (*ProductVariant, error)
+	// FindByProductID find the variants of the product ordered by the creation time
+	FindByProductID(ctx context.Context, productID int64) ([]*ProductVariant, error)
+	// ReplaceByProductID store the variants of the product within the given db transaction,
+	// the stored variants missing from the list are soft deleted and returned so their caches can be deleted
+	ReplaceByProductID(ctx context.Context, tx *gorm.DB, productID int64, variants []*ProductVariant) (removed []*ProductVariant, err error)
+	// DecreaseStockByID decrease the stock within the given db transaction,
+	// return ErrInsufficientStock when the remaining stock is less than quantity
+	DecreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error
+	// IncreaseStockByID return the stock within the given db transaction
+	IncreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error
+	// DeleteCachesByProductID delete the caches of the product variant list and the given variants
+	DeleteCachesByProductID(productID int64, variants []*ProductVariant) error
+	// DeleteCachesByIDs delete the variant caches, used after the stock is changed outside this repository
+	DeleteCachesByIDs(ids []int64) error
+}
+
+// ProductVariantInput a variant of the product input
+type ProductVariantInput struct {
+	// ID the existing variant on update, empty for a new variant
+	ID int64 `json:"id" validate:"gte=0" example:"1695599921375543118"`
+	// Options the chosen value of every option group, keyed by the option group name
+	Options map[string]string `json:"options" validate:"required"`
+	// SKU generated from the product & variant name when empty
+	SKU     string `json:"sku" validate:"max=64" example:"ES-TEH-L"`
+	Barcode string `json:"barcode" validate:"omitempty,barcode" example:"8991234567891"`
+	Price   int64  `json:"price" validate:"gte=0" example:"8000"`
+}
+
+// CreateProductVariantInput a variant of the new product input along with its initial stock
+type CreateProductVariantInput struct {
+	ProductVariantInput
+	Quantity int64 `json:"quantity" validate:"gte=0" example:"10"`
+}
+
+// ProductVariantKey the product & variant of a sold line, VariantID is zero for a product without variants
+type ProductVariantKey struct {
+	ProductID int64
+	VariantID int64
+}
+
+// Less order the keys by product then variant, so the stock rows are always locked in the same order
+func (k ProductVariantKey) Less(other ProductVariantKey) bool {
+	if k.ProductID != other.ProductID {
+		return k.ProductID < other.ProductID
+	}
+
+	return k.VariantID < other.VariantID
+}
+
+type ProductVariantResponse struct {
+	ID       string            `json:"id" example:"1695599921375543118"`
+	Name     string            `json:"name" example:"L / Dingin"`
+	SKU      string            `json:"sku" example:"ES-TEH-L-DINGIN"`
+	Barcode  string            `json:"barcode" example:"8991234567891"`
+	Options  map[string]string `json:"options"`
+	Price    string            `json:"price" example:"Rp8.000"`
+	Quantity string            `json:"quantity" example:"10"`
+}
+
+func (p ProductVariant) ToProductVariantResponse() ProductVariantResponse {
+	return ProductVariantResponse{
+		ID:       utils.Int64ToString(p.ID),
+		Name:     p.Name,
+		SKU:      p.SKU,
+		Barcode:  p.Barcode,
+		Options:  p.Options,
+		Price:    utils.Int64ToRupiah(p.Price),
+		Quantity: utils.Int64ToString(p.Quantity),
+	}
+}
+
+type AnyProductVariants []*ProductVariant
+
+// FindByID find the variant with the id, return nil when the variant is not in the list
+func (av AnyProductVariants) FindByID(id int64) *ProductVariant {
+	for _, variant := range av {
+		if variant.ID == id {
+			return variant
+		}
+	}
+
+	return nil
+}
+
+func (av AnyProductVariants) ToListProductVariantResponse() (responses []ProductVariantResponse) {
+	for _, variant := range av {
+		responses = append(responses, variant.ToProductVariantResponse())
+	}
+
+	return responses
+}
@@ -0,0 +1,168 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"time"
+)
+
+// ErrUnknownProfitReportGroup error when the profit report is grouped by an unsupported dimension
+var ErrUnknownProfitReportGroup = errors.New("unknown profit report group")
+
+// ProfitReportGroup the dimension the profit report is grouped by
+type ProfitReportGroup string
+
+// ProfitReportGroup constants
+const (
+	ProfitReportGroupProduct ProfitReportGroup = "PRODUCT"
+	// ProfitReportGroupCategory group by the current category of the sold products
+	ProfitReportGroupCategory ProfitReportGroup = "CATEGORY"
+	ProfitReportGroupCashier  ProfitReportGroup = "CASHIER"
+	// ProfitReportGroupDay group by the day of the sale in western indonesian time
+	ProfitReportGroupDay ProfitReportGroup = "DAY"
+)
+
+// IsValid check if the profit report group is supported
+func (g ProfitReportGroup) IsValid() bool {
+	switch g {
+	case ProfitReportGroupProduct, ProfitReportGroupCategory, ProfitReportGroupCashier, ProfitReportGroupDay:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProfitReport the gross profit of the sales made in a period, grouped by GroupBy.
+// The period starts inclusive at PeriodStart and ends exclusive at PeriodEnd,
+// a zero PeriodStart means the period starts from the very first sale.
+// The sales whose unit cost is unknown, which were made before the cost was snapshotted, are left out.
+type ProfitReport struct {
+	GroupBy     ProfitReportGroup `json:"group_by"`
+	PeriodStart time.Time         `json:"period_start"`
+	PeriodEnd   time.Time         `json:"period_end"`
+	Summary     ProfitLine        `json:"summary"`
+	Lines       []*ProfitLine     `json:"lines"`
+}
+
+// Summarize total the lines into the summary
+func (r *ProfitReport) Summarize() {
+	r.Summary = ProfitLine{}
+	for _, line := range r.Lines {
+		r.Summary.Quantity += line.Quantity
+		r.Summary.Revenue += line.Revenue
+		r.Summary.Cost += line.Cost
+	}
+}
+
+// ProfitLine the sales of a group after the refunds. The Revenue is the share of the sold lines in the net sales,
+// which is the total price without the tax & service charge, so the cart & manual discounts are prorated to the lines.
+// The Cost is the quantity times the unit cost snapshot of the sold lines.
+// Key is the id of the product, category or cashier, or the date of the day.
+type ProfitLine struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Quantity int64  `json:"quantity"`
+	Revenue  int64  `json:"revenue"`
+	Cost     int64  `json:"cost"`
+}
+
+// GrossProfit the revenue deducted by the cost
+func (l ProfitLine) GrossProfit() int64 {
+	return l.Revenue - l.Cost
+}
+
+// Margin the gross profit in percent of the revenue, zero when there is no revenue
+func (l ProfitLine) Margin() float64 {
+	if l.Revenue == 0 {
+		return 0
+	}
+
+	return float64(l.GrossProfit()) * 100 / float64(l.Revenue)
+}
+
+// ProfitReportCriteria criteria of the profit report, the dates are on western indonesian time
+type ProfitReportCriteria struct {
+	GroupBy   ProfitReportGroup `json:"group_by" query:"groupBy" example:"PRODUCT"`
+	StartDate string            `json:"start_date" query:"startDate" example:"2023-09-01"`
+	EndDate   string            `json:"end_date" query:"endDate" example:"2023-09-30"`
+}
+
+// SetDefaultValue will set the group by product if empty
+func (c *ProfitReportCriteria) SetDefaultValue() {
+	if c.GroupBy == "" {
+		c.GroupBy = ProfitReportGroupProduct
+	}
+}
+
+// Validate validate the group and the date range of the criteria
+func (c *ProfitReportCriteria) Validate() error {
+	if !c.GroupBy.IsValid() {
+		return ErrUnknownProfitReportGroup
+	}
+
+	startAt, endAt, err := c.DateRange()
+	if err != nil {
+		return err
+	}
+
+	if startAt != nil && endAt != nil && endAt.Before(*startAt) {
+		return ErrInvalidDateRange
+	}
+
+	return nil
+}
+
+// DateRange parse StartDate & EndDate the same way as TransactionSearchCriteria.DateRange
+func (c *ProfitReportCriteria) DateRange() (startAt, endAt *time.Time, err error) {
+	criteria := TransactionSearchCriteria{StartDate: c.StartDate, EndDate: c.EndDate}
+	return criteria.DateRange()
+}
+
+type ProfitLineResponse struct {
+	Key         string `json:"key" example:"1695599921375543118"`
+	Name        string `json:"name" example:"Indomie Goreng"`
+	Quantity    string `json:"quantity" example:"40"`
+	Revenue     string `json:"revenue" example:"Rp130.000"`
+	Cost        string `json:"cost" example:"Rp100.000"`
+	GrossProfit string `json:"gross_profit" example:"Rp30.000"`
+	Margin      string `json:"margin" example:"23.08%"`
+}
+
+func (l ProfitLine) ToProfitLineResponse() ProfitLineResponse {
+	return ProfitLineResponse{
+		Key:         l.Key,
+		Name:        l.Name,
+		Quantity:    utils.Int64ToString(l.Quantity),
+		Revenue:     utils.Int64ToRupiah(l.Revenue),
+		Cost:        utils.Int64ToRupiah(l.Cost),
+		GrossProfit: utils.Int64ToRupiah(l.GrossProfit()),
+		Margin:      fmt.Sprintf("%.2f%%", l.Margin()),
+	}
+}
+
+type ProfitReportResponse struct {
+	GroupBy     string               `json:"group_by" example:"PRODUCT"`
+	PeriodStart string               `json:"period_start" example:"1 September 2023 00:00 WIB"`
+	PeriodEnd   string               `json:"period_end" example:"1 October 2023 00:00 WIB"`
+	Summary     ProfitLineResponse   `json:"summary"`
+	Lines       []ProfitLineResponse `json:"lines"`
+}
+
+func (r ProfitReport) ToProfitReportResponse() ProfitReportResponse {
+	response := ProfitReportResponse{
+		GroupBy:   string(r.GroupBy),
+		PeriodEnd: utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &r.PeriodEnd),
+		Summary:   r.Summary.ToProfitLineResponse(),
+		Lines:     make([]ProfitLineResponse, 0, len(r.Lines)),
+	}
+	if !r.PeriodStart.IsZero() {
+		response.PeriodStart = utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &r.PeriodStart)
+	}
+
+	for _, line := range r.Lines {
+		response.Lines = append(response.Lines, line.ToProfitLineResponse())
+	}
+
+	return response
+}
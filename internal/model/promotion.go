@@ -0,0 +1,352 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+// promotion errors
+var (
+	ErrInvalidPromotionPeriod = errors.New("invalid promotion period")
+	ErrInvalidPromotionRule   = errors.New("invalid promotion rule")
+)
+
+// DailyTimeLayout layout of the daily window of a promotion, e.g. happy hour
+const DailyTimeLayout = "15:04"
+
+// PromotionType type of promotion
+type PromotionType string
+
+// PromotionType constants
+const (
+	// PromotionTypeLinePercentage percentage off the line of the product
+	PromotionTypeLinePercentage PromotionType = "LINE_PERCENTAGE"
+	// PromotionTypeLineFixed fixed amount off every unit of the product
+	PromotionTypeLineFixed PromotionType = "LINE_FIXED"
+	// PromotionTypeCartPercentage percentage off the whole cart
+	PromotionTypeCartPercentage PromotionType = "CART_PERCENTAGE"
+	// PromotionTypeCartFixed fixed amount off the whole cart
+	PromotionTypeCartFixed PromotionType = "CART_FIXED"
+	// PromotionTypeBuyXGetY every BuyQuantity units of the product get GetQuantity units for free
+	PromotionTypeBuyXGetY PromotionType = "BUY_X_GET_Y"
+)
+
+// Promotion model
+type Promotion struct {
+	ID             int64          `json:"id" gorm:"primary_key"`
+	Name           string         `json:"name"`
+	Type           PromotionType  `json:"type"`
+	ProductID      int64          `json:"product_id"`
+	Value          int64          `json:"value" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	BuyQuantity    int64          `json:"buy_quantity"`
+	GetQuantity    int64          `json:"get_quantity"`
+	MinimumSpend   int64          `json:"minimum_spend" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	StartAt        time.Time      `json:"start_at"`
+	EndAt          time.Time      `json:"end_at"`
+	DailyStartTime string         `json:"daily_start_time"`
+	DailyEndTime   string         `json:"daily_end_time"`
+	IsActive       bool           `json:"is_active"`
+	CreatedAt      time.Time      `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+	UpdatedAt      time.Time      `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at"`
+}
+
+// PromotionRepository repository
+type PromotionRepository interface {
+	FindByID(ctx context.Context, id int64) (*Promotion, error)
+	SearchByPage(ctx context.Context, criteria PromotionSearchCriteria) (ids []int64, count int64, err error)
+	// FindAllActive find all promotions flagged as active, the period is evaluated by the caller
+	FindAllActive(ctx context.Context) ([]*Promotion, error)
+	Create(ctx context.Context, userID int64, promotion *Promotion) error
+	Update(ctx context.Context, userID int64, promotion *Promotion) error
+	Delete(ctx context.Context, userID int64, promotion *Promotion) error
+}
+
+// PromotionUsecase usecase
+type PromotionUsecase interface {
+	FindByID(ctx context.Context, requester *User, id int64) (*Promotion, error)
+	Search(ctx context.Context, requester *User, criteria PromotionSearchCriteria) (promotions AnyPromotions, count int64, err error)
+	Create(ctx context.Context, requester *User, input CreatePromotionInput) (*Promotion, error)
+	UpdateByID(ctx context.Context, requester *User, id int64, input UpdatePromotionInput) (*Promotion, error)
+	DeleteByID(ctx context.Context, requester *User, id int64) error
+}
+
+// IsLinePromotion check if the promotion discounts a product line instead of the whole cart
+func (p *Promotion) IsLinePromotion() bool {
+	switch p.Type {
+	case PromotionTypeLinePercentage, PromotionTypeLineFixed, PromotionTypeBuyXGetY:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsApplicableAt check if the promotion is active at the given time,
+// the daily window is evaluated on western indonesian time and may cross midnight
+func (p *Promotion) IsApplicableAt(t time.Time) bool {
+	if !p.IsActive || t.Before(p.StartAt) || !t.Before(p.EndAt) {
+		return false
+	}
+
+	if p.DailyStartTime == "" || p.DailyEndTime == "" {
+		return true
+	}
+
+	clock := t.In(utils.WesternIndonesianLocation()).Format(DailyTimeLayout)
+	if p.DailyStartTime <= p.DailyEndTime {
+		return clock >= p.DailyStartTime && clock < p.DailyEndTime
+	}
+
+	return clock >= p.DailyStartTime || clock < p.DailyEndTime
+}
+
+// LineDiscount the discount of a line promotion for the given detail
+func (p *Promotion) LineDiscount(detail *TransactionDetail) int64 {
+	if !p.IsLinePromotion() || p.ProductID != detail.ProductID {
+		return 0
+	}
+
+	gross := detail.UnitPrice * detail.Quantity
+	switch p.Type {
+	case PromotionTypeLinePercentage:
+		return gross * p.Value / 100
+	case PromotionTypeLineFixed:
+		return minInt64(p.Value, detail.UnitPrice) * detail.Quantity
+	case PromotionTypeBuyXGetY:
+		bundle := p.BuyQuantity + p.GetQuantity
+		if bundle <= 0 {
+			return 0
+		}
+		return detail.Quantity / bundle * p.GetQuantity * detail.UnitPrice
+	default:
+		return 0
+	}
+}
+
+// CartDiscount the discount of a cart promotion for the given subtotal
+func (p *Promotion) CartDiscount(subtotal int64) int64 {
+	switch p.Type {
+	case PromotionTypeCartPercentage:
+		return subtotal * p.Value / 100
+	case PromotionTypeCartFixed:
+		return minInt64(p.Value, subtotal)
+	default:
+		return 0
+	}
+}
+
+type AnyPromotions []*Promotion
+
+// Apply evaluate the promotions against the details at the given time.
+// Each line gets the best line promotion of its product, then the cart gets the best cart promotion,
+// promotions are never stacked. The discount & subtotal of the details are updated in place.
+func (ap AnyPromotions) Apply(details []*TransactionDetail, at time.Time) (cartDiscount int64, applied []*TransactionPromotion) {
+	var gross int64
+	for _, detail := range details {
+		gross += detail.UnitPrice * detail.Quantity
+	}
+
+	var applicable []*Promotion
+	for _, promotion := range ap {
+		if promotion.IsApplicableAt(at) && gross >= promotion.MinimumSpend {
+			applicable = append(applicable, promotion)
+		}
+	}
+
+	var subtotal int64
+	for _, detail := range details {
+		var best *Promotion
+		var bestDiscount int64
+		for _, promotion := range applicable {
+			if discount := promotion.LineDiscount(detail); discount > bestDiscount {
+				best, bestDiscount = promotion, discount
+			}
+		}
+
+		detail.Discount = bestDiscount
+		detail.Subtotal = detail.UnitPrice*detail.Quantity - bestDiscount
+		subtotal += detail.Subtotal
+
+		if best != nil {
+			applied = append(applied, &TransactionPromotion{
+				TransactionID: detail.TransactionID,
+				PromotionID:   best.ID,
+				PromotionName: best.Name,
+				ProductID:     detail.ProductID,
+				Discount:      bestDiscount,
+			})
+		}
+	}
+
+	var best *Promotion
+	for _, promotion := range applicable {
+		if discount := promotion.CartDiscount(subtotal); discount > cartDiscount {
+			best, cartDiscount = promotion, discount
+		}
+	}
+
+	if best != nil {
+		var transactionID int64
+		if len(details) > 0 {
+			transactionID = details[0].TransactionID
+		}
+
+		applied = append(applied, &TransactionPromotion{
+			TransactionID: transactionID,
+			PromotionID:   best.ID,
+			PromotionName: best.Name,
+			Discount:      cartDiscount,
+		})
+	}
+
+	return cartDiscount, applied
+}
+
+// CreatePromotionInput create promotion input
+type CreatePromotionInput struct {
+	Name           string        `json:"name" validate:"required,min=3,max=60" example:"Happy Hour Pisang Goreng"`
+	Type           PromotionType `json:"type" validate:"required,oneof=LINE_PERCENTAGE LINE_FIXED CART_PERCENTAGE CART_FIXED BUY_X_GET_Y" example:"LINE_PERCENTAGE"`
+	ProductID      int64         `json:"product_id" validate:"gte=0" example:"1695599921375543118"`
+	Value          int64         `json:"value" validate:"gte=0" example:"10"`
+	BuyQuantity    int64         `json:"buy_quantity" validate:"gte=0" example:"0"`
+	GetQuantity    int64         `json:"get_quantity" validate:"gte=0" example:"0"`
+	MinimumSpend   int64         `json:"minimum_spend" validate:"gte=0" example:"0"`
+	StartAt        time.Time     `json:"start_at" validate:"required" example:"2023-09-25T00:00:00+07:00"`
+	EndAt          time.Time     `json:"end_at" validate:"required" example:"2023-10-25T00:00:00+07:00"`
+	DailyStartTime string        `json:"daily_start_time" example:"15:00"`
+	DailyEndTime   string        `json:"daily_end_time" example:"17:00"`
+	IsActive       bool          `json:"is_active" example:"true"`
+}
+
+type UpdatePromotionInput = CreatePromotionInput
+
+// ValidateAndFormat validate promotion input and format the daily window
+func (c *CreatePromotionInput) ValidateAndFormat() error {
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+
+	if !c.EndAt.After(c.StartAt) {
+		return ErrInvalidPromotionPeriod
+	}
+
+	if (c.DailyStartTime == "") != (c.DailyEndTime == "") {
+		return ErrInvalidPromotionPeriod
+	}
+
+	// the daily window is compared as a string, so it is formatted zero padded, e.g. 9:00 as 09:00
+	if c.DailyStartTime != "" {
+		start, err := time.Parse(DailyTimeLayout, c.DailyStartTime)
+		if err != nil {
+			return ErrInvalidPromotionPeriod
+		}
+		end, err := time.Parse(DailyTimeLayout, c.DailyEndTime)
+		if err != nil {
+			return ErrInvalidPromotionPeriod
+		}
+		c.DailyStartTime = start.Format(DailyTimeLayout)
+		c.DailyEndTime = end.Format(DailyTimeLayout)
+	}
+
+	switch c.Type {
+	case PromotionTypeLinePercentage:
+		if c.ProductID <= 0 || c.Value <= 0 || c.Value > 100 {
+			return ErrInvalidPromotionRule
+		}
+	case PromotionTypeLineFixed:
+		if c.ProductID <= 0 || c.Value <= 0 {
+			return ErrInvalidPromotionRule
+		}
+	case PromotionTypeCartPercentage:
+		if c.Value <= 0 || c.Value > 100 {
+			return ErrInvalidPromotionRule
+		}
+	case PromotionTypeCartFixed:
+		if c.Value <= 0 {
+			return ErrInvalidPromotionRule
+		}
+	case PromotionTypeBuyXGetY:
+		if c.ProductID <= 0 || c.BuyQuantity <= 0 || c.GetQuantity <= 0 {
+			return ErrInvalidPromotionRule
+		}
+	}
+
+	return nil
+}
+
+// PromotionSearchCriteria criteria for searching promotion
+type PromotionSearchCriteria struct {
+	Page int `json:"page" query:"page"`
+	Size int `json:"size" query:"size"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *PromotionSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+}
+
+type PromotionResponse struct {
+	ID             string        `json:"id" example:"1695599921375543118"`
+	Name           string        `json:"name" example:"Happy Hour Pisang Goreng"`
+	Type           PromotionType `json:"type" example:"LINE_PERCENTAGE"`
+	ProductID      string        `json:"product_id" example:"1695599921375543118"`
+	Value          string        `json:"value" example:"10"`
+	BuyQuantity    string        `json:"buy_quantity" example:"0"`
+	GetQuantity    string        `json:"get_quantity" example:"0"`
+	MinimumSpend   string        `json:"minimum_spend" example:"Rp0"`
+	StartAt        string        `json:"start_at" example:"25 September 2023 00:00 WIB"`
+	EndAt          string        `json:"end_at" example:"25 Oktober 2023 00:00 WIB"`
+	DailyStartTime string        `json:"daily_start_time" example:"15:00"`
+	DailyEndTime   string        `json:"daily_end_time" example:"17:00"`
+	IsActive       bool          `json:"is_active" example:"true"`
+	CreatedAt      string        `json:"created_at" example:"25 September 2023 13:59 WIB"`
+	UpdatedAt      string        `json:"updated_at" example:"25 September 2023 13:59 WIB"`
+}
+
+func (p Promotion) ToPromotionResponse() PromotionResponse {
+	return PromotionResponse{
+		ID:             utils.Int64ToString(p.ID),
+		Name:           p.Name,
+		Type:           p.Type,
+		ProductID:      utils.Int64ToString(p.ProductID),
+		Value:          utils.Int64ToString(p.Value),
+		BuyQuantity:    utils.Int64ToString(p.BuyQuantity),
+		GetQuantity:    utils.Int64ToString(p.GetQuantity),
+		MinimumSpend:   utils.Int64ToRupiah(p.MinimumSpend),
+		StartAt:        utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.StartAt),
+		EndAt:          utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.EndAt),
+		DailyStartTime: p.DailyStartTime,
+		DailyEndTime:   p.DailyEndTime,
+		IsActive:       p.IsActive,
+		CreatedAt:      utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.CreatedAt),
+		UpdatedAt:      utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.UpdatedAt),
+	}
+}
+
+func (ap AnyPromotions) ToListPromotionResponse() (promotionResponses []PromotionResponse) {
+	for _, promotion := range ap {
+		promotionResponses = append(promotionResponses, promotion.ToPromotionResponse())
+	}
+
+	return promotionResponses
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
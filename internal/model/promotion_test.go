@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePromotionInput_ValidateAndFormat(t *testing.T) {
+	loc := utils.WesternIndonesianLocation()
+	newInput := func(start, end string) *CreatePromotionInput {
+		return &CreatePromotionInput{
+			Name:           "Happy Hour",
+			Type:           PromotionTypeCartPercentage,
+			Value:          10,
+			StartAt:        time.Date(2023, 9, 1, 0, 0, 0, 0, loc),
+			EndAt:          time.Date(2023, 10, 1, 0, 0, 0, 0, loc),
+			DailyStartTime: start,
+			DailyEndTime:   end,
+			IsActive:       true,
+		}
+	}
+
+	t.Run("ok - unpadded hour is normalized", func(t *testing.T) {
+		input := newInput("9:00", "17:00")
+		require.NoError(t, input.ValidateAndFormat())
+		assert.Equal(t, "09:00", input.DailyStartTime)
+		assert.Equal(t, "17:00", input.DailyEndTime)
+
+		promotion := &Promotion{
+			IsActive:       input.IsActive,
+			StartAt:        input.StartAt,
+			EndAt:          input.EndAt,
+			DailyStartTime: input.DailyStartTime,
+			DailyEndTime:   input.DailyEndTime,
+		}
+		assert.True(t, promotion.IsApplicableAt(time.Date(2023, 9, 10, 10, 0, 0, 0, loc)))
+		assert.False(t, promotion.IsApplicableAt(time.Date(2023, 9, 10, 20, 0, 0, 0, loc)))
+	})
+
+	t.Run("ok - window crossing midnight", func(t *testing.T) {
+		input := newInput("22:00", "2:00")
+		require.NoError(t, input.ValidateAndFormat())
+		assert.Equal(t, "02:00", input.DailyEndTime)
+	})
+
+	t.Run("failed - invalid time", func(t *testing.T) {
+		input := newInput("25:00", "17:00")
+		require.ErrorIs(t, input.ValidateAndFormat(), ErrInvalidPromotionPeriod)
+	})
+}
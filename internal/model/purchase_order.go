@@ -0,0 +1,459 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"sort"
+	"time"
+)
+
+// purchase order errors
+var (
+	ErrInvalidPurchaseOrderStatus = errors.New("purchase order status does not allow the action")
+	ErrUnknownPurchaseOrderItem   = errors.New("unknown purchase order item")
+	ErrPurchaseOrderOverReceived  = errors.New("received quantity exceeds the remaining ordered quantity")
+)
+
+// PurchaseOrderStatus status of a purchase order
+type PurchaseOrderStatus string
+
+// PurchaseOrderStatus constants
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "DRAFT"
+	PurchaseOrderStatusSent              PurchaseOrderStatus = "SENT"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "PARTIALLY_RECEIVED"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "RECEIVED"
+	PurchaseOrderStatusClosed            PurchaseOrderStatus = "CLOSED"
+)
+
+// purchaseOrderTransitions the statuses a purchase order may move to from its current status,
+// a purchase order which is not fully received can be closed to give up the remaining items
+var purchaseOrderTransitions = map[PurchaseOrderStatus][]PurchaseOrderStatus{
+	PurchaseOrderStatusDraft:             {PurchaseOrderStatusSent},
+	PurchaseOrderStatusSent:              {PurchaseOrderStatusPartiallyReceived, PurchaseOrderStatusReceived, PurchaseOrderStatusClosed},
+	PurchaseOrderStatusPartiallyReceived: {PurchaseOrderStatusPartiallyReceived, PurchaseOrderStatusReceived, PurchaseOrderStatusClosed},
+	PurchaseOrderStatusReceived:          {PurchaseOrderStatusClosed},
+}
+
+// CanTransitionTo check if a purchase order of the status may move to the next status
+func (s PurchaseOrderStatus) CanTransitionTo(next PurchaseOrderStatus) bool {
+	for _, status := range purchaseOrderTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanReceive check if the goods of a purchase order of the status can be received
+func (s PurchaseOrderStatus) CanReceive() bool {
+	return s == PurchaseOrderStatusSent || s == PurchaseOrderStatusPartiallyReceived
+}
+
+// StockReferenceGoodsReceipt the goods receipt of a receipt movement
+const StockReferenceGoodsReceipt = "goods_receipt"
+
+// PurchaseOrder an order of products to a supplier, the items are only editable while it is a draft.
+// TotalCost is the ordered quantity times the cost price of every item.
+type PurchaseOrder struct {
+	ID         int64               `json:"id"`
+	SupplierID int64               `json:"supplier_id"`
+	Status     PurchaseOrderStatus `json:"status"`
+	Note       string              `json:"note"`
+	TotalCost  int64               `json:"total_cost" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	CreatedBy  int64               `json:"created_by" gorm:"->;<-:create"` // create & read only
+	SentAt     *time.Time          `json:"sent_at"`
+	ClosedAt   *time.Time          `json:"closed_at"`
+	CreatedAt  time.Time           `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+	UpdatedAt  time.Time           `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
+
+	Items    []*PurchaseOrderItem `json:"items" gorm:"-"`
+	Receipts []*GoodsReceipt      `json:"receipts" gorm:"-"`
+}
+
+// IsDraft check if the purchase order can still be edited
+func (p PurchaseOrder) IsDraft() bool {
+	return p.Status == PurchaseOrderStatusDraft
+}
+
+// CalculateTotalCost sum the cost of the ordered items
+func (p *PurchaseOrder) CalculateTotalCost() {
+	p.TotalCost = 0
+	for _, item := range p.Items {
+		p.TotalCost += item.Quantity * item.CostPrice
+	}
+}
+
+// IsFullyReceived check if every item is received in full
+func (p PurchaseOrder) IsFullyReceived() bool {
+	for _, item := range p.Items {
+		if item.RemainingQuantity() > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Receive add the receipt quantities to the received quantity of the items and move the purchase order
+// to partially received or received. The receipt items are completed with the product, variant & cost price
+// of their purchase order item. Return ErrPurchaseOrderOverReceived when more than the remaining quantity is received.
+func (p *PurchaseOrder) Receive(receipt *GoodsReceipt) error {
+	if !p.Status.CanReceive() {
+		return ErrInvalidPurchaseOrderStatus
+	}
+
+	for _, receiptItem := range receipt.Items {
+		item := AnyPurchaseOrderItems(p.Items).FindByID(receiptItem.PurchaseOrderItemID)
+		if item == nil {
+			return ErrUnknownPurchaseOrderItem
+		}
+		if receiptItem.Quantity > item.RemainingQuantity() {
+			return ErrPurchaseOrderOverReceived
+		}
+
+		item.ReceivedQuantity += receiptItem.Quantity
+		receiptItem.ProductID = item.ProductID
+		receiptItem.VariantID = item.VariantID
+		receiptItem.CostPrice = item.CostPrice
+	}
+
+	p.Status = PurchaseOrderStatusPartiallyReceived
+	if p.IsFullyReceived() {
+		p.Status = PurchaseOrderStatusReceived
+	}
+
+	return nil
+}
+
+// PurchaseOrderItem an ordered product or variant along with its cost price, VariantID is zero for a product without variants
+type PurchaseOrderItem struct {
+	ID               int64 `json:"id"`
+	PurchaseOrderID  int64 `json:"purchase_order_id"`
+	ProductID        int64 `json:"product_id"`
+	VariantID        int64 `json:"variant_id"`
+	Quantity         int64 `json:"quantity"`
+	ReceivedQuantity int64 `json:"received_quantity"`
+	CostPrice        int64 `json:"cost_price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+}
+
+// RemainingQuantity the quantity which is not received yet
+func (p PurchaseOrderItem) RemainingQuantity() int64 {
+	return p.Quantity - p.ReceivedQuantity
+}
+
+type AnyPurchaseOrderItems []*PurchaseOrderItem
+
+// FindByID find the item by id, return nil when it is not an item of the purchase order
+func (ai AnyPurchaseOrderItems) FindByID(id int64) *PurchaseOrderItem {
+	for _, item := range ai {
+		if item.ID == id {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// GoodsReceipt the goods received for a purchase order, a purchase order may be received over many receipts
+type GoodsReceipt struct {
+	ID              int64     `json:"id"`
+	PurchaseOrderID int64     `json:"purchase_order_id"`
+	Note            string    `json:"note"`
+	ReceivedBy      int64     `json:"received_by"`
+	CreatedAt       time.Time `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+
+	Items []*GoodsReceiptItem `json:"items" gorm:"-"`
+}
+
+// GoodsReceiptItem the received quantity of a purchase order item, at the cost price of the item
+type GoodsReceiptItem struct {
+	GoodsReceiptID      int64 `json:"goods_receipt_id"`
+	PurchaseOrderItemID int64 `json:"purchase_order_item_id"`
+	ProductID           int64 `json:"product_id"`
+	VariantID           int64 `json:"variant_id"`
+	Quantity            int64 `json:"quantity"`
+	CostPrice           int64 `json:"cost_price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+}
+
+// StockMovements the receipt movements which add the received goods to the stock
+func (g GoodsReceipt) StockMovements() []*StockMovement {
+	movements := make([]*StockMovement, 0, len(g.Items))
+	for _, item := range g.Items {
+		movements = append(movements, &StockMovement{
+			ProductID:     item.ProductID,
+			VariantID:     item.VariantID,
+			Type:          StockMovementTypeReceipt,
+			Quantity:      item.Quantity,
+			ReferenceType: StockReferenceGoodsReceipt,
+			ReferenceID:   g.ID,
+			Note:          g.Note,
+			CreatedBy:     g.ReceivedBy,
+		})
+	}
+
+	return movements
+}
+
+// ReceivedCost the quantity of a product received by a goods receipt and its cost in total
+type ReceivedCost struct {
+	ProductID int64
+	Quantity  int64
+	Amount    int64
+}
+
+// ReceivedCosts the received quantity & cost per product across its variants ordered by the product id,
+// so the products are locked in the same order as the stock movements
+func (g GoodsReceipt) ReceivedCosts() []*ReceivedCost {
+	costs := make(map[int64]*ReceivedCost)
+	for _, item := range g.Items {
+		cost, ok := costs[item.ProductID]
+		if !ok {
+			cost = &ReceivedCost{ProductID: item.ProductID}
+			costs[item.ProductID] = cost
+		}
+		cost.Quantity += item.Quantity
+		cost.Amount += item.Quantity * item.CostPrice
+	}
+
+	receivedCosts := make([]*ReceivedCost, 0, len(costs))
+	for _, cost := range costs {
+		receivedCosts = append(receivedCosts, cost)
+	}
+	sort.Slice(receivedCosts, func(i, j int) bool {
+		return receivedCosts[i].ProductID < receivedCosts[j].ProductID
+	})
+
+	return receivedCosts
+}
+
+// PurchaseOrderRepository repository
+type PurchaseOrderRepository interface {
+	// FindByID find the purchase order along with its items & goods receipts
+	FindByID(ctx context.Context, id int64) (*PurchaseOrder, error)
+	SearchByPage(ctx context.Context, criteria PurchaseOrderSearchCriteria) (ids []int64, count int64, err error)
+	Create(ctx context.Context, userID int64, order *PurchaseOrder) error
+	// Update replace the items of the purchase order, return ErrInvalidPurchaseOrderStatus when it is not a draft
+	Update(ctx context.Context, userID int64, order *PurchaseOrder) error
+	// UpdateStatus move the purchase order to the status,
+	// return ErrInvalidPurchaseOrderStatus when the stored status can not move to the status
+	UpdateStatus(ctx context.Context, userID int64, order *PurchaseOrder, status PurchaseOrderStatus) error
+	// Receive store the goods receipt, add the received goods to the stock and average the cost price
+	// of the received products in a single db transaction
+	Receive(ctx context.Context, userID int64, order *PurchaseOrder, receipt *GoodsReceipt) error
+}
+
+// PurchaseOrderUsecase usecase
+type PurchaseOrderUsecase interface {
+	FindByID(ctx context.Context, requester *User, id int64) (*PurchaseOrder, error)
+	Search(ctx context.Context, requester *User, criteria PurchaseOrderSearchCriteria) (orders AnyPurchaseOrders, count int64, err error)
+	Create(ctx context.Context, requester *User, input CreatePurchaseOrderInput) (*PurchaseOrder, error)
+	UpdateByID(ctx context.Context, requester *User, id int64, input UpdatePurchaseOrderInput) (*PurchaseOrder, error)
+	Send(ctx context.Context, requester *User, id int64) (*PurchaseOrder, error)
+	Receive(ctx context.Context, requester *User, id int64, input ReceivePurchaseOrderInput) (*PurchaseOrder, error)
+	Close(ctx context.Context, requester *User, id int64) (*PurchaseOrder, error)
+}
+
+// CreatePurchaseOrderInput create purchase order input, the purchase order is created as a draft
+type CreatePurchaseOrderInput struct {
+	SupplierID int64                    `json:"supplier_id" validate:"required" example:"1695599921375543118"`
+	Note       string                   `json:"note" validate:"max=200" example:"stok mingguan"`
+	Items      []PurchaseOrderItemInput `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+type UpdatePurchaseOrderInput = CreatePurchaseOrderInput
+
+// PurchaseOrderItemInput the ordered quantity of a product, the variant is required for a product with variants
+type PurchaseOrderItemInput struct {
+	ProductID int64 `json:"product_id" validate:"required" example:"1695599921375543118"`
+	VariantID int64 `json:"variant_id" validate:"gte=0" example:"0"`
+	Quantity  int64 `json:"quantity" validate:"required,gt=0" example:"24"`
+	CostPrice int64 `json:"cost_price" validate:"gte=0" example:"3000"`
+}
+
+// Validate validate purchase order input, a product may only be ordered once per purchase order
+func (c *CreatePurchaseOrderInput) Validate() error {
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+
+	keys := make(map[ProductVariantKey]bool, len(c.Items))
+	for _, item := range c.Items {
+		key := ProductVariantKey{ProductID: item.ProductID, VariantID: item.VariantID}
+		if keys[key] {
+			return ErrDuplicateProductVariant
+		}
+		keys[key] = true
+	}
+
+	return nil
+}
+
+// ReceivePurchaseOrderInput the goods received for the purchase order items, the items which are not received
+// yet can be received later
+type ReceivePurchaseOrderInput struct {
+	Note  string                  `json:"note" validate:"max=200" example:"surat jalan 0042"`
+	Items []GoodsReceiptItemInput `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// GoodsReceiptItemInput the received quantity of a purchase order item
+type GoodsReceiptItemInput struct {
+	PurchaseOrderItemID int64 `json:"purchase_order_item_id" validate:"required" example:"1695599921375543118"`
+	Quantity            int64 `json:"quantity" validate:"required,gt=0" example:"12"`
+}
+
+// Validate validate receive purchase order input, an item may only be received once per receipt
+func (r *ReceivePurchaseOrderInput) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	ids := make(map[int64]bool, len(r.Items))
+	for _, item := range r.Items {
+		if ids[item.PurchaseOrderItemID] {
+			return ErrDuplicateProductVariant
+		}
+		ids[item.PurchaseOrderItemID] = true
+	}
+
+	return nil
+}
+
+// PurchaseOrderSearchCriteria criteria for searching purchase order
+type PurchaseOrderSearchCriteria struct {
+	Page       int                 `json:"page" query:"page"`
+	Size       int                 `json:"size" query:"size"`
+	Status     PurchaseOrderStatus `json:"status" query:"status"`
+	SupplierID int64               `json:"supplier_id" query:"supplierID"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *PurchaseOrderSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+}
+
+type PurchaseOrderItemResponse struct {
+	ID               string `json:"id" example:"1695599921375543118"`
+	ProductID        string `json:"product_id" example:"1695599921375543118"`
+	VariantID        string `json:"variant_id" example:"0"`
+	Quantity         string `json:"quantity" example:"24"`
+	ReceivedQuantity string `json:"received_quantity" example:"12"`
+	CostPrice        string `json:"cost_price" example:"Rp3.000"`
+}
+
+func (p PurchaseOrderItem) ToPurchaseOrderItemResponse() PurchaseOrderItemResponse {
+	return PurchaseOrderItemResponse{
+		ID:               utils.Int64ToString(p.ID),
+		ProductID:        utils.Int64ToString(p.ProductID),
+		VariantID:        utils.Int64ToString(p.VariantID),
+		Quantity:         utils.Int64ToString(p.Quantity),
+		ReceivedQuantity: utils.Int64ToString(p.ReceivedQuantity),
+		CostPrice:        utils.Int64ToRupiah(p.CostPrice),
+	}
+}
+
+type GoodsReceiptItemResponse struct {
+	PurchaseOrderItemID string `json:"purchase_order_item_id" example:"1695599921375543118"`
+	ProductID           string `json:"product_id" example:"1695599921375543118"`
+	VariantID           string `json:"variant_id" example:"0"`
+	Quantity            string `json:"quantity" example:"12"`
+	CostPrice           string `json:"cost_price" example:"Rp3.000"`
+}
+
+type GoodsReceiptResponse struct {
+	ID         string                     `json:"id" example:"1695599921375543118"`
+	Note       string                     `json:"note" example:"surat jalan 0042"`
+	ReceivedBy string                     `json:"received_by" example:"1695599921375543118"`
+	CreatedAt  string                     `json:"created_at" example:"27 September 2023 09:00 WIB"`
+	Items      []GoodsReceiptItemResponse `json:"items"`
+}
+
+func (g GoodsReceipt) ToGoodsReceiptResponse() GoodsReceiptResponse {
+	response := GoodsReceiptResponse{
+		ID:         utils.Int64ToString(g.ID),
+		Note:       g.Note,
+		ReceivedBy: utils.Int64ToString(g.ReceivedBy),
+		CreatedAt:  utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &g.CreatedAt),
+		Items:      make([]GoodsReceiptItemResponse, 0, len(g.Items)),
+	}
+
+	for _, item := range g.Items {
+		response.Items = append(response.Items, GoodsReceiptItemResponse{
+			PurchaseOrderItemID: utils.Int64ToString(item.PurchaseOrderItemID),
+			ProductID:           utils.Int64ToString(item.ProductID),
+			VariantID:           utils.Int64ToString(item.VariantID),
+			Quantity:            utils.Int64ToString(item.Quantity),
+			CostPrice:           utils.Int64ToRupiah(item.CostPrice),
+		})
+	}
+
+	return response
+}
+
+type PurchaseOrderResponse struct {
+	ID         string                      `json:"id" example:"1695599921375543118"`
+	SupplierID string                      `json:"supplier_id" example:"1695599921375543118"`
+	Status     string                      `json:"status" example:"DRAFT"`
+	Note       string                      `json:"note" example:"stok mingguan"`
+	TotalCost  string                      `json:"total_cost" example:"Rp72.000"`
+	CreatedBy  string                      `json:"created_by" example:"1695599921375543118"`
+	SentAt     string                      `json:"sent_at" example:""`
+	ClosedAt   string                      `json:"closed_at" example:""`
+	CreatedAt  string                      `json:"created_at" example:"25 September 2023 13:59 WIB"`
+	UpdatedAt  string                      `json:"updated_at" example:"25 September 2023 13:59 WIB"`
+	Items      []PurchaseOrderItemResponse `json:"items"`
+	Receipts   []GoodsReceiptResponse      `json:"receipts"`
+}
+
+func (p PurchaseOrder) ToPurchaseOrderResponse() PurchaseOrderResponse {
+	response := PurchaseOrderResponse{
+		ID:         utils.Int64ToString(p.ID),
+		SupplierID: utils.Int64ToString(p.SupplierID),
+		Status:     string(p.Status),
+		Note:       p.Note,
+		TotalCost:  utils.Int64ToRupiah(p.TotalCost),
+		CreatedBy:  utils.Int64ToString(p.CreatedBy),
+		CreatedAt:  utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.CreatedAt),
+		UpdatedAt:  utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &p.UpdatedAt),
+		Items:      make([]PurchaseOrderItemResponse, 0, len(p.Items)),
+		Receipts:   make([]GoodsReceiptResponse, 0, len(p.Receipts)),
+	}
+	if p.SentAt != nil {
+		response.SentAt = utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, p.SentAt)
+	}
+	if p.ClosedAt != nil {
+		response.ClosedAt = utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, p.ClosedAt)
+	}
+
+	for _, item := range p.Items {
+		response.Items = append(response.Items, item.ToPurchaseOrderItemResponse())
+	}
+
+	for _, receipt := range p.Receipts {
+		response.Receipts = append(response.Receipts, receipt.ToGoodsReceiptResponse())
+	}
+
+	return response
+}
+
+type AnyPurchaseOrders []*PurchaseOrder
+
+func (ap AnyPurchaseOrders) ToListPurchaseOrderResponse() (responses []PurchaseOrderResponse) {
+	for _, order := range ap {
+		responses = append(responses, order.ToPurchaseOrderResponse())
+	}
+
+	return responses
+}
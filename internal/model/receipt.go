@@ -0,0 +1,45 @@
+package model
+
+import "errors"
+
+// ErrUnknownReceiptFormat error when the requested receipt format is not supported
+var ErrUnknownReceiptFormat = errors.New("unknown receipt format")
+
+// ReceiptFormat output format of a printed receipt
+type ReceiptFormat string
+
+// ReceiptFormat constants, the text & ESC/POS formats are provided for 58mm & 80mm thermal paper
+const (
+	ReceiptFormatText58   ReceiptFormat = "TEXT_58"
+	ReceiptFormatText80   ReceiptFormat = "TEXT_80"
+	ReceiptFormatESCPOS58 ReceiptFormat = "ESCPOS_58"
+	ReceiptFormatESCPOS80 ReceiptFormat = "ESCPOS_80"
+	ReceiptFormatPDF      ReceiptFormat = "PDF"
+)
+
+// IsValid check if the receipt format is supported
+func (f ReceiptFormat) IsValid() bool {
+	switch f {
+	case ReceiptFormatText58, ReceiptFormatText80, ReceiptFormatESCPOS58, ReceiptFormatESCPOS80, ReceiptFormatPDF:
+		return true
+	default:
+		return false
+	}
+}
+
+// Receipt a rendered receipt of a transaction
+type Receipt struct {
+	TransactionID int64
+	Format        ReceiptFormat
+	ContentType   string
+	Filename      string
+	Content       []byte
+	// PrintCount how many times the receipt has been printed including this one, above one is a reprint
+	PrintCount int64
+}
+
+// ReceiptSetting store information printed on the receipt header & footer
+type ReceiptSetting struct {
+	Header []string
+	Footer []string
+}
@@ -0,0 +1,219 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"sort"
+	"time"
+)
+
+// refund errors
+var (
+	ErrRefundQuantityExceeded     = errors.New("refund quantity exceeds the remaining sold quantity")
+	ErrTransactionAlreadyRefunded = errors.New("transaction already has refund")
+)
+
+// RefundType type of refund
+type RefundType string
+
+// RefundType constants
+const (
+	// RefundTypeVoid cancel the whole transaction, used for same shift mistakes
+	RefundTypeVoid RefundType = "VOID"
+	// RefundTypeRefund return some or all of the sold lines
+	RefundTypeRefund RefundType = "REFUND"
+)
+
+// Refund a void or refund of a transaction, the original transaction is never changed
+type Refund struct {
+	ID            int64      `json:"id"`
+	TransactionID int64      `json:"transaction_id"`
+	Type          RefundType `json:"type"`
+	TotalAmount   int64      `json:"total_amount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	ShiftID       int64      `json:"shift_id"`                                                       // the shift whose drawer pays the refund, zero when paid outside of any shift
+	CashAmount    int64      `json:"cash_amount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"` // the part paid back in cash
+	Reason        string     `json:"reason"`
+	ApprovedBy    int64      `json:"approved_by" gorm:"->;<-:create"`                                          // create & read only
+	CreatedAt     time.Time  `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+
+	RefundDetails []*RefundDetail `json:"refund_details" gorm:"-"`
+}
+
+// MergeRefundDetails merge the details of the same product & variant and sort them by product & variant id,
+// so a refund locks the product & variant rows in the same order as the sales
+func (r *Refund) MergeRefundDetails() {
+	merged := make(map[ProductVariantKey]*RefundDetail, len(r.RefundDetails))
+	details := make([]*RefundDetail, 0, len(r.RefundDetails))
+	for _, detail := range r.RefundDetails {
+		if existing, ok := merged[detail.Key()]; ok {
+			existing.Quantity += detail.Quantity
+			existing.Subtotal += detail.Subtotal
+			continue
+		}
+
+		merged[detail.Key()] = detail
+		details = append(details, detail)
+	}
+
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Key().Less(details[j].Key())
+	})
+
+	r.RefundDetails = details
+}
+
+// StockMovements the refund movements of the restocked lines
+func (r Refund) StockMovements(userID int64) []*StockMovement {
+	var movements []*StockMovement
+	for _, detail := range r.RefundDetails {
+		movements = append(movements, &StockMovement{
+			ProductID:     detail.ProductID,
+			VariantID:     detail.VariantID,
+			Type:          StockMovementTypeRefund,
+			Quantity:      detail.Quantity,
+			ReferenceType: StockReferenceRefund,
+			ReferenceID:   r.ID,
+			Note:          r.Reason,
+			CreatedBy:     userID,
+		})
+	}
+
+	return movements
+}
+
+// RefundDetail the refunded quantity of a transaction detail
+type RefundDetail struct {
+	RefundID      int64 `json:"refund_id"`
+	TransactionID int64 `json:"transaction_id"`
+	ProductID     int64 `json:"product_id"`
+	VariantID     int64 `json:"variant_id"`
+	Quantity      int64 `json:"quantity"`
+	Subtotal      int64 `json:"subtotal" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+}
+
+// Key the product & variant of the refunded line
+func (r RefundDetail) Key() ProductVariantKey {
+	return ProductVariantKey{ProductID: r.ProductID, VariantID: r.VariantID}
+}
+
+// RefundRepository repository
+type RefundRepository interface {
+	FindByTransactionID(ctx context.Context, transactionID int64) ([]*Refund, error)
+	// Create store the refund and restock the refunded products,
+	// return ErrRefundQuantityExceeded or ErrTransactionAlreadyRefunded when the refund is not allowed
+	// and ErrShiftClosed when the shift paying the refund is no longer open
+	Create(ctx context.Context, userID int64, refund *Refund) error
+}
+
+// VoidTransactionInput input to void a transaction
+type VoidTransactionInput struct {
+	Reason string `json:"reason" validate:"required,max=255" example:"salah input produk"`
+}
+
+// Validate validate void input
+func (v *VoidTransactionInput) Validate() error {
+	return validate.Struct(v)
+}
+
+// RefundTransactionInput input to refund some lines of a transaction
+type RefundTransactionInput struct {
+	Reason        string              `json:"reason" validate:"required,max=255" example:"produk rusak"`
+	RefundDetails []RefundDetailInput `json:"refund_details" validate:"required,min=1,dive"`
+}
+
+// RefundDetailInput the refunded product and its quantity, VariantID is zero for a product without variants
+type RefundDetailInput struct {
+	ProductID int64 `json:"product_id" validate:"required" example:"1695599921375543118"`
+	VariantID int64 `json:"variant_id" validate:"gte=0" example:"0"`
+	Quantity  int64 `json:"quantity" validate:"gt=0" example:"1"`
+}
+
+// Validate validate refund input
+func (r *RefundTransactionInput) Validate() error {
+	return validate.Struct(r)
+}
+
+type RefundResponse struct {
+	ID            string                 `json:"id" example:"1695599921375543118"`
+	Type          RefundType             `json:"type" example:"REFUND"`
+	TotalAmount   string                 `json:"total_amount" example:"Rp5.000"`
+	CashAmount    string                 `json:"cash_amount" example:"Rp5.000"`
+	Reason        string                 `json:"reason" example:"produk rusak"`
+	ApprovedBy    string                 `json:"approved_by" example:"1695599921375543118"`
+	CreatedAt     string                 `json:"created_at" example:"25 September 2023 13:59 WIB"`
+	RefundDetails []RefundDetailResponse `json:"refund_details"`
+}
+
+type RefundDetailResponse struct {
+	ProductID string `json:"product_id" example:"1695599921375543118"`
+	VariantID string `json:"variant_id" example:"0"`
+	Quantity  string `json:"quantity" example:"1"`
+	Subtotal  string `json:"subtotal" example:"Rp5.000"`
+}
+
+func (r Refund) ToRefundResponse() RefundResponse {
+	var details []RefundDetailResponse
+	for _, detail := range r.RefundDetails {
+		details = append(details, RefundDetailResponse{
+			ProductID: utils.Int64ToString(detail.ProductID),
+			VariantID: utils.Int64ToString(detail.VariantID),
+			Quantity:  utils.Int64ToString(detail.Quantity),
+			Subtotal:  utils.Int64ToRupiah(detail.Subtotal),
+		})
+	}
+
+	return RefundResponse{
+		ID:            utils.Int64ToString(r.ID),
+		Type:          r.Type,
+		TotalAmount:   utils.Int64ToRupiah(r.TotalAmount),
+		CashAmount:    utils.Int64ToRupiah(r.CashAmount),
+		Reason:        r.Reason,
+		ApprovedBy:    utils.Int64ToString(r.ApprovedBy),
+		CreatedAt:     utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &r.CreatedAt),
+		RefundDetails: details,
+	}
+}
+
+type AnyRefunds []*Refund
+
+func (ar AnyRefunds) ToListRefundResponse() (refundResponses []RefundResponse) {
+	for _, refund := range ar {
+		refundResponses = append(refundResponses, refund.ToRefundResponse())
+	}
+
+	return refundResponses
+}
+
+// TotalAmount sum of all refunds amount
+func (ar AnyRefunds) TotalAmount() (total int64) {
+	for _, refund := range ar {
+		total += refund.TotalAmount
+	}
+
+	return total
+}
+
+// CashAmount sum of all refunds amount paid back in cash
+func (ar AnyRefunds) CashAmount() (total int64) {
+	for _, refund := range ar {
+		total += refund.CashAmount
+	}
+
+	return total
+}
+
+// RefundedDetails the refunded quantity & subtotal of every line of the refunds keyed by product & variant
+func (ar AnyRefunds) RefundedDetails() map[ProductVariantKey]RefundDetail {
+	refunded := make(map[ProductVariantKey]RefundDetail)
+	for _, refund := range ar {
+		for _, detail := range refund.RefundDetails {
+			line := refunded[detail.Key()]
+			line.Quantity += detail.Quantity
+			line.Subtotal += detail.Subtotal
+			refunded[detail.Key()] = line
+		}
+	}
+
+	return refunded
+}
@@ -0,0 +1,314 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"time"
+)
+
+// ErrUnknownReportFormat error when the requested report format is not supported
+var ErrUnknownReportFormat = errors.New("unknown report format")
+
+// ReportType type of a sales report
+type ReportType string
+
+// ReportType constants
+const (
+	// ReportTypeX mid-day report, it covers the sales since the last Z report and does not reset anything
+	ReportTypeX ReportType = "X"
+	// ReportTypeZ end of day report, it closes the period since the last Z report, is numbered and immutable
+	ReportTypeZ ReportType = "Z"
+)
+
+// ReportFormat output format of a report
+type ReportFormat string
+
+// ReportFormat constants
+const (
+	ReportFormatJSON ReportFormat = "JSON"
+	ReportFormatCSV  ReportFormat = "CSV"
+)
+
+// IsValid check if the report format is supported
+func (f ReportFormat) IsValid() bool {
+	switch f {
+	case ReportFormatJSON, ReportFormatCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// SalesReport the sales aggregate of a period, only the Z reports are stored.
+// The period starts inclusive at PeriodStart and ends exclusive at PeriodEnd,
+// a zero PeriodStart means the period starts from the very first sale.
+type SalesReport struct {
+	ID             int64                 `json:"id" gorm:"->;<-:create"`     // create & read only
+	Type           ReportType            `json:"type" gorm:"-"`              // a stored report is always a Z report
+	Number         int64                 `json:"number" gorm:"->;<-:create"` // create & read only
+	PeriodStart    time.Time             `json:"period_start" gorm:"->;<-:create"`
+	PeriodEnd      time.Time             `json:"period_end" gorm:"->;<-:create"`
+	Summary        *SalesSummary         `json:"summary" gorm:"->;<-:create;serializer:json"`
+	Hourly         []*HourlySales        `json:"hourly" gorm:"->;<-:create;serializer:json"`
+	Products       []*ProductSales       `json:"products" gorm:"->;<-:create;serializer:json"`
+	Cashiers       []*CashierSales       `json:"cashiers" gorm:"->;<-:create;serializer:json"`
+	PaymentMethods []*PaymentMethodSales `json:"payment_methods" gorm:"->;<-:create;serializer:json"`
+	CreatedBy      int64                 `json:"created_by" gorm:"->;<-:create"`                                           // create & read only
+	CreatedAt      time.Time             `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+}
+
+// TableName only the Z reports are stored
+func (SalesReport) TableName() string {
+	return "z_reports"
+}
+
+// SalesSummary the totals of a period, the refunds are counted on the period they are made
+// regardless of when the refunded transaction was made
+type SalesSummary struct {
+	TransactionCount int64 `json:"transaction_count"`
+	// GrossSales the sales before any discount, tax & service charge
+	GrossSales     int64 `json:"gross_sales"`
+	Discount       int64 `json:"discount"`
+	ManualDiscount int64 `json:"manual_discount"`
+	TaxAmount      int64 `json:"tax_amount"`
+	ServiceCharge  int64 `json:"service_charge"`
+	TotalSales     int64 `json:"total_sales"`
+	VoidCount      int64 `json:"void_count"`
+	VoidAmount     int64 `json:"void_amount"`
+	RefundCount    int64 `json:"refund_count"`
+	RefundAmount   int64 `json:"refund_amount"`
+	// Change the change given in cash, it is already deducted from the cash payment method
+	Change int64 `json:"change"`
+}
+
+// NetSales the total sales after deducted by the voids & refunds
+func (s SalesSummary) NetSales() int64 {
+	return s.TotalSales - s.VoidAmount - s.RefundAmount
+}
+
+// HourlySales the sales on an hour of the day in western indonesian time
+type HourlySales struct {
+	Hour             int   `json:"hour"`
+	TransactionCount int64 `json:"transaction_count"`
+	TotalSales       int64 `json:"total_sales"`
+}
+
+// ProductSales the sales of a product, the name is taken from the transaction detail snapshot
+type ProductSales struct {
+	ProductID   int64  `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int64  `json:"quantity"`
+	GrossSales  int64  `json:"gross_sales"`
+	Discount    int64  `json:"discount"`
+	Subtotal    int64  `json:"subtotal"`
+}
+
+// CashierSales the sales made by a cashier
+type CashierSales struct {
+	CashierID        int64  `json:"cashier_id"`
+	CashierName      string `json:"cashier_name"`
+	TransactionCount int64  `json:"transaction_count"`
+	Discount         int64  `json:"discount"`
+	TotalSales       int64  `json:"total_sales"`
+}
+
+// PaymentMethodSales the payments received by a payment method, the cash amount is net of the change given
+type PaymentMethodSales struct {
+	Method           PaymentMethod `json:"method"`
+	TransactionCount int64         `json:"transaction_count"`
+	Amount           int64         `json:"amount"`
+}
+
+// ReportRepository repository
+type ReportRepository interface {
+	// Aggregate the sales of the period from startAt inclusive until endAt exclusive
+	Aggregate(ctx context.Context, startAt, endAt time.Time) (*SalesReport, error)
+	FindZReportByID(ctx context.Context, id int64) (*SalesReport, error)
+	// FindLastZReport find the latest Z report, return nil when there is none
+	FindLastZReport(ctx context.Context) (*SalesReport, error)
+	SearchZReportsByPage(ctx context.Context, criteria ZReportSearchCriteria) (ids []int64, count int64, err error)
+	// CreateZReport aggregate the sales since the last Z report until now and store them as the next numbered Z report
+	CreateZReport(ctx context.Context, userID int64, report *SalesReport) error
+	// AggregateProfit the gross profit of the sales made from startAt inclusive until endAt exclusive
+	AggregateProfit(ctx context.Context, groupBy ProfitReportGroup, startAt, endAt time.Time) (*ProfitReport, error)
+}
+
+// ReportUsecase usecase
+type ReportUsecase interface {
+	GenerateXReport(ctx context.Context, requester *User) (*SalesReport, error)
+	CreateZReport(ctx context.Context, requester *User) (*SalesReport, error)
+	FindZReportByID(ctx context.Context, requester *User, id int64) (*SalesReport, error)
+	SearchZReports(ctx context.Context, requester *User, criteria ZReportSearchCriteria) (reports AnySalesReports, count int64, err error)
+	GenerateProfitReport(ctx context.Context, requester *User, criteria ProfitReportCriteria) (*ProfitReport, error)
+}
+
+// ZReportSearchCriteria criteria for searching Z report
+type ZReportSearchCriteria struct {
+	Page int `json:"page" query:"page"`
+	Size int `json:"size" query:"size"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *ZReportSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+}
+
+type SalesSummaryResponse struct {
+	TransactionCount string `json:"transaction_count" example:"120"`
+	GrossSales       string `json:"gross_sales" example:"Rp5.200.000"`
+	Discount         string `json:"discount" example:"Rp200.000"`
+	ManualDiscount   string `json:"manual_discount" example:"Rp50.000"`
+	TaxAmount        string `json:"tax_amount" example:"Rp550.000"`
+	ServiceCharge    string `json:"service_charge" example:"Rp0"`
+	TotalSales       string `json:"total_sales" example:"Rp5.550.000"`
+	VoidCount        string `json:"void_count" example:"1"`
+	VoidAmount       string `json:"void_amount" example:"Rp25.000"`
+	RefundCount      string `json:"refund_count" example:"2"`
+	RefundAmount     string `json:"refund_amount" example:"Rp40.000"`
+	NetSales         string `json:"net_sales" example:"Rp5.485.000"`
+}
+
+func (s SalesSummary) ToSalesSummaryResponse() SalesSummaryResponse {
+	return SalesSummaryResponse{
+		TransactionCount: utils.Int64ToString(s.TransactionCount),
+		GrossSales:       utils.Int64ToRupiah(s.GrossSales),
+		Discount:         utils.Int64ToRupiah(s.Discount),
+		ManualDiscount:   utils.Int64ToRupiah(s.ManualDiscount),
+		TaxAmount:        utils.Int64ToRupiah(s.TaxAmount),
+		ServiceCharge:    utils.Int64ToRupiah(s.ServiceCharge),
+		TotalSales:       utils.Int64ToRupiah(s.TotalSales),
+		VoidCount:        utils.Int64ToString(s.VoidCount),
+		VoidAmount:       utils.Int64ToRupiah(s.VoidAmount),
+		RefundCount:      utils.Int64ToString(s.RefundCount),
+		RefundAmount:     utils.Int64ToRupiah(s.RefundAmount),
+		NetSales:         utils.Int64ToRupiah(s.NetSales()),
+	}
+}
+
+type HourlySalesResponse struct {
+	Hour             string `json:"hour" example:"13:00"`
+	TransactionCount string `json:"transaction_count" example:"12"`
+	TotalSales       string `json:"total_sales" example:"Rp520.000"`
+}
+
+type ProductSalesResponse struct {
+	ProductID   string `json:"product_id" example:"1695599921375543118"`
+	ProductName string `json:"product_name" example:"Indomie Goreng"`
+	Quantity    string `json:"quantity" example:"40"`
+	GrossSales  string `json:"gross_sales" example:"Rp140.000"`
+	Discount    string `json:"discount" example:"Rp10.000"`
+	Subtotal    string `json:"subtotal" example:"Rp130.000"`
+}
+
+type CashierSalesResponse struct {
+	CashierID        string `json:"cashier_id" example:"1695599921375543118"`
+	CashierName      string `json:"cashier_name" example:"Budi"`
+	TransactionCount string `json:"transaction_count" example:"60"`
+	Discount         string `json:"discount" example:"Rp100.000"`
+	TotalSales       string `json:"total_sales" example:"Rp2.750.000"`
+}
+
+type PaymentMethodSalesResponse struct {
+	Method           string `json:"method" example:"CASH"`
+	TransactionCount string `json:"transaction_count" example:"80"`
+	Amount           string `json:"amount" example:"Rp3.100.000"`
+}
+
+type SalesReportResponse struct {
+	ID             string                       `json:"id,omitempty" example:"1695599921375543118"`
+	Type           string                       `json:"type" example:"Z"`
+	Number         string                       `json:"number,omitempty" example:"12"`
+	PeriodStart    string                       `json:"period_start" example:"25 September 2023 22:00 WIB"`
+	PeriodEnd      string                       `json:"period_end" example:"26 September 2023 22:00 WIB"`
+	Summary        SalesSummaryResponse         `json:"summary"`
+	Hourly         []HourlySalesResponse        `json:"hourly"`
+	Products       []ProductSalesResponse       `json:"products"`
+	Cashiers       []CashierSalesResponse       `json:"cashiers"`
+	PaymentMethods []PaymentMethodSalesResponse `json:"payment_methods"`
+	CreatedBy      string                       `json:"created_by,omitempty" example:"1695599921375543118"`
+	CreatedAt      string                       `json:"created_at,omitempty" example:"26 September 2023 22:00 WIB"`
+}
+
+func (s SalesReport) ToSalesReportResponse() SalesReportResponse {
+	response := SalesReportResponse{
+		Type:      string(s.Type),
+		PeriodEnd: utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.PeriodEnd),
+	}
+	if !s.PeriodStart.IsZero() {
+		response.PeriodStart = utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.PeriodStart)
+	}
+	if s.Summary != nil {
+		response.Summary = s.Summary.ToSalesSummaryResponse()
+	}
+	if s.Type == ReportTypeZ {
+		response.ID = utils.Int64ToString(s.ID)
+		response.Number = utils.Int64ToString(s.Number)
+		response.CreatedBy = utils.Int64ToString(s.CreatedBy)
+		response.CreatedAt = utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.CreatedAt)
+	}
+
+	for _, hourly := range s.Hourly {
+		response.Hourly = append(response.Hourly, HourlySalesResponse{
+			Hour:             HourLabel(hourly.Hour),
+			TransactionCount: utils.Int64ToString(hourly.TransactionCount),
+			TotalSales:       utils.Int64ToRupiah(hourly.TotalSales),
+		})
+	}
+
+	for _, product := range s.Products {
+		response.Products = append(response.Products, ProductSalesResponse{
+			ProductID:   utils.Int64ToString(product.ProductID),
+			ProductName: product.ProductName,
+			Quantity:    utils.Int64ToString(product.Quantity),
+			GrossSales:  utils.Int64ToRupiah(product.GrossSales),
+			Discount:    utils.Int64ToRupiah(product.Discount),
+			Subtotal:    utils.Int64ToRupiah(product.Subtotal),
+		})
+	}
+
+	for _, cashier := range s.Cashiers {
+		response.Cashiers = append(response.Cashiers, CashierSalesResponse{
+			CashierID:        utils.Int64ToString(cashier.CashierID),
+			CashierName:      cashier.CashierName,
+			TransactionCount: utils.Int64ToString(cashier.TransactionCount),
+			Discount:         utils.Int64ToRupiah(cashier.Discount),
+			TotalSales:       utils.Int64ToRupiah(cashier.TotalSales),
+		})
+	}
+
+	for _, method := range s.PaymentMethods {
+		response.PaymentMethods = append(response.PaymentMethods, PaymentMethodSalesResponse{
+			Method:           string(method.Method),
+			TransactionCount: utils.Int64ToString(method.TransactionCount),
+			Amount:           utils.Int64ToRupiah(method.Amount),
+		})
+	}
+
+	return response
+}
+
+type AnySalesReports []*SalesReport
+
+func (as AnySalesReports) ToListSalesReportResponse() (responses []SalesReportResponse) {
+	for _, report := range as {
+		responses = append(responses, report.ToSalesReportResponse())
+	}
+
+	return responses
+}
+
+// HourLabel format the hour of the day, e.g. 13:00
+func HourLabel(hour int) string {
+	return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC).Format("15:04")
+}
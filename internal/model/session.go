@@ -15,6 +15,8 @@ type SessionRepository interface {
 	CheckToken(ctx context.Context, token string) (exist bool, err error)
 	RefreshToken(ctx context.Context, oldSess, sess *Session) (*Session, error)
 	DeleteByUserIDAndMaxRemainderSession(ctx context.Context, userID int64, maxRemainderSess int) error
+	// DeleteByUserID delete every session of the user along with their cached tokens
+	DeleteByUserID(ctx context.Context, userID int64) error
 	Delete(ctx context.Context, session *Session) error
 }
 
@@ -41,7 +43,8 @@ type Session struct {
 	CreatedAt             time.Time `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"`
 	UpdatedAt             time.Time `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
 
-	Role rbac.Role `gorm:"-"`
+	Role       rbac.Role  `gorm:"-"`
+	UserStatus UserStatus `gorm:"-"`
 }
 
 // IsAccessTokenExpired check access token expired at against now
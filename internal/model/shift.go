@@ -0,0 +1,257 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+// shift errors
+var (
+	ErrShiftAlreadyOpen = errors.New("cashier already has an open shift")
+	ErrShiftNotOpen     = errors.New("no open shift")
+	ErrShiftClosed      = errors.New("shift is already closed")
+)
+
+// ShiftStatus status of a cashier shift
+type ShiftStatus string
+
+// ShiftStatus constants
+const (
+	ShiftStatusOpen   ShiftStatus = "OPEN"
+	ShiftStatusClosed ShiftStatus = "CLOSED"
+)
+
+// Shift a working shift of a cashier and its cash drawer.
+// The expected cash is the opening float plus the cash sales & cash in, minus the cash out,
+// the cash sales are net of the cash part of the voids & refunds paid from the shift drawer.
+type Shift struct {
+	ID           int64       `json:"id"`
+	CashierID    int64       `json:"cashier_id" gorm:"->;<-:create"` // create & read only
+	Status       ShiftStatus `json:"status"`
+	OpeningFloat int64       `json:"opening_float" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	CashSales    int64       `json:"cash_sales" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	CashIn       int64       `json:"cash_in" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	CashOut      int64       `json:"cash_out" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	ExpectedCash int64       `json:"expected_cash" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	ClosingCash  int64       `json:"closing_cash" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Variance     int64       `json:"variance" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	OpeningNote  string      `json:"opening_note"`
+	ClosingNote  string      `json:"closing_note"`
+	OpenedAt     time.Time   `json:"opened_at" gorm:"->;<-:create"` // create & read only
+	ClosedAt     *time.Time  `json:"closed_at"`
+
+	CashMovements []*CashMovement `json:"cash_movements" gorm:"-"`
+}
+
+// IsOpen check if the shift is still open
+func (s Shift) IsOpen() bool {
+	return s.Status == ShiftStatusOpen
+}
+
+// Reconcile close the shift with the counted closing cash,
+// the variance is negative when the drawer is short and positive when it is over
+func (s *Shift) Reconcile(cashSales, cashIn, cashOut, closingCash int64, closedAt time.Time) {
+	s.Status = ShiftStatusClosed
+	s.CashSales = cashSales
+	s.CashIn = cashIn
+	s.CashOut = cashOut
+	s.ExpectedCash = s.OpeningFloat + cashSales + cashIn - cashOut
+	s.ClosingCash = closingCash
+	s.Variance = closingCash - s.ExpectedCash
+	s.ClosedAt = &closedAt
+}
+
+// CashMovementType type of a cash drawer movement
+type CashMovementType string
+
+// CashMovementType constants
+const (
+	// CashMovementTypeIn cash put into the drawer, e.g. additional change
+	CashMovementTypeIn CashMovementType = "CASH_IN"
+	// CashMovementTypeOut cash taken from the drawer, e.g. petty cash or a safe drop
+	CashMovementTypeOut CashMovementType = "CASH_OUT"
+)
+
+// CashMovement a cash drawer movement outside of the sales
+type CashMovement struct {
+	ID        int64            `json:"id"`
+	ShiftID   int64            `json:"shift_id"`
+	Type      CashMovementType `json:"type"`
+	Amount    int64            `json:"amount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Reason    string           `json:"reason"`
+	CreatedBy int64            `json:"created_by" gorm:"->;<-:create"`                                           // create & read only
+	CreatedAt time.Time        `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+}
+
+// ShiftRepository repository
+type ShiftRepository interface {
+	FindByID(ctx context.Context, id int64) (*Shift, error)
+	// FindOpenByCashierID find the open shift of the cashier, return nil when there is none
+	FindOpenByCashierID(ctx context.Context, cashierID int64) (*Shift, error)
+	SearchByPage(ctx context.Context, criteria ShiftSearchCriteria) (ids []int64, count int64, err error)
+	// Open store a new open shift, return ErrShiftAlreadyOpen when the cashier still has an open shift
+	Open(ctx context.Context, userID int64, shift *Shift) error
+	// CreateCashMovement store a cash movement, return ErrShiftClosed when the shift is no longer open
+	CreateCashMovement(ctx context.Context, userID int64, movement *CashMovement) error
+	// Close sum the cash tenders, cash refunds & movements of the shift and reconcile it with the closing cash,
+	// return ErrShiftClosed when the shift is already closed
+	Close(ctx context.Context, userID int64, shift *Shift, closingCash int64) error
+	// LockOpenByID lock the shift within the given db transaction so it can not be closed until the db transaction ends,
+	// return ErrShiftClosed when the shift is no longer open
+	LockOpenByID(ctx context.Context, tx *gorm.DB, id int64) error
+}
+
+// ShiftUsecase usecase
+type ShiftUsecase interface {
+	FindByID(ctx context.Context, requester *User, id int64) (*Shift, error)
+	FindCurrent(ctx context.Context, requester *User) (*Shift, error)
+	Search(ctx context.Context, requester *User, criteria ShiftSearchCriteria) (shifts AnyShifts, count int64, err error)
+	Open(ctx context.Context, requester *User, input OpenShiftInput) (*Shift, error)
+	AddCashMovement(ctx context.Context, requester *User, id int64, input CashMovementInput) (*CashMovement, error)
+	Close(ctx context.Context, requester *User, id int64, input CloseShiftInput) (*Shift, error)
+}
+
+// OpenShiftInput input to open a shift
+type OpenShiftInput struct {
+	OpeningFloat int64  `json:"opening_float" validate:"gte=0" example:"200000"`
+	Note         string `json:"note" validate:"max=255" example:"shift pagi"`
+}
+
+// Validate validate open shift input
+func (o *OpenShiftInput) Validate() error {
+	return validate.Struct(o)
+}
+
+// CashMovementInput input to record a cash movement
+type CashMovementInput struct {
+	Type   CashMovementType `json:"type" validate:"required,oneof=CASH_IN CASH_OUT" example:"CASH_OUT"`
+	Amount int64            `json:"amount" validate:"gt=0" example:"500000"`
+	Reason string           `json:"reason" validate:"required,max=255" example:"setor ke brankas"`
+}
+
+// Validate validate cash movement input
+func (c *CashMovementInput) Validate() error {
+	return validate.Struct(c)
+}
+
+// CloseShiftInput input to close a shift
+type CloseShiftInput struct {
+	ClosingCash int64  `json:"closing_cash" validate:"gte=0" example:"750000"`
+	Note        string `json:"note" validate:"max=255" example:"uang pas"`
+}
+
+// Validate validate close shift input
+func (c *CloseShiftInput) Validate() error {
+	return validate.Struct(c)
+}
+
+// ShiftSearchCriteria criteria for searching shift
+type ShiftSearchCriteria struct {
+	Page      int         `json:"page" query:"page"`
+	Size      int         `json:"size" query:"size"`
+	CashierID int64       `json:"cashier_id" query:"cashierID"`
+	Status    ShiftStatus `json:"status" query:"status"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *ShiftSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+}
+
+type CashMovementResponse struct {
+	ID        string `json:"id" example:"1695599921375543118"`
+	Type      string `json:"type" example:"CASH_OUT"`
+	Amount    string `json:"amount" example:"Rp500.000"`
+	Reason    string `json:"reason" example:"setor ke brankas"`
+	CreatedBy string `json:"created_by" example:"1695599921375543118"`
+	CreatedAt string `json:"created_at" example:"25 September 2023 13:59 WIB"`
+}
+
+func (c CashMovement) ToCashMovementResponse() CashMovementResponse {
+	return CashMovementResponse{
+		ID:        utils.Int64ToString(c.ID),
+		Type:      string(c.Type),
+		Amount:    utils.Int64ToRupiah(c.Amount),
+		Reason:    c.Reason,
+		CreatedBy: utils.Int64ToString(c.CreatedBy),
+		CreatedAt: utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &c.CreatedAt),
+	}
+}
+
+type AnyCashMovements []*CashMovement
+
+func (ac AnyCashMovements) ToListCashMovementResponse() (responses []CashMovementResponse) {
+	for _, movement := range ac {
+		responses = append(responses, movement.ToCashMovementResponse())
+	}
+
+	return responses
+}
+
+type ShiftResponse struct {
+	ID            string                 `json:"id" example:"1695599921375543118"`
+	CashierID     string                 `json:"cashier_id" example:"1695599921375543118"`
+	Status        string                 `json:"status" example:"CLOSED"`
+	OpeningFloat  string                 `json:"opening_float" example:"Rp200.000"`
+	CashSales     string                 `json:"cash_sales" example:"Rp1.050.000"`
+	CashIn        string                 `json:"cash_in" example:"Rp0"`
+	CashOut       string                 `json:"cash_out" example:"Rp500.000"`
+	ExpectedCash  string                 `json:"expected_cash" example:"Rp750.000"`
+	ClosingCash   string                 `json:"closing_cash" example:"Rp745.000"`
+	Variance      string                 `json:"variance" example:"-Rp5.000"`
+	OpeningNote   string                 `json:"opening_note" example:"shift pagi"`
+	ClosingNote   string                 `json:"closing_note" example:"kurang 5 ribu"`
+	OpenedAt      string                 `json:"opened_at" example:"25 September 2023 07:00 WIB"`
+	ClosedAt      string                 `json:"closed_at" example:"25 September 2023 15:00 WIB"`
+	CashMovements []CashMovementResponse `json:"cash_movements"`
+}
+
+func (s Shift) ToShiftResponse() ShiftResponse {
+	response := ShiftResponse{
+		ID:            utils.Int64ToString(s.ID),
+		CashierID:     utils.Int64ToString(s.CashierID),
+		Status:        string(s.Status),
+		OpeningFloat:  utils.Int64ToRupiah(s.OpeningFloat),
+		CashSales:     utils.Int64ToRupiah(s.CashSales),
+		CashIn:        utils.Int64ToRupiah(s.CashIn),
+		CashOut:       utils.Int64ToRupiah(s.CashOut),
+		ExpectedCash:  utils.Int64ToRupiah(s.ExpectedCash),
+		ClosingCash:   utils.Int64ToRupiah(s.ClosingCash),
+		Variance:      utils.Int64ToRupiah(s.Variance),
+		OpeningNote:   s.OpeningNote,
+		ClosingNote:   s.ClosingNote,
+		OpenedAt:      utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.OpenedAt),
+		CashMovements: AnyCashMovements(s.CashMovements).ToListCashMovementResponse(),
+	}
+	if s.Variance < 0 {
+		response.Variance = "-" + utils.Int64ToRupiah(-s.Variance)
+	}
+	if s.ClosedAt != nil {
+		response.ClosedAt = utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, s.ClosedAt)
+	}
+
+	return response
+}
+
+type AnyShifts []*Shift
+
+func (as AnyShifts) ToListShiftResponse() (responses []ShiftResponse) {
+	for _, shift := range as {
+		responses = append(responses, shift.ToShiftResponse())
+	}
+
+	return responses
+}
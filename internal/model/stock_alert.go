@@ -0,0 +1,50 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// LowStockAlert the alert of a product which stock has reached its minimum stock level
+type LowStockAlert struct {
+	ProductID       int64     `json:"product_id"`
+	ProductName     string    `json:"product_name"`
+	SKU             string    `json:"sku"`
+	Quantity        int64     `json:"quantity"`
+	MinStock        int64     `json:"min_stock"`
+	ReorderQuantity int64     `json:"reorder_quantity"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// NewLowStockAlert create the low stock alert of the product
+func NewLowStockAlert(product *Product, createdAt time.Time) *LowStockAlert {
+	return &LowStockAlert{
+		ProductID:       product.ID,
+		ProductName:     product.Name,
+		SKU:             product.SKU,
+		Quantity:        product.Quantity,
+		MinStock:        product.MinStock,
+		ReorderQuantity: product.ReorderQuantity,
+		CreatedAt:       createdAt,
+	}
+}
+
+// StockAlertNotifier send the low stock alerts, e.g. to a log file or a webhook
+type StockAlertNotifier interface {
+	Notify(ctx context.Context, alert *LowStockAlert) error
+}
+
+// StockAlertRepository repository, keep track of the alerted products so a product is not alerted repeatedly
+type StockAlertRepository interface {
+	// IsAlerted check if the product was alerted within the repeat interval
+	IsAlerted(ctx context.Context, productID int64) (bool, error)
+	// MarkAlerted mark the product as alerted until the repeat interval passes
+	MarkAlerted(ctx context.Context, productID int64) error
+}
+
+// StockAlertUsecase usecase
+type StockAlertUsecase interface {
+	// NotifyLowStock send an alert for every low stock product which is not alerted yet,
+	// return the number of the sent alerts
+	NotifyLowStock(ctx context.Context) (int, error)
+}
@@ -0,0 +1,374 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+// ErrInvalidStockMovement error when the quantity sign does not match the movement type
+var ErrInvalidStockMovement = errors.New("invalid stock movement quantity for the movement type")
+
+// StockMovementType the cause of a stock movement
+type StockMovementType string
+
+const (
+	StockMovementTypeSale       StockMovementType = "SALE"
+	StockMovementTypeRefund     StockMovementType = "REFUND"
+	StockMovementTypeAdjustment StockMovementType = "ADJUSTMENT"
+	StockMovementTypeReceipt    StockMovementType = "RECEIPT"
+	StockMovementTypeTransfer   StockMovementType = "TRANSFER"
+	StockMovementTypeWaste      StockMovementType = "WASTE"
+)
+
+// the documents referenced by the stock movements
+const (
+	StockReferenceTransaction = "transaction"
+	StockReferenceRefund      = "refund"
+	StockReferenceProduct     = "product"
+	StockReferenceManual      = "manual"
+)
+
+// StockMovement an entry of the append-only stock ledger, Quantity is positive for an incoming stock
+// and negative for an outgoing stock. VariantID is zero for a product without variants.
+// The stock of a product or a variant is the sum of its movements.
+type StockMovement struct {
+	ID            int64             `json:"id" gorm:"->;<-:create"` // create & read only
+	ProductID     int64             `json:"product_id" gorm:"->;<-:create"`
+	VariantID     int64             `json:"variant_id" gorm:"->;<-:create"`
+	Type          StockMovementType `json:"type" gorm:"->;<-:create"`
+	Quantity      int64             `json:"quantity" gorm:"->;<-:create"`
+	ReferenceType string            `json:"reference_type" gorm:"->;<-:create"`
+	ReferenceID   int64             `json:"reference_id" gorm:"->;<-:create"`
+	Note          string            `json:"note" gorm:"->;<-:create"`
+	CreatedBy     int64             `json:"created_by" gorm:"->;<-:create"`
+	CreatedAt     time.Time         `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"`
+}
+
+// Key the product & variant of the movement
+func (s StockMovement) Key() ProductVariantKey {
+	return ProductVariantKey{ProductID: s.ProductID, VariantID: s.VariantID}
+}
+
+// StockMovementRepository repository, the movements are never updated nor deleted
+type StockMovementRepository interface {
+	// Create store the movements within the given db transaction, the stock itself is changed by the caller
+	Create(ctx context.Context, tx *gorm.DB, movements []*StockMovement) error
+	FindStockCard(ctx context.Context, criteria StockCardCriteria) (*StockCard, error)
+	// FindDiscrepancies find the products & variants which stock differs from the sum of their movements
+	FindDiscrepancies(ctx context.Context) ([]*StockDiscrepancy, error)
+}
+
+// StockMovementUsecase usecase
+type StockMovementUsecase interface {
+	FindStockCard(ctx context.Context, requester *User, criteria StockCardCriteria) (*StockCard, error)
+	// Create post a waste or transfer of a product stock
+	Create(ctx context.Context, requester *User, productID int64, input CreateStockMovementInput) (*StockMovement, error)
+}
+
+type AnyStockMovements []*StockMovement
+
+// Sorted the movements ordered by product then variant, so every db transaction locks the stock rows in the same order
+func (am AnyStockMovements) Sorted() AnyStockMovements {
+	sorted := append(AnyStockMovements{}, am...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key().Less(sorted[j].Key())
+	})
+
+	return sorted
+}
+
+// ProductIDs the product ids of the movements, used to delete the product caches
+func (am AnyStockMovements) ProductIDs() (ids []int64) {
+	for _, movement := range am {
+		ids = append(ids, movement.ProductID)
+	}
+
+	return ids
+}
+
+// VariantIDs the variant ids of the movements, used to delete the variant caches
+func (am AnyStockMovements) VariantIDs() (ids []int64) {
+	for _, movement := range am {
+		if movement.VariantID > 0 {
+			ids = append(ids, movement.VariantID)
+		}
+	}
+
+	return ids
+}
+
+// NewStockAdjustments the adjustment movements which bring the stock of the stored product & variants
+// to the stock of the product, a new product is given as an empty stored product.
+// The removed variants are adjusted to zero, since their stock is no longer counted on the product.
+func NewStockAdjustments(stored *Product, storedVariants []*ProductVariant, product *Product, userID int64, note string) []*StockMovement {
+	var movements []*StockMovement
+	add := func(variantID, quantity int64) {
+		if quantity == 0 {
+			return
+		}
+		movements = append(movements, &StockMovement{
+			ProductID:     product.ID,
+			VariantID:     variantID,
+			Type:          StockMovementTypeAdjustment,
+			Quantity:      quantity,
+			ReferenceType: StockReferenceProduct,
+			ReferenceID:   product.ID,
+			Note:          note,
+			CreatedBy:     userID,
+		})
+	}
+
+	var storedQuantity, quantity int64
+	if !stored.HasVariants() {
+		storedQuantity = stored.Quantity
+	}
+	if !product.HasVariants() {
+		quantity = product.Quantity
+	}
+	add(0, quantity-storedQuantity)
+
+	storedByID := make(map[int64]int64, len(storedVariants))
+	for _, variant := range storedVariants {
+		storedByID[variant.ID] = variant.Quantity
+	}
+	for _, variant := range product.Variants {
+		add(variant.ID, variant.Quantity-storedByID[variant.ID])
+		delete(storedByID, variant.ID)
+	}
+	for _, variant := range storedVariants {
+		if quantity, ok := storedByID[variant.ID]; ok {
+			add(variant.ID, -quantity)
+		}
+	}
+
+	return movements
+}
+
+// CreateStockMovementInput a waste or transfer of a product stock, the waste quantity is negative
+// and the transfer quantity is negative for the stock sent out and positive for the stock received
+type CreateStockMovementInput struct {
+	VariantID int64             `json:"variant_id" validate:"gte=0" example:"0"`
+	Type      StockMovementType `json:"type" validate:"required,oneof=WASTE TRANSFER" example:"WASTE"`
+	Quantity  int64             `json:"quantity" validate:"required" example:"-2"`
+	Note      string            `json:"note" validate:"max=200" example:"expired"`
+}
+
+// Validate validate stock movement input
+func (c *CreateStockMovementInput) Validate() error {
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+
+	if c.Type == StockMovementTypeWaste && c.Quantity > 0 {
+		return ErrInvalidStockMovement
+	}
+
+	return nil
+}
+
+// StockCardCriteria the movements of a product, or one of its variants, between StartDate & EndDate inclusive
+type StockCardCriteria struct {
+	ProductID int64  `json:"product_id" query:"-"`
+	VariantID int64  `json:"variant_id" query:"variantID"`
+	StartDate string `json:"start_date" query:"startDate" example:"2023-09-01"`
+	EndDate   string `json:"end_date" query:"endDate" example:"2023-09-30"`
+}
+
+// SetDefaultValue default the period to the current month until today
+func (c *StockCardCriteria) SetDefaultValue() {
+	now := time.Now().In(utils.WesternIndonesianLocation())
+	if c.StartDate == "" {
+		c.StartDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format(DateLayout)
+	}
+
+	if c.EndDate == "" {
+		c.EndDate = now.Format(DateLayout)
+	}
+}
+
+// DateRange parse StartDate & EndDate on western indonesian time, the returned endAt is exclusive
+func (c *StockCardCriteria) DateRange() (startAt, endAt time.Time, err error) {
+	startAt, err = utils.ParseWesternIndonesianDate(DateLayout, c.StartDate)
+	if err != nil {
+		return startAt, endAt, ErrInvalidDateRange
+	}
+
+	endAt, err = utils.ParseWesternIndonesianDate(DateLayout, c.EndDate)
+	if err != nil {
+		return startAt, endAt, ErrInvalidDateRange
+	}
+	endAt = endAt.AddDate(0, 0, 1)
+
+	if !endAt.After(startAt) {
+		return startAt, endAt, ErrInvalidDateRange
+	}
+
+	return startAt, endAt, nil
+}
+
+// StockCard the stock movements of a period along with the running balance
+type StockCard struct {
+	ProductID      int64             `json:"product_id"`
+	VariantID      int64             `json:"variant_id"`
+	StartAt        time.Time         `json:"start_at"`
+	EndAt          time.Time         `json:"end_at"`
+	OpeningBalance int64             `json:"opening_balance"`
+	TotalIn        int64             `json:"total_in"`
+	TotalOut       int64             `json:"total_out"`
+	ClosingBalance int64             `json:"closing_balance"`
+	Entries        []*StockCardEntry `json:"entries"`
+}
+
+// StockCardEntry a movement along with the balance after the movement
+type StockCardEntry struct {
+	*StockMovement
+	Balance int64 `json:"balance"`
+}
+
+// NewStockCard calculate the running balance of the movements ordered by time, starting from the opening balance
+func NewStockCard(criteria StockCardCriteria, startAt, endAt time.Time, openingBalance int64, movements []*StockMovement) *StockCard {
+	card := &StockCard{
+		ProductID:      criteria.ProductID,
+		VariantID:      criteria.VariantID,
+		StartAt:        startAt,
+		EndAt:          endAt,
+		OpeningBalance: openingBalance,
+		ClosingBalance: openingBalance,
+	}
+
+	for _, movement := range movements {
+		if movement.Quantity > 0 {
+			card.TotalIn += movement.Quantity
+		} else {
+			card.TotalOut -= movement.Quantity
+		}
+		card.ClosingBalance += movement.Quantity
+		card.Entries = append(card.Entries, &StockCardEntry{StockMovement: movement, Balance: card.ClosingBalance})
+	}
+
+	return card
+}
+
+// StockDiscrepancy the stock of a product or variant which differs from the sum of its movements,
+// the product discrepancy covers the movements of all of its variants
+type StockDiscrepancy struct {
+	ProductID      int64 `json:"product_id"`
+	VariantID      int64 `json:"variant_id"`
+	Quantity       int64 `json:"quantity"`
+	LedgerQuantity int64 `json:"ledger_quantity"`
+}
+
+// Difference the quantity missing from the ledger
+func (s StockDiscrepancy) Difference() int64 {
+	return s.Quantity - s.LedgerQuantity
+}
+
+type AnyStockDiscrepancies []*StockDiscrepancy
+
+// ReconcilingMovements the adjustment movements which bring the ledger to the current stock.
+// The variants are reconciled first, the rest of a product discrepancy is posted on the product itself.
+func (ad AnyStockDiscrepancies) ReconcilingMovements(note string) []*StockMovement {
+	var movements []*StockMovement
+	variantDifferences := make(map[int64]int64)
+	for _, discrepancy := range ad {
+		if discrepancy.VariantID <= 0 {
+			continue
+		}
+		variantDifferences[discrepancy.ProductID] += discrepancy.Difference()
+		movements = append(movements, newReconcilingMovement(discrepancy.ProductID, discrepancy.VariantID, discrepancy.Difference(), note))
+	}
+
+	for _, discrepancy := range ad {
+		if discrepancy.VariantID > 0 {
+			continue
+		}
+		if difference := discrepancy.Difference() - variantDifferences[discrepancy.ProductID]; difference != 0 {
+			movements = append(movements, newReconcilingMovement(discrepancy.ProductID, 0, difference, note))
+		}
+	}
+
+	return movements
+}
+
+func newReconcilingMovement(productID, variantID, quantity int64, note string) *StockMovement {
+	return &StockMovement{
+		ProductID:     productID,
+		VariantID:     variantID,
+		Type:          StockMovementTypeAdjustment,
+		Quantity:      quantity,
+		ReferenceType: StockReferenceProduct,
+		ReferenceID:   productID,
+		Note:          note,
+	}
+}
+
+type StockMovementResponse struct {
+	ID            string `json:"id" example:"1695599921375543118"`
+	ProductID     string `json:"product_id" example:"1695599921375543118"`
+	VariantID     string `json:"variant_id" example:"0"`
+	Type          string `json:"type" example:"SALE"`
+	Quantity      string `json:"quantity" example:"-2"`
+	ReferenceType string `json:"reference_type" example:"transaction"`
+	ReferenceID   string `json:"reference_id" example:"1695599921375543118"`
+	Note          string `json:"note" example:""`
+	CreatedBy     string `json:"created_by" example:"1695599921375543118"`
+	CreatedAt     string `json:"created_at" example:"25 September 2023 13:59 WIB"`
+}
+
+func (s StockMovement) ToStockMovementResponse() StockMovementResponse {
+	return StockMovementResponse{
+		ID:            utils.Int64ToString(s.ID),
+		ProductID:     utils.Int64ToString(s.ProductID),
+		VariantID:     utils.Int64ToString(s.VariantID),
+		Type:          string(s.Type),
+		Quantity:      utils.Int64ToString(s.Quantity),
+		ReferenceType: s.ReferenceType,
+		ReferenceID:   utils.Int64ToString(s.ReferenceID),
+		Note:          s.Note,
+		CreatedBy:     utils.Int64ToString(s.CreatedBy),
+		CreatedAt:     utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.CreatedAt),
+	}
+}
+
+type StockCardEntryResponse struct {
+	StockMovementResponse
+	Balance string `json:"balance" example:"8"`
+}
+
+type StockCardResponse struct {
+	ProductID      string                   `json:"product_id" example:"1695599921375543118"`
+	VariantID      string                   `json:"variant_id" example:"0"`
+	StartAt        string                   `json:"start_at" example:"01 September 2023 00:00 WIB"`
+	EndAt          string                   `json:"end_at" example:"01 October 2023 00:00 WIB"`
+	OpeningBalance string                   `json:"opening_balance" example:"10"`
+	TotalIn        string                   `json:"total_in" example:"0"`
+	TotalOut       string                   `json:"total_out" example:"2"`
+	ClosingBalance string                   `json:"closing_balance" example:"8"`
+	Entries        []StockCardEntryResponse `json:"entries"`
+}
+
+func (s StockCard) ToStockCardResponse() StockCardResponse {
+	entries := make([]StockCardEntryResponse, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		entries = append(entries, StockCardEntryResponse{
+			StockMovementResponse: entry.ToStockMovementResponse(),
+			Balance:               utils.Int64ToString(entry.Balance),
+		})
+	}
+
+	return StockCardResponse{
+		ProductID:      utils.Int64ToString(s.ProductID),
+		VariantID:      utils.Int64ToString(s.VariantID),
+		StartAt:        utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.StartAt),
+		EndAt:          utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.EndAt),
+		OpeningBalance: utils.Int64ToString(s.OpeningBalance),
+		TotalIn:        utils.Int64ToString(s.TotalIn),
+		TotalOut:       utils.Int64ToString(s.TotalOut),
+		ClosingBalance: utils.Int64ToString(s.ClosingBalance),
+		Entries:        entries,
+	}
+}
@@ -0,0 +1,288 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"sort"
+	"time"
+)
+
+// stock opname errors
+var (
+	ErrStockOpnameNotOpen = errors.New("stock opname is already approved")
+	ErrStockOpnameEmpty   = errors.New("stock opname has no counted product")
+)
+
+// StockOpnameStatus status of a stock opname
+type StockOpnameStatus string
+
+// StockOpnameStatus constants
+const (
+	StockOpnameStatusOpen     StockOpnameStatus = "OPEN"
+	StockOpnameStatusApproved StockOpnameStatus = "APPROVED"
+)
+
+// StockReferenceStockOpname the approved stock opname of an adjustment movement
+const StockReferenceStockOpname = "stock_opname"
+
+// StockOpname a stock take session, the products are counted while the session is open
+// and the variances are posted as adjustment movements once it is approved
+type StockOpname struct {
+	ID         int64             `json:"id"`
+	Status     StockOpnameStatus `json:"status"`
+	Note       string            `json:"note"`
+	StartedBy  int64             `json:"started_by" gorm:"->;<-:create"` // create & read only
+	ApprovedBy int64             `json:"approved_by"`
+	ApprovedAt *time.Time        `json:"approved_at"`
+	CreatedAt  time.Time         `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+
+	Counts []*StockOpnameCount `json:"counts" gorm:"-"`
+}
+
+// IsOpen check if the stock opname still accepts counts
+func (s StockOpname) IsOpen() bool {
+	return s.Status == StockOpnameStatusOpen
+}
+
+// StockOpnameCount the quantity of a product or variant counted by a user, the users counting
+// the same product on different locations are summed and a recount of the same user replaces the count.
+// SystemQuantity is the stock when the product was counted, so the sales made after the count are kept.
+type StockOpnameCount struct {
+	StockOpnameID  int64     `json:"stock_opname_id"`
+	ProductID      int64     `json:"product_id"`
+	VariantID      int64     `json:"variant_id"`
+	CountedBy      int64     `json:"counted_by"`
+	Quantity       int64     `json:"quantity"`
+	SystemQuantity int64     `json:"system_quantity"`
+	CountedAt      time.Time `json:"counted_at"`
+}
+
+// Key the product & variant of the count
+func (s StockOpnameCount) Key() ProductVariantKey {
+	return ProductVariantKey{ProductID: s.ProductID, VariantID: s.VariantID}
+}
+
+// StockOpnameVariance the counted quantity of a product or variant against its system stock,
+// the variance is negative when the stock is short and positive when it is over
+type StockOpnameVariance struct {
+	ProductID       int64   `json:"product_id"`
+	VariantID       int64   `json:"variant_id"`
+	SystemQuantity  int64   `json:"system_quantity"`
+	CountedQuantity int64   `json:"counted_quantity"`
+	Variance        int64   `json:"variance"`
+	CountedBy       []int64 `json:"counted_by"`
+}
+
+type AnyStockOpnameCounts []*StockOpnameCount
+
+// Variances sum the counts of every product & variant ordered by product then variant,
+// the system stock is taken from the first count of the product
+func (ac AnyStockOpnameCounts) Variances() []*StockOpnameVariance {
+	counts := append(AnyStockOpnameCounts{}, ac...)
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Key() != counts[j].Key() {
+			return counts[i].Key().Less(counts[j].Key())
+		}
+		return counts[i].CountedAt.Before(counts[j].CountedAt)
+	})
+
+	var variances []*StockOpnameVariance
+	var current *StockOpnameVariance
+	for _, count := range counts {
+		if current == nil || current.ProductID != count.ProductID || current.VariantID != count.VariantID {
+			current = &StockOpnameVariance{
+				ProductID:      count.ProductID,
+				VariantID:      count.VariantID,
+				SystemQuantity: count.SystemQuantity,
+			}
+			variances = append(variances, current)
+		}
+
+		current.CountedQuantity += count.Quantity
+		current.CountedBy = append(current.CountedBy, count.CountedBy)
+	}
+
+	for _, variance := range variances {
+		variance.Variance = variance.CountedQuantity - variance.SystemQuantity
+	}
+
+	return variances
+}
+
+// StockMovements the adjustment movements of the variances, the counted products without variance are skipped
+func (s StockOpname) StockMovements(userID int64) []*StockMovement {
+	var movements []*StockMovement
+	for _, variance := range AnyStockOpnameCounts(s.Counts).Variances() {
+		if variance.Variance == 0 {
+			continue
+		}
+
+		movements = append(movements, &StockMovement{
+			ProductID:     variance.ProductID,
+			VariantID:     variance.VariantID,
+			Type:          StockMovementTypeAdjustment,
+			Quantity:      variance.Variance,
+			ReferenceType: StockReferenceStockOpname,
+			ReferenceID:   s.ID,
+			Note:          s.Note,
+			CreatedBy:     userID,
+		})
+	}
+
+	return movements
+}
+
+// StockOpnameRepository repository
+type StockOpnameRepository interface {
+	// FindByID find the stock opname along with its counts
+	FindByID(ctx context.Context, id int64) (*StockOpname, error)
+	SearchByPage(ctx context.Context, criteria StockOpnameSearchCriteria) (ids []int64, count int64, err error)
+	Create(ctx context.Context, userID int64, opname *StockOpname) error
+	// SaveCounts store the counts of the user along with the current stock,
+	// return ErrStockOpnameNotOpen when the stock opname is already approved
+	SaveCounts(ctx context.Context, userID, opnameID int64, counts []*StockOpnameCount) error
+	// Approve post the variances of the counts as adjustment movements in a single db transaction,
+	// return ErrStockOpnameNotOpen when the stock opname is already approved
+	Approve(ctx context.Context, userID int64, opname *StockOpname) error
+}
+
+// StockOpnameUsecase usecase
+type StockOpnameUsecase interface {
+	FindByID(ctx context.Context, requester *User, id int64) (*StockOpname, error)
+	Search(ctx context.Context, requester *User, criteria StockOpnameSearchCriteria) (opnames AnyStockOpnames, count int64, err error)
+	Start(ctx context.Context, requester *User, input StartStockOpnameInput) (*StockOpname, error)
+	SubmitCounts(ctx context.Context, requester *User, id int64, input SubmitStockOpnameCountsInput) (*StockOpname, error)
+	Approve(ctx context.Context, requester *User, id int64) (*StockOpname, error)
+}
+
+// StartStockOpnameInput input to start a stock opname
+type StartStockOpnameInput struct {
+	Note string `json:"note" validate:"max=200" example:"stock opname akhir bulan"`
+}
+
+// Validate validate start stock opname input
+func (s *StartStockOpnameInput) Validate() error {
+	return validate.Struct(s)
+}
+
+// SubmitStockOpnameCountsInput the counted products, the products which are not counted yet
+// can be submitted later or by another user
+type SubmitStockOpnameCountsInput struct {
+	Counts []StockOpnameCountInput `json:"counts" validate:"required,min=1,max=500,dive"`
+}
+
+// StockOpnameCountInput the counted quantity of a product, the variant is required for a product with variants
+type StockOpnameCountInput struct {
+	ProductID int64 `json:"product_id" validate:"required" example:"1695599921375543118"`
+	VariantID int64 `json:"variant_id" validate:"gte=0" example:"0"`
+	Quantity  int64 `json:"quantity" validate:"gte=0" example:"8"`
+}
+
+// Validate validate submit stock opname counts input, a product may only be counted once per submission
+func (s *SubmitStockOpnameCountsInput) Validate() error {
+	if err := validate.Struct(s); err != nil {
+		return err
+	}
+
+	keys := make(map[ProductVariantKey]bool, len(s.Counts))
+	for _, count := range s.Counts {
+		key := ProductVariantKey{ProductID: count.ProductID, VariantID: count.VariantID}
+		if keys[key] {
+			return ErrDuplicateProductVariant
+		}
+		keys[key] = true
+	}
+
+	return nil
+}
+
+// StockOpnameSearchCriteria criteria for searching stock opname
+type StockOpnameSearchCriteria struct {
+	Page   int               `json:"page" query:"page"`
+	Size   int               `json:"size" query:"size"`
+	Status StockOpnameStatus `json:"status" query:"status"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *StockOpnameSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+}
+
+type StockOpnameVarianceResponse struct {
+	ProductID       string   `json:"product_id" example:"1695599921375543118"`
+	VariantID       string   `json:"variant_id" example:"0"`
+	SystemQuantity  string   `json:"system_quantity" example:"10"`
+	CountedQuantity string   `json:"counted_quantity" example:"8"`
+	Variance        string   `json:"variance" example:"-2"`
+	CountedBy       []string `json:"counted_by" example:"1695599921375543118"`
+}
+
+func (s StockOpnameVariance) ToStockOpnameVarianceResponse() StockOpnameVarianceResponse {
+	countedBy := make([]string, 0, len(s.CountedBy))
+	for _, userID := range s.CountedBy {
+		countedBy = append(countedBy, utils.Int64ToString(userID))
+	}
+
+	return StockOpnameVarianceResponse{
+		ProductID:       utils.Int64ToString(s.ProductID),
+		VariantID:       utils.Int64ToString(s.VariantID),
+		SystemQuantity:  utils.Int64ToString(s.SystemQuantity),
+		CountedQuantity: utils.Int64ToString(s.CountedQuantity),
+		Variance:        utils.Int64ToString(s.Variance),
+		CountedBy:       countedBy,
+	}
+}
+
+type StockOpnameResponse struct {
+	ID         string                        `json:"id" example:"1695599921375543118"`
+	Status     string                        `json:"status" example:"OPEN"`
+	Note       string                        `json:"note" example:"stock opname akhir bulan"`
+	StartedBy  string                        `json:"started_by" example:"1695599921375543118"`
+	ApprovedBy string                        `json:"approved_by" example:"0"`
+	ApprovedAt string                        `json:"approved_at" example:""`
+	CreatedAt  string                        `json:"created_at" example:"30 September 2023 20:00 WIB"`
+	Variances  []StockOpnameVarianceResponse `json:"variances"`
+}
+
+func (s StockOpname) ToStockOpnameResponse() StockOpnameResponse {
+	variances := AnyStockOpnameCounts(s.Counts).Variances()
+	response := StockOpnameResponse{
+		ID:         utils.Int64ToString(s.ID),
+		Status:     string(s.Status),
+		Note:       s.Note,
+		StartedBy:  utils.Int64ToString(s.StartedBy),
+		ApprovedBy: utils.Int64ToString(s.ApprovedBy),
+		CreatedAt:  utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.CreatedAt),
+		Variances:  make([]StockOpnameVarianceResponse, 0, len(variances)),
+	}
+	if s.ApprovedAt != nil {
+		response.ApprovedAt = utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, s.ApprovedAt)
+	}
+
+	for _, variance := range variances {
+		response.Variances = append(response.Variances, variance.ToStockOpnameVarianceResponse())
+	}
+
+	return response
+}
+
+type AnyStockOpnames []*StockOpname
+
+func (as AnyStockOpnames) ToListStockOpnameResponse() (responses []StockOpnameResponse) {
+	for _, opname := range as {
+		responses = append(responses, opname.ToStockOpnameResponse())
+	}
+
+	return responses
+}
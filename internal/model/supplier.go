@@ -0,0 +1,120 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ErrUnknownSupplier error when the supplier of a purchase order does not exist
+var ErrUnknownSupplier = errors.New("unknown supplier")
+
+// Supplier the vendor which the products are purchased from
+type Supplier struct {
+	ID          int64          `json:"id" gorm:"primary_key"`
+	Name        string         `json:"name"`
+	ContactName string         `json:"contact_name"`
+	Phone       string         `json:"phone"`
+	Email       string         `json:"email"`
+	Address     string         `json:"address"`
+	Note        string         `json:"note"`
+	CreatedAt   time.Time      `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+	UpdatedAt   time.Time      `json:"updated_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at"`
+}
+
+// SupplierRepository repository
+type SupplierRepository interface {
+	FindByID(ctx context.Context, id int64) (*Supplier, error)
+	SearchByPage(ctx context.Context, criteria SupplierSearchCriteria) (ids []int64, count int64, err error)
+	Create(ctx context.Context, userID int64, supplier *Supplier) error
+	Update(ctx context.Context, userID int64, supplier *Supplier) error
+	// Delete soft delete the supplier, its purchase orders are kept
+	Delete(ctx context.Context, userID int64, supplier *Supplier) error
+}
+
+// SupplierUsecase usecase
+type SupplierUsecase interface {
+	FindByID(ctx context.Context, requester *User, id int64) (*Supplier, error)
+	Search(ctx context.Context, requester *User, criteria SupplierSearchCriteria) (suppliers AnySuppliers, count int64, err error)
+	Create(ctx context.Context, requester *User, input CreateSupplierInput) (*Supplier, error)
+	UpdateByID(ctx context.Context, requester *User, id int64, input UpdateSupplierInput) (*Supplier, error)
+	DeleteByID(ctx context.Context, requester *User, id int64) error
+}
+
+// CreateSupplierInput create supplier input
+type CreateSupplierInput struct {
+	Name        string `json:"name" validate:"required,min=2,max=60" example:"CV Sumber Pangan"`
+	ContactName string `json:"contact_name" validate:"max=60" example:"Budi"`
+	Phone       string `json:"phone" validate:"max=20" example:"081234567890"`
+	Email       string `json:"email" validate:"omitempty,email,max=100" example:"order@sumberpangan.co.id"`
+	Address     string `json:"address" validate:"max=200" example:"Jl. Kaliurang KM 5, Sleman"`
+	Note        string `json:"note" validate:"max=200" example:"pengiriman setiap senin"`
+}
+
+type UpdateSupplierInput = CreateSupplierInput
+
+// Validate validate supplier input
+func (c *CreateSupplierInput) Validate() error {
+	return validate.Struct(c)
+}
+
+// SupplierSearchCriteria criteria for searching supplier by name
+type SupplierSearchCriteria struct {
+	Query string `json:"query" query:"query"`
+	Page  int    `json:"page" query:"page"`
+	Size  int    `json:"size" query:"size"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *SupplierSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+}
+
+type SupplierResponse struct {
+	ID          string `json:"id" example:"1695599921375543118"`
+	Name        string `json:"name" example:"CV Sumber Pangan"`
+	ContactName string `json:"contact_name" example:"Budi"`
+	Phone       string `json:"phone" example:"081234567890"`
+	Email       string `json:"email" example:"order@sumberpangan.co.id"`
+	Address     string `json:"address" example:"Jl. Kaliurang KM 5, Sleman"`
+	Note        string `json:"note" example:"pengiriman setiap senin"`
+	CreatedAt   string `json:"created_at" example:"25 September 2023 13:59 WIB"`
+	UpdatedAt   string `json:"updated_at" example:"25 September 2023 13:59 WIB"`
+}
+
+func (s Supplier) ToSupplierResponse() SupplierResponse {
+	return SupplierResponse{
+		ID:          utils.Int64ToString(s.ID),
+		Name:        s.Name,
+		ContactName: s.ContactName,
+		Phone:       s.Phone,
+		Email:       s.Email,
+		Address:     s.Address,
+		Note:        s.Note,
+		CreatedAt:   utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.CreatedAt),
+		UpdatedAt:   utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &s.UpdatedAt),
+	}
+}
+
+type AnySuppliers []*Supplier
+
+func (as AnySuppliers) ToListSupplierResponse() (responses []SupplierResponse) {
+	for _, supplier := range as {
+		responses = append(responses, supplier.ToSupplierResponse())
+	}
+
+	return responses
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrUnknownTaxCategory error when the tax category has no configured rate
+var ErrUnknownTaxCategory = errors.New("unknown tax category")
+
+// TaxCategory tax category of a product
+type TaxCategory string
+
+// TaxCategory constants, the other categories can be added through the config
+const (
+	TaxCategoryStandard TaxCategory = "STANDARD"
+	TaxCategoryExempt   TaxCategory = "EXEMPT"
+)
+
+// basisPoints 100% in basis points
+const basisPoints = 10000
+
+// TaxSetting rates used to calculate the tax & service charge of a transaction
+type TaxSetting struct {
+	// Rates tax rate in percent of each tax category, e.g. 11 for the PPN
+	Rates map[TaxCategory]float64
+	// ServiceChargeRate service charge in percent of the net sales, taxed with the standard rate
+	ServiceChargeRate float64
+	// PriceIncludeTax the product price already includes the tax
+	PriceIncludeTax bool
+}
+
+// TaxCalculation the result of the tax calculation, TaxBase only covers the taxable sales
+type TaxCalculation struct {
+	TaxBase       int64
+	TaxAmount     int64
+	ServiceCharge int64
+	TotalPrice    int64
+}
+
+// HasCategory check if the tax category has a configured rate
+func (s TaxSetting) HasCategory(category TaxCategory) bool {
+	_, ok := s.Rates[category]
+	return ok
+}
+
+// Calculate the tax of the details after the cart discount.
+// The cart discount is prorated to the lines so every line is taxed with the rate of its own category.
+// On exclusive pricing the tax is added on top of the net sales, on inclusive pricing the tax is extracted from it.
+func (s TaxSetting) Calculate(details []*TransactionDetail, cartDiscount int64) TaxCalculation {
+	var linesTotal int64
+	for _, detail := range details {
+		linesTotal += detail.Subtotal
+	}
+
+	var calc TaxCalculation
+	var netSales int64
+	remainingDiscount := cartDiscount
+	for i, detail := range details {
+		// the last line takes the rounding remainder of the prorated discount
+		discount := remainingDiscount
+		if i < len(details)-1 && linesTotal > 0 {
+			discount = cartDiscount * detail.Subtotal / linesTotal
+		}
+		remainingDiscount -= discount
+
+		rate := s.rateOf(detail.TaxCategory)
+		base, tax := s.split(detail.Subtotal-discount, rate)
+		netSales += base
+		if rate > 0 {
+			calc.TaxBase += base
+			calc.TaxAmount += tax
+		}
+	}
+
+	calc.ServiceCharge = percentOf(netSales, s.ServiceChargeRate)
+	if rate := s.rateOf(TaxCategoryStandard); rate > 0 {
+		calc.TaxBase += calc.ServiceCharge
+		calc.TaxAmount += percentOf(calc.ServiceCharge, rate)
+	}
+	calc.TotalPrice = netSales + calc.ServiceCharge + calc.TaxAmount
+
+	return calc
+}
+
+// split the amount into its tax base and tax
+func (s TaxSetting) split(amount int64, rate float64) (base, tax int64) {
+	if !s.PriceIncludeTax {
+		return amount, percentOf(amount, rate)
+	}
+
+	base = int64(math.Round(float64(amount) * basisPoints / (basisPoints + rate*100)))
+	return base, amount - base
+}
+
+func (s TaxSetting) rateOf(category TaxCategory) float64 {
+	if category == "" {
+		category = TaxCategoryStandard
+	}
+
+	return s.Rates[category]
+}
+
+// percentOf calculate the rate in percent of the amount, rounded to the nearest rupiah
+func percentOf(amount int64, rate float64) int64 {
+	return int64(math.Round(float64(amount) * rate / 100))
+}
@@ -2,30 +2,352 @@ package model
 
 import (
 	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"sort"
 	"time"
 )
 
+// transaction search criteria errors
+var (
+	ErrInvalidDateRange   = errors.New("invalid date range")
+	ErrInvalidAmountRange = errors.New("invalid amount range")
+)
+
+// DateLayout layout for date only query params
+const DateLayout = "2006-01-02"
+
+// Transaction model, Discount is the total discount of the lines, the cart promotion & the manual discount
 type Transaction struct {
-	ID         int64     `json:"id"`
-	TotalPrice int64     `json:"total_price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
-	AmountPaid int64     `json:"amount_paid" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
-	Change     int64     `json:"change" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
-	CreatedBy  int64     `json:"created_by" gorm:"->;<-:create"`                                           // create & read only
-	CreatedAt  time.Time `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+	ID              int64     `json:"id"`
+	TotalPrice      int64     `json:"total_price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Discount        int64     `json:"discount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	ManualDiscount  int64     `json:"manual_discount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	TaxBase         int64     `json:"tax_base" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	TaxAmount       int64     `json:"tax_amount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	ServiceCharge   int64     `json:"service_charge" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	PriceIncludeTax bool      `json:"price_include_tax"`
+	ReceiptPrints   int64     `json:"receipt_prints"`
+	ShiftID         int64     `json:"shift_id"`
+	AmountPaid      int64     `json:"amount_paid" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Change          int64     `json:"change" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	CreatedBy       int64     `json:"created_by" gorm:"->;<-:create"`                                           // create & read only
+	CreatedAt       time.Time `json:"created_at" sql:"DEFAULT:'now()':::STRING::TIMESTAMP" gorm:"->;<-:create"` // create & read only
+
+	TransactionDetails    []*TransactionDetail    `json:"transaction_details" gorm:"-"`
+	TransactionPayments   []*TransactionPayment   `json:"transaction_payments" gorm:"-"`
+	TransactionPromotions []*TransactionPromotion `json:"transaction_promotions" gorm:"-"`
+	Refunds               []*Refund               `json:"refunds" gorm:"-"`
+}
+
+// LineTotalPrices the share of every line in the total price keyed by product & variant,
+// which deducts the cart & manual discount and adds the tax & service charge.
+// The last line takes the rounding remainder, so the shares add up to the total price.
+func (t Transaction) LineTotalPrices() map[ProductVariantKey]int64 {
+	var linesTotal int64
+	for _, detail := range t.TransactionDetails {
+		linesTotal += detail.Subtotal
+	}
+
+	shares := make(map[ProductVariantKey]int64, len(t.TransactionDetails))
+	remaining := t.TotalPrice
+	for i, detail := range t.TransactionDetails {
+		share := remaining
+		if i < len(t.TransactionDetails)-1 && linesTotal > 0 {
+			share = detail.Subtotal * t.TotalPrice / linesTotal
+		}
+		remaining -= share
+		shares[detail.Key()] += share
+	}
+
+	return shares
+}
+
+// RefundAmount the amount refunded for the given quantity of the sold line, prorated from the share of the line in the total price.
+// The refund returning the last remaining units of the line takes what is left of its share,
+// so the partial refunds of a line add up to its share.
+func (t Transaction) RefundAmount(sold *TransactionDetail, quantity int64) int64 {
+	if sold.Quantity <= 0 {
+		return 0
+	}
+
+	share := t.LineTotalPrices()[sold.Key()]
+	refunded := AnyRefunds(t.Refunds).RefundedDetails()[sold.Key()]
+	if refunded.Quantity+quantity >= sold.Quantity {
+		return share - refunded.Subtotal
+	}
+
+	return share * quantity / sold.Quantity
+}
+
+// RefundCashAmount the part of a refund amount paid back in cash. The refund is split by tender
+// in proportion to the cash kept from the sale, which is the cash tendered minus the change,
+// and capped at the cash kept which is not refunded yet
+func (t Transaction) RefundCashAmount(amount int64) int64 {
+	var cashTendered int64
+	for _, payment := range t.TransactionPayments {
+		if payment.Method == PaymentMethodCash {
+			cashTendered += payment.Amount
+		}
+	}
+
+	cashKept := cashTendered - t.Change
+	if cashKept <= 0 || t.TotalPrice <= 0 {
+		return 0
+	}
+
+	cash := amount * cashKept / t.TotalPrice
+	remaining := cashKept - AnyRefunds(t.Refunds).CashAmount()
+	if cash > remaining {
+		cash = remaining
+	}
+	if cash < 0 {
+		return 0
+	}
 
-	TransactionDetails []*TransactionDetail `json:"transaction_details" gorm:"-"`
+	return cash
+}
+
+// NetTotalPrice total price after deducted by all refunds
+func (t Transaction) NetTotalPrice() int64 {
+	return t.TotalPrice - AnyRefunds(t.Refunds).TotalAmount()
+}
+
+// StockMovements the sale movements of the sold lines
+func (t Transaction) StockMovements(userID int64) []*StockMovement {
+	var movements []*StockMovement
+	for _, detail := range t.TransactionDetails {
+		movements = append(movements, &StockMovement{
+			ProductID:     detail.ProductID,
+			VariantID:     detail.VariantID,
+			Type:          StockMovementTypeSale,
+			Quantity:      -detail.Quantity,
+			ReferenceType: StockReferenceTransaction,
+			ReferenceID:   t.ID,
+			CreatedBy:     userID,
+		})
+	}
+
+	return movements
 }
 
 type TransactionRepository interface {
+	FindByID(ctx context.Context, id int64) (*Transaction, error)
+	SearchByPage(ctx context.Context, criteria TransactionSearchCriteria) (ids []int64, count int64, err error)
 	Create(ctx context.Context, userID int64, transaction *Transaction) error
+	// RecordReceiptPrint increase the receipt print count and audit the print, return the print count after increased
+	RecordReceiptPrint(ctx context.Context, userID int64, transaction *Transaction) (printCount int64, err error)
 }
 
 type TransactionUsecase interface {
+	FindByID(ctx context.Context, requester *User, id int64) (*Transaction, error)
+	Search(ctx context.Context, requester *User, criteria TransactionSearchCriteria) (transactions AnyTransactions, count int64, err error)
 	Create(ctx context.Context, requester *User, input CreateTransactionInput) (*Transaction, error)
+	Void(ctx context.Context, requester *User, id int64, input VoidTransactionInput) (*Refund, error)
+	Refund(ctx context.Context, requester *User, id int64, input RefundTransactionInput) (*Refund, error)
+	// PrintReceipt render the receipt of the transaction and record the print, a print after the first one is audited as reprint
+	PrintReceipt(ctx context.Context, requester *User, id int64, format ReceiptFormat) (*Receipt, error)
 }
 
-// CreateTransactionInput to create a new transaction
+// CreateTransactionInput to create a new transaction,
+// AmountPaid is treated as a single cash payment when TransactionPayments is empty,
+// ManualDiscount is given on top of the promotions and limited by the requester role
 type CreateTransactionInput struct {
-	TransactionDetails []TransactionDetail `json:"transaction_details"`
-	AmountPaid         int64               `json:"amount_paid"`
+	TransactionDetails  []TransactionDetail  `json:"transaction_details" validate:"required,min=1,dive"`
+	TransactionPayments []TransactionPayment `json:"transaction_payments" validate:"omitempty,dive"`
+	AmountPaid          int64                `json:"amount_paid" validate:"gte=0"`
+	ManualDiscount      int64                `json:"manual_discount" validate:"gte=0"`
+}
+
+// Validate validate transaction input
+func (c *CreateTransactionInput) Validate() error {
+	return validate.Struct(c)
+}
+
+// Payments the tenders of the transaction
+func (c *CreateTransactionInput) Payments() []TransactionPayment {
+	if len(c.TransactionPayments) > 0 {
+		return c.TransactionPayments
+	}
+
+	if c.AmountPaid <= 0 {
+		return nil
+	}
+
+	return []TransactionPayment{{Method: PaymentMethodCash, Amount: c.AmountPaid}}
+}
+
+// MergedTransactionDetails merge the details with the same product & variant and sort them by product then variant,
+// so every transaction locks the stock rows in the same order
+func (c *CreateTransactionInput) MergedTransactionDetails() []TransactionDetail {
+	quantities := make(map[ProductVariantKey]int64)
+	for _, detail := range c.TransactionDetails {
+		quantities[detail.Key()] += detail.Quantity
+	}
+
+	details := make([]TransactionDetail, 0, len(quantities))
+	for key, quantity := range quantities {
+		details = append(details, TransactionDetail{
+			ProductID: key.ProductID,
+			VariantID: key.VariantID,
+			Quantity:  quantity,
+		})
+	}
+
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Key().Less(details[j].Key())
+	})
+
+	return details
+}
+
+// TransactionSortType sort type for transaction search
+type TransactionSortType string
+
+const (
+	TransactionSortTypeCreatedAtAsc   TransactionSortType = "CREATED_AT_ASC"
+	TransactionSortTypeCreatedAtDesc  TransactionSortType = "CREATED_AT_DESC"
+	TransactionSortTypeTotalPriceAsc  TransactionSortType = "TOTAL_PRICE_ASC"
+	TransactionSortTypeTotalPriceDesc TransactionSortType = "TOTAL_PRICE_DESC"
+)
+
+// QueryTransactionSortByMap sort type to query string map for database ordering
+var QueryTransactionSortByMap = map[TransactionSortType]string{
+	TransactionSortTypeCreatedAtAsc:   "created_at ASC",
+	TransactionSortTypeCreatedAtDesc:  "created_at DESC",
+	TransactionSortTypeTotalPriceAsc:  "total_price ASC",
+	TransactionSortTypeTotalPriceDesc: "total_price DESC",
+}
+
+// TransactionSearchCriteria criteria for searching & sorting transaction
+type TransactionSearchCriteria struct {
+	Page          int                 `json:"page" query:"page"`
+	Size          int                 `json:"size" query:"size"`
+	SortType      TransactionSortType `json:"sort_type" query:"sortBy"`
+	CreatedBy     int64               `json:"created_by" query:"createdBy"`
+	ProductID     int64               `json:"product_id" query:"productID"`
+	StartDate     string              `json:"start_date" query:"startDate" example:"2023-09-25"`
+	EndDate       string              `json:"end_date" query:"endDate" example:"2023-09-30"`
+	MinTotalPrice int64               `json:"min_total_price" query:"minTotalPrice"`
+	MaxTotalPrice int64               `json:"max_total_price" query:"maxTotalPrice"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *TransactionSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+
+	if c.SortType == "" {
+		c.SortType = TransactionSortTypeCreatedAtDesc
+	}
+}
+
+// Validate validate the date range and amount range of the criteria
+func (c *TransactionSearchCriteria) Validate() error {
+	startAt, endAt, err := c.DateRange()
+	if err != nil {
+		return err
+	}
+
+	if startAt != nil && endAt != nil && endAt.Before(*startAt) {
+		return ErrInvalidDateRange
+	}
+
+	if c.MinTotalPrice < 0 || c.MaxTotalPrice < 0 {
+		return ErrInvalidAmountRange
+	}
+
+	if c.MaxTotalPrice > 0 && c.MaxTotalPrice < c.MinTotalPrice {
+		return ErrInvalidAmountRange
+	}
+
+	return nil
+}
+
+// DateRange parse StartDate & EndDate on western indonesian time.
+// The returned endAt is exclusive, which is the start of the day after EndDate.
+func (c *TransactionSearchCriteria) DateRange() (startAt, endAt *time.Time, err error) {
+	if c.StartDate != "" {
+		t, err := utils.ParseWesternIndonesianDate(DateLayout, c.StartDate)
+		if err != nil {
+			return nil, nil, ErrInvalidDateRange
+		}
+		startAt = &t
+	}
+
+	if c.EndDate != "" {
+		t, err := utils.ParseWesternIndonesianDate(DateLayout, c.EndDate)
+		if err != nil {
+			return nil, nil, ErrInvalidDateRange
+		}
+		t = t.AddDate(0, 0, 1)
+		endAt = &t
+	}
+
+	return startAt, endAt, nil
+}
+
+type TransactionResponse struct {
+	ID                    string                         `json:"id" example:"1695599921375543118"`
+	TotalPrice            string                         `json:"total_price" example:"Rp10.000"`
+	Discount              string                         `json:"discount" example:"Rp0"`
+	ManualDiscount        string                         `json:"manual_discount" example:"Rp0"`
+	TaxBase               string                         `json:"tax_base" example:"Rp10.000"`
+	TaxAmount             string                         `json:"tax_amount" example:"Rp1.100"`
+	ServiceCharge         string                         `json:"service_charge" example:"Rp0"`
+	PriceIncludeTax       bool                           `json:"price_include_tax" example:"false"`
+	AmountPaid            string                         `json:"amount_paid" example:"Rp20.000"`
+	Change                string                         `json:"change" example:"Rp10.000"`
+	CreatedBy             string                         `json:"created_by" example:"1695599921375543118"`
+	ShiftID               string                         `json:"shift_id" example:"1695599921375543118"`
+	CreatedAt             string                         `json:"created_at" example:"25 September 2023 13:59 WIB"`
+	RefundedAmount        string                         `json:"refunded_amount" example:"Rp0"`
+	NetTotalPrice         string                         `json:"net_total_price" example:"Rp10.000"`
+	TransactionDetails    []TransactionDetailResponse    `json:"transaction_details"`
+	TransactionPayments   []TransactionPaymentResponse   `json:"transaction_payments"`
+	TransactionPromotions []TransactionPromotionResponse `json:"transaction_promotions"`
+	Refunds               []RefundResponse               `json:"refunds"`
+}
+
+func (t Transaction) ToTransactionResponse() TransactionResponse {
+	return TransactionResponse{
+		ID:                    utils.Int64ToString(t.ID),
+		TotalPrice:            utils.Int64ToRupiah(t.TotalPrice),
+		Discount:              utils.Int64ToRupiah(t.Discount),
+		ManualDiscount:        utils.Int64ToRupiah(t.ManualDiscount),
+		TaxBase:               utils.Int64ToRupiah(t.TaxBase),
+		TaxAmount:             utils.Int64ToRupiah(t.TaxAmount),
+		ServiceCharge:         utils.Int64ToRupiah(t.ServiceCharge),
+		PriceIncludeTax:       t.PriceIncludeTax,
+		AmountPaid:            utils.Int64ToRupiah(t.AmountPaid),
+		Change:                utils.Int64ToRupiah(t.Change),
+		CreatedBy:             utils.Int64ToString(t.CreatedBy),
+		ShiftID:               utils.Int64ToString(t.ShiftID),
+		CreatedAt:             utils.FormatToWesternIndonesianTime(WesternIndonesiaLayout, &t.CreatedAt),
+		RefundedAmount:        utils.Int64ToRupiah(AnyRefunds(t.Refunds).TotalAmount()),
+		NetTotalPrice:         utils.Int64ToRupiah(t.NetTotalPrice()),
+		TransactionDetails:    AnyTransactionDetails(t.TransactionDetails).ToListTransactionDetailResponse(),
+		TransactionPayments:   AnyTransactionPayments(t.TransactionPayments).ToListTransactionPaymentResponse(),
+		TransactionPromotions: AnyTransactionPromotions(t.TransactionPromotions).ToListTransactionPromotionResponse(),
+		Refunds:               AnyRefunds(t.Refunds).ToListRefundResponse(),
+	}
+}
+
+type AnyTransactions []*Transaction
+
+func (at AnyTransactions) ToListTransactionResponse() (transactionResponses []TransactionResponse) {
+	for _, transaction := range at {
+		transactionResponses = append(transactionResponses, transaction.ToTransactionResponse())
+	}
+
+	return transactionResponses
 }
@@ -2,16 +2,89 @@ package model
 
 import (
 	"context"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"gorm.io/gorm"
 )
 
+// TransactionDetail a sold line, the product name, slug, variant name, sku, unit price, unit cost & tax category are snapshots
+// taken at sale time so the history stays intact after the product is renamed, repriced or deleted.
+// The UnitCost is the moving average cost price of the product, it is kept out of the responses.
+// It is NULL when the cost was unknown at sale time, i.e. the product had no cost price recorded
+// or the line was sold before the cost was snapshotted, such lines are left out of the profit reports.
+// VariantID is zero for a product without variants.
+// An input line may give the scanned Barcode instead of the product & variant, the code is resolved before the sale.
 type TransactionDetail struct {
-	TransactionID int64 `json:"transaction_id"`
-	ProductID     int64 `json:"product_id"`
-	Quantity      int64 `json:"quantity"`
-	Subtotal      int64 `json:"subtotal" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	TransactionID int64       `json:"transaction_id"`
+	ProductID     int64       `json:"product_id" validate:"required_without=Barcode"`
+	VariantID     int64       `json:"variant_id" validate:"gte=0"`
+	Barcode       string      `json:"barcode,omitempty" gorm:"-" validate:"max=64"`
+	ProductName   string      `json:"product_name"`
+	ProductSlug   string      `json:"product_slug"`
+	VariantName   string      `json:"variant_name"`
+	SKU           string      `json:"sku"`
+	UnitPrice     int64       `json:"unit_price" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	UnitCost      *int64      `json:"unit_cost" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Quantity      int64       `json:"quantity" validate:"gt=0"`
+	Discount      int64       `json:"discount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Subtotal      int64       `json:"subtotal" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	TaxCategory   TaxCategory `json:"tax_category"`
+}
+
+// Key the product & variant of the line
+func (t TransactionDetail) Key() ProductVariantKey {
+	return ProductVariantKey{ProductID: t.ProductID, VariantID: t.VariantID}
+}
+
+// DisplayName the product name along with the variant name, e.g. "Es Teh (L)"
+func (t TransactionDetail) DisplayName() string {
+	if t.VariantName == "" {
+		return t.ProductName
+	}
+
+	return t.ProductName + " (" + t.VariantName + ")"
 }
 
 type TransactionDetailRepository interface {
+	FindByTransactionID(ctx context.Context, transactionID int64) ([]*TransactionDetail, error)
 	Create(ctx context.Context, tx *gorm.DB, detail []*TransactionDetail) error
 }
+
+type TransactionDetailResponse struct {
+	ProductID   string `json:"product_id" example:"1695599921375543118"`
+	VariantID   string `json:"variant_id" example:"0"`
+	ProductName string `json:"product_name" example:"Pisang Goreng"`
+	ProductSlug string `json:"product_slug" example:"pisang-goreng"`
+	VariantName string `json:"variant_name" example:""`
+	SKU         string `json:"sku" example:""`
+	UnitPrice   string `json:"unit_price" example:"Rp5.000"`
+	Quantity    string `json:"quantity" example:"2"`
+	Discount    string `json:"discount" example:"Rp0"`
+	Subtotal    string `json:"subtotal" example:"Rp10.000"`
+	TaxCategory string `json:"tax_category" example:"STANDARD"`
+}
+
+func (t TransactionDetail) ToTransactionDetailResponse() TransactionDetailResponse {
+	return TransactionDetailResponse{
+		ProductID:   utils.Int64ToString(t.ProductID),
+		VariantID:   utils.Int64ToString(t.VariantID),
+		ProductName: t.ProductName,
+		ProductSlug: t.ProductSlug,
+		VariantName: t.VariantName,
+		SKU:         t.SKU,
+		UnitPrice:   utils.Int64ToRupiah(t.UnitPrice),
+		Quantity:    utils.Int64ToString(t.Quantity),
+		Discount:    utils.Int64ToRupiah(t.Discount),
+		Subtotal:    utils.Int64ToRupiah(t.Subtotal),
+		TaxCategory: string(t.TaxCategory),
+	}
+}
+
+type AnyTransactionDetails []*TransactionDetail
+
+func (ad AnyTransactionDetails) ToListTransactionDetailResponse() (detailResponses []TransactionDetailResponse) {
+	for _, detail := range ad {
+		detailResponses = append(detailResponses, detail.ToTransactionDetailResponse())
+	}
+
+	return detailResponses
+}
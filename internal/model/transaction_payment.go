@@ -0,0 +1,88 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+)
+
+// transaction payment errors
+var (
+	ErrInsufficientPayment = errors.New("payment is less than the total price")
+	ErrNonCashOverpayment  = errors.New("non cash payment exceeds the total price")
+)
+
+// PaymentMethod method of a transaction payment
+type PaymentMethod string
+
+// PaymentMethod constants
+const (
+	PaymentMethodCash         PaymentMethod = "CASH"
+	PaymentMethodDebitCard    PaymentMethod = "DEBIT_CARD"
+	PaymentMethodCreditCard   PaymentMethod = "CREDIT_CARD"
+	PaymentMethodBankTransfer PaymentMethod = "BANK_TRANSFER"
+	PaymentMethodEWallet      PaymentMethod = "E_WALLET"
+	PaymentMethodQRIS         PaymentMethod = "QRIS"
+)
+
+// TransactionPayment one tender of a transaction, a sale can be split across several tenders
+type TransactionPayment struct {
+	TransactionID int64         `json:"transaction_id"`
+	Method        PaymentMethod `json:"method" validate:"required,oneof=CASH DEBIT_CARD CREDIT_CARD BANK_TRANSFER E_WALLET QRIS"`
+	Amount        int64         `json:"amount" validate:"gt=0" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+	Reference     string        `json:"reference" validate:"max=100"`
+}
+
+type TransactionPaymentRepository interface {
+	FindByTransactionID(ctx context.Context, transactionID int64) ([]*TransactionPayment, error)
+	Create(ctx context.Context, tx *gorm.DB, payments []*TransactionPayment) error
+}
+
+type AnyTransactionPayments []*TransactionPayment
+
+// CalculateChange calculate the amount paid & change of the payments against the total price.
+// The change is given from the cash part only, so the non cash payments can never exceed the total price.
+func (ap AnyTransactionPayments) CalculateChange(totalPrice int64) (amountPaid, change int64, err error) {
+	var cash, nonCash int64
+	for _, payment := range ap {
+		if payment.Method == PaymentMethodCash {
+			cash += payment.Amount
+			continue
+		}
+		nonCash += payment.Amount
+	}
+
+	if nonCash > totalPrice {
+		return 0, 0, ErrNonCashOverpayment
+	}
+
+	amountPaid = cash + nonCash
+	if amountPaid < totalPrice {
+		return 0, 0, ErrInsufficientPayment
+	}
+
+	return amountPaid, amountPaid - totalPrice, nil
+}
+
+type TransactionPaymentResponse struct {
+	Method    PaymentMethod `json:"method" example:"CASH"`
+	Amount    string        `json:"amount" example:"Rp20.000"`
+	Reference string        `json:"reference" example:""`
+}
+
+func (t TransactionPayment) ToTransactionPaymentResponse() TransactionPaymentResponse {
+	return TransactionPaymentResponse{
+		Method:    t.Method,
+		Amount:    utils.Int64ToRupiah(t.Amount),
+		Reference: t.Reference,
+	}
+}
+
+func (ap AnyTransactionPayments) ToListTransactionPaymentResponse() (paymentResponses []TransactionPaymentResponse) {
+	for _, payment := range ap {
+		paymentResponses = append(paymentResponses, payment.ToTransactionPaymentResponse())
+	}
+
+	return paymentResponses
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"gorm.io/gorm"
+)
+
+// TransactionPromotion a promotion applied to a transaction, ProductID is empty for cart promotions
+type TransactionPromotion struct {
+	TransactionID int64  `json:"transaction_id"`
+	PromotionID   int64  `json:"promotion_id"`
+	PromotionName string `json:"promotion_name"`
+	ProductID     int64  `json:"product_id"`
+	Discount      int64  `json:"discount" sql:"type:decimal(20,0)" gorm:"type:numeric(20,0)"`
+}
+
+type TransactionPromotionRepository interface {
+	FindByTransactionID(ctx context.Context, transactionID int64) ([]*TransactionPromotion, error)
+	Create(ctx context.Context, tx *gorm.DB, promotions []*TransactionPromotion) error
+}
+
+type TransactionPromotionResponse struct {
+	PromotionID   string `json:"promotion_id" example:"1695599921375543118"`
+	PromotionName string `json:"promotion_name" example:"Happy Hour Pisang Goreng"`
+	ProductID     string `json:"product_id" example:"1695599921375543118"`
+	Discount      string `json:"discount" example:"Rp1.000"`
+}
+
+func (t TransactionPromotion) ToTransactionPromotionResponse() TransactionPromotionResponse {
+	return TransactionPromotionResponse{
+		PromotionID:   utils.Int64ToString(t.PromotionID),
+		PromotionName: t.PromotionName,
+		ProductID:     utils.Int64ToString(t.ProductID),
+		Discount:      utils.Int64ToRupiah(t.Discount),
+	}
+}
+
+type AnyTransactionPromotions []*TransactionPromotion
+
+func (ap AnyTransactionPromotions) ToListTransactionPromotionResponse() (promotionResponses []TransactionPromotionResponse) {
+	for _, promotion := range ap {
+		promotionResponses = append(promotionResponses, promotion.ToTransactionPromotionResponse())
+	}
+
+	return promotionResponses
+}
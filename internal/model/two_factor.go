@@ -0,0 +1,124 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// two factor authentication errors
+var (
+	ErrInvalidTwoFactorCode      = errors.New("invalid two factor code")
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two factor challenge")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two factor authentication already enabled")
+	ErrTwoFactorNotEnabled       = errors.New("two factor authentication not enabled")
+	ErrTwoFactorNotEnrolled      = errors.New("two factor enrollment not started")
+	ErrTwoFactorRequired         = errors.New("two factor authentication is required for the role")
+)
+
+// UserTwoFactor the TOTP secret of the user, the two factor authentication is pending until
+// the first code of the authenticator app is confirmed
+type UserTwoFactor struct {
+	UserID int64  `json:"user_id" gorm:"primaryKey"`
+	Secret string `json:"-"`
+	// LastUsedStep the time step of the last accepted code, a code can not be used twice
+	LastUsedStep int64      `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"->;<-:create"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsEnabled check whether the enrollment is confirmed
+func (u *UserTwoFactor) IsEnabled() bool {
+	return u != nil && u.EnabledAt != nil
+}
+
+// RecoveryCode a single use code to log in without the authenticator app, only the hash of the code is stored
+type RecoveryCode struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"->;<-:create"`
+}
+
+// TwoFactorChallenge the second step of a login whose password is verified, only the hash of the token is stored
+type TwoFactorChallenge struct {
+	ID        int64  `json:"id"`
+	UserID    int64  `json:"user_id"`
+	AppID     int64  `json:"app_id"`
+	TokenHash string `json:"-"`
+	// Token the plain token, only known when the challenge is created
+	Token string `json:"-" gorm:"-"`
+	// Enrollment the user has to enroll first since the role requires two factor authentication
+	Enrollment bool       `json:"enrollment"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	ExpiredAt  time.Time  `json:"expired_at"`
+	UsedAt     *time.Time `json:"used_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"->;<-:create"`
+}
+
+// IsExpired check expired at against now
+func (t *TwoFactorChallenge) IsExpired() bool {
+	return time.Now().After(t.ExpiredAt)
+}
+
+// TwoFactorEnrollment the secret to be added to the authenticator app, either typed or scanned from the QR code
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+	// QRCode the PNG image of the URI
+	QRCode []byte `json:"-"`
+}
+
+// TwoFactorRepository repository
+type TwoFactorRepository interface {
+	// FindByUserID find the two factor of the user, return nil when the user never enrolls
+	FindByUserID(ctx context.Context, userID int64) (*UserTwoFactor, error)
+	// SavePending create or replace the pending enrollment of the user, an enabled one is kept as is
+	SavePending(ctx context.Context, twoFactor *UserTwoFactor) error
+	// Enable confirm the enrollment of the user along with its first recovery codes
+	Enable(ctx context.Context, userID int64, recoveryCodeHashes []string) error
+	// ReplaceRecoveryCodes delete the recovery codes of the user and store the new ones
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, recoveryCodeHashes []string) error
+	// UseStep record the time step of an accepted code, return false when the step or a later one is already used
+	UseStep(ctx context.Context, userID, step int64) (bool, error)
+	// UseRecoveryCode mark the recovery code used, return false when the user has no such unused code
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+	// DeleteByUserID delete the two factor & the recovery codes of the user
+	DeleteByUserID(ctx context.Context, userID int64) error
+
+	CreateChallenge(ctx context.Context, challenge *TwoFactorChallenge) error
+	// FindChallengeByTokenHash find the challenge by its hash, return nil when not found
+	FindChallengeByTokenHash(ctx context.Context, tokenHash string) (*TwoFactorChallenge, error)
+	// UseChallenge mark the challenge used, return false when the challenge is already used
+	UseChallenge(ctx context.Context, challenge *TwoFactorChallenge) (bool, error)
+}
+
+// TwoFactorCodeInput a code of the authenticator app
+type TwoFactorCodeInput struct {
+	Code string `json:"code" validate:"required,numeric,len=6" example:"123456"`
+}
+
+// Validate validate the input
+func (t *TwoFactorCodeInput) Validate() error {
+	return validate.Struct(t)
+}
+
+// DisableTwoFactorInput turn off the two factor authentication, proven by the password and either a code or a recovery code
+type DisableTwoFactorInput struct {
+	Password     string `json:"password" validate:"required" example:"123456"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6" example:"123456"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code" example:""`
+}
+
+// Validate validate the input
+func (d *DisableTwoFactorInput) Validate() error {
+	return validate.Struct(d)
+}
+
+// VerifyTwoFactorRequest request, the challenge is answered either with a code or with a recovery code
+type VerifyTwoFactorRequest struct {
+	ChallengeToken, Code, RecoveryCode string
+}
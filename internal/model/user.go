@@ -5,12 +5,26 @@ import (
 	"errors"
 	"github.com/irvankadhafi/go-point-of-sales/rbac"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
 // ErrPasswordMismatch error
 var ErrPasswordMismatch = errors.New("password mismatch")
 
+// ErrUnknownRole error when the role can not be given to a user
+var ErrUnknownRole = errors.New("unknown role")
+
+// ErrUserSelfChange error when the requester changes the role or the status of their own user,
+// so an admin can never lock themselves out
+var ErrUserSelfChange = errors.New("can not change the role or status of your own user")
+
+// user status errors, a user must be active to log in or to use their tokens
+var (
+	ErrUserPending  = errors.New("user is pending activation")
+	ErrUserInactive = errors.New("user is deactivated")
+)
+
 type UserRepository interface {
 	Create(ctx context.Context, userID int64, user *User) error
 	Update(ctx context.Context, userID int64, user *User) (*User, error)
@@ -20,6 +34,13 @@ type UserRepository interface {
 	IsLoginByEmailPasswordLocked(ctx context.Context, email string) (bool, error)
 	IncrementLoginByEmailPasswordRetryAttempts(ctx context.Context, email string) error
 	FindPasswordByID(ctx context.Context, id int64) ([]byte, error)
+	FindPINByID(ctx context.Context, id int64) ([]byte, error)
+	UpdatePINByID(ctx context.Context, userID int64, pin string) error
+	IsLoginByPINLocked(ctx context.Context, appID, userID int64) (bool, error)
+	IncrementLoginByPINRetryAttempts(ctx context.Context, appID, userID int64) error
+	IsLoginByTwoFactorLocked(ctx context.Context, userID int64) (bool, error)
+	IncrementLoginByTwoFactorRetryAttempts(ctx context.Context, userID int64) error
+	SearchByPage(ctx context.Context, criteria UserSearchCriteria) (ids []int64, count int64, err error)
 }
 
 type UserUsecase interface {
@@ -27,6 +48,21 @@ type UserUsecase interface {
 	Create(ctx context.Context, requester *User, input CreateUserInput) (*User, error)
 	ChangePassword(ctx context.Context, requester *User, input ChangePasswordInput) (*User, error)
 	UpdateProfile(ctx context.Context, requester *User, input UpdateProfileInput) (*User, error)
+	Search(ctx context.Context, requester *User, criteria UserSearchCriteria) (users AnyUsers, count int64, err error)
+	// ChangeRoleByID change the role of the user, the sessions of the user are revoked so the new role
+	// takes effect on the next login
+	ChangeRoleByID(ctx context.Context, requester *User, id int64, input ChangeUserRoleInput) (*User, error)
+	// ActivateByID activate the user, the sessions of the user are revoked along with their cached status
+	ActivateByID(ctx context.Context, requester *User, id int64) (*User, error)
+	// DeactivateByID deactivate the user and revoke all of their sessions
+	DeactivateByID(ctx context.Context, requester *User, id int64) (*User, error)
+	// ForgotPassword mail a password reset token to the active user with the email,
+	// nothing is told when there is no such user or the mail fails
+	ForgotPassword(ctx context.Context, input ForgotPasswordInput) error
+	// ResetPassword reset the password with the token and revoke all of the sessions of the user
+	ResetPassword(ctx context.Context, input ResetPasswordInput) error
+	// ChangePIN set the PIN of the requester, used to log in on the POS terminals
+	ChangePIN(ctx context.Context, requester *User, input ChangePINInput) error
 }
 
 // User :nodoc:
@@ -57,6 +93,18 @@ const (
 	StatusInactive UserStatus = "INACTIVE"
 )
 
+// Err return ErrUserPending or ErrUserInactive when the user with the status is not allowed to log in
+func (s UserStatus) Err() error {
+	switch s {
+	case StatusActive:
+		return nil
+	case StatusPending:
+		return ErrUserPending
+	default:
+		return ErrUserInactive
+	}
+}
+
 // SetPermission set permission to user
 func (u *User) SetPermission(perm *rbac.Permission) {
 	if perm == nil {
@@ -88,27 +136,130 @@ func (u *User) HasAccess(resource rbac.Resource, action rbac.Action) bool {
 	return u.rolePerm.HasAccess(resource, action)
 }
 
+// CanGiveManualDiscount check if the manual discount is within the maximum percent of the user role
+func (u *User) CanGiveManualDiscount(discount, total int64) bool {
+	if discount <= 0 {
+		return true
+	}
+
+	return discount*100 <= total*rbac.MaxManualDiscountPercent(u.Role)
+}
+
 // IsAdmin check if the user ADMIN
 func (u *User) IsAdmin() bool {
 	return u.Role == rbac.RoleAdmin
 }
 
+// IsAssignableRole check if the role can be given to a user, the internal service role is reserved for the services
+func IsAssignableRole(role rbac.Role) bool {
+	switch role {
+	case rbac.RoleAdmin, rbac.RoleProductManager, rbac.RoleCashiers, rbac.RoleFinancialAuditor:
+		return true
+	default:
+		return false
+	}
+}
+
 type CreateUserInput struct {
-	Name                 string `json:"name" validate:"required"`
-	Email                string `json:"email" validate:"required,email"`
-	Password             string `json:"password" validate:"required,min=6"`
-	PasswordConfirmation string `json:"password_confirmation" validate:"required,min=6,eqfield=Password"`
+	Name                 string    `json:"name" validate:"required" example:"Budi Santoso"`
+	Email                string    `json:"email" validate:"required,email" example:"budi@mail.com"`
+	Password             string    `json:"password" validate:"required,min=6" example:"123456"`
+	PasswordConfirmation string    `json:"password_confirmation" validate:"required,min=6,eqfield=Password" example:"123456"`
+	Role                 rbac.Role `json:"role" validate:"required" example:"CASHIER"`
 }
 
-// ValidateAndFormat validate and format the phone number
+// ValidateAndFormat validate the input and upper case the role
 func (c *CreateUserInput) ValidateAndFormat() error {
+	c.Role = rbac.Role(strings.ToUpper(string(c.Role)))
 	if err := validate.Struct(c); err != nil {
 		return err
 	}
 
+	if !IsAssignableRole(c.Role) {
+		return ErrUnknownRole
+	}
+
 	return nil
 }
 
+// ChangeUserRoleInput change the role of a user
+type ChangeUserRoleInput struct {
+	Role rbac.Role `json:"role" validate:"required" example:"PRODUCT_MANAGER"`
+}
+
+// ValidateAndFormat validate the input and upper case the role
+func (c *ChangeUserRoleInput) ValidateAndFormat() error {
+	c.Role = rbac.Role(strings.ToUpper(string(c.Role)))
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+
+	if !IsAssignableRole(c.Role) {
+		return ErrUnknownRole
+	}
+
+	return nil
+}
+
+// UserSearchCriteria criteria for searching user by name or email, optionally filtered by the role & status
+type UserSearchCriteria struct {
+	Query  string     `json:"query" query:"query"`
+	Role   rbac.Role  `json:"role" query:"role"`
+	Status UserStatus `json:"status" query:"status"`
+	Page   int        `json:"page" query:"page"`
+	Size   int        `json:"size" query:"size"`
+}
+
+// SetDefaultValue will set default value for page and size if zero
+func (c *UserSearchCriteria) SetDefaultValue() {
+	if c.Page <= 0 {
+		c.Page = 1
+	}
+
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+
+	if c.Size >= 20 {
+		c.Size = 20
+	}
+
+	c.Role = rbac.Role(strings.ToUpper(string(c.Role)))
+	c.Status = UserStatus(strings.ToUpper(string(c.Status)))
+}
+
+type UserResponse struct {
+	ID        int64      `json:"id" example:"1695599921375543118"`
+	Name      string     `json:"name" example:"Budi Santoso"`
+	Email     string     `json:"email" example:"budi@mail.com"`
+	Role      rbac.Role  `json:"role" example:"CASHIER"`
+	Status    UserStatus `json:"status" example:"ACTIVE"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (u *User) ToUserResponse() UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		Role:      u.Role,
+		Status:    u.Status,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+type AnyUsers []*User
+
+func (au AnyUsers) ToListUserResponse() (responses []UserResponse) {
+	for _, user := range au {
+		responses = append(responses, user.ToUserResponse())
+	}
+
+	return
+}
+
 type UpdateProfileInput struct {
 	Name string `json:"name" validate:"required"`
 }
@@ -128,6 +279,18 @@ type ChangePasswordInput struct {
 	NewPasswordConfirmation string `json:"new_password_confirmation" validate:"required,min=6,eqfield=NewPassword"`
 }
 
+// ChangePINInput set the PIN of the user, the password is required so a left open session can not set the PIN
+type ChangePINInput struct {
+	Password        string `json:"password" validate:"required"`
+	PIN             string `json:"pin" validate:"required,numeric,min=4,max=6" example:"1234"`
+	PINConfirmation string `json:"pin_confirmation" validate:"required,eqfield=PIN" example:"1234"`
+}
+
+// Validate validate the input
+func (c *ChangePINInput) Validate() error {
+	return validate.Struct(c)
+}
+
 // Validate validate user's password & input body
 func (c *ChangePasswordInput) Validate() error {
 	if c.NewPassword != c.NewPasswordConfirmation {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/go-playground/validator"
+	"github.com/irvankadhafi/go-point-of-sales/internal/barcode"
 	"sync"
 )
 
@@ -13,5 +14,11 @@ var initOnce sync.Once
 func init() {
 	initOnce.Do(func() {
 		validate = validator.New()
+		_ = validate.RegisterValidation("barcode", validateBarcode)
 	})
 }
+
+// validateBarcode the field must be an EAN-13 or UPC-A with a valid check digit, or a Code128
+func validateBarcode(fl validator.FieldLevel) bool {
+	return barcode.Validate(fl.Field().String()) == nil
+}
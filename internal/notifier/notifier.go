@@ -0,0 +1,105 @@
+// Package notifier send the low stock alerts to a log, a file or a webhook
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// notifier kinds
+const (
+	KindLog     = "log"
+	KindFile    = "file"
+	KindWebhook = "webhook"
+)
+
+// ErrUnknownKind error when the notifier kind is not supported
+var ErrUnknownKind = errors.New("unknown notifier kind")
+
+// Config where the alerts are sent, Target is the file path of the file notifier and the url of the webhook notifier
+type Config struct {
+	Kind    string
+	Target  string
+	Timeout time.Duration
+}
+
+// New create the notifier of the config kind
+func New(cfg Config) (model.StockAlertNotifier, error) {
+	switch cfg.Kind {
+	case KindLog:
+		return NewLogNotifier(), nil
+	case KindFile:
+		return NewFileNotifier(cfg.Target)
+	case KindWebhook:
+		return NewWebhookNotifier(cfg.Target, cfg.Timeout)
+	default:
+		return nil, ErrUnknownKind
+	}
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier create a notifier which writes the alerts to the application log
+func NewLogNotifier() model.StockAlertNotifier {
+	return &logNotifier{}
+}
+
+// Notify log the alert as a warning
+func (l *logNotifier) Notify(_ context.Context, alert *model.LowStockAlert) error {
+	logrus.WithFields(logrus.Fields{
+		"productID":       alert.ProductID,
+		"productName":     alert.ProductName,
+		"sku":             alert.SKU,
+		"quantity":        alert.Quantity,
+		"minStock":        alert.MinStock,
+		"reorderQuantity": alert.ReorderQuantity,
+	}).Warn("product stock is low")
+
+	return nil
+}
+
+type writerNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterNotifier create a notifier which writes every alert as a JSON line to w
+func NewWriterNotifier(w io.Writer) model.StockAlertNotifier {
+	return &writerNotifier{w: w}
+}
+
+// NewFileNotifier create a notifier which appends every alert as a JSON line to the file,
+// the file is created when it does not exist
+func NewFileNotifier(path string) (model.StockAlertNotifier, error) {
+	if path == "" {
+		return nil, errors.New("notifier file path is required")
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWriterNotifier(file), nil
+}
+
+// Notify write the alert as a JSON line
+func (w *writerNotifier) Notify(_ context.Context, alert *model.LowStockAlert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err = w.w.Write(append(line, '\n'))
+	return err
+}
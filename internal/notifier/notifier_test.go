@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+var testAlert = &model.LowStockAlert{
+	ProductID:       222,
+	ProductName:     "Pisang Goreng",
+	SKU:             "PISANG-GORENG",
+	Quantity:        3,
+	MinStock:        5,
+	ReorderQuantity: 20,
+	CreatedAt:       time.Date(2023, 9, 25, 13, 0, 0, 0, time.UTC),
+}
+
+func TestWriterNotifier_Notify(t *testing.T) {
+	buf := &bytes.Buffer{}
+	n := NewWriterNotifier(buf)
+
+	require.NoError(t, n.Notify(context.TODO(), testAlert))
+	require.NoError(t, n.Notify(context.TODO(), testAlert))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	alert := &model.LowStockAlert{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), alert))
+	require.Equal(t, testAlert, alert)
+}
+
+func TestNew(t *testing.T) {
+	t.Run("file notifier append to the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "alerts.log")
+		n, err := New(Config{Kind: KindFile, Target: path})
+		require.NoError(t, err)
+		require.NoError(t, n.Notify(context.TODO(), testAlert))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Contains(t, string(content), `"sku":"PISANG-GORENG"`)
+	})
+
+	t.Run("file notifier require the path", func(t *testing.T) {
+		_, err := New(Config{Kind: KindFile})
+		require.Error(t, err)
+	})
+
+	t.Run("webhook notifier require a valid url", func(t *testing.T) {
+		_, err := New(Config{Kind: KindWebhook, Target: "not a url"})
+		require.Error(t, err)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := New(Config{Kind: "sms"})
+		require.ErrorIs(t, err, ErrUnknownKind)
+	})
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var payload WebhookPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		n, err := NewWebhookNotifier(server.URL, time.Second)
+		require.NoError(t, err)
+		require.NoError(t, n.Notify(context.TODO(), testAlert))
+		require.Equal(t, WebhookEventLowStock, payload.Event)
+		require.Equal(t, testAlert, payload.Alert)
+	})
+
+	t.Run("failed - non 2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		n, err := NewWebhookNotifier(server.URL, time.Second)
+		require.NoError(t, err)
+		require.Error(t, n.Notify(context.TODO(), testAlert))
+	})
+}
@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WebhookEventLowStock the event of the low stock alert posted to the webhook
+const WebhookEventLowStock = "stock.low"
+
+// WebhookPayload the body posted to the webhook
+type WebhookPayload struct {
+	Event string               `json:"event"`
+	Alert *model.LowStockAlert `json:"alert"`
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier create a notifier which posts every alert as JSON to the url
+func NewWebhookNotifier(webhookURL string, timeout time.Duration) (model.StockAlertNotifier, error) {
+	if _, err := url.ParseRequestURI(webhookURL); err != nil {
+		return nil, errors.New("notifier webhook url is invalid")
+	}
+
+	return &webhookNotifier{
+		url:    webhookURL,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Notify post the alert to the webhook, a non 2xx response is an error so the alert is sent again on the next check
+func (w *webhookNotifier) Notify(ctx context.Context, alert *model.LowStockAlert) error {
+	body, err := json.Marshal(WebhookPayload{Event: WebhookEventLowStock, Alert: alert})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.WrapCloser(res.Body.Close)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,456 @@
+// Package qrcode encode the text as QR code symbols (ISO/IEC 18004) in the byte mode with the medium error correction level
+package qrcode
+
+import "errors"
+
+// QuietZone the modules kept blank around the symbol so the scanners can find it
+const QuietZone = 4
+
+// ErrDataTooLong error when the data does not fit the largest supported version
+var ErrDataTooLong = errors.New("data too long for a QR code")
+
+// version the error correction blocks & alignment patterns of a version with the medium error correction level
+type version struct {
+	ecCodewords int
+	// groups the count & the data codewords of the blocks, the blocks of the second group carry one more data codeword
+	groups     [2][2]int
+	alignments []int
+}
+
+// versions the versions 1 to 15, enough for an otpauth URI with a long account name
+var versions = [...]version{
+	{ecCodewords: 10, groups: [2][2]int{{1, 16}}},
+	{ecCodewords: 16, groups: [2][2]int{{1, 28}}, alignments: []int{6, 18}},
+	{ecCodewords: 26, groups: [2][2]int{{1, 44}}, alignments: []int{6, 22}},
+	{ecCodewords: 18, groups: [2][2]int{{2, 32}}, alignments: []int{6, 26}},
+	{ecCodewords: 24, groups: [2][2]int{{2, 43}}, alignments: []int{6, 30}},
+	{ecCodewords: 16, groups: [2][2]int{{4, 27}}, alignments: []int{6, 34}},
+	{ecCodewords: 18, groups: [2][2]int{{4, 31}}, alignments: []int{6, 22, 38}},
+	{ecCodewords: 22, groups: [2][2]int{{2, 38}, {2, 39}}, alignments: []int{6, 24, 42}},
+	{ecCodewords: 22, groups: [2][2]int{{3, 36}, {2, 37}}, alignments: []int{6, 26, 46}},
+	{ecCodewords: 26, groups: [2][2]int{{4, 43}, {1, 44}}, alignments: []int{6, 28, 50}},
+	{ecCodewords: 30, groups: [2][2]int{{1, 50}, {4, 51}}, alignments: []int{6, 30, 54}},
+	{ecCodewords: 22, groups: [2][2]int{{6, 36}, {2, 37}}, alignments: []int{6, 32, 58}},
+	{ecCodewords: 22, groups: [2][2]int{{8, 37}, {1, 38}}, alignments: []int{6, 34, 62}},
+	{ecCodewords: 24, groups: [2][2]int{{4, 40}, {5, 41}}, alignments: []int{6, 26, 46, 66}},
+	{ecCodewords: 24, groups: [2][2]int{{5, 41}, {5, 42}}, alignments: []int{6, 26, 48, 70}},
+}
+
+func (v version) dataCodewords() int {
+	return v.groups[0][0]*v.groups[0][1] + v.groups[1][0]*v.groups[1][1]
+}
+
+// countBits the length of the character count indicator of the byte mode
+func countBits(number int) int {
+	if number < 10 {
+		return 8
+	}
+	return 16
+}
+
+// Code an encoded QR code symbol, the quiet zone is left to the renderer
+type Code struct {
+	Size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// Dark check whether the module at the column x & the row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode encode the data with the smallest version it fits and the mask with the lowest penalty
+func Encode(data string) (*Code, error) {
+	number := 0
+	for i, v := range versions {
+		if 4+countBits(i+1)+len(data)*8 <= v.dataCodewords()*8 {
+			number = i + 1
+			break
+		}
+	}
+	if number == 0 {
+		return nil, ErrDataTooLong
+	}
+
+	v := versions[number-1]
+	c := newCode(number)
+	c.drawFunctionPatterns(number, v)
+	c.drawCodewords(addErrorCorrection(encodeData(data, number, v), v))
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		c.applyMask(mask)
+	}
+
+	c.applyMask(bestMask)
+	c.drawFormatBits(bestMask)
+	return c, nil
+}
+
+func newCode(number int) *Code {
+	size := number*4 + 17
+	c := &Code{
+		Size:     size,
+		modules:  make([][]bool, size),
+		function: make([][]bool, size),
+	}
+	for y := 0; y < size; y++ {
+		c.modules[y] = make([]bool, size)
+		c.function[y] = make([]bool, size)
+	}
+
+	return c
+}
+
+// encodeData the data codewords, the byte mode segment followed by the terminator & the pad codewords
+func encodeData(data string, number int, v version) []byte {
+	capacity := v.dataCodewords() * 8
+	bb := &bitBuffer{}
+	bb.append(0b0100, 4)
+	bb.append(len(data), countBits(number))
+	for i := 0; i < len(data); i++ {
+		bb.append(int(data[i]), 8)
+	}
+
+	terminator := capacity - len(bb.bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb.bits)%8)%8)
+	for pad := 0xEC; len(bb.bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	return bb.bytes()
+}
+
+// addErrorCorrection split the data codewords into the blocks and interleave them with their error correction codewords
+func addErrorCorrection(data []byte, v version) []byte {
+	divisor := reedSolomonDivisor(v.ecCodewords)
+	var blocks, ecBlocks [][]byte
+	for _, group := range v.groups {
+		for i := 0; i < group[0]; i++ {
+			block := data[:group[1]]
+			data = data[group[1]:]
+			blocks = append(blocks, block)
+			ecBlocks = append(ecBlocks, reedSolomonRemainder(block, divisor))
+		}
+	}
+
+	var result []byte
+	for i := 0; i <= v.groups[0][1]; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecCodewords; i++ {
+		for _, ec := range ecBlocks {
+			result = append(result, ec[i])
+		}
+	}
+
+	return result
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns(number int, v version) {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	last := len(v.alignments) - 1
+	for i, y := range v.alignments {
+		for j, x := range v.alignments {
+			// the alignment patterns overlapping the finder patterns are skipped
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+
+	// reserve the format modules, they are drawn once the mask is chosen
+	c.drawFormatBits(0)
+	c.drawVersion(number)
+}
+
+// drawFinder draw the finder pattern along with its separator centered at x & y
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draw both copies of the format information of the medium error correction level & the mask
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+// drawVersion draw both copies of the version information, only the version 7 and up carry it
+func (c *Code) drawVersion(number int) {
+	if number < 7 {
+		return
+	}
+
+	bits := versionBits(number)
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// formatBits the BCH(15,5) code of the format information, the medium level is encoded as 00
+func formatBits(mask int) int {
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionBits the BCH(18,6) code of the version information
+func versionBits(number int) int {
+	rem := number
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+
+	return number<<12 | rem
+}
+
+// drawCodewords place the codewords in the two modules wide columns zigzagging up & down from the bottom right corner,
+// the remainder modules are left light
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		// the vertical timing pattern is skipped entirely
+		if right == 6 {
+			right = 5
+		}
+
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+
+				c.modules[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flip the data modules matched by the mask, applying the same mask twice undo it
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+
+			if flip {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// finderLike the 1:1:3:1:1 pattern preceded or followed by four light modules, penalized since it confuses the scanners
+var finderLike = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// penalty score the symbol by the four rules of the specification, the lower the better
+func (c *Code) penalty() int {
+	penalty, dark := 0, 0
+	for i := 0; i < c.Size; i++ {
+		rowRun, colRun := 1, 1
+		for j := 0; j < c.Size; j++ {
+			if c.modules[i][j] {
+				dark++
+			}
+
+			if j > 0 {
+				rowRun, penalty = run(c.modules[i][j] == c.modules[i][j-1], rowRun, penalty)
+				colRun, penalty = run(c.modules[j][i] == c.modules[j-1][i], colRun, penalty)
+			}
+
+			if i > 0 && j > 0 {
+				m := c.modules[i][j]
+				if m == c.modules[i-1][j] && m == c.modules[i][j-1] && m == c.modules[i-1][j-1] {
+					penalty += 3
+				}
+			}
+
+			if j+11 <= c.Size {
+				for _, pattern := range finderLike {
+					if c.matchRow(i, j, pattern) {
+						penalty += 40
+					}
+					if c.matchColumn(i, j, pattern) {
+						penalty += 40
+					}
+				}
+			}
+		}
+	}
+
+	percent := dark * 100 / (c.Size * c.Size)
+	return penalty + abs(percent-50)/5*10
+}
+
+// run extend the run of the same colored modules, the runs of five and more modules are penalized
+func run(same bool, length, penalty int) (int, int) {
+	if !same {
+		return 1, penalty
+	}
+
+	length++
+	switch {
+	case length == 5:
+		penalty += 3
+	case length > 5:
+		penalty++
+	}
+
+	return length, penalty
+}
+
+func (c *Code) matchRow(y, x int, pattern [11]bool) bool {
+	for i, dark := range pattern {
+		if c.modules[y][x+i] != dark {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Code) matchColumn(x, y int, pattern [11]bool) bool {
+	for i, dark := range pattern {
+		if c.modules[y+i][x] != dark {
+			return false
+		}
+	}
+	return true
+}
+
+type bitBuffer struct {
+	bits []bool
+}
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		b.bits = append(b.bits, value>>i&1 == 1)
+	}
+}
+
+func (b *bitBuffer) bytes() []byte {
+	result := make([]byte, len(b.bits)/8)
+	for i, dark := range b.bits {
+		if dark {
+			result[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return result
+}
+
+func bit(value, i int) bool {
+	return value>>i&1 == 1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
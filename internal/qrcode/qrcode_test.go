@@ -0,0 +1,199 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// the 1-M data codewords of "HELLO WORLD" from the specification examples
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	ec := reedSolomonRemainder(data, reedSolomonDivisor(10))
+	require.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ec)
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	require.Equal(t, 0b101010000010010, formatBits(0))
+	require.Equal(t, 0b100010111111001, formatBits(4))
+	require.Equal(t, 0b100101010100000, formatBits(7))
+	require.Equal(t, 0x07C94, versionBits(7))
+	require.Equal(t, 0x0F928, versionBits(15))
+}
+
+func TestFunctionPatterns(t *testing.T) {
+	remainderBits := []int{0, 7, 7, 7, 7, 7, 0, 0, 0, 0, 0, 0, 0, 3, 3}
+	for i, v := range versions {
+		number := i + 1
+		c := newCode(number)
+		c.drawFunctionPatterns(number, v)
+
+		dataModules := 0
+		for y := 0; y < c.Size; y++ {
+			for x := 0; x < c.Size; x++ {
+				if !c.function[y][x] {
+					dataModules++
+				}
+			}
+		}
+
+		totalCodewords := v.dataCodewords() + v.ecCodewords*(v.groups[0][0]+v.groups[1][0])
+		require.Equal(t, totalCodewords*8+remainderBits[i], dataModules, "version %d", number)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		data    string
+		version int
+	}{
+		{data: "hello", version: 1},
+		{data: strings.Repeat("a", 14), version: 1},
+		{data: strings.Repeat("a", 15), version: 2},
+		{data: "otpauth://totp/Go%20Point%20of%20Sales:irvankadhafi@mail.com?algorithm=SHA1&digits=6&issuer=Go%20Point%20of%20Sales&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP", version: 9},
+		{data: strings.Repeat("a", 213), version: 10},
+		{data: strings.Repeat("a", 412), version: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			c, err := Encode(tt.data)
+			require.NoError(t, err)
+			require.Equal(t, tt.version*4+17, c.Size)
+			require.Equal(t, tt.data, decode(t, c, tt.version))
+		})
+	}
+
+	_, err := Encode(strings.Repeat("a", 413))
+	require.ErrorIs(t, err, ErrDataTooLong)
+}
+
+func TestPNG(t *testing.T) {
+	c, err := Encode("hello")
+	require.NoError(t, err)
+
+	b, err := c.PNG(4)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	require.Equal(t, (21+QuietZone*2)*4, img.Bounds().Dx())
+}
+
+// decode read the data back from the symbol, the mask is taken from the format information
+// and every error correction block must have no error
+func decode(t *testing.T, c *Code, number int) string {
+	v := versions[number-1]
+
+	format := 0
+	for i := 0; i < 8; i++ {
+		if c.Dark(c.Size-1-i, 8) {
+			format |= 1 << i
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if c.Dark(8, c.Size-15+i) {
+			format |= 1 << i
+		}
+	}
+
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m) == format {
+			mask = m
+		}
+	}
+	require.NotEqual(t, -1, mask, "unknown format information")
+
+	unmasked := newCode(number)
+	unmasked.drawFunctionPatterns(number, v)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !unmasked.function[y][x] {
+				unmasked.modules[y][x] = c.modules[y][x]
+			}
+		}
+	}
+	unmasked.applyMask(mask)
+
+	bb := &bitBuffer{}
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+
+			for j := 0; j < 2; j++ {
+				if !unmasked.function[y][right-j] {
+					bb.bits = append(bb.bits, unmasked.modules[y][right-j])
+				}
+			}
+		}
+	}
+	codewords := bb.bytes()
+
+	var blocks [][]byte
+	for _, group := range v.groups {
+		for i := 0; i < group[0]; i++ {
+			blocks = append(blocks, make([]byte, 0, group[1]+v.ecCodewords))
+		}
+	}
+
+	pos := 0
+	for i := 0; i <= v.groups[0][1]; i++ {
+		for b, group := 0, 0; b < len(blocks); b++ {
+			if b >= v.groups[0][0] {
+				group = 1
+			}
+			if i < v.groups[group][1] {
+				blocks[b] = append(blocks[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+	for i := 0; i < v.ecCodewords; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[pos])
+			pos++
+		}
+	}
+
+	divisor := reedSolomonDivisor(v.ecCodewords)
+	data := &bitBuffer{}
+	for _, block := range blocks {
+		dataLen := len(block) - v.ecCodewords
+		require.Equal(t, block[dataLen:], reedSolomonRemainder(block[:dataLen], divisor))
+		for _, b := range block[:dataLen] {
+			data.append(int(b), 8)
+		}
+	}
+
+	read := func(offset, length int) int {
+		value := 0
+		for _, dark := range data.bits[offset : offset+length] {
+			value <<= 1
+			if dark {
+				value |= 1
+			}
+		}
+		return value
+	}
+
+	require.Equal(t, 0b0100, read(0, 4))
+	n := read(4, countBits(number))
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = byte(read(4+countBits(number)+i*8, 8))
+	}
+
+	return string(result)
+}
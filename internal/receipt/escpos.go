@@ -0,0 +1,47 @@
+package receipt
+
+import "bytes"
+
+// ESC/POS commands supported by most of the thermal printers
+var (
+	escposInit    = []byte{0x1b, 0x40}             // ESC @, reset the printer
+	escposBoldOn  = []byte{0x1b, 0x45, 0x01}       // ESC E 1
+	escposBoldOff = []byte{0x1b, 0x45, 0x00}       // ESC E 0
+	escposFeed    = []byte{0x1b, 0x64, 0x04}       // ESC d 4, feed 4 lines before cutting
+	escposCut     = []byte{0x1d, 0x56, 0x42, 0x00} // GS V 66 0, partial cut
+)
+
+// ESCPOS render the receipt as ESC/POS command bytes of the given characters per line,
+// the text is printed on the printer code page so the non ASCII characters are replaced
+func ESCPOS(data Data, width int) []byte {
+	var buf bytes.Buffer
+	buf.Write(escposInit)
+	for _, l := range buildLines(data, width) {
+		if l.bold {
+			buf.Write(escposBoldOn)
+		}
+		buf.WriteString(toASCII(l.text))
+		if l.bold {
+			buf.Write(escposBoldOff)
+		}
+		buf.WriteByte('\n')
+	}
+	buf.Write(escposFeed)
+	buf.Write(escposCut)
+
+	return buf.Bytes()
+}
+
+// toASCII replace the characters outside of the printable ASCII with '?'
+func toASCII(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			out = append(out, '?')
+			continue
+		}
+		out = append(out, byte(r))
+	}
+
+	return string(out)
+}
@@ -0,0 +1,246 @@
+// Package receipt render the receipt of a transaction as plain text, ESC/POS command bytes or PDF
+package receipt
+
+import (
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"strings"
+	"unicode/utf8"
+)
+
+// characters per line of the thermal paper using the default font
+const (
+	width58mm = 32
+	width80mm = 48
+)
+
+var paymentMethodLabels = map[model.PaymentMethod]string{
+	model.PaymentMethodCash:         "Tunai",
+	model.PaymentMethodDebitCard:    "Kartu Debit",
+	model.PaymentMethodCreditCard:   "Kartu Kredit",
+	model.PaymentMethodBankTransfer: "Transfer Bank",
+	model.PaymentMethodEWallet:      "E-Wallet",
+	model.PaymentMethodQRIS:         "QRIS",
+}
+
+// Data everything printed on the receipt
+type Data struct {
+	Transaction *model.Transaction
+	CashierName string
+	Setting     model.ReceiptSetting
+	// PrintCount how many times the receipt has been printed, above one is marked as reprint
+	PrintCount int64
+}
+
+// line a single printed line, the text is already padded to the paper width
+type line struct {
+	text string
+	bold bool
+}
+
+// Render render the receipt data on the given format
+func Render(format model.ReceiptFormat, data Data) (*model.Receipt, error) {
+	receipt := &model.Receipt{
+		TransactionID: data.Transaction.ID,
+		Format:        format,
+		PrintCount:    data.PrintCount,
+	}
+
+	name := fmt.Sprintf("receipt-%d", data.Transaction.ID)
+	switch format {
+	case model.ReceiptFormatText58, model.ReceiptFormatText80:
+		receipt.Content = Text(data, paperWidth(format))
+		receipt.ContentType = "text/plain; charset=utf-8"
+		receipt.Filename = name + ".txt"
+	case model.ReceiptFormatESCPOS58, model.ReceiptFormatESCPOS80:
+		receipt.Content = ESCPOS(data, paperWidth(format))
+		receipt.ContentType = "application/octet-stream"
+		receipt.Filename = name + ".bin"
+	case model.ReceiptFormatPDF:
+		receipt.Content = PDF(data)
+		receipt.ContentType = "application/pdf"
+		receipt.Filename = name + ".pdf"
+	default:
+		return nil, model.ErrUnknownReceiptFormat
+	}
+
+	return receipt, nil
+}
+
+func paperWidth(format model.ReceiptFormat) int {
+	if format == model.ReceiptFormatText58 || format == model.ReceiptFormatESCPOS58 {
+		return width58mm
+	}
+
+	return width80mm
+}
+
+// Text render the receipt as plain text of the given characters per line
+func Text(data Data, width int) []byte {
+	var sb strings.Builder
+	for _, l := range buildLines(data, width) {
+		sb.WriteString(l.text)
+		sb.WriteString("\n")
+	}
+
+	return []byte(sb.String())
+}
+
+// buildLines lay out the receipt lines, amounts use the rupiah format and the time is printed on WIB
+func buildLines(data Data, width int) []line {
+	t := data.Transaction
+	var lines []line
+	add := func(text string, bold bool) {
+		for _, text := range strings.Split(text, "\n") {
+			lines = append(lines, line{text: text, bold: bold})
+		}
+	}
+	separator := func() {
+		add(strings.Repeat("-", width), false)
+	}
+
+	for _, header := range data.Setting.Header {
+		for _, text := range wrap(header, width) {
+			add(center(text, width), true)
+		}
+	}
+	if data.PrintCount > 1 {
+		add(center(fmt.Sprintf("*** CETAK ULANG #%d ***", data.PrintCount-1), width), true)
+	}
+	separator()
+
+	add(columns("No", utils.Int64ToString(t.ID), width), false)
+	add(columns("Waktu", utils.FormatToWesternIndonesianTime(model.WesternIndonesiaLayout, &t.CreatedAt), width), false)
+	add(columns("Kasir", data.CashierName, width), false)
+	separator()
+
+	var subtotal, lineDiscount int64
+	for _, detail := range t.TransactionDetails {
+		for _, text := range wrap(detail.DisplayName(), width) {
+			add(text, false)
+		}
+		quantity := fmt.Sprintf("  %d x %s", detail.Quantity, utils.Int64ToRupiah(detail.UnitPrice))
+		add(columns(quantity, utils.Int64ToRupiah(detail.UnitPrice*detail.Quantity), width), false)
+		if detail.Discount > 0 {
+			add(columns("  Diskon", negativeRupiah(detail.Discount), width), false)
+		}
+
+		subtotal += detail.Subtotal
+		lineDiscount += detail.Discount
+	}
+	separator()
+
+	add(columns("Subtotal", utils.Int64ToRupiah(subtotal), width), false)
+	if cartDiscount := t.Discount - lineDiscount; cartDiscount > 0 {
+		add(columns("Diskon", negativeRupiah(cartDiscount), width), false)
+	}
+	if t.ServiceCharge > 0 {
+		add(columns("Biaya Layanan", utils.Int64ToRupiah(t.ServiceCharge), width), false)
+	}
+	if t.TaxAmount > 0 {
+		label := "PPN"
+		if t.PriceIncludeTax {
+			label = "PPN (termasuk)"
+		}
+		add(columns("DPP", utils.Int64ToRupiah(t.TaxBase), width), false)
+		add(columns(label, utils.Int64ToRupiah(t.TaxAmount), width), false)
+	}
+	add(columns("TOTAL", utils.Int64ToRupiah(t.TotalPrice), width), true)
+	separator()
+
+	for _, payment := range t.TransactionPayments {
+		label, ok := paymentMethodLabels[payment.Method]
+		if !ok {
+			label = string(payment.Method)
+		}
+		add(columns(label, utils.Int64ToRupiah(payment.Amount), width), false)
+		if payment.Reference != "" {
+			add(columns("  Ref", payment.Reference, width), false)
+		}
+	}
+	add(columns("Kembali", utils.Int64ToRupiah(t.Change), width), false)
+
+	if refunded := model.AnyRefunds(t.Refunds).TotalAmount(); refunded > 0 {
+		separator()
+		add(columns("Refund", negativeRupiah(refunded), width), false)
+		add(columns("Total Bersih", utils.Int64ToRupiah(t.NetTotalPrice()), width), true)
+	}
+
+	if len(data.Setting.Footer) > 0 {
+		separator()
+	}
+	for _, footer := range data.Setting.Footer {
+		for _, text := range wrap(footer, width) {
+			add(center(text, width), false)
+		}
+	}
+
+	return lines
+}
+
+func negativeRupiah(amount int64) string {
+	return "-" + utils.Int64ToRupiah(amount)
+}
+
+// columns print the left text and the right aligned text on one line,
+// the right text is moved to the next line when both do not fit
+func columns(left, right string, width int) string {
+	gap := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap >= 1 {
+		return left + strings.Repeat(" ", gap) + right
+	}
+
+	return left + "\n" + pad(right, width)
+}
+
+// pad right align the text
+func pad(text string, width int) string {
+	gap := width - utf8.RuneCountInString(text)
+	if gap <= 0 {
+		return text
+	}
+
+	return strings.Repeat(" ", gap) + text
+}
+
+func center(text string, width int) string {
+	gap := (width - utf8.RuneCountInString(text)) / 2
+	if gap <= 0 {
+		return text
+	}
+
+	return strings.Repeat(" ", gap) + text
+}
+
+// wrap split the text into lines no longer than the width, breaking on spaces when possible
+func wrap(text string, width int) []string {
+	var lines []string
+	var current []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(current) > 0 {
+				lines = append(lines, string(current))
+				current = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+
+		switch {
+		case len(current) == 0:
+			current = runes
+		case len(current)+1+len(runes) <= width:
+			current = append(append(current, ' '), runes...)
+		default:
+			lines = append(lines, string(current))
+			current = runes
+		}
+	}
+	if len(current) > 0 {
+		lines = append(lines, string(current))
+	}
+
+	return lines
+}
@@ -0,0 +1,85 @@
+package receipt
+
+import (
+	"bytes"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func newTestData() Data {
+	return Data{
+		Transaction: &model.Transaction{
+			ID:              1695599921375543118,
+			TotalPrice:      11100,
+			Discount:        1000,
+			TaxBase:         10000,
+			TaxAmount:       1100,
+			AmountPaid:      20000,
+			Change:          8900,
+			CreatedAt:       time.Date(2023, 9, 25, 6, 59, 0, 0, time.UTC),
+			PriceIncludeTax: false,
+			TransactionDetails: []*model.TransactionDetail{
+				{ProductID: 222, ProductName: "Pisang Goreng Keju Cokelat Spesial Jumbo", UnitPrice: 5500, Quantity: 2, Discount: 1000, Subtotal: 10000},
+			},
+			TransactionPayments: []*model.TransactionPayment{
+				{Method: model.PaymentMethodCash, Amount: 20000},
+			},
+		},
+		CashierName: "Budi",
+		Setting: model.ReceiptSetting{
+			Header: []string{"Toko Point of Sales"},
+			Footer: []string{"Terima kasih"},
+		},
+		PrintCount: 1,
+	}
+}
+
+func TestText(t *testing.T) {
+	for _, width := range []int{width58mm, width80mm} {
+		text := string(Text(newTestData(), width))
+		for _, l := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			require.LessOrEqual(t, utf8.RuneCountInString(l), width, l)
+		}
+
+		require.Contains(t, text, "25 September 2023 13:59 WIB")
+		require.Contains(t, text, "Rp11.100")
+		require.Contains(t, text, "-Rp1.000")
+		require.Contains(t, text, "Tunai")
+		require.NotContains(t, text, "CETAK ULANG")
+	}
+}
+
+func TestESCPOS(t *testing.T) {
+	data := newTestData()
+	data.CashierName = "Budi Ñ"
+	res := ESCPOS(data, width58mm)
+
+	require.True(t, bytes.HasPrefix(res, escposInit))
+	require.True(t, bytes.HasSuffix(res, escposCut))
+	require.Contains(t, string(res), "Budi ?")
+}
+
+func TestPDF(t *testing.T) {
+	data := newTestData()
+	data.PrintCount = 3
+	res := PDF(data)
+
+	require.True(t, bytes.HasPrefix(res, []byte("%PDF-1.4")))
+	require.True(t, bytes.HasSuffix(res, []byte("%%EOF\n")))
+	require.Contains(t, string(res), "CETAK ULANG #2")
+}
+
+func TestRender(t *testing.T) {
+	res, err := Render(model.ReceiptFormatESCPOS80, newTestData())
+	require.NoError(t, err)
+	require.Equal(t, "application/octet-stream", res.ContentType)
+	require.Equal(t, "receipt-1695599921375543118.bin", res.Filename)
+
+	res, err = Render("DOCX", newTestData())
+	require.ErrorIs(t, err, model.ErrUnknownReceiptFormat)
+	require.Nil(t, res)
+}
@@ -0,0 +1,110 @@
+// Package report render the sales reports for exporting
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"strconv"
+	"time"
+)
+
+// CSVContentType content type of the CSV report
+const CSVContentType = "text/csv; charset=utf-8"
+
+// CSV render the sales report as CSV, every breakdown is written as its own section
+// separated by an empty line, the amounts are kept as plain numbers so they can be summed on a spreadsheet
+func CSV(r *model.SalesReport) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+
+	summary := model.SalesSummary{}
+	if r.Summary != nil {
+		summary = *r.Summary
+	}
+
+	records := [][]string{
+		{"report", string(r.Type)},
+		{"number", itoa(r.Number)},
+		{"period_start", formatTime(r.PeriodStart)},
+		{"period_end", formatTime(r.PeriodEnd)},
+		{},
+		{"summary"},
+		{"transaction_count", itoa(summary.TransactionCount)},
+		{"gross_sales", itoa(summary.GrossSales)},
+		{"discount", itoa(summary.Discount)},
+		{"manual_discount", itoa(summary.ManualDiscount)},
+		{"tax_amount", itoa(summary.TaxAmount)},
+		{"service_charge", itoa(summary.ServiceCharge)},
+		{"total_sales", itoa(summary.TotalSales)},
+		{"void_count", itoa(summary.VoidCount)},
+		{"void_amount", itoa(summary.VoidAmount)},
+		{"refund_count", itoa(summary.RefundCount)},
+		{"refund_amount", itoa(summary.RefundAmount)},
+		{"net_sales", itoa(summary.NetSales())},
+		{},
+		{"hour", "transaction_count", "total_sales"},
+	}
+
+	for _, hourly := range r.Hourly {
+		records = append(records, []string{model.HourLabel(hourly.Hour), itoa(hourly.TransactionCount), itoa(hourly.TotalSales)})
+	}
+
+	records = append(records, []string{}, []string{"product_id", "product_name", "quantity", "gross_sales", "discount", "subtotal"})
+	for _, product := range r.Products {
+		records = append(records, []string{
+			itoa(product.ProductID),
+			product.ProductName,
+			itoa(product.Quantity),
+			itoa(product.GrossSales),
+			itoa(product.Discount),
+			itoa(product.Subtotal),
+		})
+	}
+
+	records = append(records, []string{}, []string{"cashier_id", "cashier_name", "transaction_count", "discount", "total_sales"})
+	for _, cashier := range r.Cashiers {
+		records = append(records, []string{
+			itoa(cashier.CashierID),
+			cashier.CashierName,
+			itoa(cashier.TransactionCount),
+			itoa(cashier.Discount),
+			itoa(cashier.TotalSales),
+		})
+	}
+
+	records = append(records, []string{}, []string{"payment_method", "transaction_count", "amount"})
+	for _, method := range r.PaymentMethods {
+		records = append(records, []string{string(method.Method), itoa(method.TransactionCount), itoa(method.Amount)})
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CSVFilename the file name of the CSV report, e.g. z-report-12.csv
+func CSVFilename(r *model.SalesReport) string {
+	if r.Type == model.ReportTypeZ {
+		return fmt.Sprintf("z-report-%d.csv", r.Number)
+	}
+
+	return fmt.Sprintf("x-report-%s.csv", r.PeriodEnd.In(utils.WesternIndonesianLocation()).Format("20060102-1504"))
+}
+
+func itoa(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+// formatTime format the time as RFC3339 on western indonesian time, a zero time is left empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.In(utils.WesternIndonesianLocation()).Format(time.RFC3339)
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSV(t *testing.T) {
+	salesReport := &model.SalesReport{
+		ID:        1,
+		Type:      model.ReportTypeZ,
+		Number:    12,
+		PeriodEnd: time.Date(2023, 9, 25, 15, 0, 0, 0, time.UTC),
+		Summary: &model.SalesSummary{
+			TransactionCount: 2,
+			TotalSales:       18000,
+			RefundAmount:     3000,
+		},
+		Hourly:         []*model.HourlySales{{Hour: 9, TransactionCount: 2, TotalSales: 18000}},
+		Products:       []*model.ProductSales{{ProductID: 555, ProductName: "Kopi, Susu", Quantity: 6, Subtotal: 18000}},
+		Cashiers:       []*model.CashierSales{{CashierID: 111, CashierName: "Budi", TransactionCount: 2, TotalSales: 18000}},
+		PaymentMethods: []*model.PaymentMethodSales{{Method: model.PaymentMethodCash, TransactionCount: 2, Amount: 18000}},
+	}
+
+	content, err := CSV(salesReport)
+	require.NoError(t, err)
+
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"report", "Z"}, records[0])
+	require.Equal(t, []string{"number", "12"}, records[1])
+	require.Equal(t, []string{"period_start", ""}, records[2])
+	require.Equal(t, []string{"period_end", "2023-09-25T22:00:00+07:00"}, records[3])
+	require.Contains(t, records, []string{"net_sales", "15000"})
+	require.Contains(t, records, []string{"09:00", "2", "18000"})
+	// the comma on the product name is quoted
+	require.Contains(t, records, []string{"555", "Kopi, Susu", "6", "0", "0", "18000"})
+	require.Contains(t, records, []string{"CASH", "2", "18000"})
+
+	require.Equal(t, "z-report-12.csv", CSVFilename(salesReport))
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProfitCSV render the profit report as CSV, a line per group followed by the total line,
+// the margin is written as a percentage number with two decimals
+func ProfitCSV(r *model.ProfitReport) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+
+	records := [][]string{
+		{"report", "profit"},
+		{"group_by", string(r.GroupBy)},
+		{"period_start", formatTime(r.PeriodStart)},
+		{"period_end", formatTime(r.PeriodEnd)},
+		{},
+		{"key", "name", "quantity", "revenue", "cost", "gross_profit", "margin"},
+	}
+
+	for _, line := range r.Lines {
+		records = append(records, profitRecord(line.Key, line.Name, *line))
+	}
+	records = append(records, profitRecord("total", "", r.Summary))
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ProfitCSVFilename the file name of the CSV profit report named after the last day of the period,
+// e.g. profit-report-product-20230930.csv
+func ProfitCSVFilename(r *model.ProfitReport) string {
+	lastDay := r.PeriodEnd.Add(-time.Nanosecond)
+	return fmt.Sprintf("profit-report-%s-%s.csv",
+		strings.ToLower(string(r.GroupBy)),
+		lastDay.In(utils.WesternIndonesianLocation()).Format("20060102"))
+}
+
+func profitRecord(key, name string, line model.ProfitLine) []string {
+	return []string{
+		key,
+		name,
+		itoa(line.Quantity),
+		itoa(line.Revenue),
+		itoa(line.Cost),
+		itoa(line.GrossProfit()),
+		strconv.FormatFloat(line.Margin(), 'f', 2, 64),
+	}
+}
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfitCSV(t *testing.T) {
+	profitReport := &model.ProfitReport{
+		GroupBy:   model.ProfitReportGroupCategory,
+		PeriodEnd: time.Date(2023, 9, 30, 17, 0, 0, 0, time.UTC),
+		Lines: []*model.ProfitLine{
+			{Key: "1", Name: "Minuman, Dingin", Quantity: 10, Revenue: 40000, Cost: 30000},
+			{Key: "0", Name: "", Quantity: 2, Revenue: 0, Cost: 1000},
+		},
+	}
+	profitReport.Summarize()
+
+	content, err := ProfitCSV(profitReport)
+	require.NoError(t, err)
+
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"group_by", "CATEGORY"}, records[1])
+	require.Equal(t, []string{"period_end", "2023-10-01T00:00:00+07:00"}, records[3])
+	// the comma on the category name is quoted
+	require.Contains(t, records, []string{"1", "Minuman, Dingin", "10", "40000", "30000", "10000", "25.00"})
+	// a group without revenue has no margin
+	require.Contains(t, records, []string{"0", "", "2", "0", "1000", "-1000", "0.00"})
+	require.Equal(t, []string{"total", "", "12", "40000", "31000", "9000", "22.50"}, records[len(records)-1])
+
+	require.Equal(t, "profit-report-category-20230930.csv", ProfitCSVFilename(profitReport))
+}
@@ -29,7 +29,7 @@ func TestAuditLogRepository_Audit(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		dbmock.ExpectBegin()
 		dbmock.ExpectExec("INSERT INTO \"audits\"").
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 		dbmock.ExpectCommit()
@@ -39,7 +39,7 @@ func TestAuditLogRepository_Audit(t *testing.T) {
 	})
 	t.Run("Failed Create Error", func(t *testing.T) {
 		dbmock.ExpectExec("INSERT INTO \"audits\"").
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnError(gorm.ErrInvalidData)
 		err := ar.Audit(context.TODO(), db, user, audit)
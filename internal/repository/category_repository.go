@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type categoryRepository struct {
+	db        *gorm.DB
+	cache     cacher.CacheManager
+	auditRepo model.AuditRepository
+}
+
+// NewCategoryRepository instantiate a new category repository
+func NewCategoryRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	auditRepo model.AuditRepository,
+) model.CategoryRepository {
+	return &categoryRepository{
+		db:        db,
+		cache:     cache,
+		auditRepo: auditRepo,
+	}
+}
+
+// FindByID find category by id
+func (c *categoryRepository) FindByID(ctx context.Context, id int64) (*model.Category, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"categoryID": id,
+	})
+
+	cacheKey := c.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.Category](c.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	category := &model.Category{}
+	err := c.db.WithContext(ctx).Take(category, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, c.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := c.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(category))); err != nil {
+		logger.Error(err)
+	}
+
+	return category, nil
+}
+
+// FindBySlug find category with specific slug
+func (c *categoryRepository) FindBySlug(ctx context.Context, slug string) (*model.Category, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":  utils.DumpIncomingContext(ctx),
+		"slug": slug,
+	})
+
+	cacheKey := c.newCacheKeyBySlug(slug)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[int64](c.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return c.FindByID(ctx, reply)
+		}
+	}
+
+	var id int64
+	err := c.db.WithContext(ctx).Model(model.Category{}).Select("id").Take(&id, "slug = ?", slug).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, c.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err = c.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, id)); err != nil {
+		logger.Error(err)
+	}
+
+	return c.FindByID(ctx, id)
+}
+
+// FindAll find all categories ordered by the sort order & name
+func (c *categoryRepository) FindAll(ctx context.Context) ([]*model.Category, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+	})
+
+	cacheKey := c.newCacheKeyAll()
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[[]*model.Category](c.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	var categories []*model.Category
+	err := c.db.WithContext(ctx).
+		Order("sort_order ASC").
+		Order("name ASC").
+		Find(&categories).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(categories) <= 0 {
+		cacher.StoreNil(ctx, c.cache, cacheKey)
+		return nil, nil
+	}
+
+	if err := c.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(categories))); err != nil {
+		logger.Error(err)
+	}
+
+	return categories, nil
+}
+
+// Create category
+func (c *categoryRepository) Create(ctx context.Context, userID int64, category *model.Category) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"userID":   userID,
+		"category": utils.Dump(category),
+	})
+
+	category.UpdatedAt = time.Now()
+
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(category).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := c.auditRepo.Audit(ctx, tx, category, &model.Audit{
+			UserID:        userID,
+			AuditableType: c.name(),
+			AuditableID:   category.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := c.deleteCaches(category); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Update category, the parent id is updated as well so a zero parent id moves the category to the root
+func (c *categoryRepository) Update(ctx context.Context, userID int64, category *model.Category) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"userID":   userID,
+		"category": utils.Dump(category),
+	})
+
+	category.UpdatedAt = time.Now()
+
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Select("parent_id", "name", "slug", "description", "sort_order", "updated_at").
+			Updates(category).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := c.auditRepo.Audit(ctx, tx, category, &model.Audit{
+			UserID:        userID,
+			AuditableType: c.name(),
+			AuditableID:   category.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := c.deleteCaches(category); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Delete soft delete a category which has no subcategories and no products
+func (c *categoryRepository) Delete(ctx context.Context, userID int64, category *model.Category) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"userID":   userID,
+		"category": utils.Dump(category),
+	})
+
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var childCount, productCount int64
+		err := tx.Model(model.Category{}).Where("parent_id = ?", category.ID).Count(&childCount).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		err = tx.Model(model.Product{}).Where("category_id = ?", category.ID).Count(&productCount).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if childCount > 0 || productCount > 0 {
+			return model.ErrCategoryNotEmpty
+		}
+
+		if err := tx.Delete(category).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := c.auditRepo.Audit(ctx, tx, category, &model.Audit{
+			UserID:        userID,
+			AuditableType: c.name(),
+			AuditableID:   category.ID,
+			Action:        model.AuditActionDelete,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := c.deleteCaches(category); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+func (c *categoryRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:category:id:%d", id)
+}
+
+func (c *categoryRepository) newCacheKeyBySlug(slug string) string {
+	return fmt.Sprintf("cache:object:category:slug:%s", slug)
+}
+
+func (c *categoryRepository) newCacheKeyAll() string {
+	return "cache:object:category:all"
+}
+
+// deleteCaches delete related cache
+func (c *categoryRepository) deleteCaches(category *model.Category) error {
+	if category == nil {
+		return nil
+	}
+
+	return c.cache.DeleteByKeys([]string{
+		c.newCacheKeyByID(category.ID),
+		c.newCacheKeyBySlug(category.Slug),
+		c.newCacheKeyAll(),
+	})
+}
+
+func (c *categoryRepository) name() string {
+	return "category"
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCategoryRepository_FindAll(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &categoryRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	t.Run("ok - retrieve from db then from cache", func(t *testing.T) {
+		defer kit.miniredis.FlushAll()
+
+		mock.ExpectQuery(`^SELECT \* FROM "categories" WHERE "categories"."deleted_at" IS NULL ORDER BY sort_order ASC,name ASC`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name", "slug"}).
+				AddRow(1, 0, "Minuman", "minuman").
+				AddRow(2, 1, "Kopi", "minuman-kopi"))
+
+		categories, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, categories, 2)
+
+		cached, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, cached, 2)
+		require.Equal(t, int64(1), cached[1].ParentID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepository_Delete(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &categoryRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	category := &model.Category{ID: utils.GenerateID(), Name: "Kopi", Slug: "kopi"}
+
+	t.Run("ok", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT count\(\*\) FROM "categories" WHERE parent_id = .+`).
+			WithArgs(category.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`^SELECT count\(\*\) FROM "products" WHERE category_id = .+`).
+			WithArgs(category.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(`^UPDATE "categories" SET "deleted_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.Delete(ctx, userID, category)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - still has products", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT count\(\*\) FROM "categories"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`^SELECT count\(\*\) FROM "products"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectRollback()
+
+		err := repo.Delete(ctx, userID, category)
+		require.ErrorIs(t, err, model.ErrCategoryNotEmpty)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -72,6 +72,13 @@ type repoTestKit struct {
 	mockAuditRepo   *mock.MockAuditRepository
 	mockUserRepo    *mock.MockUserRepository
 	mockSessionRepo *mock.MockSessionRepository
+
+	mockTransactionDetailRepo    *mock.MockTransactionDetailRepository
+	mockTransactionPaymentRepo   *mock.MockTransactionPaymentRepository
+	mockTransactionPromotionRepo *mock.MockTransactionPromotionRepository
+	mockProductRepo              *mock.MockProductRepository
+	mockProductVariantRepo       *mock.MockProductVariantRepository
+	mockStockMovementRepo        *mock.MockStockMovementRepository
 }
 
 func initializeRepoTestKit(t *testing.T) (kit *repoTestKit, close func()) {
@@ -100,6 +107,12 @@ func initializeRepoTestKit(t *testing.T) (kit *repoTestKit, close func()) {
 	auditRepo := mock.NewMockAuditRepository(ctrl)
 	userRepo := mock.NewMockUserRepository(ctrl)
 	sessionRepo := mock.NewMockSessionRepository(ctrl)
+	transactionDetailRepo := mock.NewMockTransactionDetailRepository(ctrl)
+	transactionPaymentRepo := mock.NewMockTransactionPaymentRepository(ctrl)
+	transactionPromotionRepo := mock.NewMockTransactionPromotionRepository(ctrl)
+	productRepo := mock.NewMockProductRepository(ctrl)
+	productVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	stockMovementRepo := mock.NewMockStockMovementRepository(ctrl)
 
 	tk := &repoTestKit{
 		cache:           k,
@@ -110,6 +123,13 @@ func initializeRepoTestKit(t *testing.T) (kit *repoTestKit, close func()) {
 		mockAuditRepo:   auditRepo,
 		mockUserRepo:    userRepo,
 		mockSessionRepo: sessionRepo,
+
+		mockTransactionDetailRepo:    transactionDetailRepo,
+		mockTransactionPaymentRepo:   transactionPaymentRepo,
+		mockTransactionPromotionRepo: transactionPromotionRepo,
+		mockProductRepo:              productRepo,
+		mockProductVariantRepo:       productVariantRepo,
+		mockStockMovementRepo:        stockMovementRepo,
 	}
 
 	close = func() {
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type passwordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetTokenRepository create new repository, the tokens are read straight from the db
+// since each of them is used at most once
+func NewPasswordResetTokenRepository(db *gorm.DB) model.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		db: db,
+	}
+}
+
+// Create store the token
+func (p *passwordResetTokenRepository) Create(ctx context.Context, token *model.PasswordResetToken) error {
+	if err := p.db.WithContext(ctx).Create(token).Error; err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": token.UserID,
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindByTokenHash find the token by its hash
+func (p *passwordResetTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordResetToken, error) {
+	token := &model.PasswordResetToken{}
+	err := p.db.WithContext(ctx).Take(token, "token_hash = ?", tokenHash).Error
+	switch err {
+	case nil:
+		return token, nil
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	default:
+		logrus.WithField("ctx", utils.DumpIncomingContext(ctx)).Error(err)
+		return nil, err
+	}
+}
+
+// Use mark the token used, the update only matches an unused token so a token can not be used twice
+// even by the concurrent requests. The other unused tokens of the user are marked used as well.
+func (p *passwordResetTokenRepository) Use(ctx context.Context, token *model.PasswordResetToken) (used bool, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":     utils.DumpIncomingContext(ctx),
+		"tokenID": token.ID,
+		"userID":  token.UserID,
+	})
+
+	now := time.Now()
+	err = p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(model.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected <= 0 {
+			return nil
+		}
+
+		used = true
+		return tx.Model(model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error
+	})
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	if used {
+		token.UsedAt = &now
+	}
+
+	return used, nil
+}
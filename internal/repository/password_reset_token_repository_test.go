@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetTokenRepository_Use(t *testing.T) {
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+	initializeTest()
+
+	ctx := context.TODO()
+	repo := &passwordResetTokenRepository{db: kit.db}
+
+	t.Run("ok - the other tokens of the user are used as well", func(t *testing.T) {
+		token := &model.PasswordResetToken{ID: 7, UserID: 2, ExpiredAt: time.Now().Add(time.Hour)}
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "password_reset_tokens" SET "used_at"=\$1 WHERE id = \$2 AND used_at IS NULL`).
+			WithArgs(sqlmock.AnyArg(), token.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`^UPDATE "password_reset_tokens" SET "used_at"=\$1 WHERE user_id = \$2 AND used_at IS NULL`).
+			WithArgs(sqlmock.AnyArg(), token.UserID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		used, err := repo.Use(ctx, token)
+		require.NoError(t, err)
+		require.True(t, used)
+		require.NotNil(t, token.UsedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ok - already used token", func(t *testing.T) {
+		token := &model.PasswordResetToken{ID: 7, UserID: 2, ExpiredAt: time.Now().Add(time.Hour)}
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "password_reset_tokens" SET "used_at"=\$1 WHERE id = \$2 AND used_at IS NULL`).
+			WithArgs(sqlmock.AnyArg(), token.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		used, err := repo.Use(ctx, token)
+		require.NoError(t, err)
+		require.False(t, used)
+		require.Nil(t, token.UsedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -5,29 +5,37 @@ import (
 	"fmt"
 	"github.com/irvankadhafi/go-point-of-sales/cacher"
 	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 type productRepository struct {
-	db        *gorm.DB
-	cache     cacher.CacheManager
-	auditRepo model.AuditRepository
+	db           *gorm.DB
+	cache        cacher.CacheManager
+	variantRepo  model.ProductVariantRepository
+	movementRepo model.StockMovementRepository
+	auditRepo    model.AuditRepository
 }
 
 // NewProductRepository create new repository
 func NewProductRepository(
 	db *gorm.DB,
 	cache cacher.CacheManager,
+	variantRepo model.ProductVariantRepository,
+	movementRepo model.StockMovementRepository,
 	auditRepo model.AuditRepository,
 ) model.ProductRepository {
 	return &productRepository{
-		db:        db,
-		cache:     cache,
-		auditRepo: auditRepo,
+		db:           db,
+		cache:        cache,
+		variantRepo:  variantRepo,
+		movementRepo: movementRepo,
+		auditRepo:    auditRepo,
 	}
 }
 
@@ -112,7 +120,48 @@ func (p *productRepository) FindBySlug(ctx context.Context, slug string) (*model
 	return p.FindByID(ctx, id)
 }
 
-// Create product
+// FindByCode find product with specific sku or barcode, the code is cached to the product id the same way as the slug.
+// A cached code is only trusted when it still belongs to the product, since an update only deletes the caches of the new codes.
+func (p *productRepository) FindByCode(ctx context.Context, code string) (*model.Product, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"context": utils.DumpIncomingContext(ctx),
+		"code":    code})
+
+	cacheKey := p.newCacheKeyByCode(code)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[int64](p.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return p.findByIDWithCode(ctx, reply, code)
+		}
+	}
+
+	var id int64
+	err := p.db.WithContext(ctx).Model(model.Product{}).Select("id").Take(&id, "sku = ? OR barcode = ?", code, code).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, p.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err = p.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, id)); err != nil {
+		logger.Error(err)
+	}
+
+	return p.findByIDWithCode(ctx, id, code)
+}
+
+// Create product along with its variants, the initial stock is recorded as adjustment movements
 func (p *productRepository) Create(ctx context.Context, userID int64, product *model.Product) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":     utils.DumpIncomingContext(ctx),
@@ -122,23 +171,41 @@ func (p *productRepository) Create(ctx context.Context, userID int64, product *m
 	product.UpdatedAt = time.Now()
 
 	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(product).Error; err != nil {
-			logger.Error(err)
-			return err
-		}
+		return p.create(ctx, tx, userID, product)
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
 
-		if err := p.auditRepo.Audit(ctx, tx, product, &model.Audit{
-			UserID:        userID,
-			AuditableType: p.name(),
-			AuditableID:   product.ID,
-			Action:        model.AuditActionCreate,
-			CreatedAt:     time.Now(),
-		}); err != nil {
+	if err := p.deleteCaches(product); err != nil {
+		logger.Error(err)
+	}
+
+	if len(product.Variants) > 0 {
+		if err := p.variantRepo.DeleteCachesByProductID(product.ID, product.Variants); err != nil {
 			logger.Error(err)
-			return err
 		}
+	}
 
-		return nil
+	return nil
+}
+
+// Update product, every column is updated so a zero value such as an uncategorized product is stored as well.
+// The variants are replaced by the product variants, so the variants missing from the product are removed.
+// The stored product is locked first, so the stock change is recorded as adjustment movements against the current stock.
+func (p *productRepository) Update(ctx context.Context, userID int64, product *model.Product) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":     utils.DumpIncomingContext(ctx),
+		"product": utils.Dump(product),
+	})
+
+	product.UpdatedAt = time.Now()
+
+	var removedVariants []*model.ProductVariant
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		removedVariants, err = p.update(ctx, tx, userID, product)
+		return err
 	})
 	if err != nil {
 		logger.Error(err)
@@ -149,34 +216,49 @@ func (p *productRepository) Create(ctx context.Context, userID int64, product *m
 		logger.Error(err)
 	}
 
+	if err := p.variantRepo.DeleteCachesByProductID(product.ID, append(removedVariants, product.Variants...)); err != nil {
+		logger.Error(err)
+	}
+
 	return nil
 }
 
-func (p *productRepository) Update(ctx context.Context, userID int64, product *model.Product) error {
+// Delete soft delete a product along with its variants
+func (p *productRepository) Delete(ctx context.Context, userID int64, product *model.Product) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":     utils.DumpIncomingContext(ctx),
+		"userID":  userID,
 		"product": utils.Dump(product),
 	})
 
-	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Updates(product).Error; err != nil {
+	var removedVariants []*model.ProductVariant
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err := tx.Delete(product).Error; err != nil {
 			logger.Error(err)
 			return err
 		}
 
-		if err := p.auditRepo.Audit(ctx, tx, product, &model.Audit{
+		removedVariants, err = p.variantRepo.ReplaceByProductID(ctx, tx, product.ID, nil)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		err = p.auditRepo.Audit(ctx, tx, product, &model.Audit{
 			UserID:        userID,
 			AuditableType: p.name(),
 			AuditableID:   product.ID,
-			Action:        model.AuditActionUpdate,
+			Action:        model.AuditActionDelete,
 			CreatedAt:     time.Now(),
-		}); err != nil {
+		})
+		if err != nil {
 			logger.Error(err)
 			return err
 		}
 
 		return nil
 	})
+
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -186,38 +268,172 @@ func (p *productRepository) Update(ctx context.Context, userID int64, product *m
 		logger.Error(err)
 	}
 
+	if err := p.variantRepo.DeleteCachesByProductID(product.ID, removedVariants); err != nil {
+		logger.Error(err)
+	}
+
 	return nil
 }
 
-// Delete soft delete a product
-func (p *productRepository) Delete(ctx context.Context, userID int64, product *model.Product) error {
+// DecreaseStockByID decrease the product stock using conditional update within the given db transaction,
+// so concurrent sales can never make the stock negative
+func (p *productRepository) DecreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error {
 	logger := logrus.WithFields(logrus.Fields{
-		"ctx":     utils.DumpIncomingContext(ctx),
-		"userID":  userID,
-		"product": utils.Dump(product),
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"productID": id,
+		"quantity":  quantity,
+	})
+
+	res := tx.WithContext(ctx).
+		Model(model.Product{}).
+		Where("id = ? AND quantity >= ?", id, quantity).
+		Updates(map[string]any{
+			"quantity":   gorm.Expr("quantity - ?", quantity),
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		logger.Error(res.Error)
+		return res.Error
+	}
+
+	if res.RowsAffected <= 0 {
+		return model.ErrInsufficientStock
+	}
+
+	return nil
+}
+
+// IncreaseStockByID return the stock of a product within the given db transaction,
+// the product is restocked even when it has been deleted so the stock stays consistent
+func (p *productRepository) IncreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"productID": id,
+		"quantity":  quantity,
+	})
+
+	err := tx.WithContext(ctx).
+		Unscoped().
+		Model(model.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"quantity":   gorm.Expr("quantity + ?", quantity),
+			"updated_at": time.Now(),
+		}).Error
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ApplyStockMovements change the stock of the products & variants by the movements and append them to the ledger,
+// all within the given db transaction. A negative movement return ErrInsufficientStock when the stock is not enough.
+func (p *productRepository) ApplyStockMovements(ctx context.Context, tx *gorm.DB, movements []*model.StockMovement) error {
+	for _, movement := range model.AnyStockMovements(movements).Sorted() {
+		if err := p.applyStockMovement(ctx, tx, movement); err != nil {
+			return err
+		}
+	}
+
+	return p.movementRepo.Create(ctx, tx, movements)
+}
+
+// PostStockMovements apply the movements in a single db transaction and delete the caches of the changed stock
+func (p *productRepository) PostStockMovements(ctx context.Context, movements []*model.StockMovement) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"movements": utils.Dump(movements),
 	})
 
 	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Delete(product).Error; err != nil {
+		return p.ApplyStockMovements(ctx, tx, movements)
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.DeleteCachesByIDs(model.AnyStockMovements(movements).ProductIDs()); err != nil {
+		logger.Error(err)
+	}
+
+	if err := p.variantRepo.DeleteCachesByIDs(model.AnyStockMovements(movements).VariantIDs()); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// ApplyReceivedCostByID lock the product and average its cost price with the received quantity costing amount
+// in total, the stock is read under the lock so it must not include the received quantity yet
+func (p *productRepository) ApplyReceivedCostByID(ctx context.Context, tx *gorm.DB, id, quantity, amount int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"productID": id,
+		"quantity":  quantity,
+		"amount":    amount,
+	})
+
+	var stored struct {
+		Quantity  int64
+		CostPrice int64
+	}
+	err := tx.WithContext(ctx).
+		Unscoped().
+		Model(model.Product{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("quantity", "cost_price").
+		Where("id = ?", id).
+		Take(&stored).Error
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	err = tx.WithContext(ctx).
+		Unscoped().
+		Model(model.Product{}).
+		Where("id = ?", id).
+		Update("cost_price", model.MovingAverageCost(stored.Quantity, stored.CostPrice, quantity, amount)).Error
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateCostPrice set the cost price manually, the other fields of the product are left as stored
+func (p *productRepository) UpdateCostPrice(ctx context.Context, userID int64, product *model.Product) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"userID":    userID,
+		"productID": product.ID,
+		"costPrice": product.CostPrice,
+	})
+
+	product.UpdatedAt = time.Now()
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("cost_price", "updated_at").Updates(product).Error; err != nil {
 			logger.Error(err)
 			return err
 		}
 
-		err := p.auditRepo.Audit(ctx, tx, product, &model.Audit{
+		if err := p.auditRepo.Audit(ctx, tx, product, &model.Audit{
 			UserID:        userID,
 			AuditableType: p.name(),
 			AuditableID:   product.ID,
-			Action:        model.AuditActionDelete,
+			Action:        model.AuditActionUpdate,
 			CreatedAt:     time.Now(),
-		})
-		if err != nil {
+		}); err != nil {
 			logger.Error(err)
 			return err
 		}
 
 		return nil
 	})
-
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -230,6 +446,20 @@ func (p *productRepository) Delete(ctx context.Context, userID int64, product *m
 	return nil
 }
 
+// DeleteCachesByIDs delete the product caches, used after the stock is changed outside this repository
+func (p *productRepository) DeleteCachesByIDs(ids []int64) error {
+	if len(ids) <= 0 {
+		return nil
+	}
+
+	var cacheKeys []string
+	for _, id := range ids {
+		cacheKeys = append(cacheKeys, p.newCacheKeyByID(id))
+	}
+
+	return p.cache.DeleteByKeys(cacheKeys)
+}
+
 // SearchByPage find all product with specific criteria
 func (p *productRepository) SearchByPage(ctx context.Context, criteria model.ProductSearchCriteria) (ids []int64, count int64, err error) {
 	logger := logrus.WithFields(logrus.Fields{
@@ -260,12 +490,43 @@ func (p *productRepository) SearchByPage(ctx context.Context, criteria model.Pro
 	return ids, count, nil
 }
 
+// SearchLowStockByPage find the ids of the products which stock has reached their minimum stock level,
+// the products furthest below their minimum come first
+func (p *productRepository) SearchLowStockByPage(ctx context.Context, criteria model.LowStockSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = p.db.WithContext(ctx).
+		Model(model.Product{}).
+		Scopes(scopeLowStock).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = p.db.WithContext(ctx).
+		Model(model.Product{}).
+		Scopes(scopeLowStock, scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("quantity - min_stock ASC, id ASC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
 func (p *productRepository) findAllIDsByCriteria(ctx context.Context, criteria model.ProductSearchCriteria) ([]int64, error) {
-	var scopes []func(*gorm.DB) *gorm.DB
+	scopes := scopesByProductSearchCriteria(criteria)
 	scopes = append(scopes, scopeByPageAndLimit(criteria.Page, criteria.Size))
-	if criteria.Query != "" {
-		scopes = append(scopes, scopeMatchTSQuery(criteria.Query))
-	}
 
 	var ids []int64
 	err := p.db.WithContext(ctx).
@@ -285,14 +546,9 @@ func (p *productRepository) findAllIDsByCriteria(ctx context.Context, criteria m
 }
 
 func (p *productRepository) countAll(ctx context.Context, criteria model.ProductSearchCriteria) (int64, error) {
-	var scopes []func(*gorm.DB) *gorm.DB
-	if criteria.Query != "" {
-		scopes = append(scopes, scopeMatchTSQuery(criteria.Query))
-	}
-
 	var count int64
 	err := p.db.WithContext(ctx).Model(model.Product{}).
-		Scopes(scopes...).
+		Scopes(scopesByProductSearchCriteria(criteria)...).
 		Count(&count).
 		Error
 	if err != nil {
@@ -314,8 +570,15 @@ func (p *productRepository) newCacheKeyBySlug(slug string) string {
 	return fmt.Sprintf("cache:object:product:slug:%s", slug)
 }
 
+func (p *productRepository) newCacheKeyByCode(code string) string {
+	return fmt.Sprintf("cache:object:product:code:%s", code)
+}
+
 func (p *productRepository) newProductCacheKeyByCriteria(criteria model.ProductSearchCriteria) string {
 	key := fmt.Sprintf("cache:object:productMultiValue:page:%d:size:%d:sortType:%s", criteria.Page, criteria.Size, string(criteria.SortType))
+	if criteria.CategoryID > 0 {
+		key = fmt.Sprintf("%s:categoryID:%d", key, criteria.CategoryID)
+	}
 
 	if criteria.Query != "" {
 		return key + ":query:" + criteria.Query
@@ -330,16 +593,265 @@ func (p *productRepository) deleteCaches(product *model.Product) error {
 		return nil
 	}
 
-	return p.cache.DeleteByKeys([]string{
+	cacheKeys := []string{
 		p.newCacheKeyByID(product.ID),
 		p.newCacheKeyBySlug(product.Slug),
+	}
+	if product.SKU != "" {
+		cacheKeys = append(cacheKeys, p.newCacheKeyByCode(product.SKU))
+	}
+	if product.Barcode != "" {
+		cacheKeys = append(cacheKeys, p.newCacheKeyByCode(product.Barcode))
+	}
+
+	return p.cache.DeleteByKeys(cacheKeys)
+}
+
+// Import store the imported products in a single db transaction, the new products are created along with
+// their initial stock and the existing products are updated keeping their stock & cost price.
+// Nothing is stored when one of the products fails.
+func (p *productRepository) Import(ctx context.Context, userID int64, imports []*model.ProductImport) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":     utils.DumpIncomingContext(ctx),
+		"userID":  userID,
+		"imports": len(imports),
+	})
+
+	now := time.Now()
+	removedVariants := make(map[int64][]*model.ProductVariant)
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, in := range imports {
+			in.Product.UpdatedAt = now
+			if in.IsNew {
+				if err := p.create(ctx, tx, userID, in.Product); err != nil {
+					return err
+				}
+				continue
+			}
+
+			removed, err := p.update(ctx, tx, userID, in.Product)
+			if err != nil {
+				return err
+			}
+			removedVariants[in.Product.ID] = removed
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	for _, in := range imports {
+		if err := p.deleteCaches(in.Product); err != nil {
+			logger.Error(err)
+		}
+
+		variants := append(removedVariants[in.Product.ID], in.Product.Variants...)
+		if len(variants) <= 0 {
+			continue
+		}
+		if err := p.variantRepo.DeleteCachesByProductID(in.Product.ID, variants); err != nil {
+			logger.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// StreamAll call fn with every product ordered by id, the products are read row by row from the db
+// so the whole catalog is never held in memory. The variants of the products are not loaded.
+func (p *productRepository) StreamAll(ctx context.Context, fn func(product *model.Product) error) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
 	})
+
+	rows, err := p.db.WithContext(ctx).Model(model.Product{}).Order("id ASC").Rows()
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	defer helper.WrapCloser(rows.Close)
+
+	for rows.Next() {
+		product := &model.Product{}
+		if err := p.db.ScanRows(rows, product); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// create store the product along with its variants & initial stock within the given db transaction
+func (p *productRepository) create(ctx context.Context, tx *gorm.DB, userID int64, product *model.Product) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"productID": product.ID,
+	})
+
+	if err := tx.Create(product).Error; err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if len(product.Variants) > 0 {
+		if _, err := p.variantRepo.ReplaceByProductID(ctx, tx, product.ID, product.Variants); err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	movements := model.NewStockAdjustments(&model.Product{}, nil, product, userID, "initial stock")
+	if err := p.movementRepo.Create(ctx, tx, movements); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.auditRepo.Audit(ctx, tx, product, &model.Audit{
+		UserID:        userID,
+		AuditableType: p.name(),
+		AuditableID:   product.ID,
+		Action:        model.AuditActionCreate,
+		CreatedAt:     time.Now(),
+	}); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// update store the product keeping its stock & cost price within the given db transaction,
+// return the variants which are removed from the product
+func (p *productRepository) update(ctx context.Context, tx *gorm.DB, userID int64, product *model.Product) ([]*model.ProductVariant, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"productID": product.ID,
+	})
+
+	stored := &model.Product{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(stored, "id = ?", product.ID).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	var storedVariants []*model.ProductVariant
+	if err := tx.Where("product_id = ?", product.ID).Find(&storedVariants).Error; err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// the stock is read under the lock, so the sales made meanwhile are never overwritten
+	product.KeepStock(stored, storedVariants)
+	// the cost price is only changed by the goods receipts or UpdateCostPrice
+	product.CostPrice = stored.CostPrice
+	if err := tx.Select("*").Updates(product).Error; err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	removedVariants, err := p.variantRepo.ReplaceByProductID(ctx, tx, product.ID, product.Variants)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	movements := model.NewStockAdjustments(stored, storedVariants, product, userID, "product update")
+	if err := p.movementRepo.Create(ctx, tx, movements); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.auditRepo.Audit(ctx, tx, product, &model.Audit{
+		UserID:        userID,
+		AuditableType: p.name(),
+		AuditableID:   product.ID,
+		Action:        model.AuditActionUpdate,
+		CreatedAt:     time.Now(),
+	}); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return removedVariants, nil
+}
+
+func (p *productRepository) findByIDWithCode(ctx context.Context, id int64, code string) (*model.Product, error) {
+	product, err := p.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || !product.HasCode(code) {
+		return nil, nil
+	}
+
+	return product, nil
+}
+
+// applyStockMovement change the stock of the product, and of the variant when the movement is for a variant
+func (p *productRepository) applyStockMovement(ctx context.Context, tx *gorm.DB, movement *model.StockMovement) error {
+	if movement.Quantity < 0 {
+		if err := p.DecreaseStockByID(ctx, tx, movement.ProductID, -movement.Quantity); err != nil {
+			return err
+		}
+		if movement.VariantID > 0 {
+			return p.variantRepo.DecreaseStockByID(ctx, tx, movement.VariantID, -movement.Quantity)
+		}
+		return nil
+	}
+
+	if err := p.IncreaseStockByID(ctx, tx, movement.ProductID, movement.Quantity); err != nil {
+		return err
+	}
+	if movement.VariantID > 0 {
+		return p.variantRepo.IncreaseStockByID(ctx, tx, movement.VariantID, movement.Quantity)
+	}
+
+	return nil
 }
 
 func (p *productRepository) name() string {
 	return "product"
 }
 
+// scopesByProductSearchCriteria build the filter scopes of product search criteria
+func scopesByProductSearchCriteria(criteria model.ProductSearchCriteria) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if criteria.Query != "" {
+		scopes = append(scopes, scopeMatchTSQuery(criteria.Query))
+	}
+
+	if criteria.CategoryID > 0 {
+		scopes = append(scopes, scopeByCategoryTree(criteria.CategoryID))
+	}
+
+	return scopes
+}
+
+// scopeByCategoryTree filter the products of the category and all of its subcategories
+func scopeByCategoryTree(categoryID int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`category_id IN (WITH RECURSIVE "category_tree" AS (
+			SELECT id FROM "categories" WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT c.id FROM "categories" c JOIN "category_tree" ct ON c.parent_id = ct.id WHERE c.deleted_at IS NULL
+		) SELECT id FROM "category_tree")`, categoryID)
+	}
+}
+
 func orderByProductSortType(sortType model.ProductSortType) string {
 	if orderBy, ok := model.QueryProductSortByMap[sortType]; ok {
 		return orderBy
@@ -347,3 +859,8 @@ func orderByProductSortType(sortType model.ProductSortType) string {
 
 	return model.QueryProductSortByMap[model.ProductSortTypeCreatedAtDesc]
 }
+
+// scopeLowStock filter the products which stock has reached their minimum stock level
+func scopeLowStock(db *gorm.DB) *gorm.DB {
+	return db.Where("min_stock > 0 AND quantity <= min_stock")
+}
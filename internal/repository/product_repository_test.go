@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/stretchr/testify/assert"
@@ -20,8 +21,10 @@ func TestProductRepository_Create(t *testing.T) {
 
 	ctx := context.TODO()
 	repo := &productRepository{
-		db:    kit.db,
-		cache: kit.cache,
+		db:           kit.db,
+		cache:        kit.cache,
+		movementRepo: kit.mockStockMovementRepo,
+		auditRepo:    kit.mockAuditRepo,
 	}
 
 	userID := int64(111)
@@ -52,6 +55,16 @@ func TestProductRepository_Create(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(`^INSERT INTO "products"`).WillReturnRows(rows)
+		kit.mockStockMovementRepo.EXPECT().Create(gomock.Any(), gomock.Any(), []*model.StockMovement{{
+			ProductID:     product.ID,
+			Type:          model.StockMovementTypeAdjustment,
+			Quantity:      20,
+			ReferenceType: model.StockReferenceProduct,
+			ReferenceID:   product.ID,
+			Note:          "initial stock",
+			CreatedBy:     userID,
+		}}).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
 		mock.ExpectCommit()
 
 		err := repo.Create(context.Background(), userID, product)
@@ -177,6 +190,57 @@ func TestProductRepository_SearchByPage(t *testing.T) {
 	})
 }
 
+func TestProductRepository_SearchLowStockByPage(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	productIDs := []int64{int64(111), int64(222)}
+	criteria := model.LowStockSearchCriteria{Page: 1, Size: 10}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT count(.*) FROM "products" WHERE \(min_stock > 0 AND quantity <= min_stock\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(productIDs)))
+
+		rows := sqlmock.NewRows([]string{"id"})
+		for _, id := range productIDs {
+			rows.AddRow(id)
+		}
+		mock.ExpectQuery(`^SELECT "id" FROM "products" WHERE \(min_stock > 0 AND quantity <= min_stock\) .+ORDER BY quantity - min_stock ASC, id ASC`).
+			WillReturnRows(rows)
+
+		ids, count, err := repo.SearchLowStockByPage(ctx, criteria)
+		require.NoError(t, err)
+		require.Equal(t, productIDs, ids)
+		require.Equal(t, int64(len(productIDs)), count)
+	})
+
+	t.Run("success - no low stock product", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT count(.*) FROM "products"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		ids, count, err := repo.SearchLowStockByPage(ctx, criteria)
+		require.NoError(t, err)
+		require.Empty(t, ids)
+		require.Equal(t, int64(0), count)
+	})
+
+	t.Run("failed - count error", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT count(.*) FROM "products"`).
+			WillReturnError(errors.New("db error"))
+
+		_, _, err := repo.SearchLowStockByPage(ctx, criteria)
+		require.Error(t, err)
+	})
+}
+
 func TestProductRepository_FindBySlug(t *testing.T) {
 	initializeTest()
 	kit, closer := initializeRepoTestKit(t)
@@ -263,3 +327,510 @@ func TestProductRepository_FindBySlug(t *testing.T) {
 		assert.Equal(t, gorm.ErrInvalidValue, err)
 	})
 }
+
+func TestProductRepository_DecreaseStockByID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	productID := utils.GenerateID()
+
+	t.Run("ok", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "products" SET "quantity"=quantity - \$1,"updated_at"=\$2 WHERE \(id = \$3 AND quantity >= \$4\) AND "products"."deleted_at" IS NULL`).
+			WithArgs(int64(2), sqlmock.AnyArg(), productID, int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DecreaseStockByID(ctx, kit.db, productID, 2)
+		require.NoError(t, err)
+	})
+
+	t.Run("failed - insufficient stock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "products"`).
+			WithArgs(int64(5), sqlmock.AnyArg(), productID, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.DecreaseStockByID(ctx, kit.db, productID, 5)
+		require.ErrorIs(t, err, model.ErrInsufficientStock)
+	})
+
+	t.Run("failed - db error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "products"`).WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := repo.DecreaseStockByID(ctx, kit.db, productID, 1)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, model.ErrInsufficientStock)
+	})
+}
+
+func TestProductRepository_IncreaseStockByID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	productID := utils.GenerateID()
+
+	t.Run("ok - include deleted product", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "products" SET "quantity"=quantity \+ \$1,"updated_at"=\$2 WHERE id = \$3$`).
+			WithArgs(int64(2), sqlmock.AnyArg(), productID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.IncreaseStockByID(ctx, kit.db, productID, 2)
+		require.NoError(t, err)
+	})
+
+	t.Run("failed - db error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "products"`).WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := repo.IncreaseStockByID(ctx, kit.db, productID, 1)
+		require.Error(t, err)
+	})
+}
+
+func TestProductRepository_ApplyReceivedCostByID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	productID := utils.GenerateID()
+
+	t.Run("ok - averaged with the stock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "quantity","cost_price" FROM "products" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"quantity", "cost_price"}).AddRow(10, 3000))
+		// (10 * 3000 + 30 * 3400) / 40
+		mock.ExpectExec(`^UPDATE "products" SET "cost_price"=\$1,"updated_at"=\$2 WHERE id = \$3$`).
+			WithArgs(int64(3300), sqlmock.AnyArg(), productID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := kit.db.Transaction(func(tx *gorm.DB) error {
+			return repo.ApplyReceivedCostByID(ctx, tx, productID, 30, 102000)
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ok - the received cost is taken as is without stock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "quantity","cost_price" FROM "products" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"quantity", "cost_price"}).AddRow(-2, 3000))
+		mock.ExpectExec(`^UPDATE "products" SET "cost_price"=\$1,"updated_at"=\$2 WHERE id = \$3$`).
+			WithArgs(int64(3400), sqlmock.AnyArg(), productID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := kit.db.Transaction(func(tx *gorm.DB) error {
+			return repo.ApplyReceivedCostByID(ctx, tx, productID, 30, 102000)
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_SearchByPage_CategoryID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	categoryID := int64(555)
+	criteria := model.ProductSearchCriteria{
+		Page:       1,
+		Size:       10,
+		SortType:   model.ProductSortTypeNameAsc,
+		CategoryID: categoryID,
+	}
+
+	t.Run("success - include the subcategories", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT count(.*) FROM "products" WHERE \(category_id IN \(WITH RECURSIVE "category_tree" AS .+\)`).
+			WithArgs(categoryID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(`^SELECT "id" FROM "products" WHERE \(category_id IN \(WITH RECURSIVE "category_tree" AS .+ ORDER BY name ASC`).
+			WithArgs(categoryID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(111).AddRow(222))
+
+		ids, count, err := repo.SearchByPage(ctx, criteria)
+		require.NoError(t, err)
+		require.Equal(t, []int64{111, 222}, ids)
+		require.Equal(t, int64(2), count)
+	})
+}
+
+func TestProductRepository_FindByCode(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	product := &model.Product{
+		ID:       utils.GenerateID(),
+		Name:     "Pisang Goreng",
+		Slug:     "pisang-goreng",
+		SKU:      "PISANG-GORENG",
+		Barcode:  "8991234567891",
+		Price:    5000,
+		Quantity: 20,
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		rows := sqlmock.NewRows([]string{"id", "name", "slug", "sku", "barcode", "price", "quantity"}).
+			AddRow(product.ID, product.Name, product.Slug, product.SKU, product.Barcode, product.Price, product.Quantity)
+
+		mock.ExpectQuery(`^SELECT "id" FROM "products" WHERE \(sku = \$1 OR barcode = \$2\)`).
+			WithArgs(product.Barcode, product.Barcode).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(product.ID))
+		mock.ExpectQuery("^SELECT .+ FROM \"products\"").WillReturnRows(rows)
+
+		res, err := repo.FindByCode(ctx, product.Barcode)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		require.Equal(t, product.ID, res.ID)
+		require.True(t, kit.miniredis.Exists(repo.newCacheKeyByCode(product.Barcode)))
+	})
+
+	t.Run("ok, from cache", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		_ = kit.miniredis.Set(repo.newCacheKeyByID(product.ID), utils.Dump(product))
+		_ = kit.miniredis.Set(repo.newCacheKeyByCode(product.SKU), utils.Int64ToString(product.ID))
+
+		res, err := repo.FindByCode(ctx, product.SKU)
+		require.NoError(t, err)
+		require.EqualValues(t, product, res)
+	})
+
+	t.Run("not found, the cached code has been changed", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		_ = kit.miniredis.Set(repo.newCacheKeyByID(product.ID), utils.Dump(product))
+		_ = kit.miniredis.Set(repo.newCacheKeyByCode("8990000000007"), utils.Int64ToString(product.ID))
+
+		res, err := repo.FindByCode(ctx, "8990000000007")
+		require.NoError(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		mock.ExpectQuery(`^SELECT "id" FROM "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		res, err := repo.FindByCode(ctx, "UNKNOWN")
+		require.NoError(t, err)
+		require.Nil(t, res)
+		require.True(t, kit.miniredis.Exists(repo.newCacheKeyByCode("UNKNOWN")))
+	})
+}
+
+func TestProductRepository_Update(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:           kit.db,
+		cache:        kit.cache,
+		variantRepo:  kit.mockProductVariantRepo,
+		movementRepo: kit.mockStockMovementRepo,
+		auditRepo:    kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	product := &model.Product{
+		ID:       utils.GenerateID(),
+		Name:     "Pisang Goreng",
+		Slug:     "pisang-goreng",
+		Price:    5000,
+		Quantity: 15,
+	}
+
+	t.Run("ok - the stored stock is kept", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT \* FROM "products" WHERE id = \$1 AND "products"."deleted_at" IS NULL .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug", "price", "quantity"}).
+				AddRow(product.ID, product.Name, product.Slug, product.Price, 20))
+		mock.ExpectQuery(`^SELECT \* FROM "product_variants" WHERE product_id = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity"}))
+		mock.ExpectExec(`^UPDATE "products"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockProductVariantRepo.EXPECT().ReplaceByProductID(ctx, gomock.Any(), product.ID, product.Variants).Times(1).Return(nil, nil)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), []*model.StockMovement(nil)).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), product, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductVariantRepo.EXPECT().DeleteCachesByProductID(product.ID, gomock.Any()).Times(1).Return(nil)
+
+		err := repo.Update(ctx, userID, product)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, int64(20), product.Quantity)
+	})
+
+	t.Run("ok - the stock of a removed variant is recorded as an adjustment", func(t *testing.T) {
+		options := model.ProductOptions{{Name: "Ukuran", Values: []string{"S", "M"}}}
+		variantProduct := &model.Product{
+			ID:      utils.GenerateID(),
+			Name:    "Es Teh",
+			Slug:    "es-teh",
+			Options: options,
+			Variants: []*model.ProductVariant{
+				{ID: 301, Options: map[string]string{"Ukuran": "S"}},
+			},
+		}
+		variantProduct.Variants[0].ProductID = variantProduct.ID
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT \* FROM "products" WHERE id = \$1 AND "products"."deleted_at" IS NULL .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug", "quantity", "options"}).
+				AddRow(variantProduct.ID, variantProduct.Name, variantProduct.Slug, 7, `[{"name":"Ukuran","values":["S","M"]}]`))
+		mock.ExpectQuery(`^SELECT \* FROM "product_variants" WHERE product_id = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity"}).
+				AddRow(301, variantProduct.ID, 4).
+				AddRow(302, variantProduct.ID, 3))
+		mock.ExpectExec(`^UPDATE "products"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockProductVariantRepo.EXPECT().ReplaceByProductID(ctx, gomock.Any(), variantProduct.ID, variantProduct.Variants).Times(1).Return(nil, nil)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), []*model.StockMovement{{
+			ProductID:     variantProduct.ID,
+			VariantID:     302,
+			Type:          model.StockMovementTypeAdjustment,
+			Quantity:      -3,
+			ReferenceType: model.StockReferenceProduct,
+			ReferenceID:   variantProduct.ID,
+			Note:          "product update",
+			CreatedBy:     userID,
+		}}).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), variantProduct, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductVariantRepo.EXPECT().DeleteCachesByProductID(variantProduct.ID, gomock.Any()).Times(1).Return(nil)
+
+		err := repo.Update(ctx, userID, variantProduct)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, int64(4), variantProduct.Quantity)
+		require.Equal(t, int64(4), variantProduct.Variants[0].Quantity)
+	})
+
+	t.Run("failed - product not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT \* FROM "products"`).WillReturnError(gorm.ErrRecordNotFound)
+		mock.ExpectRollback()
+
+		err := repo.Update(ctx, userID, product)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_PostStockMovements(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:           kit.db,
+		cache:        kit.cache,
+		variantRepo:  kit.mockProductVariantRepo,
+		movementRepo: kit.mockStockMovementRepo,
+	}
+
+	movements := []*model.StockMovement{
+		{ProductID: 333, VariantID: 301, Type: model.StockMovementTypeTransfer, Quantity: 4},
+		{ProductID: 222, Type: model.StockMovementTypeWaste, Quantity: -2},
+	}
+
+	t.Run("ok - change the stock ordered by product", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "products" SET "quantity"=quantity - \$1`).
+			WithArgs(int64(2), sqlmock.AnyArg(), int64(222), int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`^UPDATE "products" SET "quantity"=quantity \+ \$1`).
+			WithArgs(int64(4), sqlmock.AnyArg(), int64(333)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockProductVariantRepo.EXPECT().IncreaseStockByID(ctx, gomock.Any(), int64(301), int64(4)).Times(1).Return(nil)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), movements).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductVariantRepo.EXPECT().DeleteCachesByIDs([]int64{301}).Times(1).Return(nil)
+
+		err := repo.PostStockMovements(ctx, movements)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - insufficient stock rollback every movement", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "products" SET "quantity"=quantity - \$1`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.PostStockMovements(ctx, movements)
+		require.ErrorIs(t, err, model.ErrInsufficientStock)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_Import(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:           kit.db,
+		cache:        kit.cache,
+		variantRepo:  kit.mockProductVariantRepo,
+		movementRepo: kit.mockStockMovementRepo,
+		auditRepo:    kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	newProduct := &model.Product{
+		ID:       utils.GenerateID(),
+		Name:     "Kopi Susu",
+		Slug:     "kopi-susu",
+		SKU:      "KOPI-SUSU",
+		Price:    18000,
+		Quantity: 12,
+	}
+	existingProduct := &model.Product{
+		ID:       utils.GenerateID(),
+		Name:     "Pisang Goreng",
+		Slug:     "pisang-goreng",
+		SKU:      "PISANG-GORENG",
+		Price:    6000,
+		Quantity: 99,
+	}
+	imports := []*model.ProductImport{
+		{Line: 2, Product: newProduct, IsNew: true},
+		{Line: 3, Product: existingProduct},
+	}
+
+	t.Run("ok - the existing product keeps its stock & cost price", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^INSERT INTO "products"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(newProduct.ID))
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), []*model.StockMovement{{
+			ProductID:     newProduct.ID,
+			Type:          model.StockMovementTypeAdjustment,
+			Quantity:      12,
+			ReferenceType: model.StockReferenceProduct,
+			ReferenceID:   newProduct.ID,
+			Note:          "initial stock",
+			CreatedBy:     userID,
+		}}).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), newProduct, gomock.Any()).Times(1).Return(nil)
+
+		mock.ExpectQuery(`^SELECT \* FROM "products" WHERE id = \$1 AND "products"."deleted_at" IS NULL .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug", "quantity", "cost_price"}).
+				AddRow(existingProduct.ID, existingProduct.Name, existingProduct.Slug, 20, 4000))
+		mock.ExpectQuery(`^SELECT \* FROM "product_variants" WHERE product_id = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity"}))
+		mock.ExpectExec(`^UPDATE "products"`).WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockProductVariantRepo.EXPECT().ReplaceByProductID(ctx, gomock.Any(), existingProduct.ID, existingProduct.Variants).Times(1).Return(nil, nil)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), []*model.StockMovement(nil)).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), existingProduct, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.Import(ctx, userID, imports)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, int64(20), existingProduct.Quantity)
+		require.Equal(t, int64(4000), existingProduct.CostPrice)
+	})
+
+	t.Run("failed - a product fails, the whole batch is rolled back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^INSERT INTO "products"`).WillReturnError(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := repo.Import(ctx, userID, imports)
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_StreamAll(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT \* FROM "products" WHERE "products"."deleted_at" IS NULL ORDER BY id ASC`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price"}).
+				AddRow(1, "Kopi Susu", 18000).
+				AddRow(2, "Pisang Goreng", 6000))
+
+		var names []string
+		err := repo.StreamAll(ctx, func(product *model.Product) error {
+			names = append(names, product.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, []string{"Kopi Susu", "Pisang Goreng"}, names)
+	})
+
+	t.Run("failed - fn return err, the rest is not read", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT \* FROM "products"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(1, "Kopi Susu").
+				AddRow(2, "Pisang Goreng"))
+
+		calls := 0
+		err := repo.StreamAll(ctx, func(product *model.Product) error {
+			calls++
+			return errors.New("write error")
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+}
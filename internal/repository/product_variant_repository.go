@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type productVariantRepository struct {
+	db    *gorm.DB
+	cache cacher.CacheManager
+}
+
+// NewProductVariantRepository instantiate a new product variant repository,
+// the variants are written along with their product so they are audited as a part of the product
+func NewProductVariantRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+) model.ProductVariantRepository {
+	return &productVariantRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// FindByID find product variant by id
+func (p *productVariantRepository) FindByID(ctx context.Context, id int64) (*model.ProductVariant, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"variantID": id,
+	})
+
+	cacheKey := p.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.ProductVariant](p.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	variant := &model.ProductVariant{}
+	err := p.db.WithContext(ctx).Take(variant, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, p.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(variant))); err != nil {
+		logger.Error(err)
+	}
+
+	return variant, nil
+}
+
+// FindByCode find product variant with specific sku or barcode, the code is cached to the variant id.
+// A cached code is only trusted when it still belongs to the variant, since an update only deletes the caches of the new codes.
+func (p *productVariantRepository) FindByCode(ctx context.Context, code string) (*model.ProductVariant, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":  utils.DumpIncomingContext(ctx),
+		"code": code,
+	})
+
+	cacheKey := p.newCacheKeyByCode(code)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[int64](p.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return p.findByIDWithCode(ctx, reply, code)
+		}
+	}
+
+	var id int64
+	err := p.db.WithContext(ctx).Model(model.ProductVariant{}).Select("id").Take(&id, "sku = ? OR barcode = ?", code, code).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, p.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err = p.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, id)); err != nil {
+		logger.Error(err)
+	}
+
+	return p.findByIDWithCode(ctx, id, code)
+}
+
+// FindByProductID find the variants of the product ordered by the creation time,
+// only the ids are cached under the product so a stock change only deletes the variant cache
+func (p *productVariantRepository) FindByProductID(ctx context.Context, productID int64) ([]*model.ProductVariant, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"productID": productID,
+	})
+
+	ids, err := p.findIDsByProductID(ctx, productID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	var variants []*model.ProductVariant
+	for _, id := range ids {
+		variant, err := p.FindByID(ctx, id)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		if variant != nil {
+			variants = append(variants, variant)
+		}
+	}
+
+	return variants, nil
+}
+
+// ReplaceByProductID create the new variants, update the existing variants
+// and soft delete the stored variants which are missing from the list, all within the given db transaction
+func (p *productVariantRepository) ReplaceByProductID(ctx context.Context, tx *gorm.DB, productID int64, variants []*model.ProductVariant) (removed []*model.ProductVariant, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"productID": productID,
+		"variants":  utils.Dump(variants),
+	})
+
+	var storedVariants []*model.ProductVariant
+	err = tx.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Find(&storedVariants).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	stored := make(map[int64]bool, len(storedVariants))
+	for _, variant := range storedVariants {
+		stored[variant.ID] = true
+	}
+
+	kept := make(map[int64]bool, len(variants))
+	for _, variant := range variants {
+		kept[variant.ID] = true
+		variant.ProductID = productID
+		variant.UpdatedAt = time.Now()
+
+		if !stored[variant.ID] {
+			if err := tx.WithContext(ctx).Create(variant).Error; err != nil {
+				logger.Error(err)
+				return nil, err
+			}
+			continue
+		}
+
+		err := tx.WithContext(ctx).
+			Select("name", "sku", "barcode", "options", "price", "quantity", "updated_at").
+			Updates(variant).Error
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+	}
+
+	for _, variant := range storedVariants {
+		if !kept[variant.ID] {
+			removed = append(removed, variant)
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := tx.WithContext(ctx).Delete(&removed).Error; err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+	}
+
+	return removed, nil
+}
+
+// DecreaseStockByID decrease the variant stock using conditional update within the given db transaction,
+// so concurrent sales can never make the stock negative
+func (p *productVariantRepository) DecreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"variantID": id,
+		"quantity":  quantity,
+	})
+
+	res := tx.WithContext(ctx).
+		Model(model.ProductVariant{}).
+		Where("id = ? AND quantity >= ?", id, quantity).
+		Updates(map[string]any{
+			"quantity":   gorm.Expr("quantity - ?", quantity),
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		logger.Error(res.Error)
+		return res.Error
+	}
+
+	if res.RowsAffected <= 0 {
+		return model.ErrInsufficientStock
+	}
+
+	return nil
+}
+
+// IncreaseStockByID return the stock of a variant within the given db transaction,
+// the variant is restocked even when it has been deleted so the stock stays consistent with its product
+func (p *productVariantRepository) IncreaseStockByID(ctx context.Context, tx *gorm.DB, id, quantity int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"variantID": id,
+		"quantity":  quantity,
+	})
+
+	err := tx.WithContext(ctx).
+		Unscoped().
+		Model(model.ProductVariant{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"quantity":   gorm.Expr("quantity + ?", quantity),
+			"updated_at": time.Now(),
+		}).Error
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCachesByProductID delete the variant ids cache of the product and the caches of the given variants
+func (p *productVariantRepository) DeleteCachesByProductID(productID int64, variants []*model.ProductVariant) error {
+	cacheKeys := []string{p.newCacheKeyByProductID(productID)}
+	for _, variant := range variants {
+		cacheKeys = append(cacheKeys, p.newCacheKeyByID(variant.ID))
+		if variant.SKU != "" {
+			cacheKeys = append(cacheKeys, p.newCacheKeyByCode(variant.SKU))
+		}
+		if variant.Barcode != "" {
+			cacheKeys = append(cacheKeys, p.newCacheKeyByCode(variant.Barcode))
+		}
+	}
+
+	return p.cache.DeleteByKeys(cacheKeys)
+}
+
+// DeleteCachesByIDs delete the variant caches, used after the stock is changed outside this repository
+func (p *productVariantRepository) DeleteCachesByIDs(ids []int64) error {
+	if len(ids) <= 0 {
+		return nil
+	}
+
+	var cacheKeys []string
+	for _, id := range ids {
+		cacheKeys = append(cacheKeys, p.newCacheKeyByID(id))
+	}
+
+	return p.cache.DeleteByKeys(cacheKeys)
+}
+
+func (p *productVariantRepository) findByIDWithCode(ctx context.Context, id int64, code string) (*model.ProductVariant, error) {
+	variant, err := p.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if variant == nil || !variant.HasCode(code) {
+		return nil, nil
+	}
+
+	return variant, nil
+}
+
+func (p *productVariantRepository) findIDsByProductID(ctx context.Context, productID int64) ([]int64, error) {
+	cacheKey := p.newCacheKeyByProductID(productID)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[[]int64](p.cache, cacheKey)
+		if err != nil {
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	var ids []int64
+	err := p.db.WithContext(ctx).
+		Model(model.ProductVariant{}).
+		Where("product_id = ?", productID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) <= 0 {
+		cacher.StoreNil(ctx, p.cache, cacheKey)
+		return nil, nil
+	}
+
+	if err := p.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(ids))); err != nil {
+		logrus.WithField("productID", productID).Error(err)
+	}
+
+	return ids, nil
+}
+
+func (p *productVariantRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:product_variant:id:%d", id)
+}
+
+func (p *productVariantRepository) newCacheKeyByCode(code string) string {
+	return fmt.Sprintf("cache:object:product_variant:code:%s", code)
+}
+
+func (p *productVariantRepository) newCacheKeyByProductID(productID int64) string {
+	return fmt.Sprintf("cache:object:product_variant:product_id:%d", productID)
+}
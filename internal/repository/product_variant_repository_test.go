@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestProductVariantRepository_FindByProductID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productVariantRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	productID := int64(222)
+
+	t.Run("ok - the ids are cached under the product", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+
+		mock.ExpectQuery(`^SELECT "id" FROM "product_variants" WHERE product_id = .+ ORDER BY created_at ASC,id ASC`).
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(301).AddRow(302))
+		mock.ExpectQuery(`^SELECT \* FROM "product_variants" WHERE id = .+`).
+			WithArgs(301).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "options", "price", "quantity"}).
+				AddRow(301, productID, "S", "ES-TEH-S", `{"Ukuran":"S"}`, 5000, 10))
+		mock.ExpectQuery(`^SELECT \* FROM "product_variants" WHERE id = .+`).
+			WithArgs(302).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "options", "price", "quantity"}).
+				AddRow(302, productID, "L", "ES-TEH-L", `{"Ukuran":"L"}`, 8000, 4))
+
+		variants, err := repo.FindByProductID(ctx, productID)
+		require.NoError(t, err)
+		require.Len(t, variants, 2)
+		require.Equal(t, "L", variants[1].Options["Ukuran"])
+		require.True(t, kit.miniredis.Exists(repo.newCacheKeyByProductID(productID)))
+
+		// a stock change only deletes the variant cache
+		require.NoError(t, repo.DeleteCachesByIDs([]int64{302}))
+		mock.ExpectQuery(`^SELECT \* FROM "product_variants" WHERE id = .+`).
+			WithArgs(302).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "price", "quantity"}).
+				AddRow(302, productID, "L", "ES-TEH-L", 8000, 3))
+
+		variants, err = repo.FindByProductID(ctx, productID)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), variants[1].Quantity)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductVariantRepository_ReplaceByProductID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productVariantRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	productID := int64(222)
+
+	t.Run("ok - update, create and remove the variants", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT \* FROM "product_variants" WHERE product_id = .+`).
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku"}).
+				AddRow(301, productID, "S", "ES-TEH-S").
+				AddRow(302, productID, "M", "ES-TEH-M"))
+		mock.ExpectExec(`^UPDATE "product_variants" SET "name"=.+,"sku"=.+,"barcode"=.+,"options"=.+,"price"=.+,"quantity"=.+,"updated_at"=.+ WHERE .+"id" = .+`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`^INSERT INTO "product_variants"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(303))
+		mock.ExpectExec(`^UPDATE "product_variants" SET "deleted_at"=.+ WHERE "product_variants"."id" = .+`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		var removed []*model.ProductVariant
+		err := kit.db.Transaction(func(tx *gorm.DB) (err error) {
+			removed, err = repo.ReplaceByProductID(ctx, tx, productID, []*model.ProductVariant{
+				{ID: 301, Name: "S", SKU: "ES-TEH-S", Options: map[string]string{"Ukuran": "S"}, Price: 5000, Quantity: 10},
+				{ID: 303, Name: "L", SKU: "ES-TEH-L", Options: map[string]string{"Ukuran": "L"}, Price: 8000, Quantity: 5},
+			})
+			return err
+		})
+		require.NoError(t, err)
+		require.Len(t, removed, 1)
+		require.Equal(t, int64(302), removed[0].ID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductVariantRepository_DecreaseStockByID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &productVariantRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	t.Run("failed - insufficient stock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "product_variants" SET "quantity"=quantity - .+ WHERE \(id = .+ AND quantity >= .+\)`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := kit.db.Transaction(func(tx *gorm.DB) error {
+			return repo.DecreaseStockByID(ctx, tx, 301, 5)
+		})
+		require.ErrorIs(t, err, model.ErrInsufficientStock)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type promotionRepository struct {
+	db        *gorm.DB
+	cache     cacher.CacheManager
+	auditRepo model.AuditRepository
+}
+
+// NewPromotionRepository create new repository
+func NewPromotionRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	auditRepo model.AuditRepository,
+) model.PromotionRepository {
+	return &promotionRepository{
+		db:        db,
+		cache:     cache,
+		auditRepo: auditRepo,
+	}
+}
+
+// FindByID find promotion by id
+func (p *promotionRepository) FindByID(ctx context.Context, id int64) (*model.Promotion, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":         utils.DumpIncomingContext(ctx),
+		"promotionID": id,
+	})
+
+	cacheKey := p.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.Promotion](p.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	promotion := &model.Promotion{}
+	err := p.db.WithContext(ctx).Take(promotion, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, p.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(promotion))); err != nil {
+		logger.Error(err)
+	}
+
+	return promotion, nil
+}
+
+// SearchByPage find all promotion ids ordered by the newest
+func (p *promotionRepository) SearchByPage(ctx context.Context, criteria model.PromotionSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = p.db.WithContext(ctx).Model(model.Promotion{}).Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = p.db.WithContext(ctx).
+		Model(model.Promotion{}).
+		Scopes(scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("created_at DESC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
+// FindAllActive find all promotions flagged as active
+func (p *promotionRepository) FindAllActive(ctx context.Context) ([]*model.Promotion, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+	})
+
+	cacheKey := p.newActiveCacheKey()
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[[]*model.Promotion](p.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	var promotions []*model.Promotion
+	err := p.db.WithContext(ctx).
+		Where("is_active = ? AND end_at > ?", true, time.Now()).
+		Order("id ASC").
+		Find(&promotions).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(promotions) <= 0 {
+		cacher.StoreNil(ctx, p.cache, cacheKey)
+		return nil, nil
+	}
+
+	if err := p.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(promotions))); err != nil {
+		logger.Error(err)
+	}
+
+	return promotions, nil
+}
+
+// Create promotion
+func (p *promotionRepository) Create(ctx context.Context, userID int64, promotion *model.Promotion) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"promotion": utils.Dump(promotion),
+	})
+
+	promotion.UpdatedAt = time.Now()
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(promotion).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.auditRepo.Audit(ctx, tx, promotion, &model.Audit{
+			UserID:        userID,
+			AuditableType: p.name(),
+			AuditableID:   promotion.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.deleteCaches(promotion); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Update promotion, the zero values are updated too
+func (p *promotionRepository) Update(ctx context.Context, userID int64, promotion *model.Promotion) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"promotion": utils.Dump(promotion),
+	})
+
+	promotion.UpdatedAt = time.Now()
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("*").Omit("created_at", "deleted_at").Updates(promotion).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.auditRepo.Audit(ctx, tx, promotion, &model.Audit{
+			UserID:        userID,
+			AuditableType: p.name(),
+			AuditableID:   promotion.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.deleteCaches(promotion); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Delete soft delete a promotion
+func (p *promotionRepository) Delete(ctx context.Context, userID int64, promotion *model.Promotion) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"userID":    userID,
+		"promotion": utils.Dump(promotion),
+	})
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(promotion).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.auditRepo.Audit(ctx, tx, promotion, &model.Audit{
+			UserID:        userID,
+			AuditableType: p.name(),
+			AuditableID:   promotion.ID,
+			Action:        model.AuditActionDelete,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.deleteCaches(promotion); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+func (p *promotionRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:promotion:id:%d", id)
+}
+
+func (p *promotionRepository) newActiveCacheKey() string {
+	return "cache:object:promotion:active"
+}
+
+// deleteCaches delete related cache
+func (p *promotionRepository) deleteCaches(promotion *model.Promotion) error {
+	if promotion == nil {
+		return nil
+	}
+
+	return p.cache.DeleteByKeys([]string{
+		p.newCacheKeyByID(promotion.ID),
+		p.newActiveCacheKey(),
+	})
+}
+
+func (p *promotionRepository) name() string {
+	return "promotion"
+}
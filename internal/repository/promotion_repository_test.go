@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestPromotionRepository_FindByID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &promotionRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	promotionID := utils.GenerateID()
+
+	t.Run("ok", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		mock.ExpectQuery(`^SELECT .+ FROM "promotions" WHERE id = .+ AND "promotions"."deleted_at" IS NULL`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "type"}).
+				AddRow(promotionID, "Diskon 10%", model.PromotionTypeCartPercentage))
+
+		res, err := repo.FindByID(ctx, promotionID)
+		require.NoError(t, err)
+		require.Equal(t, promotionID, res.ID)
+		require.True(t, kit.miniredis.Exists(repo.newCacheKeyByID(promotionID)))
+	})
+
+	t.Run("failed - not found", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		mock.ExpectQuery(`^SELECT .+ FROM "promotions"`).WillReturnError(gorm.ErrRecordNotFound)
+
+		res, err := repo.FindByID(ctx, promotionID)
+		require.NoError(t, err)
+		require.Nil(t, res)
+
+		cacheVal, err := kit.miniredis.Get(repo.newCacheKeyByID(promotionID))
+		require.NoError(t, err)
+		require.Equal(t, `null`, cacheVal)
+	})
+}
+
+func TestPromotionRepository_FindAllActive(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &promotionRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		mock.ExpectQuery(`^SELECT .+ FROM "promotions" WHERE \(is_active = .+ AND end_at > .+\) AND "promotions"."deleted_at" IS NULL ORDER BY id ASC`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "is_active"}).AddRow(int64(111), true).AddRow(int64(222), true))
+
+		res, err := repo.FindAllActive(ctx)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.True(t, kit.miniredis.Exists(repo.newActiveCacheKey()))
+	})
+
+	t.Run("ok - from cache", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		err := kit.miniredis.Set(repo.newActiveCacheKey(), utils.Dump([]*model.Promotion{{ID: 111, IsActive: true}}))
+		require.NoError(t, err)
+
+		res, err := repo.FindAllActive(ctx)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+	})
+}
+
+func TestPromotionRepository_Create(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &promotionRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	promotion := &model.Promotion{
+		ID:       utils.GenerateID(),
+		Name:     "Diskon 10%",
+		Type:     model.PromotionTypeCartPercentage,
+		Value:    10,
+		StartAt:  time.Now(),
+		EndAt:    time.Now().Add(time.Hour),
+		IsActive: true,
+	}
+
+	t.Run("ok - invalidate active promotions cache", func(t *testing.T) {
+		err := kit.miniredis.Set(repo.newActiveCacheKey(), "null")
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^INSERT INTO "promotions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), promotion, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err = repo.Create(ctx, int64(111), promotion)
+		require.NoError(t, err)
+		require.False(t, kit.miniredis.Exists(repo.newActiveCacheKey()))
+	})
+}
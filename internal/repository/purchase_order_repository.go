@@ -0,0 +1,507 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type purchaseOrderRepository struct {
+	db                 *gorm.DB
+	cache              cacher.CacheManager
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+	auditRepo          model.AuditRepository
+}
+
+// NewPurchaseOrderRepository instantiate a new purchase order repository
+func NewPurchaseOrderRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	auditRepo model.AuditRepository,
+) model.PurchaseOrderRepository {
+	return &purchaseOrderRepository{
+		db:                 db,
+		cache:              cache,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+		auditRepo:          auditRepo,
+	}
+}
+
+// FindByID find purchase order by id along with its items & goods receipts
+func (p *purchaseOrderRepository) FindByID(ctx context.Context, id int64) (*model.PurchaseOrder, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":             utils.DumpIncomingContext(ctx),
+		"purchaseOrderID": id,
+	})
+
+	cacheKey := p.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.PurchaseOrder](p.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	order := &model.PurchaseOrder{}
+	err := p.db.WithContext(ctx).Take(order, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, p.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	order.Items, err = p.findItemsByID(p.db.WithContext(ctx), order.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	order.Receipts, err = p.findReceiptsByID(p.db.WithContext(ctx), order.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(order))); err != nil {
+		logger.Error(err)
+	}
+
+	return order, nil
+}
+
+// SearchByPage find all purchase order ids ordered by the newest
+func (p *purchaseOrderRepository) SearchByPage(ctx context.Context, criteria model.PurchaseOrderSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = p.db.WithContext(ctx).
+		Model(model.PurchaseOrder{}).
+		Scopes(scopesByPurchaseOrderSearchCriteria(criteria)...).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = p.db.WithContext(ctx).
+		Model(model.PurchaseOrder{}).
+		Scopes(scopesByPurchaseOrderSearchCriteria(criteria)...).
+		Scopes(scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("created_at DESC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
+// Create store a new draft purchase order along with its items
+func (p *purchaseOrderRepository) Create(ctx context.Context, userID int64, order *model.PurchaseOrder) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+		"order":  utils.Dump(order),
+	})
+
+	now := time.Now()
+	order.Status = model.PurchaseOrderStatusDraft
+	order.CreatedBy = userID
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.createItems(tx, order); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.auditRepo.Audit(ctx, tx, order, &model.Audit{
+			UserID:        userID,
+			AuditableType: p.name(),
+			AuditableID:   order.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.deleteCaches(order); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Update the supplier, note & items of a draft purchase order, the stored items are replaced
+func (p *purchaseOrderRepository) Update(ctx context.Context, userID int64, order *model.PurchaseOrder) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+		"order":  utils.Dump(order),
+	})
+
+	order.UpdatedAt = time.Now()
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		status, err := p.lockStatusByID(tx, order.ID)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+		if status != model.PurchaseOrderStatusDraft {
+			return model.ErrInvalidPurchaseOrderStatus
+		}
+
+		if err := tx.Select("supplier_id", "note", "total_cost", "updated_at").Updates(order).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := tx.Where("purchase_order_id = ?", order.ID).Delete(&model.PurchaseOrderItem{}).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.createItems(tx, order); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.auditRepo.Audit(ctx, tx, order, &model.Audit{
+			UserID:        userID,
+			AuditableType: p.name(),
+			AuditableID:   order.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.deleteCaches(order); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// UpdateStatus lock the purchase order and move it to the status, the sent & closed time are recorded
+func (p *purchaseOrderRepository) UpdateStatus(ctx context.Context, userID int64, order *model.PurchaseOrder, status model.PurchaseOrderStatus) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+		"order":  utils.Dump(order),
+		"status": status,
+	})
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		storedStatus, err := p.lockStatusByID(tx, order.ID)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+		if !storedStatus.CanTransitionTo(status) {
+			return model.ErrInvalidPurchaseOrderStatus
+		}
+
+		now := time.Now()
+		order.Status = status
+		order.UpdatedAt = now
+		switch status {
+		case model.PurchaseOrderStatusSent:
+			order.SentAt = &now
+		case model.PurchaseOrderStatusClosed:
+			order.ClosedAt = &now
+		}
+
+		if err := tx.Select("status", "sent_at", "closed_at", "updated_at").Updates(order).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.auditRepo.Audit(ctx, tx, order, &model.Audit{
+			UserID:        userID,
+			AuditableType: p.name(),
+			AuditableID:   order.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.deleteCaches(order); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Receive lock the purchase order and receive the goods of the receipt against its stored items,
+// so concurrent receipts never receive more than ordered. The received goods are added to the stock
+// as receipt movements and the cost price of the received products is averaged with the cost price of the items.
+func (p *purchaseOrderRepository) Receive(ctx context.Context, userID int64, order *model.PurchaseOrder, receipt *model.GoodsReceipt) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":     utils.DumpIncomingContext(ctx),
+		"userID":  userID,
+		"order":   utils.Dump(order),
+		"receipt": utils.Dump(receipt),
+	})
+
+	receipt.PurchaseOrderID = order.ID
+	receipt.ReceivedBy = userID
+	receipt.CreatedAt = time.Now()
+
+	var movements []*model.StockMovement
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		stored := &model.PurchaseOrder{}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Take(stored, "id = ?", order.ID).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		stored.Items, err = p.findItemsByID(tx, stored.ID)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := stored.Receive(receipt); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := tx.Create(receipt).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		for _, item := range receipt.Items {
+			item.GoodsReceiptID = receipt.ID
+		}
+		if err := tx.Create(receipt.Items).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		for _, receiptItem := range receipt.Items {
+			item := model.AnyPurchaseOrderItems(stored.Items).FindByID(receiptItem.PurchaseOrderItemID)
+			err := tx.Model(item).Update("received_quantity", item.ReceivedQuantity).Error
+			if err != nil {
+				logger.Error(err)
+				return err
+			}
+		}
+
+		// the cost is averaged against the stock before the received goods are added
+		for _, cost := range receipt.ReceivedCosts() {
+			if err := p.productRepo.ApplyReceivedCostByID(ctx, tx, cost.ProductID, cost.Quantity, cost.Amount); err != nil {
+				logger.Error(err)
+				return err
+			}
+		}
+
+		movements = receipt.StockMovements()
+		if err := p.productRepo.ApplyStockMovements(ctx, tx, movements); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		stored.UpdatedAt = time.Now()
+		if err := tx.Select("status", "updated_at").Updates(stored).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := p.auditRepo.Audit(ctx, tx, receipt, &model.Audit{
+			UserID:        userID,
+			AuditableType: p.name(),
+			AuditableID:   stored.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		stored.Receipts = append(order.Receipts, receipt)
+		*order = *stored
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.deleteCaches(order); err != nil {
+		logger.Error(err)
+	}
+
+	if err := p.productRepo.DeleteCachesByIDs(model.AnyStockMovements(movements).ProductIDs()); err != nil {
+		logger.Error(err)
+	}
+
+	if err := p.productVariantRepo.DeleteCachesByIDs(model.AnyStockMovements(movements).VariantIDs()); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+func (p *purchaseOrderRepository) createItems(tx *gorm.DB, order *model.PurchaseOrder) error {
+	if len(order.Items) <= 0 {
+		return nil
+	}
+
+	for _, item := range order.Items {
+		item.PurchaseOrderID = order.ID
+	}
+
+	return tx.Create(order.Items).Error
+}
+
+func (p *purchaseOrderRepository) findItemsByID(db *gorm.DB, id int64) (items []*model.PurchaseOrderItem, err error) {
+	err = db.Where("purchase_order_id = ?", id).
+		Order("id ASC").
+		Find(&items).Error
+	return items, err
+}
+
+// findReceiptsByID find the goods receipts of the purchase order along with their items, the oldest first
+func (p *purchaseOrderRepository) findReceiptsByID(db *gorm.DB, id int64) ([]*model.GoodsReceipt, error) {
+	var receipts []*model.GoodsReceipt
+	err := db.Where("purchase_order_id = ?", id).
+		Order("created_at ASC").
+		Find(&receipts).Error
+	if err != nil || len(receipts) <= 0 {
+		return receipts, err
+	}
+
+	receiptIDs := make([]int64, 0, len(receipts))
+	for _, receipt := range receipts {
+		receiptIDs = append(receiptIDs, receipt.ID)
+	}
+
+	var items []*model.GoodsReceiptItem
+	if err := db.Where("goods_receipt_id IN (?)", receiptIDs).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	for _, receipt := range receipts {
+		for _, item := range items {
+			if item.GoodsReceiptID == receipt.ID {
+				receipt.Items = append(receipt.Items, item)
+			}
+		}
+	}
+
+	return receipts, nil
+}
+
+func (p *purchaseOrderRepository) lockStatusByID(tx *gorm.DB, id int64) (model.PurchaseOrderStatus, error) {
+	// gorm only scans a single column into the builtin types
+	var status string
+	err := tx.Model(model.PurchaseOrder{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("status").
+		Where("id = ?", id).
+		Take(&status).Error
+	if err != nil {
+		return "", err
+	}
+
+	return model.PurchaseOrderStatus(status), nil
+}
+
+func (p *purchaseOrderRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:purchase_order:id:%d", id)
+}
+
+// deleteCaches delete related cache
+func (p *purchaseOrderRepository) deleteCaches(order *model.PurchaseOrder) error {
+	if order == nil {
+		return nil
+	}
+
+	return p.cache.DeleteByKeys([]string{p.newCacheKeyByID(order.ID)})
+}
+
+func (p *purchaseOrderRepository) name() string {
+	return "purchase_order"
+}
+
+// scopesByPurchaseOrderSearchCriteria build the filter scopes of purchase order search criteria
+func scopesByPurchaseOrderSearchCriteria(criteria model.PurchaseOrderSearchCriteria) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if criteria.Status != "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("status = ?", criteria.Status)
+		})
+	}
+
+	if criteria.SupplierID > 0 {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("supplier_id = ?", criteria.SupplierID)
+		})
+	}
+
+	return scopes
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPurchaseOrderRepository_UpdateStatus(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &purchaseOrderRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+
+	t.Run("ok - send a draft", func(t *testing.T) {
+		order := &model.PurchaseOrder{ID: utils.GenerateID(), Status: model.PurchaseOrderStatusDraft}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "purchase_orders" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.PurchaseOrderStatusDraft))
+		mock.ExpectExec(`^UPDATE "purchase_orders" SET "status"=.+,"sent_at"=.+,"closed_at"=.+,"updated_at"=.+ WHERE "id" = .+`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), order, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.UpdateStatus(ctx, userID, order, model.PurchaseOrderStatusSent)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, model.PurchaseOrderStatusSent, order.Status)
+		require.NotNil(t, order.SentAt)
+	})
+
+	t.Run("failed - a closed purchase order can not be sent", func(t *testing.T) {
+		order := &model.PurchaseOrder{ID: utils.GenerateID(), Status: model.PurchaseOrderStatusDraft}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "purchase_orders" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.PurchaseOrderStatusClosed))
+		mock.ExpectRollback()
+
+		err := repo.UpdateStatus(ctx, userID, order, model.PurchaseOrderStatusSent)
+		require.ErrorIs(t, err, model.ErrInvalidPurchaseOrderStatus)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPurchaseOrderRepository_Receive(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &purchaseOrderRepository{
+		db:                 kit.db,
+		cache:              kit.cache,
+		productRepo:        kit.mockProductRepo,
+		productVariantRepo: kit.mockProductVariantRepo,
+		auditRepo:          kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	orderColumns := []string{"id", "supplier_id", "status", "note", "total_cost", "created_by", "created_at", "updated_at"}
+	itemColumns := []string{"id", "purchase_order_id", "product_id", "variant_id", "quantity", "received_quantity", "cost_price"}
+
+	t.Run("ok - partially received", func(t *testing.T) {
+		order := &model.PurchaseOrder{ID: utils.GenerateID(), Status: model.PurchaseOrderStatusSent}
+		receipt := &model.GoodsReceipt{
+			ID:    utils.GenerateID(),
+			Note:  "surat jalan 0042",
+			Items: []*model.GoodsReceiptItem{{PurchaseOrderItemID: 701, Quantity: 12}},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT \* FROM "purchase_orders" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow(order.ID, 555, model.PurchaseOrderStatusSent, "", 102000, userID, time.Now(), time.Now()))
+		mock.ExpectQuery(`^SELECT \* FROM "purchase_order_items" WHERE purchase_order_id = .+ ORDER BY id ASC`).
+			WithArgs(order.ID).
+			WillReturnRows(sqlmock.NewRows(itemColumns).
+				AddRow(701, order.ID, 222, 0, 24, 0, 3000).
+				AddRow(702, order.ID, 333, 301, 10, 0, 3000))
+		mock.ExpectQuery(`^INSERT INTO "goods_receipts"`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		mock.ExpectExec(`^INSERT INTO "goods_receipt_items"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`^UPDATE "purchase_order_items" SET "received_quantity"=.+ WHERE "id" = .+`).
+			WithArgs(int64(12), int64(701)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockProductRepo.EXPECT().ApplyReceivedCostByID(ctx, gomock.Any(), int64(222), int64(12), int64(36000)).Times(1).Return(nil)
+		kit.mockProductRepo.EXPECT().ApplyStockMovements(ctx, gomock.Any(), []*model.StockMovement{{
+			ProductID:     222,
+			Type:          model.StockMovementTypeReceipt,
+			Quantity:      12,
+			ReferenceType: model.StockReferenceGoodsReceipt,
+			ReferenceID:   receipt.ID,
+			Note:          receipt.Note,
+			CreatedBy:     userID,
+		}}).Times(1).Return(nil)
+		mock.ExpectExec(`^UPDATE "purchase_orders" SET "status"=.+,"updated_at"=.+ WHERE "id" = .+`).
+			WithArgs(model.PurchaseOrderStatusPartiallyReceived, sqlmock.AnyArg(), order.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), receipt, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductRepo.EXPECT().DeleteCachesByIDs([]int64{222}).Times(1).Return(nil)
+		kit.mockProductVariantRepo.EXPECT().DeleteCachesByIDs(gomock.Nil()).Times(1).Return(nil)
+
+		err := repo.Receive(ctx, userID, order, receipt)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, model.PurchaseOrderStatusPartiallyReceived, order.Status)
+		require.Equal(t, int64(12), order.Items[0].ReceivedQuantity)
+		require.Len(t, order.Receipts, 1)
+		require.Equal(t, int64(3000), receipt.Items[0].CostPrice)
+	})
+
+	t.Run("failed - received more than the remaining quantity", func(t *testing.T) {
+		order := &model.PurchaseOrder{ID: utils.GenerateID(), Status: model.PurchaseOrderStatusPartiallyReceived}
+		receipt := &model.GoodsReceipt{
+			ID:    utils.GenerateID(),
+			Items: []*model.GoodsReceiptItem{{PurchaseOrderItemID: 701, Quantity: 13}},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT \* FROM "purchase_orders" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow(order.ID, 555, model.PurchaseOrderStatusPartiallyReceived, "", 72000, userID, time.Now(), time.Now()))
+		mock.ExpectQuery(`^SELECT \* FROM "purchase_order_items" WHERE purchase_order_id = .+ ORDER BY id ASC`).
+			WithArgs(order.ID).
+			WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(701, order.ID, 222, 0, 24, 12, 3000))
+		mock.ExpectRollback()
+
+		err := repo.Receive(ctx, userID, order, receipt)
+		require.ErrorIs(t, err, model.ErrPurchaseOrderOverReceived)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - purchase order is closed", func(t *testing.T) {
+		order := &model.PurchaseOrder{ID: utils.GenerateID(), Status: model.PurchaseOrderStatusSent}
+		receipt := &model.GoodsReceipt{
+			ID:    utils.GenerateID(),
+			Items: []*model.GoodsReceiptItem{{PurchaseOrderItemID: 701, Quantity: 1}},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT \* FROM "purchase_orders" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows(orderColumns).
+				AddRow(order.ID, 555, model.PurchaseOrderStatusClosed, "", 72000, userID, time.Now(), time.Now()))
+		mock.ExpectQuery(`^SELECT \* FROM "purchase_order_items" WHERE purchase_order_id = .+ ORDER BY id ASC`).
+			WithArgs(order.ID).
+			WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(701, order.ID, 222, 0, 24, 0, 3000))
+		mock.ExpectRollback()
+
+		err := repo.Receive(ctx, userID, order, receipt)
+		require.ErrorIs(t, err, model.ErrInvalidPurchaseOrderStatus)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -20,7 +20,7 @@ func TestGroupRepository_CreateRoleResourceAction(t *testing.T) {
 	mock := kit.dbmock
 	repo := &rbacRepository{
 		db:           kit.db,
-		cacheManager: kit.cacheKeeper,
+		cacheManager: kit.cache,
 	}
 
 	var (
@@ -56,7 +56,7 @@ func TestGroupRepository_LoadPermission(t *testing.T) {
 	mock := kit.dbmock
 	repo := &rbacRepository{
 		db:           kit.db,
-		cacheManager: kit.cacheKeeper,
+		cacheManager: kit.cache,
 	}
 
 	var (
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type refundRepository struct {
+	db                 *gorm.DB
+	cache              cacher.CacheManager
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+	stockMovementRepo  model.StockMovementRepository
+	shiftRepo          model.ShiftRepository
+	auditRepo          model.AuditRepository
+}
+
+// NewRefundRepository instantiate a new refund repository
+func NewRefundRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	stockMovementRepo model.StockMovementRepository,
+	shiftRepo model.ShiftRepository,
+	auditRepo model.AuditRepository,
+) model.RefundRepository {
+	return &refundRepository{
+		db:                 db,
+		cache:              cache,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+		stockMovementRepo:  stockMovementRepo,
+		shiftRepo:          shiftRepo,
+		auditRepo:          auditRepo,
+	}
+}
+
+// FindByTransactionID find all refunds of a transaction along with their details, ordered by creation time
+func (r *refundRepository) FindByTransactionID(ctx context.Context, transactionID int64) ([]*model.Refund, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"transactionID": transactionID,
+	})
+
+	cacheKey := r.newCacheKeyByTransactionID(transactionID)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[[]*model.Refund](r.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	var refunds []*model.Refund
+	err := r.db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("created_at ASC").
+		Find(&refunds).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(refunds) <= 0 {
+		cacher.StoreNil(ctx, r.cache, cacheKey)
+		return nil, nil
+	}
+
+	var details []*model.RefundDetail
+	err = r.db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("product_id ASC").
+		Find(&details).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	refundByID := make(map[int64]*model.Refund, len(refunds))
+	for _, refund := range refunds {
+		refundByID[refund.ID] = refund
+	}
+	for _, detail := range details {
+		if refund, ok := refundByID[detail.RefundID]; ok {
+			refund.RefundDetails = append(refund.RefundDetails, detail)
+		}
+	}
+
+	if err := r.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(refunds))); err != nil {
+		logger.Error(err)
+	}
+
+	return refunds, nil
+}
+
+// Create store the refund and restock the refunded products & variants in a single db transaction,
+// every restocked line is recorded as a refund movement on the stock ledger.
+// The transaction row is locked first, so concurrent refunds of the same transaction
+// can never return more than what was sold. The shift paying the refund is share locked,
+// so it can not be closed before the refund is committed. The details are merged & sorted by product & variant first,
+// so the product & variant rows are locked in the same order as the sales and concurrent sales can not deadlock.
+func (r *refundRepository) Create(ctx context.Context, userID int64, refund *model.Refund) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+		"refund": utils.Dump(refund),
+	})
+
+	refund.MergeRefundDetails()
+
+	var productIDs, variantIDs []int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Take(&model.Transaction{}, "id = ?", refund.TransactionID).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if refund.ShiftID > 0 {
+			if err := r.shiftRepo.LockOpenByID(ctx, tx, refund.ShiftID); err != nil {
+				logger.Error(err)
+				return err
+			}
+		}
+
+		if err := r.checkRefundable(ctx, tx, refund); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := tx.Create(refund).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := tx.Create(refund.RefundDetails).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		for _, detail := range refund.RefundDetails {
+			if err := r.productRepo.IncreaseStockByID(ctx, tx, detail.ProductID, detail.Quantity); err != nil {
+				logger.Error(err)
+				return err
+			}
+			productIDs = append(productIDs, detail.ProductID)
+
+			if detail.VariantID <= 0 {
+				continue
+			}
+
+			if err := r.productVariantRepo.IncreaseStockByID(ctx, tx, detail.VariantID, detail.Quantity); err != nil {
+				logger.Error(err)
+				return err
+			}
+			variantIDs = append(variantIDs, detail.VariantID)
+		}
+
+		if err := r.stockMovementRepo.Create(ctx, tx, refund.StockMovements(userID)); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := r.auditRepo.Audit(ctx, tx, refund, &model.Audit{
+			UserID:        userID,
+			AuditableType: r.name(),
+			AuditableID:   refund.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := r.cache.DeleteByKeys([]string{r.newCacheKeyByTransactionID(refund.TransactionID)}); err != nil {
+		logger.Error(err)
+	}
+
+	if err := r.productRepo.DeleteCachesByIDs(productIDs); err != nil {
+		logger.Error(err)
+	}
+
+	if len(variantIDs) > 0 {
+		if err := r.productVariantRepo.DeleteCachesByIDs(variantIDs); err != nil {
+			logger.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// checkRefundable make sure a void is the only refund of a transaction,
+// and the refunded quantity never exceeds the sold quantity of each product & variant
+func (r *refundRepository) checkRefundable(ctx context.Context, tx *gorm.DB, refund *model.Refund) error {
+	var refundCount int64
+	err := tx.WithContext(ctx).
+		Model(model.Refund{}).
+		Where("transaction_id = ?", refund.TransactionID).
+		Count(&refundCount).Error
+	if err != nil {
+		return err
+	}
+
+	if refund.Type == model.RefundTypeVoid && refundCount > 0 {
+		return model.ErrTransactionAlreadyRefunded
+	}
+
+	var soldDetails []*model.TransactionDetail
+	err = tx.WithContext(ctx).
+		Where("transaction_id = ?", refund.TransactionID).
+		Find(&soldDetails).Error
+	if err != nil {
+		return err
+	}
+
+	var refundedDetails []*model.RefundDetail
+	err = tx.WithContext(ctx).
+		Model(model.RefundDetail{}).
+		Select("product_id, variant_id, SUM(quantity) AS quantity").
+		Where("transaction_id = ?", refund.TransactionID).
+		Group("product_id, variant_id").
+		Scan(&refundedDetails).Error
+	if err != nil {
+		return err
+	}
+
+	remaining := make(map[model.ProductVariantKey]int64, len(soldDetails))
+	for _, detail := range soldDetails {
+		remaining[detail.Key()] += detail.Quantity
+	}
+	for _, refunded := range refundedDetails {
+		remaining[refunded.Key()] -= refunded.Quantity
+	}
+
+	for _, detail := range refund.RefundDetails {
+		if detail.Quantity > remaining[detail.Key()] {
+			return model.ErrRefundQuantityExceeded
+		}
+	}
+
+	return nil
+}
+
+func (r *refundRepository) newCacheKeyByTransactionID(transactionID int64) string {
+	return fmt.Sprintf("cache:object:refund:transactionID:%d", transactionID)
+}
+
+func (r *refundRepository) name() string {
+	return "refund"
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRefundRepository_FindByTransactionID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &refundRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	transactionID := utils.GenerateID()
+	refundID := utils.GenerateID()
+
+	t.Run("ok", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		mock.ExpectQuery(`^SELECT .+ FROM "refunds" WHERE transaction_id = .+ ORDER BY created_at ASC`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "type", "total_amount"}).
+				AddRow(refundID, transactionID, model.RefundTypeRefund, 5000))
+		mock.ExpectQuery(`^SELECT .+ FROM "refund_details" WHERE transaction_id = .+ ORDER BY product_id ASC`).
+			WillReturnRows(sqlmock.NewRows([]string{"refund_id", "transaction_id", "product_id", "quantity", "subtotal"}).
+				AddRow(refundID, transactionID, int64(222), 1, 5000))
+
+		res, err := repo.FindByTransactionID(ctx, transactionID)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Len(t, res[0].RefundDetails, 1)
+		require.True(t, kit.miniredis.Exists(repo.newCacheKeyByTransactionID(transactionID)))
+	})
+
+	t.Run("ok - no refund", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		mock.ExpectQuery(`^SELECT .+ FROM "refunds"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		res, err := repo.FindByTransactionID(ctx, transactionID)
+		require.NoError(t, err)
+		require.Nil(t, res)
+
+		cacheVal, err := kit.miniredis.Get(repo.newCacheKeyByTransactionID(transactionID))
+		require.NoError(t, err)
+		require.Equal(t, `null`, cacheVal)
+	})
+}
+
+func TestRefundRepository_Create(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &refundRepository{
+		db:                kit.db,
+		cache:             kit.cache,
+		productRepo:       kit.mockProductRepo,
+		stockMovementRepo: kit.mockStockMovementRepo,
+		shiftRepo:         &shiftRepository{db: kit.db},
+		auditRepo:         kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	transactionID := utils.GenerateID()
+	refundID := utils.GenerateID()
+	newRefund := func(refundType model.RefundType, quantity int64) *model.Refund {
+		return &model.Refund{
+			ID:            refundID,
+			TransactionID: transactionID,
+			Type:          refundType,
+			TotalAmount:   5000 * quantity,
+			Reason:        "produk rusak",
+			ApprovedBy:    userID,
+			CreatedAt:     time.Now(),
+			RefundDetails: []*model.RefundDetail{
+				{RefundID: refundID, TransactionID: transactionID, ProductID: int64(222), Quantity: quantity, Subtotal: 5000 * quantity},
+			},
+		}
+	}
+
+	expectLockAndCountRefunds := func(refundCount int64) {
+		mock.ExpectQuery(`^SELECT "id" FROM "transactions" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionID))
+		mock.ExpectQuery(`^SELECT count(.*) FROM "refunds" WHERE transaction_id = .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(refundCount))
+	}
+
+	expectRemaining := func(refundedQuantity int64) {
+		mock.ExpectQuery(`^SELECT .+ FROM "transaction_details" WHERE transaction_id = .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "product_id", "quantity", "subtotal"}).
+				AddRow(transactionID, int64(222), 3, 15000))
+		rows := sqlmock.NewRows([]string{"product_id", "variant_id", "quantity"})
+		if refundedQuantity > 0 {
+			rows.AddRow(int64(222), int64(0), refundedQuantity)
+		}
+		mock.ExpectQuery(`^SELECT product_id, variant_id, SUM\(quantity\) AS quantity FROM "refund_details" WHERE transaction_id = .+ GROUP BY product_id, variant_id`).
+			WillReturnRows(rows)
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		refund := newRefund(model.RefundTypeRefund, 2)
+		mock.ExpectBegin()
+		expectLockAndCountRefunds(1)
+		expectRemaining(1)
+		mock.ExpectQuery(`^INSERT INTO "refunds"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(refund.ID))
+		mock.ExpectExec(`^INSERT INTO "refund_details"`).WillReturnResult(sqlmock.NewResult(1, 1))
+		kit.mockProductRepo.EXPECT().IncreaseStockByID(ctx, gomock.Any(), int64(222), int64(2)).Times(1).Return(nil)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), []*model.StockMovement{{
+			ProductID:     222,
+			Type:          model.StockMovementTypeRefund,
+			Quantity:      2,
+			ReferenceType: model.StockReferenceRefund,
+			ReferenceID:   refund.ID,
+			Note:          refund.Reason,
+			CreatedBy:     userID,
+		}}).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), refund, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductRepo.EXPECT().DeleteCachesByIDs([]int64{222}).Times(1).Return(nil)
+
+		err := repo.Create(ctx, userID, refund)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ok - restock in the product order of the sales", func(t *testing.T) {
+		refund := newRefund(model.RefundTypeVoid, 1)
+		refund.RefundDetails = []*model.RefundDetail{
+			{RefundID: refundID, TransactionID: transactionID, ProductID: int64(333), Quantity: 1, Subtotal: 4000},
+			{RefundID: refundID, TransactionID: transactionID, ProductID: int64(222), Quantity: 1, Subtotal: 5000},
+			{RefundID: refundID, TransactionID: transactionID, ProductID: int64(333), Quantity: 1, Subtotal: 4000},
+		}
+		mock.ExpectBegin()
+		expectLockAndCountRefunds(0)
+		mock.ExpectQuery(`^SELECT .+ FROM "transaction_details" WHERE transaction_id = .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "product_id", "quantity", "subtotal"}).
+				AddRow(transactionID, int64(222), 1, 5000).
+				AddRow(transactionID, int64(333), 2, 8000))
+		mock.ExpectQuery(`^SELECT product_id, variant_id, SUM\(quantity\) AS quantity FROM "refund_details"`).
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "variant_id", "quantity"}))
+		mock.ExpectQuery(`^INSERT INTO "refunds"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(refund.ID))
+		mock.ExpectExec(`^INSERT INTO "refund_details"`).WillReturnResult(sqlmock.NewResult(1, 2))
+		gomock.InOrder(
+			kit.mockProductRepo.EXPECT().IncreaseStockByID(ctx, gomock.Any(), int64(222), int64(1)).Times(1).Return(nil),
+			kit.mockProductRepo.EXPECT().IncreaseStockByID(ctx, gomock.Any(), int64(333), int64(2)).Times(1).Return(nil),
+		)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Len(2)).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), refund, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductRepo.EXPECT().DeleteCachesByIDs([]int64{222, 333}).Times(1).Return(nil)
+
+		err := repo.Create(ctx, userID, refund)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, refund.RefundDetails, 2)
+		require.Equal(t, int64(8000), refund.RefundDetails[1].Subtotal)
+	})
+
+	t.Run("failed - quantity exceeded", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLockAndCountRefunds(1)
+		expectRemaining(2)
+		mock.ExpectRollback()
+
+		err := repo.Create(ctx, userID, newRefund(model.RefundTypeRefund, 2))
+		require.ErrorIs(t, err, model.ErrRefundQuantityExceeded)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - the shift paying the refund is closed", func(t *testing.T) {
+		refund := newRefund(model.RefundTypeVoid, 3)
+		refund.ShiftID = utils.GenerateID()
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "id" FROM "transactions" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionID))
+		mock.ExpectQuery(`^SELECT "status" FROM "shifts" WHERE id = .+ FOR SHARE`).
+			WithArgs(refund.ShiftID).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.ShiftStatusClosed))
+		mock.ExpectRollback()
+
+		err := repo.Create(ctx, userID, refund)
+		require.ErrorIs(t, err, model.ErrShiftClosed)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - void an already refunded transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLockAndCountRefunds(1)
+		mock.ExpectRollback()
+
+		err := repo.Create(ctx, userID, newRefund(model.RefundTypeVoid, 3))
+		require.ErrorIs(t, err, model.ErrTransactionAlreadyRefunded)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRefundRepository_Create_Variant(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &refundRepository{
+		db:                 kit.db,
+		cache:              kit.cache,
+		productRepo:        kit.mockProductRepo,
+		productVariantRepo: kit.mockProductVariantRepo,
+		stockMovementRepo:  kit.mockStockMovementRepo,
+		auditRepo:          kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	transactionID := utils.GenerateID()
+	newRefund := func(variantID, quantity int64) *model.Refund {
+		refundID := utils.GenerateID()
+		return &model.Refund{
+			ID:            refundID,
+			TransactionID: transactionID,
+			Type:          model.RefundTypeRefund,
+			TotalAmount:   8000 * quantity,
+			Reason:        "salah ukuran",
+			ApprovedBy:    userID,
+			CreatedAt:     time.Now(),
+			RefundDetails: []*model.RefundDetail{
+				{RefundID: refundID, TransactionID: transactionID, ProductID: 222, VariantID: variantID, Quantity: quantity, Subtotal: 8000 * quantity},
+			},
+		}
+	}
+
+	// 2 of the small variant and 3 of the large variant are sold, 1 of the small variant is refunded
+	expectRemaining := func() {
+		mock.ExpectQuery(`^SELECT "id" FROM "transactions" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionID))
+		mock.ExpectQuery(`^SELECT count(.*) FROM "refunds" WHERE transaction_id = .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`^SELECT .+ FROM "transaction_details" WHERE transaction_id = .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "product_id", "variant_id", "quantity", "subtotal"}).
+				AddRow(transactionID, 222, 301, 2, 12000).
+				AddRow(transactionID, 222, 302, 3, 24000))
+		mock.ExpectQuery(`^SELECT product_id, variant_id, SUM\(quantity\) AS quantity FROM "refund_details"`).
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "variant_id", "quantity"}).AddRow(222, 301, 1))
+	}
+
+	t.Run("ok - restock the variant and its product", func(t *testing.T) {
+		refund := newRefund(302, 3)
+		mock.ExpectBegin()
+		expectRemaining()
+		mock.ExpectQuery(`^INSERT INTO "refunds"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(refund.ID))
+		mock.ExpectExec(`^INSERT INTO "refund_details"`).WillReturnResult(sqlmock.NewResult(1, 1))
+		kit.mockProductRepo.EXPECT().IncreaseStockByID(ctx, gomock.Any(), int64(222), int64(3)).Times(1).Return(nil)
+		kit.mockProductVariantRepo.EXPECT().IncreaseStockByID(ctx, gomock.Any(), int64(302), int64(3)).Times(1).Return(nil)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), refund.StockMovements(userID)).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), refund, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductRepo.EXPECT().DeleteCachesByIDs([]int64{222}).Times(1).Return(nil)
+		kit.mockProductVariantRepo.EXPECT().DeleteCachesByIDs([]int64{302}).Times(1).Return(nil)
+
+		err := repo.Create(ctx, userID, refund)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - the remaining of the variant is exceeded", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectRemaining()
+		mock.ExpectRollback()
+
+		err := repo.Create(ctx, userID, newRefund(301, 2))
+		require.ErrorIs(t, err, model.ErrRefundQuantityExceeded)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
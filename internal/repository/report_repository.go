@@ -0,0 +1,438 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+// reportTimeZone the time zone of the hourly sales, the timestamps are stored in UTC
+const reportTimeZone = "Asia/Jakarta"
+
+type reportRepository struct {
+	db        *gorm.DB
+	cache     cacher.CacheManager
+	auditRepo model.AuditRepository
+}
+
+// NewReportRepository instantiate a new report repository
+func NewReportRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	auditRepo model.AuditRepository,
+) model.ReportRepository {
+	return &reportRepository{
+		db:        db,
+		cache:     cache,
+		auditRepo: auditRepo,
+	}
+}
+
+// Aggregate the sales of the period from startAt inclusive until endAt exclusive
+func (r *reportRepository) Aggregate(ctx context.Context, startAt, endAt time.Time) (*model.SalesReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":     utils.DumpIncomingContext(ctx),
+		"startAt": startAt,
+		"endAt":   endAt,
+	})
+
+	report, err := r.aggregate(r.db.WithContext(ctx), startAt, endAt)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// FindZReportByID find Z report by id, a Z report never changes so it is cached without expiry concern
+func (r *reportRepository) FindZReportByID(ctx context.Context, id int64) (*model.SalesReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"zReportID": id,
+	})
+
+	cacheKey := r.newZReportCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.SalesReport](r.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	report := &model.SalesReport{}
+	err := r.db.WithContext(ctx).Take(report, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		storeNil(r.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	report.Type = model.ReportTypeZ
+	if err := r.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(report))); err != nil {
+		logger.Error(err)
+	}
+
+	return report, nil
+}
+
+// FindLastZReport find the latest Z report, return nil when there is none
+func (r *reportRepository) FindLastZReport(ctx context.Context) (*model.SalesReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+	})
+
+	var id int64
+	err := r.db.WithContext(ctx).
+		Model(model.SalesReport{}).
+		Select("id").
+		Order("number DESC").
+		Take(&id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	return r.FindZReportByID(ctx, id)
+}
+
+// SearchZReportsByPage find all Z report ids ordered by the newest number
+func (r *reportRepository) SearchZReportsByPage(ctx context.Context, criteria model.ZReportSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = r.db.WithContext(ctx).
+		Model(model.SalesReport{}).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(model.SalesReport{}).
+		Scopes(scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("number DESC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
+// CreateZReport aggregate the sales since the last Z report until now and store them as the next numbered Z report.
+// The sales tables are share locked so the in flight sales & refunds are committed before the period is closed
+// and no sale can slip in between the aggregate and the store.
+func (r *reportRepository) CreateZReport(ctx context.Context, userID int64, report *model.SalesReport) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"userID":    userID,
+		"zReportID": report.ID,
+	})
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`LOCK TABLE "z_reports" IN EXCLUSIVE MODE`).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := tx.Exec(`LOCK TABLE "transactions", "refunds" IN SHARE MODE`).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		var last struct {
+			Number    int64
+			PeriodEnd time.Time
+		}
+		err := tx.Model(model.SalesReport{}).
+			Select("number", "period_end").
+			Order("number DESC").
+			Take(&last).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			logger.Error(err)
+			return err
+		}
+
+		aggregate, err := r.aggregate(tx, last.PeriodEnd, time.Now())
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		aggregate.ID = report.ID
+		aggregate.Type = model.ReportTypeZ
+		aggregate.Number = last.Number + 1
+		aggregate.CreatedBy = userID
+		aggregate.CreatedAt = aggregate.PeriodEnd
+		*report = *aggregate
+
+		if err := tx.Create(report).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := r.auditRepo.Audit(ctx, tx, report, &model.Audit{
+			UserID:        userID,
+			AuditableType: r.name(),
+			AuditableID:   report.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// profitLinesQuery the sold lines of the period with their quantity, revenue & cost after the refunds.
+// The revenue of a line is its share of the net sales of the transaction, which is the total price
+// without the tax & service charge, so the cart & manual discounts are prorated to the lines.
+// The lines without a known unit cost, i.e. sold without a cost price or before the cost was snapshotted, are left out.
+const profitLinesQuery = `WITH "sold_lines" AS (
+	SELECT td.transaction_id, td.product_id, td.variant_id, td.product_name, td.quantity, td.subtotal, td.unit_cost,
+		t.created_by, t.created_at,
+		t.total_price - t.tax_amount - t.service_charge AS net_sales,
+		SUM(td.subtotal) OVER (PARTITION BY td.transaction_id) AS lines_total
+	FROM "transaction_details" td
+	JOIN "transactions" t ON t.id = td.transaction_id
+	WHERE t.created_at >= ? AND t.created_at < ? AND td.unit_cost IS NOT NULL
+), "refunded_lines" AS (
+	SELECT transaction_id, product_id, variant_id, SUM(quantity) AS quantity
+	FROM "refund_details"
+	WHERE transaction_id IN (SELECT transaction_id FROM "sold_lines")
+	GROUP BY transaction_id, product_id, variant_id
+), "profit_lines" AS (
+	SELECT sl.product_id, sl.product_name, sl.created_by, sl.created_at,
+		sl.quantity - COALESCE(rl.quantity, 0) AS quantity,
+		CASE WHEN sl.lines_total > 0
+			THEN sl.subtotal * sl.net_sales / sl.lines_total * (sl.quantity - COALESCE(rl.quantity, 0)) / sl.quantity
+			ELSE 0 END AS revenue,
+		sl.unit_cost * (sl.quantity - COALESCE(rl.quantity, 0)) AS cost
+	FROM "sold_lines" sl
+	LEFT JOIN "refunded_lines" rl
+		ON rl.transaction_id = sl.transaction_id AND rl.product_id = sl.product_id AND rl.variant_id = sl.variant_id
+)`
+
+// profitGroupQueries the select of the profit lines per group ordered by the highest gross profit,
+// except the days which are ordered by date and take the time zone as their last argument
+var profitGroupQueries = map[model.ProfitReportGroup]string{
+	model.ProfitReportGroupProduct: `SELECT pl.product_id::TEXT AS key, MAX(pl.product_name) AS name,
+		SUM(pl.quantity) AS quantity, ROUND(SUM(pl.revenue))::BIGINT AS revenue, SUM(pl.cost)::BIGINT AS cost
+		FROM "profit_lines" pl
+		GROUP BY pl.product_id ORDER BY ROUND(SUM(pl.revenue)) - SUM(pl.cost) DESC, pl.product_id`,
+	model.ProfitReportGroupCategory: `SELECT COALESCE(p.category_id, 0)::TEXT AS key, COALESCE(MAX(c.name), '') AS name,
+		SUM(pl.quantity) AS quantity, ROUND(SUM(pl.revenue))::BIGINT AS revenue, SUM(pl.cost)::BIGINT AS cost
+		FROM "profit_lines" pl
+		LEFT JOIN "products" p ON p.id = pl.product_id
+		LEFT JOIN "categories" c ON c.id = p.category_id
+		GROUP BY COALESCE(p.category_id, 0) ORDER BY ROUND(SUM(pl.revenue)) - SUM(pl.cost) DESC, COALESCE(p.category_id, 0)`,
+	model.ProfitReportGroupCashier: `SELECT pl.created_by::TEXT AS key, COALESCE(MAX(u.name), '') AS name,
+		SUM(pl.quantity) AS quantity, ROUND(SUM(pl.revenue))::BIGINT AS revenue, SUM(pl.cost)::BIGINT AS cost
+		FROM "profit_lines" pl
+		LEFT JOIN "users" u ON u.id = pl.created_by
+		GROUP BY pl.created_by ORDER BY ROUND(SUM(pl.revenue)) - SUM(pl.cost) DESC, pl.created_by`,
+	model.ProfitReportGroupDay: `SELECT d.day AS key, d.day AS name,
+		SUM(d.quantity) AS quantity, ROUND(SUM(d.revenue))::BIGINT AS revenue, SUM(d.cost)::BIGINT AS cost
+		FROM (
+			SELECT TO_CHAR(created_at AT TIME ZONE 'UTC' AT TIME ZONE ?, 'YYYY-MM-DD') AS day, quantity, revenue, cost
+			FROM "profit_lines"
+		) d
+		GROUP BY d.day ORDER BY d.day`,
+}
+
+// AggregateProfit the gross profit of the sales made from startAt inclusive until endAt exclusive,
+// the refunds are deducted from the sales they refund regardless of when they are made
+func (r *reportRepository) AggregateProfit(ctx context.Context, groupBy model.ProfitReportGroup, startAt, endAt time.Time) (*model.ProfitReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":     utils.DumpIncomingContext(ctx),
+		"groupBy": groupBy,
+		"startAt": startAt,
+		"endAt":   endAt,
+	})
+
+	groupQuery, ok := profitGroupQueries[groupBy]
+	if !ok {
+		return nil, model.ErrUnknownProfitReportGroup
+	}
+
+	report := &model.ProfitReport{
+		GroupBy:     groupBy,
+		PeriodStart: startAt,
+		PeriodEnd:   endAt,
+	}
+	args := []any{startAt, endAt}
+	if groupBy == model.ProfitReportGroupDay {
+		args = append(args, reportTimeZone)
+	}
+
+	err := r.db.WithContext(ctx).
+		Raw(profitLinesQuery+" "+groupQuery, args...).
+		Scan(&report.Lines).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	report.Summarize()
+	return report, nil
+}
+
+// aggregate the sales of the period using the given db, so it can run within a db transaction
+func (r *reportRepository) aggregate(db *gorm.DB, startAt, endAt time.Time) (*model.SalesReport, error) {
+	report := &model.SalesReport{
+		PeriodStart: startAt,
+		PeriodEnd:   endAt,
+		Summary:     &model.SalesSummary{},
+	}
+
+	err := db.Raw(`SELECT
+		COUNT(*) AS transaction_count,
+		COALESCE(SUM(discount), 0) AS discount,
+		COALESCE(SUM(manual_discount), 0) AS manual_discount,
+		COALESCE(SUM(tax_amount), 0) AS tax_amount,
+		COALESCE(SUM(service_charge), 0) AS service_charge,
+		COALESCE(SUM(total_price), 0) AS total_sales,
+		COALESCE(SUM(change), 0) AS change
+		FROM "transactions" WHERE created_at >= ? AND created_at < ?`, startAt, endAt).
+		Scan(report.Summary).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var refunds []struct {
+		Type        model.RefundType
+		Count       int64
+		TotalAmount int64
+	}
+	err = db.Raw(`SELECT type, COUNT(*) AS count, COALESCE(SUM(total_amount), 0) AS total_amount
+		FROM "refunds" WHERE created_at >= ? AND created_at < ? GROUP BY type`, startAt, endAt).
+		Scan(&refunds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, refund := range refunds {
+		switch refund.Type {
+		case model.RefundTypeVoid:
+			report.Summary.VoidCount = refund.Count
+			report.Summary.VoidAmount = refund.TotalAmount
+		default:
+			report.Summary.RefundCount += refund.Count
+			report.Summary.RefundAmount += refund.TotalAmount
+		}
+	}
+
+	err = db.Raw(`SELECT
+		EXTRACT(HOUR FROM created_at AT TIME ZONE 'UTC' AT TIME ZONE ?)::INT AS hour,
+		COUNT(*) AS transaction_count,
+		COALESCE(SUM(total_price), 0) AS total_sales
+		FROM "transactions" WHERE created_at >= ? AND created_at < ?
+		GROUP BY hour ORDER BY hour`, reportTimeZone, startAt, endAt).
+		Scan(&report.Hourly).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Raw(`SELECT
+		td.product_id,
+		MAX(td.product_name) AS product_name,
+		COALESCE(SUM(td.quantity), 0) AS quantity,
+		COALESCE(SUM(td.unit_price * td.quantity), 0) AS gross_sales,
+		COALESCE(SUM(td.discount), 0) AS discount,
+		COALESCE(SUM(td.subtotal), 0) AS subtotal
+		FROM "transaction_details" td
+		JOIN "transactions" t ON t.id = td.transaction_id
+		WHERE t.created_at >= ? AND t.created_at < ?
+		GROUP BY td.product_id ORDER BY subtotal DESC, td.product_id`, startAt, endAt).
+		Scan(&report.Products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range report.Products {
+		report.Summary.GrossSales += product.GrossSales
+	}
+
+	err = db.Raw(`SELECT
+		t.created_by AS cashier_id,
+		COALESCE(MAX(u.name), '') AS cashier_name,
+		COUNT(*) AS transaction_count,
+		COALESCE(SUM(t.discount), 0) AS discount,
+		COALESCE(SUM(t.total_price), 0) AS total_sales
+		FROM "transactions" t
+		LEFT JOIN "users" u ON u.id = t.created_by
+		WHERE t.created_at >= ? AND t.created_at < ?
+		GROUP BY t.created_by ORDER BY total_sales DESC, t.created_by`, startAt, endAt).
+		Scan(&report.Cashiers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Raw(`SELECT
+		tp.method,
+		COUNT(DISTINCT tp.transaction_id) AS transaction_count,
+		COALESCE(SUM(tp.amount), 0) AS amount
+		FROM "transaction_payments" tp
+		JOIN "transactions" t ON t.id = tp.transaction_id
+		WHERE t.created_at >= ? AND t.created_at < ?
+		GROUP BY tp.method ORDER BY tp.method`, startAt, endAt).
+		Scan(&report.PaymentMethods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// the change is only given from the cash part of the payments
+	for _, method := range report.PaymentMethods {
+		if method.Method == model.PaymentMethodCash {
+			method.Amount -= report.Summary.Change
+		}
+	}
+
+	return report, nil
+}
+
+func (r *reportRepository) newZReportCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:z_report:id:%d", id)
+}
+
+func (r *reportRepository) name() string {
+	return "z_report"
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// expectAggregateQueries expect the aggregate queries of a period with one product sold twice in cash
+func expectAggregateQueries(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`^SELECT (.+) FROM "transactions" WHERE created_at >= .+ AND created_at < .+`).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_count", "discount", "manual_discount", "tax_amount", "service_charge", "total_sales", "change"}).
+			AddRow(2, 2000, 1000, 1800, 0, 18000, 2000))
+	mock.ExpectQuery(`^SELECT type, COUNT\(\*\) AS count, (.+) FROM "refunds" .+ GROUP BY type`).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "count", "total_amount"}).
+			AddRow(model.RefundTypeVoid, 1, 9000).
+			AddRow(model.RefundTypeRefund, 1, 3000))
+	mock.ExpectQuery(`EXTRACT\(HOUR FROM created_at AT TIME ZONE 'UTC' AT TIME ZONE .+\)::INT AS hour`).
+		WithArgs(reportTimeZone, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"hour", "transaction_count", "total_sales"}).
+			AddRow(9, 1, 9000).
+			AddRow(13, 1, 9000))
+	mock.ExpectQuery(`FROM "transaction_details" td JOIN "transactions" t .+ GROUP BY td.product_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "product_name", "quantity", "gross_sales", "discount", "subtotal"}).
+			AddRow(555, "Indomie Goreng", 6, 18000, 2000, 16000))
+	mock.ExpectQuery(`FROM "transactions" t LEFT JOIN "users" u .+ GROUP BY t.created_by`).
+		WillReturnRows(sqlmock.NewRows([]string{"cashier_id", "cashier_name", "transaction_count", "discount", "total_sales"}).
+			AddRow(111, "Budi", 2, 2000, 18000))
+	mock.ExpectQuery(`FROM "transaction_payments" tp JOIN "transactions" t .+ GROUP BY tp.method`).
+		WillReturnRows(sqlmock.NewRows([]string{"method", "transaction_count", "amount"}).
+			AddRow(model.PaymentMethodCash, 2, 20000))
+}
+
+func TestReportRepository_Aggregate(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &reportRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	startAt := time.Date(2023, 9, 25, 0, 0, 0, 0, time.UTC)
+	endAt := startAt.Add(24 * time.Hour)
+
+	t.Run("ok", func(t *testing.T) {
+		expectAggregateQueries(mock)
+
+		report, err := repo.Aggregate(ctx, startAt, endAt)
+		require.NoError(t, err)
+		require.Equal(t, startAt, report.PeriodStart)
+		require.Equal(t, endAt, report.PeriodEnd)
+
+		require.Equal(t, int64(2), report.Summary.TransactionCount)
+		require.Equal(t, int64(18000), report.Summary.GrossSales)
+		require.Equal(t, int64(18000), report.Summary.TotalSales)
+		require.Equal(t, int64(1), report.Summary.VoidCount)
+		require.Equal(t, int64(9000), report.Summary.VoidAmount)
+		require.Equal(t, int64(1), report.Summary.RefundCount)
+		require.Equal(t, int64(3000), report.Summary.RefundAmount)
+		require.Equal(t, int64(6000), report.Summary.NetSales())
+
+		require.Len(t, report.Hourly, 2)
+		require.Equal(t, 13, report.Hourly[1].Hour)
+		require.Equal(t, "Indomie Goreng", report.Products[0].ProductName)
+		require.Equal(t, "Budi", report.Cashiers[0].CashierName)
+		// the change is deducted from the cash tendered
+		require.Equal(t, int64(18000), report.PaymentMethods[0].Amount)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReportRepository_CreateZReport(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &reportRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	lastPeriodEnd := time.Date(2023, 9, 24, 22, 0, 0, 0, time.UTC)
+
+	t.Run("ok - next number continues from the last Z report", func(t *testing.T) {
+		report := &model.SalesReport{ID: utils.GenerateID()}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`^LOCK TABLE "z_reports" IN EXCLUSIVE MODE`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^LOCK TABLE "transactions", "refunds" IN SHARE MODE`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`^SELECT "number","period_end" FROM "z_reports" ORDER BY number DESC`).
+			WillReturnRows(sqlmock.NewRows([]string{"number", "period_end"}).AddRow(11, lastPeriodEnd))
+		expectAggregateQueries(mock)
+		mock.ExpectQuery(`^INSERT INTO "z_reports"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(report.ID))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.CreateZReport(ctx, userID, report)
+		require.NoError(t, err)
+		require.Equal(t, model.ReportTypeZ, report.Type)
+		require.Equal(t, int64(12), report.Number)
+		require.Equal(t, lastPeriodEnd, report.PeriodStart)
+		require.Equal(t, userID, report.CreatedBy)
+		require.Equal(t, int64(2), report.Summary.TransactionCount)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ok - the first Z report", func(t *testing.T) {
+		report := &model.SalesReport{ID: utils.GenerateID()}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`^LOCK TABLE "z_reports"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^LOCK TABLE "transactions", "refunds"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`^SELECT "number","period_end" FROM "z_reports"`).
+			WillReturnRows(sqlmock.NewRows([]string{"number", "period_end"}))
+		expectAggregateQueries(mock)
+		mock.ExpectQuery(`^INSERT INTO "z_reports"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(report.ID))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.CreateZReport(ctx, userID, report)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), report.Number)
+		require.True(t, report.PeriodStart.IsZero())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReportRepository_AggregateProfit(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &reportRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	startAt := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	endAt := startAt.AddDate(0, 1, 0)
+	columns := []string{"key", "name", "quantity", "revenue", "cost"}
+
+	t.Run("ok - per product", func(t *testing.T) {
+		// the lines of unknown cost are left out
+		mock.ExpectQuery(`^WITH "sold_lines" AS \(.+ AND td.unit_cost IS NOT NULL\s*\), "refunded_lines" AS \(.+\), "profit_lines" AS \(.+\) SELECT pl.product_id::TEXT AS key, .+ FROM "profit_lines" pl GROUP BY pl.product_id`).
+			WithArgs(startAt, endAt).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("555", "Indomie Goreng", 6, 16000, 12000).
+				AddRow("666", "Es Teh", 2, 6000, 2000))
+
+		report, err := repo.AggregateProfit(ctx, model.ProfitReportGroupProduct, startAt, endAt)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, model.ProfitReportGroupProduct, report.GroupBy)
+		require.Len(t, report.Lines, 2)
+		require.Equal(t, model.ProfitLine{Quantity: 8, Revenue: 22000, Cost: 14000}, report.Summary)
+		require.Equal(t, int64(8000), report.Summary.GrossProfit())
+	})
+
+	t.Run("ok - per day on western indonesian time", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT d.day AS key, .+ TO_CHAR\(created_at AT TIME ZONE 'UTC' AT TIME ZONE .+, 'YYYY-MM-DD'\) AS day, .+ GROUP BY d.day ORDER BY d.day`).
+			WithArgs(startAt, endAt, reportTimeZone).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("2023-09-01", "2023-09-01", 6, 16000, 12000))
+
+		report, err := repo.AggregateProfit(ctx, model.ProfitReportGroupDay, startAt, endAt)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, "2023-09-01", report.Lines[0].Key)
+	})
+
+	t.Run("failed - unknown group", func(t *testing.T) {
+		report, err := repo.AggregateProfit(ctx, "SUPPLIER", startAt, endAt)
+		require.ErrorIs(t, err, model.ErrUnknownProfitReportGroup)
+		require.Nil(t, report)
+	})
+}
@@ -72,6 +72,11 @@ func (s *sessionRepo) DeleteByUserIDAndMaxRemainderSession(ctx context.Context,
 	return nil
 }
 
+// DeleteByUserID delete every session of the user along with their cached tokens
+func (s *sessionRepo) DeleteByUserID(ctx context.Context, userID int64) error {
+	return s.DeleteByUserIDAndMaxRemainderSession(ctx, userID, 0)
+}
+
 func (s *sessionRepo) Create(ctx context.Context, sess *model.Session) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":    utils.DumpIncomingContext(ctx),
@@ -138,6 +143,7 @@ func (s *sessionRepo) FindByToken(ctx context.Context, tokenType model.TokenType
 	}
 
 	sess.Role = user.Role
+	sess.UserStatus = user.Status
 	if err = s.cacheToken(sess); err != nil {
 		logger.Error(err)
 	}
@@ -185,6 +191,7 @@ func (s *sessionRepo) FindByID(ctx context.Context, id int64) (*model.Session, e
 	}
 
 	sess.Role = user.Role
+	sess.UserStatus = user.Status
 	if err = s.cacheToken(&sess); err != nil {
 		logger.Error(err)
 	}
@@ -0,0 +1,404 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type shiftRepository struct {
+	db        *gorm.DB
+	cache     cacher.CacheManager
+	auditRepo model.AuditRepository
+}
+
+// NewShiftRepository instantiate a new shift repository
+func NewShiftRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	auditRepo model.AuditRepository,
+) model.ShiftRepository {
+	return &shiftRepository{
+		db:        db,
+		cache:     cache,
+		auditRepo: auditRepo,
+	}
+}
+
+// FindByID find shift by id along with its cash movements
+func (s *shiftRepository) FindByID(ctx context.Context, id int64) (*model.Shift, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":     utils.DumpIncomingContext(ctx),
+		"shiftID": id,
+	})
+
+	cacheKey := s.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.Shift](s.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	shift := &model.Shift{}
+	err := s.db.WithContext(ctx).Take(shift, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, s.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).
+		Where("shift_id = ?", shift.ID).
+		Order("created_at ASC").
+		Find(&shift.CashMovements).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(shift))); err != nil {
+		logger.Error(err)
+	}
+
+	return shift, nil
+}
+
+// FindOpenByCashierID find the open shift of the cashier
+func (s *shiftRepository) FindOpenByCashierID(ctx context.Context, cashierID int64) (*model.Shift, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"cashierID": cashierID,
+	})
+
+	var id int64
+	err := s.db.WithContext(ctx).
+		Model(model.Shift{}).
+		Select("id").
+		Where("cashier_id = ? AND status = ?", cashierID, model.ShiftStatusOpen).
+		Take(&id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	return s.FindByID(ctx, id)
+}
+
+// SearchByPage find all shift ids ordered by the newest
+func (s *shiftRepository) SearchByPage(ctx context.Context, criteria model.ShiftSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = s.db.WithContext(ctx).
+		Model(model.Shift{}).
+		Scopes(scopesByShiftSearchCriteria(criteria)...).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = s.db.WithContext(ctx).
+		Model(model.Shift{}).
+		Scopes(scopesByShiftSearchCriteria(criteria)...).
+		Scopes(scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("opened_at DESC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
+// Open store a new open shift, the cashier row is locked so a cashier never has two open shifts
+func (s *shiftRepository) Open(ctx context.Context, userID int64, shift *model.Shift) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+		"shift":  utils.Dump(shift),
+	})
+
+	shift.Status = model.ShiftStatusOpen
+	shift.OpenedAt = time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Take(&model.User{}, "id = ?", shift.CashierID).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		var openCount int64
+		err = tx.Model(model.Shift{}).
+			Where("cashier_id = ? AND status = ?", shift.CashierID, model.ShiftStatusOpen).
+			Count(&openCount).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+		if openCount > 0 {
+			return model.ErrShiftAlreadyOpen
+		}
+
+		if err := tx.Create(shift).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, shift, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   shift.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(shift); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// CreateCashMovement store a cash movement of an open shift
+func (s *shiftRepository) CreateCashMovement(ctx context.Context, userID int64, movement *model.CashMovement) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"userID":   userID,
+		"movement": utils.Dump(movement),
+	})
+
+	movement.CreatedBy = userID
+	movement.CreatedAt = time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.LockOpenByID(ctx, tx, movement.ShiftID); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := tx.Create(movement).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, movement, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   movement.ShiftID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(&model.Shift{ID: movement.ShiftID}); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Close lock the shift, sum its cash tenders & movements and reconcile it with the closing cash.
+// The cash sales are the cash tenders minus the change given of the transactions linked to the shift,
+// minus the cash part of the voids & refunds paid from the shift drawer.
+func (s *shiftRepository) Close(ctx context.Context, userID int64, shift *model.Shift, closingCash int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":         utils.DumpIncomingContext(ctx),
+		"userID":      userID,
+		"shift":       utils.Dump(shift),
+		"closingCash": closingCash,
+	})
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lockOpenByID(tx, shift.ID, "UPDATE"); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		var cashTendered, change int64
+		err := tx.Raw(`SELECT COALESCE(SUM(tp.amount), 0) FROM "transaction_payments" tp
+			JOIN "transactions" t ON t.id = tp.transaction_id
+			WHERE t.shift_id = ? AND tp.method = ?`, shift.ID, model.PaymentMethodCash).
+			Scan(&cashTendered).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		err = tx.Raw(`SELECT COALESCE(SUM(change), 0) FROM "transactions" WHERE shift_id = ?`, shift.ID).
+			Scan(&change).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		var cashRefunds int64
+		err = tx.Raw(`SELECT COALESCE(SUM(cash_amount), 0) FROM "refunds" WHERE shift_id = ?`, shift.ID).
+			Scan(&cashRefunds).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		var movements struct {
+			CashIn  int64
+			CashOut int64
+		}
+		err = tx.Raw(`SELECT
+			COALESCE(SUM(amount) FILTER (WHERE type = ?), 0) AS cash_in,
+			COALESCE(SUM(amount) FILTER (WHERE type = ?), 0) AS cash_out
+			FROM "cash_movements" WHERE shift_id = ?`, model.CashMovementTypeIn, model.CashMovementTypeOut, shift.ID).
+			Scan(&movements).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		shift.Reconcile(cashTendered-change-cashRefunds, movements.CashIn, movements.CashOut, closingCash, time.Now())
+
+		err = tx.Select("status", "cash_sales", "cash_in", "cash_out", "expected_cash", "closing_cash", "variance", "closing_note", "closed_at").
+			Updates(shift).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, shift, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   shift.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(shift); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// LockOpenByID share lock the open shift, so the sales can go on concurrently while the shift can not be closed
+func (s *shiftRepository) LockOpenByID(ctx context.Context, tx *gorm.DB, id int64) error {
+	return s.lockOpenByID(tx.WithContext(ctx), id, "SHARE")
+}
+
+func (s *shiftRepository) lockOpenByID(tx *gorm.DB, id int64, strength string) error {
+	// gorm only scans a single column into the builtin types
+	var status string
+	err := tx.Model(model.Shift{}).
+		Clauses(clause.Locking{Strength: strength}).
+		Select("status").
+		Where("id = ?", id).
+		Take(&status).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		return model.ErrShiftNotOpen
+	default:
+		return err
+	}
+
+	if model.ShiftStatus(status) != model.ShiftStatusOpen {
+		return model.ErrShiftClosed
+	}
+
+	return nil
+}
+
+func (s *shiftRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:shift:id:%d", id)
+}
+
+// deleteCaches delete related cache
+func (s *shiftRepository) deleteCaches(shift *model.Shift) error {
+	if shift == nil {
+		return nil
+	}
+
+	return s.cache.DeleteByKeys([]string{s.newCacheKeyByID(shift.ID)})
+}
+
+func (s *shiftRepository) name() string {
+	return "shift"
+}
+
+// scopesByShiftSearchCriteria build the filter scopes of shift search criteria
+func scopesByShiftSearchCriteria(criteria model.ShiftSearchCriteria) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if criteria.CashierID > 0 {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("cashier_id = ?", criteria.CashierID)
+		})
+	}
+
+	if criteria.Status != "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("status = ?", criteria.Status)
+		})
+	}
+
+	return scopes
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestShiftRepository_Open(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &shiftRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	cashierID := int64(111)
+
+	t.Run("ok", func(t *testing.T) {
+		shift := &model.Shift{ID: utils.GenerateID(), CashierID: cashierID, OpeningFloat: 200000}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "id" FROM "users" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(cashierID))
+		mock.ExpectQuery(`^SELECT count\(.+\) FROM "shifts" WHERE cashier_id = .+ AND status = .+`).
+			WithArgs(cashierID, model.ShiftStatusOpen).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`^INSERT INTO "shifts"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(shift.ID))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.Open(ctx, cashierID, shift)
+		require.NoError(t, err)
+		require.Equal(t, model.ShiftStatusOpen, shift.Status)
+		require.False(t, shift.OpenedAt.IsZero())
+	})
+
+	t.Run("failed - already open", func(t *testing.T) {
+		shift := &model.Shift{ID: utils.GenerateID(), CashierID: cashierID}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "id" FROM "users" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(cashierID))
+		mock.ExpectQuery(`^SELECT count\(.+\) FROM "shifts"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		err := repo.Open(ctx, cashierID, shift)
+		require.ErrorIs(t, err, model.ErrShiftAlreadyOpen)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestShiftRepository_Close(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &shiftRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+
+	t.Run("ok", func(t *testing.T) {
+		shift := &model.Shift{ID: utils.GenerateID(), CashierID: userID, Status: model.ShiftStatusOpen, OpeningFloat: 200000}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "shifts" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.ShiftStatusOpen))
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(tp.amount\), 0\) FROM "transaction_payments"`).
+			WithArgs(shift.ID, model.PaymentMethodCash).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1200000))
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(change\), 0\) FROM "transactions"`).
+			WithArgs(shift.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(150000))
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(cash_amount\), 0\) FROM "refunds" WHERE shift_id = .+`).
+			WithArgs(shift.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+		mock.ExpectQuery(`FROM "cash_movements" WHERE shift_id = .+`).
+			WithArgs(model.CashMovementTypeIn, model.CashMovementTypeOut, shift.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"cash_in", "cash_out"}).AddRow(0, 500000))
+		mock.ExpectExec(`^UPDATE "shifts" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.Close(ctx, userID, shift, 745000)
+		require.NoError(t, err)
+		require.Equal(t, model.ShiftStatusClosed, shift.Status)
+		require.Equal(t, int64(1050000), shift.CashSales)
+		require.Equal(t, int64(750000), shift.ExpectedCash)
+		require.Equal(t, int64(-5000), shift.Variance)
+	})
+
+	t.Run("ok - only the cash part of a voided split tender sale is deducted", func(t *testing.T) {
+		shift := &model.Shift{ID: utils.GenerateID(), CashierID: userID, Status: model.ShiftStatusOpen, OpeningFloat: 200000}
+
+		// a sale of 100000 paid with 10000 in cash & 90000 by debit card, voided within the shift
+		sale := model.Transaction{
+			TotalPrice: 100000,
+			TransactionPayments: []*model.TransactionPayment{
+				{Method: model.PaymentMethodCash, Amount: 10000},
+				{Method: model.PaymentMethodDebitCard, Amount: 90000},
+			},
+		}
+		cashRefund := sale.RefundCashAmount(sale.TotalPrice)
+		require.Equal(t, int64(10000), cashRefund)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "shifts" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.ShiftStatusOpen))
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(tp.amount\), 0\) FROM "transaction_payments"`).
+			WithArgs(shift.ID, model.PaymentMethodCash).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(10000))
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(change\), 0\) FROM "transactions"`).
+			WithArgs(shift.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(cash_amount\), 0\) FROM "refunds" WHERE shift_id = .+`).
+			WithArgs(shift.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(cashRefund))
+		mock.ExpectQuery(`FROM "cash_movements" WHERE shift_id = .+`).
+			WithArgs(model.CashMovementTypeIn, model.CashMovementTypeOut, shift.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"cash_in", "cash_out"}).AddRow(0, 0))
+		mock.ExpectExec(`^UPDATE "shifts" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.Close(ctx, userID, shift, 200000)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, int64(0), shift.CashSales)
+		require.Equal(t, int64(200000), shift.ExpectedCash)
+		require.Equal(t, int64(0), shift.Variance)
+	})
+
+	t.Run("failed - already closed", func(t *testing.T) {
+		shift := &model.Shift{ID: utils.GenerateID(), CashierID: userID, Status: model.ShiftStatusOpen}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "shifts" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.ShiftStatusClosed))
+		mock.ExpectRollback()
+
+		err := repo.Close(ctx, userID, shift, 0)
+		require.ErrorIs(t, err, model.ErrShiftClosed)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
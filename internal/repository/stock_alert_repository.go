@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type stockAlertRepository struct {
+	cache cacher.CacheManager
+}
+
+// NewStockAlertRepository instantiate a new stock alert repository, the alerted products are kept in the cache
+func NewStockAlertRepository(cache cacher.CacheManager) model.StockAlertRepository {
+	return &stockAlertRepository{
+		cache: cache,
+	}
+}
+
+// IsAlerted check if the product was alerted within the repeat interval
+func (s *stockAlertRepository) IsAlerted(ctx context.Context, productID int64) (bool, error) {
+	alerted, err := s.cache.CheckKeyExist(s.newCacheKeyByProductID(productID))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":       utils.DumpIncomingContext(ctx),
+			"productID": productID,
+		}).Error(err)
+		return false, err
+	}
+
+	return alerted, nil
+}
+
+// MarkAlerted mark the product as alerted until the repeat interval passes
+func (s *stockAlertRepository) MarkAlerted(ctx context.Context, productID int64) error {
+	item := cacher.NewItemWithCustomTTL(s.newCacheKeyByProductID(productID), time.Now().Unix(), config.StockAlertRepeatInterval())
+	if err := s.cache.StoreWithoutBlocking(item); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":       utils.DumpIncomingContext(ctx),
+			"productID": productID,
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *stockAlertRepository) newCacheKeyByProductID(productID int64) string {
+	return fmt.Sprintf("cache:stock_alert:low_stock:product:%d", productID)
+}
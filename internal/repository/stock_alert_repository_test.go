@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockAlertRepository_MarkAlerted(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+
+	ctx := context.TODO()
+	repo := &stockAlertRepository{
+		cache: kit.cache,
+	}
+	productID := int64(222)
+
+	t.Run("ok", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+
+		alerted, err := repo.IsAlerted(ctx, productID)
+		require.NoError(t, err)
+		require.False(t, alerted)
+
+		require.NoError(t, repo.MarkAlerted(ctx, productID))
+		require.True(t, kit.miniredis.Exists(repo.newCacheKeyByProductID(productID)))
+		require.True(t, kit.miniredis.TTL(repo.newCacheKeyByProductID(productID)) > 0)
+
+		alerted, err = repo.IsAlerted(ctx, productID)
+		require.NoError(t, err)
+		require.True(t, alerted)
+	})
+}
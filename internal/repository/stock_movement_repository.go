@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+// NewStockMovementRepository create new repository, the ledger is read straight from the db
+// since every sale appends to it
+func NewStockMovementRepository(db *gorm.DB) model.StockMovementRepository {
+	return &stockMovementRepository{
+		db: db,
+	}
+}
+
+// Create append the movements to the ledger within the given db transaction
+func (s *stockMovementRepository) Create(ctx context.Context, tx *gorm.DB, movements []*model.StockMovement) error {
+	if len(movements) <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, movement := range movements {
+		if movement.ID <= 0 {
+			movement.ID = utils.GenerateID()
+		}
+		if movement.CreatedAt.IsZero() {
+			movement.CreatedAt = now
+		}
+	}
+
+	if err := tx.WithContext(ctx).Create(movements).Error; err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":       utils.DumpIncomingContext(ctx),
+			"movements": utils.Dump(movements),
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindStockCard find the movements of the period, the opening balance is the sum of the movements before the period.
+// The stock card of a product with variants covers all of its variants unless a variant is given.
+func (s *stockMovementRepository) FindStockCard(ctx context.Context, criteria model.StockCardCriteria) (*model.StockCard, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	startAt, endAt, err := criteria.DateRange()
+	if err != nil {
+		return nil, err
+	}
+
+	var openingBalance int64
+	err = s.db.WithContext(ctx).
+		Model(model.StockMovement{}).
+		Scopes(scopeByStockCardCriteria(criteria)).
+		Where("created_at < ?", startAt).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&openingBalance).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	var movements []*model.StockMovement
+	err = s.db.WithContext(ctx).
+		Scopes(scopeByStockCardCriteria(criteria)).
+		Where("created_at >= ? AND created_at < ?", startAt, endAt).
+		Order("created_at ASC, id ASC").
+		Find(&movements).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return model.NewStockCard(criteria, startAt, endAt, openingBalance, movements), nil
+}
+
+// FindDiscrepancies compare the stock of the products & variants with the sum of their movements,
+// a product is compared with the movements of all of its variants since its stock is the total of the variants
+func (s *stockMovementRepository) FindDiscrepancies(ctx context.Context) ([]*model.StockDiscrepancy, error) {
+	var discrepancies []*model.StockDiscrepancy
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT p.id AS product_id, 0 AS variant_id, p.quantity AS quantity, COALESCE(SUM(m.quantity), 0) AS ledger_quantity
+		FROM "products" p LEFT JOIN "stock_movements" m ON m.product_id = p.id
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.id, p.quantity
+		HAVING p.quantity <> COALESCE(SUM(m.quantity), 0)
+		UNION ALL
+		SELECT v.product_id AS product_id, v.id AS variant_id, v.quantity AS quantity, COALESCE(SUM(m.quantity), 0) AS ledger_quantity
+		FROM "product_variants" v LEFT JOIN "stock_movements" m ON m.variant_id = v.id
+		WHERE v.deleted_at IS NULL
+		GROUP BY v.id, v.product_id, v.quantity
+		HAVING v.quantity <> COALESCE(SUM(m.quantity), 0)
+		ORDER BY product_id, variant_id`).
+		Scan(&discrepancies).Error
+	if err != nil {
+		logrus.WithField("ctx", utils.DumpIncomingContext(ctx)).Error(err)
+		return nil, err
+	}
+
+	return discrepancies, nil
+}
+
+func scopeByStockCardCriteria(criteria model.StockCardCriteria) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("product_id = ?", criteria.ProductID)
+		if criteria.VariantID > 0 {
+			db = db.Where("variant_id = ?", criteria.VariantID)
+		}
+
+		return db
+	}
+}
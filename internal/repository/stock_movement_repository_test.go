@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestStockMovementRepository_Create(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &stockMovementRepository{db: kit.db}
+
+	t.Run("ok - generate the ids", func(t *testing.T) {
+		movements := []*model.StockMovement{
+			{ProductID: 222, Type: model.StockMovementTypeSale, Quantity: -2, ReferenceType: model.StockReferenceTransaction, ReferenceID: 555},
+			{ProductID: 333, VariantID: 301, Type: model.StockMovementTypeSale, Quantity: -1, ReferenceType: model.StockReferenceTransaction, ReferenceID: 555},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^INSERT INTO "stock_movements"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		mock.ExpectCommit()
+
+		err := kit.db.Transaction(func(tx *gorm.DB) error {
+			return repo.Create(ctx, tx, movements)
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		for _, movement := range movements {
+			require.NotZero(t, movement.ID)
+			require.False(t, movement.CreatedAt.IsZero())
+		}
+	})
+
+	t.Run("ok - nothing to create", func(t *testing.T) {
+		require.NoError(t, repo.Create(ctx, kit.db, nil))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStockMovementRepository_FindStockCard(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &stockMovementRepository{db: kit.db}
+	criteria := model.StockCardCriteria{ProductID: 222, StartDate: "2023-09-01", EndDate: "2023-09-30"}
+	columns := []string{"id", "product_id", "variant_id", "type", "quantity", "reference_type", "reference_id", "created_at"}
+
+	t.Run("ok - running balance from the opening balance", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(quantity\), 0\) FROM "stock_movements" WHERE created_at < .+ AND product_id = .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(10))
+		mock.ExpectQuery(`^SELECT \* FROM "stock_movements" WHERE \(created_at >= .+ AND created_at < .+\) AND product_id = .+ ORDER BY created_at ASC, id ASC`).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 222, 0, model.StockMovementTypeSale, -3, model.StockReferenceTransaction, 555, time.Now()).
+				AddRow(2, 222, 0, model.StockMovementTypeRefund, 1, model.StockReferenceRefund, 666, time.Now()).
+				AddRow(3, 222, 0, model.StockMovementTypeWaste, -2, model.StockReferenceManual, 0, time.Now()))
+
+		card, err := repo.FindStockCard(ctx, criteria)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, int64(10), card.OpeningBalance)
+		require.Equal(t, int64(1), card.TotalIn)
+		require.Equal(t, int64(5), card.TotalOut)
+		require.Equal(t, int64(6), card.ClosingBalance)
+		require.Len(t, card.Entries, 3)
+		require.Equal(t, int64(7), card.Entries[0].Balance)
+		require.Equal(t, int64(8), card.Entries[1].Balance)
+		require.Equal(t, int64(6), card.Entries[2].Balance)
+	})
+
+	t.Run("ok - filter by variant", func(t *testing.T) {
+		variantCriteria := criteria
+		variantCriteria.VariantID = 301
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(quantity\), 0\) FROM "stock_movements" WHERE created_at < .+ AND product_id = .+ AND variant_id = .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+		mock.ExpectQuery(`^SELECT \* FROM "stock_movements" WHERE .+ AND product_id = .+ AND variant_id = .+`).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		card, err := repo.FindStockCard(ctx, variantCriteria)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, int64(301), card.VariantID)
+		require.Empty(t, card.Entries)
+	})
+
+	t.Run("failed - invalid date range", func(t *testing.T) {
+		_, err := repo.FindStockCard(ctx, model.StockCardCriteria{ProductID: 222, StartDate: "2023-09-30", EndDate: "2023-09-01"})
+		require.ErrorIs(t, err, model.ErrInvalidDateRange)
+	})
+
+	t.Run("failed - db error", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT COALESCE\(SUM\(quantity\), 0\) FROM "stock_movements"`).
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.FindStockCard(ctx, criteria)
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStockMovementRepository_FindDiscrepancies(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &stockMovementRepository{db: kit.db}
+
+	mock.ExpectQuery(`SELECT p.id AS product_id, 0 AS variant_id, .+ UNION ALL .+ ORDER BY product_id, variant_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "variant_id", "quantity", "ledger_quantity"}).
+			AddRow(222, 0, 10, 8).
+			AddRow(333, 0, 5, 3).
+			AddRow(333, 301, 5, 3))
+
+	discrepancies, err := repo.FindDiscrepancies(ctx)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, discrepancies, 3)
+
+	// the variant discrepancy covers its product discrepancy
+	movements := model.AnyStockDiscrepancies(discrepancies).ReconcilingMovements("stock reconciliation")
+	require.Len(t, movements, 2)
+	require.Equal(t, model.ProductVariantKey{ProductID: 333, VariantID: 301}, movements[0].Key())
+	require.Equal(t, int64(2), movements[0].Quantity)
+	require.Equal(t, model.ProductVariantKey{ProductID: 222}, movements[1].Key())
+	require.Equal(t, int64(2), movements[1].Quantity)
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type stockOpnameRepository struct {
+	db                 *gorm.DB
+	cache              cacher.CacheManager
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+	auditRepo          model.AuditRepository
+}
+
+// NewStockOpnameRepository instantiate a new stock opname repository
+func NewStockOpnameRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	auditRepo model.AuditRepository,
+) model.StockOpnameRepository {
+	return &stockOpnameRepository{
+		db:                 db,
+		cache:              cache,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+		auditRepo:          auditRepo,
+	}
+}
+
+// FindByID find stock opname by id along with its counts
+func (s *stockOpnameRepository) FindByID(ctx context.Context, id int64) (*model.StockOpname, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"stockOpnameID": id,
+	})
+
+	cacheKey := s.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.StockOpname](s.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	opname := &model.StockOpname{}
+	err := s.db.WithContext(ctx).Take(opname, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, s.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	opname.Counts, err = s.findCountsByID(s.db.WithContext(ctx), opname.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(opname))); err != nil {
+		logger.Error(err)
+	}
+
+	return opname, nil
+}
+
+// SearchByPage find all stock opname ids ordered by the newest
+func (s *stockOpnameRepository) SearchByPage(ctx context.Context, criteria model.StockOpnameSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = s.db.WithContext(ctx).
+		Model(model.StockOpname{}).
+		Scopes(scopesByStockOpnameSearchCriteria(criteria)...).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = s.db.WithContext(ctx).
+		Model(model.StockOpname{}).
+		Scopes(scopesByStockOpnameSearchCriteria(criteria)...).
+		Scopes(scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("created_at DESC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
+// Create store a new open stock opname
+func (s *stockOpnameRepository) Create(ctx context.Context, userID int64, opname *model.StockOpname) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+		"opname": utils.Dump(opname),
+	})
+
+	opname.Status = model.StockOpnameStatusOpen
+	opname.StartedBy = userID
+	opname.CreatedAt = time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(opname).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, opname, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   opname.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(opname); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// SaveCounts store the counts of the user along with the current stock of the counted products,
+// the stock opname is share locked so the counts of many users are saved concurrently while it can not be approved
+func (s *stockOpnameRepository) SaveCounts(ctx context.Context, userID, opnameID int64, counts []*model.StockOpnameCount) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"userID":   userID,
+		"opnameID": opnameID,
+		"counts":   utils.Dump(counts),
+	})
+
+	now := time.Now()
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lockOpenByID(tx, opnameID, "SHARE"); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		for _, count := range counts {
+			count.StockOpnameID = opnameID
+			count.CountedBy = userID
+			count.CountedAt = now
+
+			systemQuantity, err := s.findSystemQuantity(tx, count.Key())
+			if err != nil {
+				logger.Error(err)
+				return err
+			}
+			count.SystemQuantity = systemQuantity
+		}
+
+		// a recount of the same user replaces the previous count
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "stock_opname_id"}, {Name: "product_id"}, {Name: "variant_id"}, {Name: "counted_by"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity", "system_quantity", "counted_at"}),
+		}).Create(counts).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, counts, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   opnameID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(&model.StockOpname{ID: opnameID}); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Approve lock the stock opname and post the variances of its counts as adjustment movements,
+// the stock is changed by the variance so the sales made after the count are kept
+func (s *stockOpnameRepository) Approve(ctx context.Context, userID int64, opname *model.StockOpname) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+		"opname": utils.Dump(opname),
+	})
+
+	var movements []*model.StockMovement
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err := s.lockOpenByID(tx, opname.ID, "UPDATE"); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		opname.Counts, err = s.findCountsByID(tx, opname.ID)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+		if len(opname.Counts) <= 0 {
+			return model.ErrStockOpnameEmpty
+		}
+
+		movements = opname.StockMovements(userID)
+		if len(movements) > 0 {
+			if err := s.productRepo.ApplyStockMovements(ctx, tx, movements); err != nil {
+				logger.Error(err)
+				return err
+			}
+		}
+
+		approvedAt := time.Now()
+		opname.Status = model.StockOpnameStatusApproved
+		opname.ApprovedBy = userID
+		opname.ApprovedAt = &approvedAt
+		if err := tx.Select("status", "approved_by", "approved_at").Updates(opname).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, opname, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   opname.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(opname); err != nil {
+		logger.Error(err)
+	}
+
+	if err := s.productRepo.DeleteCachesByIDs(model.AnyStockMovements(movements).ProductIDs()); err != nil {
+		logger.Error(err)
+	}
+
+	if err := s.productVariantRepo.DeleteCachesByIDs(model.AnyStockMovements(movements).VariantIDs()); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+func (s *stockOpnameRepository) findCountsByID(db *gorm.DB, id int64) (counts []*model.StockOpnameCount, err error) {
+	err = db.Where("stock_opname_id = ?", id).
+		Order("counted_at ASC").
+		Find(&counts).Error
+	return counts, err
+}
+
+// findSystemQuantity find the current stock of the product, or of the variant when it is given
+func (s *stockOpnameRepository) findSystemQuantity(tx *gorm.DB, key model.ProductVariantKey) (int64, error) {
+	// gorm only scans a single column into the builtin types
+	var quantity int64
+	query := tx.Model(model.Product{}).Select("quantity").Where("id = ?", key.ProductID)
+	if key.VariantID > 0 {
+		query = tx.Model(model.ProductVariant{}).Select("quantity").Where("id = ? AND product_id = ?", key.VariantID, key.ProductID)
+	}
+
+	err := query.Take(&quantity).Error
+	switch err {
+	case nil:
+		return quantity, nil
+	case gorm.ErrRecordNotFound:
+		return 0, model.ErrUnknownProductVariant
+	default:
+		return 0, err
+	}
+}
+
+func (s *stockOpnameRepository) lockOpenByID(tx *gorm.DB, id int64, strength string) error {
+	// gorm only scans a single column into the builtin types
+	var status string
+	err := tx.Model(model.StockOpname{}).
+		Clauses(clause.Locking{Strength: strength}).
+		Select("status").
+		Where("id = ?", id).
+		Take(&status).Error
+	if err != nil {
+		return err
+	}
+
+	if model.StockOpnameStatus(status) != model.StockOpnameStatusOpen {
+		return model.ErrStockOpnameNotOpen
+	}
+
+	return nil
+}
+
+func (s *stockOpnameRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:stock_opname:id:%d", id)
+}
+
+// deleteCaches delete related cache
+func (s *stockOpnameRepository) deleteCaches(opname *model.StockOpname) error {
+	if opname == nil {
+		return nil
+	}
+
+	return s.cache.DeleteByKeys([]string{s.newCacheKeyByID(opname.ID)})
+}
+
+func (s *stockOpnameRepository) name() string {
+	return "stock_opname"
+}
+
+// scopesByStockOpnameSearchCriteria build the filter scopes of stock opname search criteria
+func scopesByStockOpnameSearchCriteria(criteria model.StockOpnameSearchCriteria) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if criteria.Status != "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("status = ?", criteria.Status)
+		})
+	}
+
+	return scopes
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStockOpnameRepository_SaveCounts(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &stockOpnameRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	opnameID := utils.GenerateID()
+
+	t.Run("ok - snapshot the current stock of every count", func(t *testing.T) {
+		counts := []*model.StockOpnameCount{
+			{ProductID: 222, Quantity: 5},
+			{ProductID: 333, VariantID: 301, Quantity: 2},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "stock_opnames" WHERE id = .+ FOR SHARE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockOpnameStatusOpen))
+		mock.ExpectQuery(`^SELECT "quantity" FROM "products" WHERE id = .+`).
+			WithArgs(int64(222)).
+			WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(8))
+		mock.ExpectQuery(`^SELECT "quantity" FROM "product_variants" WHERE \(id = .+ AND product_id = .+\)`).
+			WithArgs(int64(301), int64(333)).
+			WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(4))
+		mock.ExpectExec(`^INSERT INTO "stock_opname_counts" .+ ON CONFLICT \("stock_opname_id","product_id","variant_id","counted_by"\) DO UPDATE SET`).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), counts, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+
+		err := repo.SaveCounts(ctx, userID, opnameID, counts)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, int64(8), counts[0].SystemQuantity)
+		require.Equal(t, int64(4), counts[1].SystemQuantity)
+		require.Equal(t, userID, counts[1].CountedBy)
+		require.Equal(t, opnameID, counts[1].StockOpnameID)
+	})
+
+	t.Run("failed - already approved", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "stock_opnames" WHERE id = .+ FOR SHARE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockOpnameStatusApproved))
+		mock.ExpectRollback()
+
+		err := repo.SaveCounts(ctx, userID, opnameID, []*model.StockOpnameCount{{ProductID: 222, Quantity: 5}})
+		require.ErrorIs(t, err, model.ErrStockOpnameNotOpen)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStockOpnameRepository_Approve(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &stockOpnameRepository{
+		db:                 kit.db,
+		cache:              kit.cache,
+		productRepo:        kit.mockProductRepo,
+		productVariantRepo: kit.mockProductVariantRepo,
+		auditRepo:          kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	countedAt := time.Now()
+	countColumns := []string{"stock_opname_id", "product_id", "variant_id", "counted_by", "quantity", "system_quantity", "counted_at"}
+
+	t.Run("ok - post the variances as adjustments", func(t *testing.T) {
+		opname := &model.StockOpname{ID: utils.GenerateID(), Status: model.StockOpnameStatusOpen, Note: "akhir bulan"}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "stock_opnames" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockOpnameStatusOpen))
+		mock.ExpectQuery(`^SELECT \* FROM "stock_opname_counts" WHERE stock_opname_id = .+ ORDER BY counted_at ASC`).
+			WithArgs(opname.ID).
+			WillReturnRows(sqlmock.NewRows(countColumns).
+				AddRow(opname.ID, 333, 301, 111, 2, 4, countedAt).
+				AddRow(opname.ID, 222, 0, 111, 5, 8, countedAt).
+				AddRow(opname.ID, 444, 0, 111, 6, 6, countedAt).
+				AddRow(opname.ID, 333, 301, 112, 3, 2, countedAt.Add(time.Minute)))
+		kit.mockProductRepo.EXPECT().ApplyStockMovements(ctx, gomock.Any(), []*model.StockMovement{
+			{
+				ProductID:     222,
+				Type:          model.StockMovementTypeAdjustment,
+				Quantity:      -3,
+				ReferenceType: model.StockReferenceStockOpname,
+				ReferenceID:   opname.ID,
+				Note:          opname.Note,
+				CreatedBy:     userID,
+			},
+			{
+				ProductID:     333,
+				VariantID:     301,
+				Type:          model.StockMovementTypeAdjustment,
+				Quantity:      1,
+				ReferenceType: model.StockReferenceStockOpname,
+				ReferenceID:   opname.ID,
+				Note:          opname.Note,
+				CreatedBy:     userID,
+			},
+		}).Times(1).Return(nil)
+		mock.ExpectExec(`^UPDATE "stock_opnames" SET "status"=.+,"approved_by"=.+,"approved_at"=.+ WHERE "id" = .+`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), opname, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductRepo.EXPECT().DeleteCachesByIDs([]int64{222, 333}).Times(1).Return(nil)
+		kit.mockProductVariantRepo.EXPECT().DeleteCachesByIDs([]int64{301}).Times(1).Return(nil)
+
+		err := repo.Approve(ctx, userID, opname)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Equal(t, model.StockOpnameStatusApproved, opname.Status)
+		require.Equal(t, userID, opname.ApprovedBy)
+		require.NotNil(t, opname.ApprovedAt)
+	})
+
+	t.Run("failed - nothing counted", func(t *testing.T) {
+		opname := &model.StockOpname{ID: utils.GenerateID(), Status: model.StockOpnameStatusOpen}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "stock_opnames" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockOpnameStatusOpen))
+		mock.ExpectQuery(`^SELECT \* FROM "stock_opname_counts"`).
+			WillReturnRows(sqlmock.NewRows(countColumns))
+		mock.ExpectRollback()
+
+		err := repo.Approve(ctx, userID, opname)
+		require.ErrorIs(t, err, model.ErrStockOpnameEmpty)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - insufficient stock rollback the approval", func(t *testing.T) {
+		opname := &model.StockOpname{ID: utils.GenerateID(), Status: model.StockOpnameStatusOpen}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "stock_opnames" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockOpnameStatusOpen))
+		mock.ExpectQuery(`^SELECT \* FROM "stock_opname_counts"`).
+			WillReturnRows(sqlmock.NewRows(countColumns).AddRow(opname.ID, 222, 0, 111, 0, 8, countedAt))
+		kit.mockProductRepo.EXPECT().ApplyStockMovements(ctx, gomock.Any(), gomock.Any()).Times(1).Return(model.ErrInsufficientStock)
+		mock.ExpectRollback()
+
+		err := repo.Approve(ctx, userID, opname)
+		require.ErrorIs(t, err, model.ErrInsufficientStock)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - already approved", func(t *testing.T) {
+		opname := &model.StockOpname{ID: utils.GenerateID(), Status: model.StockOpnameStatusOpen}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^SELECT "status" FROM "stock_opnames" WHERE id = .+ FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StockOpnameStatusApproved))
+		mock.ExpectRollback()
+
+		err := repo.Approve(ctx, userID, opname)
+		require.ErrorIs(t, err, model.ErrStockOpnameNotOpen)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
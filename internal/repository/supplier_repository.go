@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type supplierRepository struct {
+	db        *gorm.DB
+	cache     cacher.CacheManager
+	auditRepo model.AuditRepository
+}
+
+// NewSupplierRepository create new repository
+func NewSupplierRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+	auditRepo model.AuditRepository,
+) model.SupplierRepository {
+	return &supplierRepository{
+		db:        db,
+		cache:     cache,
+		auditRepo: auditRepo,
+	}
+}
+
+// FindByID find supplier by id
+func (s *supplierRepository) FindByID(ctx context.Context, id int64) (*model.Supplier, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"supplierID": id,
+	})
+
+	cacheKey := s.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.Supplier](s.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	supplier := &model.Supplier{}
+	err := s.db.WithContext(ctx).Take(supplier, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, s.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(supplier))); err != nil {
+		logger.Error(err)
+	}
+
+	return supplier, nil
+}
+
+// SearchByPage find all supplier ids ordered by name
+func (s *supplierRepository) SearchByPage(ctx context.Context, criteria model.SupplierSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = s.db.WithContext(ctx).
+		Model(model.Supplier{}).
+		Scopes(scopesBySupplierSearchCriteria(criteria)...).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = s.db.WithContext(ctx).
+		Model(model.Supplier{}).
+		Scopes(scopesBySupplierSearchCriteria(criteria)...).
+		Scopes(scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("name ASC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
+// Create supplier
+func (s *supplierRepository) Create(ctx context.Context, userID int64, supplier *model.Supplier) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"supplier": utils.Dump(supplier),
+	})
+
+	supplier.UpdatedAt = time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(supplier).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, supplier, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   supplier.ID,
+			Action:        model.AuditActionCreate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(supplier); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Update supplier, the zero values are updated too
+func (s *supplierRepository) Update(ctx context.Context, userID int64, supplier *model.Supplier) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"supplier": utils.Dump(supplier),
+	})
+
+	supplier.UpdatedAt = time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("*").Omit("created_at", "deleted_at").Updates(supplier).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, supplier, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   supplier.ID,
+			Action:        model.AuditActionUpdate,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(supplier); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+// Delete soft delete a supplier
+func (s *supplierRepository) Delete(ctx context.Context, userID int64, supplier *model.Supplier) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"userID":   userID,
+		"supplier": utils.Dump(supplier),
+	})
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(supplier).Error; err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := s.auditRepo.Audit(ctx, tx, supplier, &model.Audit{
+			UserID:        userID,
+			AuditableType: s.name(),
+			AuditableID:   supplier.ID,
+			Action:        model.AuditActionDelete,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.deleteCaches(supplier); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
+func (s *supplierRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:supplier:id:%d", id)
+}
+
+// deleteCaches delete related cache
+func (s *supplierRepository) deleteCaches(supplier *model.Supplier) error {
+	if supplier == nil {
+		return nil
+	}
+
+	return s.cache.DeleteByKeys([]string{s.newCacheKeyByID(supplier.ID)})
+}
+
+func (s *supplierRepository) name() string {
+	return "supplier"
+}
+
+// scopesBySupplierSearchCriteria build the filter scopes of supplier search criteria
+func scopesBySupplierSearchCriteria(criteria model.SupplierSearchCriteria) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if criteria.Query != "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("name ILIKE ?", "%"+criteria.Query+"%")
+		})
+	}
+
+	return scopes
+}
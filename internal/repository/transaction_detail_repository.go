@@ -24,6 +24,24 @@ func NewTransactionDetailRepository(
 	}
 }
 
+// FindByTransactionID find all details of a transaction
+func (t *transactionDetailRepository) FindByTransactionID(ctx context.Context, transactionID int64) ([]*model.TransactionDetail, error) {
+	var details []*model.TransactionDetail
+	err := t.db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("product_id ASC").
+		Find(&details).Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":           utils.DumpIncomingContext(ctx),
+			"transactionID": transactionID,
+		}).Error(err)
+		return nil, err
+	}
+
+	return details, nil
+}
+
 func (t *transactionDetailRepository) Create(ctx context.Context, tx *gorm.DB, details []*model.TransactionDetail) error {
 	if len(details) <= 0 {
 		return nil
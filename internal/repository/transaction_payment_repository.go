@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type transactionPaymentRepository struct {
+	db    *gorm.DB
+	cache cacher.CacheManager
+}
+
+func NewTransactionPaymentRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+) model.TransactionPaymentRepository {
+	return &transactionPaymentRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// FindByTransactionID find all payments of a transaction
+func (t *transactionPaymentRepository) FindByTransactionID(ctx context.Context, transactionID int64) ([]*model.TransactionPayment, error) {
+	var payments []*model.TransactionPayment
+	err := t.db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("method ASC").
+		Find(&payments).Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":           utils.DumpIncomingContext(ctx),
+			"transactionID": transactionID,
+		}).Error(err)
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (t *transactionPaymentRepository) Create(ctx context.Context, tx *gorm.DB, payments []*model.TransactionPayment) error {
+	if len(payments) <= 0 {
+		return nil
+	}
+
+	if err := tx.WithContext(ctx).Create(payments).Error; err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":      utils.DumpIncomingContext(ctx),
+			"payments": utils.Dump(payments),
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
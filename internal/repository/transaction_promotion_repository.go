@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type transactionPromotionRepository struct {
+	db    *gorm.DB
+	cache cacher.CacheManager
+}
+
+func NewTransactionPromotionRepository(
+	db *gorm.DB,
+	cache cacher.CacheManager,
+) model.TransactionPromotionRepository {
+	return &transactionPromotionRepository{
+		db:    db,
+		cache: cache,
+	}
+}
+
+// FindByTransactionID find all applied promotions of a transaction
+func (t *transactionPromotionRepository) FindByTransactionID(ctx context.Context, transactionID int64) ([]*model.TransactionPromotion, error) {
+	var promotions []*model.TransactionPromotion
+	err := t.db.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		Order("product_id DESC").
+		Find(&promotions).Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":           utils.DumpIncomingContext(ctx),
+			"transactionID": transactionID,
+		}).Error(err)
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+func (t *transactionPromotionRepository) Create(ctx context.Context, tx *gorm.DB, promotions []*model.TransactionPromotion) error {
+	if len(promotions) <= 0 {
+		return nil
+	}
+
+	if err := tx.WithContext(ctx).Create(promotions).Error; err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":        utils.DumpIncomingContext(ctx),
+			"promotions": utils.Dump(promotions),
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
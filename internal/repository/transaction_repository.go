@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"github.com/irvankadhafi/go-point-of-sales/cacher"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/sirupsen/logrus"
@@ -11,26 +13,141 @@ import (
 )
 
 type transactionRepository struct {
-	db                    *gorm.DB
-	cache                 cacher.CacheManager
-	transactionDetailRepo model.TransactionDetailRepository
-	auditRepo             model.AuditRepository
+	db                       *gorm.DB
+	cache                    cacher.CacheManager
+	transactionDetailRepo    model.TransactionDetailRepository
+	transactionPaymentRepo   model.TransactionPaymentRepository
+	transactionPromotionRepo model.TransactionPromotionRepository
+	productRepo              model.ProductRepository
+	productVariantRepo       model.ProductVariantRepository
+	shiftRepo                model.ShiftRepository
+	stockMovementRepo        model.StockMovementRepository
+	auditRepo                model.AuditRepository
 }
 
 func NewTransactionRepository(
 	db *gorm.DB,
 	cache cacher.CacheManager,
 	transactionDetailRepo model.TransactionDetailRepository,
+	transactionPaymentRepo model.TransactionPaymentRepository,
+	transactionPromotionRepo model.TransactionPromotionRepository,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	shiftRepo model.ShiftRepository,
+	stockMovementRepo model.StockMovementRepository,
 	auditRepo model.AuditRepository,
 ) model.TransactionRepository {
 	return &transactionRepository{
-		db:                    db,
-		cache:                 cache,
-		transactionDetailRepo: transactionDetailRepo,
-		auditRepo:             auditRepo,
+		db:                       db,
+		cache:                    cache,
+		transactionDetailRepo:    transactionDetailRepo,
+		transactionPaymentRepo:   transactionPaymentRepo,
+		transactionPromotionRepo: transactionPromotionRepo,
+		productRepo:              productRepo,
+		productVariantRepo:       productVariantRepo,
+		shiftRepo:                shiftRepo,
+		stockMovementRepo:        stockMovementRepo,
+		auditRepo:                auditRepo,
 	}
 }
 
+// FindByID find transaction by id along with its details, payments & applied promotions
+func (t *transactionRepository) FindByID(ctx context.Context, id int64) (*model.Transaction, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"transactionID": id,
+	})
+
+	cacheKey := t.newCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := cacher.FindFromCacheByKey[*model.Transaction](t.cache, cacheKey)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		defer cacher.SafeUnlock(mu)
+
+		if mu == nil {
+			return reply, nil
+		}
+	}
+
+	transaction := &model.Transaction{}
+	err := t.db.WithContext(ctx).Take(transaction, "id = ?", id).Error
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		cacher.StoreNil(ctx, t.cache, cacheKey)
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+
+	details, err := t.transactionDetailRepo.FindByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	transaction.TransactionDetails = details
+
+	payments, err := t.transactionPaymentRepo.FindByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	transaction.TransactionPayments = payments
+
+	promotions, err := t.transactionPromotionRepo.FindByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	transaction.TransactionPromotions = promotions
+
+	if err := t.cache.StoreWithoutBlocking(cacher.NewItem(cacheKey, utils.Dump(transaction))); err != nil {
+		logger.Error(err)
+	}
+
+	return transaction, nil
+}
+
+// SearchByPage find all transaction ids with specific criteria
+func (t *transactionRepository) SearchByPage(ctx context.Context, criteria model.TransactionSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	count, err = t.countAll(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	ids, err = t.findAllIDsByCriteria(ctx, criteria)
+	switch err {
+	case nil:
+	case gorm.ErrRecordNotFound:
+		return nil, 0, nil
+	default:
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
+// Create store the transaction and decrease the stock of every sold product in a single db transaction,
+// any failing line rolls back the whole sale including the stock changes.
+// A sold variant decreases both its own stock and the total stock of its product.
+// Every sold line is recorded as a sale movement on the stock ledger.
+// The linked shift is locked during the sale so it can not be closed before the sale is stored.
 func (t *transactionRepository) Create(ctx context.Context, userID int64, transaction *model.Transaction) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":         utils.DumpIncomingContext(ctx),
@@ -38,7 +155,33 @@ func (t *transactionRepository) Create(ctx context.Context, userID int64, transa
 		"transaction": utils.Dump(transaction),
 	})
 
+	var productIDs, variantIDs []int64
 	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if transaction.ShiftID > 0 {
+			if err := t.shiftRepo.LockOpenByID(ctx, tx, transaction.ShiftID); err != nil {
+				logger.Error(err)
+				return err
+			}
+		}
+
+		for _, detail := range transaction.TransactionDetails {
+			if err := t.productRepo.DecreaseStockByID(ctx, tx, detail.ProductID, detail.Quantity); err != nil {
+				logger.Error(err)
+				return err
+			}
+			productIDs = append(productIDs, detail.ProductID)
+
+			if detail.VariantID <= 0 {
+				continue
+			}
+
+			if err := t.productVariantRepo.DecreaseStockByID(ctx, tx, detail.VariantID, detail.Quantity); err != nil {
+				logger.Error(err)
+				return err
+			}
+			variantIDs = append(variantIDs, detail.VariantID)
+		}
+
 		if err := tx.Create(transaction).Error; err != nil {
 			logger.Error(err)
 			return err
@@ -49,6 +192,21 @@ func (t *transactionRepository) Create(ctx context.Context, userID int64, transa
 			return err
 		}
 
+		if err := t.stockMovementRepo.Create(ctx, tx, transaction.StockMovements(userID)); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := t.transactionPaymentRepo.Create(ctx, tx, transaction.TransactionPayments); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if err := t.transactionPromotionRepo.Create(ctx, tx, transaction.TransactionPromotions); err != nil {
+			logger.Error(err)
+			return err
+		}
+
 		if err := t.auditRepo.Audit(ctx, tx, transaction, &model.Audit{
 			UserID:        userID,
 			AuditableType: t.name(),
@@ -67,9 +225,174 @@ func (t *transactionRepository) Create(ctx context.Context, userID int64, transa
 		return err
 	}
 
+	if err := t.deleteCaches(transaction); err != nil {
+		logger.Error(err)
+	}
+
+	if err := t.productRepo.DeleteCachesByIDs(productIDs); err != nil {
+		logger.Error(err)
+	}
+
+	if len(variantIDs) > 0 {
+		if err := t.productVariantRepo.DeleteCachesByIDs(variantIDs); err != nil {
+			logger.Error(err)
+		}
+	}
+
 	return nil
 }
 
+// RecordReceiptPrint increase the receipt print count of the transaction and audit it as print or reprint
+func (t *transactionRepository) RecordReceiptPrint(ctx context.Context, userID int64, transaction *model.Transaction) (printCount int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"userID":        userID,
+		"transactionID": transaction.ID,
+	})
+
+	err = t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`UPDATE "transactions" SET "receipt_prints" = "receipt_prints" + 1 WHERE "id" = ? RETURNING "receipt_prints"`, transaction.ID).
+			Scan(&printCount).Error
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		action := model.AuditActionPrint
+		if printCount > 1 {
+			action = model.AuditActionReprint
+		}
+
+		if err := t.auditRepo.Audit(ctx, tx, map[string]int64{"receipt_prints": printCount}, &model.Audit{
+			UserID:        userID,
+			AuditableType: t.name(),
+			AuditableID:   transaction.ID,
+			Action:        action,
+			CreatedAt:     time.Now(),
+		}); err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	if err := t.deleteCaches(transaction); err != nil {
+		logger.Error(err)
+	}
+
+	return printCount, nil
+}
+
+func (t *transactionRepository) findAllIDsByCriteria(ctx context.Context, criteria model.TransactionSearchCriteria) ([]int64, error) {
+	scopes := scopesByTransactionSearchCriteria(criteria)
+	scopes = append(scopes, scopeByPageAndLimit(criteria.Page, criteria.Size))
+
+	var ids []int64
+	err := t.db.WithContext(ctx).
+		Model(model.Transaction{}).
+		Scopes(scopes...).
+		Order(orderByTransactionSortType(criteria.SortType)).
+		Pluck("id", &ids).Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":      utils.DumpIncomingContext(ctx),
+			"criteria": utils.Dump(criteria),
+		}).Error(err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (t *transactionRepository) countAll(ctx context.Context, criteria model.TransactionSearchCriteria) (int64, error) {
+	var count int64
+	err := t.db.WithContext(ctx).Model(model.Transaction{}).
+		Scopes(scopesByTransactionSearchCriteria(criteria)...).
+		Count(&count).
+		Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":      utils.DumpIncomingContext(ctx),
+			"criteria": utils.Dump(criteria),
+		}).Error(err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (t *transactionRepository) newCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:object:transaction:id:%d", id)
+}
+
+// deleteCaches delete related cache
+func (t *transactionRepository) deleteCaches(transaction *model.Transaction) error {
+	if transaction == nil {
+		return nil
+	}
+
+	return t.cache.DeleteByKeys([]string{
+		t.newCacheKeyByID(transaction.ID),
+	})
+}
+
 func (t *transactionRepository) name() string {
 	return "transaction"
 }
+
+// scopesByTransactionSearchCriteria build the filter scopes of transaction search criteria
+func scopesByTransactionSearchCriteria(criteria model.TransactionSearchCriteria) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if criteria.CreatedBy > 0 {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("created_by = ?", criteria.CreatedBy)
+		})
+	}
+
+	if criteria.ProductID > 0 {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("id IN (SELECT transaction_id FROM transaction_details WHERE product_id = ?)", criteria.ProductID)
+		})
+	}
+
+	// the criteria is validated on usecase, so the error can be ignored here
+	startAt, endAt, _ := criteria.DateRange()
+	if startAt != nil {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("created_at >= ?", *startAt)
+		})
+	}
+
+	if endAt != nil {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("created_at < ?", *endAt)
+		})
+	}
+
+	if criteria.MinTotalPrice > 0 {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("total_price >= ?", criteria.MinTotalPrice)
+		})
+	}
+
+	if criteria.MaxTotalPrice > 0 {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("total_price <= ?", criteria.MaxTotalPrice)
+		})
+	}
+
+	return scopes
+}
+
+func orderByTransactionSortType(sortType model.TransactionSortType) string {
+	if orderBy, ok := model.QueryTransactionSortByMap[sortType]; ok {
+		return orderBy
+	}
+
+	return model.QueryTransactionSortByMap[model.TransactionSortTypeCreatedAtDesc]
+}
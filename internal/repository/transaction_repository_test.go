@@ -0,0 +1,363 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestTransactionRepository_FindByID(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &transactionRepository{
+		db:                       kit.db,
+		cache:                    kit.cache,
+		transactionDetailRepo:    kit.mockTransactionDetailRepo,
+		transactionPaymentRepo:   kit.mockTransactionPaymentRepo,
+		transactionPromotionRepo: kit.mockTransactionPromotionRepo,
+	}
+
+	transaction := &model.Transaction{
+		ID:         utils.GenerateID(),
+		TotalPrice: 10000,
+		AmountPaid: 20000,
+		Change:     10000,
+		CreatedBy:  int64(111),
+	}
+
+	details := []*model.TransactionDetail{
+		{
+			TransactionID: transaction.ID,
+			ProductID:     int64(222),
+			Quantity:      2,
+			Subtotal:      10000,
+		},
+	}
+
+	payments := []*model.TransactionPayment{
+		{
+			TransactionID: transaction.ID,
+			Method:        model.PaymentMethodCash,
+			Amount:        20000,
+		},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		rows := sqlmock.NewRows([]string{
+			"id",
+			"total_price",
+			"amount_paid",
+			"change",
+			"created_by",
+		})
+		rows.AddRow(transaction.ID,
+			transaction.TotalPrice,
+			transaction.AmountPaid,
+			transaction.Change,
+			transaction.CreatedBy)
+
+		mock.ExpectQuery("^SELECT .+ FROM \"transactions\"").WillReturnRows(rows)
+		kit.mockTransactionDetailRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(details, nil)
+		kit.mockTransactionPaymentRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(payments, nil)
+		kit.mockTransactionPromotionRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+
+		res, err := repo.FindByID(ctx, transaction.ID)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		require.Len(t, res.TransactionDetails, 1)
+		require.Len(t, res.TransactionPayments, 1)
+		require.True(t, kit.miniredis.Exists(repo.newCacheKeyByID(transaction.ID)))
+	})
+
+	t.Run("ok - from cache", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		cached := *transaction
+		cached.TransactionDetails = details
+		err := kit.miniredis.Set(repo.newCacheKeyByID(transaction.ID), utils.Dump(cached))
+		require.NoError(t, err)
+
+		res, err := repo.FindByID(ctx, transaction.ID)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		require.Equal(t, transaction.ID, res.ID)
+		require.Len(t, res.TransactionDetails, 1)
+	})
+
+	t.Run("failed - find details return err", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		rows := sqlmock.NewRows([]string{"id"}).AddRow(transaction.ID)
+
+		mock.ExpectQuery("^SELECT .+ FROM \"transactions\"").WillReturnRows(rows)
+		kit.mockTransactionDetailRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, errors.New("db error"))
+
+		res, err := repo.FindByID(ctx, transaction.ID)
+		require.Error(t, err)
+		require.Nil(t, res)
+		require.False(t, kit.miniredis.Exists(repo.newCacheKeyByID(transaction.ID)))
+	})
+
+	t.Run("failed - not found", func(t *testing.T) {
+		defer kit.miniredis.FlushDB()
+		mock.ExpectQuery("^SELECT .+ FROM \"transactions\"").
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		res, err := repo.FindByID(ctx, transaction.ID)
+		require.NoError(t, err)
+		require.Nil(t, res)
+
+		cacheVal, err := kit.miniredis.Get(repo.newCacheKeyByID(transaction.ID))
+		require.NoError(t, err)
+		require.Equal(t, `null`, cacheVal)
+	})
+}
+
+func TestTransactionRepository_SearchByPage(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &transactionRepository{
+		db:    kit.db,
+		cache: kit.cache,
+	}
+
+	transactionIDs := []int64{int64(111), int64(222), int64(333)}
+	criteria := model.TransactionSearchCriteria{
+		Page:          1,
+		Size:          10,
+		SortType:      model.TransactionSortTypeTotalPriceDesc,
+		CreatedBy:     int64(444),
+		ProductID:     int64(555),
+		StartDate:     "2023-09-01",
+		EndDate:       "2023-09-30",
+		MinTotalPrice: 1000,
+		MaxTotalPrice: 100000,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT count(.*) FROM "transactions" WHERE created_by = .+ AND id IN \(SELECT transaction_id FROM transaction_details WHERE product_id = .+\) AND created_at >= .+ AND created_at < .+ AND total_price >= .+ AND total_price <= .+`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(transactionIDs)))
+
+		rows := sqlmock.NewRows([]string{"id"})
+		for _, id := range transactionIDs {
+			rows.AddRow(id)
+		}
+		mock.ExpectQuery(`^SELECT "id" FROM "transactions" .+ ORDER BY total_price DESC`).
+			WillReturnRows(rows)
+
+		ids, count, err := repo.SearchByPage(ctx, criteria)
+		require.NoError(t, err)
+		require.Equal(t, transactionIDs, ids)
+		require.Equal(t, int64(len(transactionIDs)), count)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mock.ExpectQuery(`^SELECT count(.*) FROM "transactions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		ids, count, err := repo.SearchByPage(ctx, model.TransactionSearchCriteria{Page: 1, Size: 10})
+		require.NoError(t, err)
+		require.Empty(t, ids)
+		require.Equal(t, int64(0), count)
+	})
+}
+
+func TestTransactionRepository_Create(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &transactionRepository{
+		db:                       kit.db,
+		cache:                    kit.cache,
+		transactionDetailRepo:    kit.mockTransactionDetailRepo,
+		transactionPaymentRepo:   kit.mockTransactionPaymentRepo,
+		transactionPromotionRepo: kit.mockTransactionPromotionRepo,
+		productRepo:              kit.mockProductRepo,
+		stockMovementRepo:        kit.mockStockMovementRepo,
+		auditRepo:                kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	transactionID := utils.GenerateID()
+	transaction := &model.Transaction{
+		ID:         transactionID,
+		TotalPrice: 15000,
+		AmountPaid: 20000,
+		Change:     5000,
+		CreatedBy:  userID,
+		TransactionDetails: []*model.TransactionDetail{
+			{TransactionID: transactionID, ProductID: int64(222), Quantity: 2, Subtotal: 10000},
+			{TransactionID: transactionID, ProductID: int64(333), Quantity: 1, Subtotal: 5000},
+		},
+		TransactionPayments: []*model.TransactionPayment{
+			{TransactionID: transactionID, Method: model.PaymentMethodQRIS, Amount: 10000, Reference: "QR-001"},
+			{TransactionID: transactionID, Method: model.PaymentMethodCash, Amount: 10000},
+		},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		mock.ExpectBegin()
+		kit.mockProductRepo.EXPECT().DecreaseStockByID(ctx, gomock.Any(), int64(222), int64(2)).Times(1).Return(nil)
+		kit.mockProductRepo.EXPECT().DecreaseStockByID(ctx, gomock.Any(), int64(333), int64(1)).Times(1).Return(nil)
+		mock.ExpectQuery(`^INSERT INTO "transactions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(transaction.CreatedAt))
+		kit.mockTransactionDetailRepo.EXPECT().Create(ctx, gomock.Any(), transaction.TransactionDetails).Times(1).Return(nil)
+		kit.mockStockMovementRepo.EXPECT().Create(ctx, gomock.Any(), []*model.StockMovement{
+			{ProductID: 222, Type: model.StockMovementTypeSale, Quantity: -2, ReferenceType: model.StockReferenceTransaction, ReferenceID: transactionID, CreatedBy: userID},
+			{ProductID: 333, Type: model.StockMovementTypeSale, Quantity: -1, ReferenceType: model.StockReferenceTransaction, ReferenceID: transactionID, CreatedBy: userID},
+		}).Times(1).Return(nil)
+		kit.mockTransactionPaymentRepo.EXPECT().Create(ctx, gomock.Any(), transaction.TransactionPayments).Times(1).Return(nil)
+		kit.mockTransactionPromotionRepo.EXPECT().Create(ctx, gomock.Any(), transaction.TransactionPromotions).Times(1).Return(nil)
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), transaction, gomock.Any()).Times(1).Return(nil)
+		mock.ExpectCommit()
+		kit.mockProductRepo.EXPECT().DeleteCachesByIDs([]int64{222, 333}).Times(1).Return(nil)
+
+		err := repo.Create(ctx, userID, transaction)
+		require.NoError(t, err)
+	})
+
+	t.Run("failed - insufficient stock rollback the whole sale", func(t *testing.T) {
+		mock.ExpectBegin()
+		kit.mockProductRepo.EXPECT().DecreaseStockByID(ctx, gomock.Any(), int64(222), int64(2)).Times(1).Return(nil)
+		kit.mockProductRepo.EXPECT().DecreaseStockByID(ctx, gomock.Any(), int64(333), int64(1)).Times(1).Return(model.ErrInsufficientStock)
+		mock.ExpectRollback()
+
+		err := repo.Create(ctx, userID, transaction)
+		require.ErrorIs(t, err, model.ErrInsufficientStock)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed - create details return err", func(t *testing.T) {
+		mock.ExpectBegin()
+		kit.mockProductRepo.EXPECT().DecreaseStockByID(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(nil)
+		mock.ExpectQuery(`^INSERT INTO "transactions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(transaction.CreatedAt))
+		kit.mockTransactionDetailRepo.EXPECT().Create(ctx, gomock.Any(), transaction.TransactionDetails).Times(1).Return(errors.New("db error"))
+		mock.ExpectRollback()
+
+		err := repo.Create(ctx, userID, transaction)
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTransactionRepository_RecordReceiptPrint(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &transactionRepository{
+		db:        kit.db,
+		cache:     kit.cache,
+		auditRepo: kit.mockAuditRepo,
+	}
+
+	userID := int64(111)
+	transaction := &model.Transaction{ID: utils.GenerateID()}
+
+	t.Run("ok - first print", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^UPDATE "transactions" SET "receipt_prints" = "receipt_prints" \+ 1`).
+			WithArgs(transaction.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"receipt_prints"}).AddRow(1))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ *gorm.DB, _ any, audit *model.Audit) error {
+				require.Equal(t, model.AuditActionPrint, audit.Action)
+				return nil
+			})
+		mock.ExpectCommit()
+
+		printCount, err := repo.RecordReceiptPrint(ctx, userID, transaction)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), printCount)
+	})
+
+	t.Run("ok - reprint", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^UPDATE "transactions" SET "receipt_prints" = "receipt_prints" \+ 1`).
+			WithArgs(transaction.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"receipt_prints"}).AddRow(2))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ *gorm.DB, _ any, audit *model.Audit) error {
+				require.Equal(t, model.AuditActionReprint, audit.Action)
+				return nil
+			})
+		mock.ExpectCommit()
+
+		printCount, err := repo.RecordReceiptPrint(ctx, userID, transaction)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), printCount)
+	})
+
+	t.Run("failed - audit error rolls back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`^UPDATE "transactions" SET "receipt_prints" = "receipt_prints" \+ 1`).
+			WithArgs(transaction.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"receipt_prints"}).AddRow(3))
+		kit.mockAuditRepo.EXPECT().Audit(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+			Return(errors.New("db error"))
+		mock.ExpectRollback()
+
+		printCount, err := repo.RecordReceiptPrint(ctx, userID, transaction)
+		require.Error(t, err)
+		require.Equal(t, int64(0), printCount)
+	})
+}
+
+func TestTransactionRepository_Create_InsufficientStock(t *testing.T) {
+	initializeTest()
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+
+	ctx := context.TODO()
+	repo := &transactionRepository{
+		db:                    kit.db,
+		cache:                 kit.cache,
+		transactionDetailRepo: kit.mockTransactionDetailRepo,
+		productRepo:           &productRepository{db: kit.db, cache: kit.cache},
+		auditRepo:             kit.mockAuditRepo,
+	}
+
+	transactionID := utils.GenerateID()
+	transaction := &model.Transaction{
+		ID:         transactionID,
+		TotalPrice: 15000,
+		TransactionDetails: []*model.TransactionDetail{
+			{TransactionID: transactionID, ProductID: int64(222), Quantity: 2, Subtotal: 10000},
+			{TransactionID: transactionID, ProductID: int64(333), Quantity: 1, Subtotal: 5000},
+		},
+	}
+
+	// the stock is only decreased when it is still enough, no affected row means it has run out
+	mock.ExpectBegin()
+	mock.ExpectExec(`^UPDATE "products" SET "quantity"=quantity - \$1,"updated_at"=\$2 WHERE \(id = \$3 AND quantity >= \$4\)`).
+		WithArgs(int64(2), sqlmock.AnyArg(), int64(222), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`^UPDATE "products" SET "quantity"=quantity - \$1,"updated_at"=\$2 WHERE \(id = \$3 AND quantity >= \$4\)`).
+		WithArgs(int64(1), sqlmock.AnyArg(), int64(333), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.Create(ctx, int64(111), transaction)
+	require.ErrorIs(t, err, model.ErrInsufficientStock)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository create new repository, the secrets & the challenges are read straight from the db
+// since the last used time step and the used flags must never be stale
+func NewTwoFactorRepository(db *gorm.DB) model.TwoFactorRepository {
+	return &twoFactorRepository{
+		db: db,
+	}
+}
+
+// FindByUserID find the two factor of the user
+func (t *twoFactorRepository) FindByUserID(ctx context.Context, userID int64) (*model.UserTwoFactor, error) {
+	twoFactor := &model.UserTwoFactor{}
+	err := t.db.WithContext(ctx).Take(twoFactor, "user_id = ?", userID).Error
+	switch err {
+	case nil:
+		return twoFactor, nil
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	default:
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": userID,
+		}).Error(err)
+		return nil, err
+	}
+}
+
+// SavePending store the pending enrollment, the conflicting row is only replaced while it is not enabled
+func (t *twoFactorRepository) SavePending(ctx context.Context, twoFactor *model.UserTwoFactor) error {
+	twoFactor.EnabledAt = nil
+	twoFactor.LastUsedStep = 0
+	err := t.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: `"user_two_factors"."enabled_at" IS NULL`}}},
+	}).Create(twoFactor).Error
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": twoFactor.UserID,
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Enable enable the two factor of the user and replace its recovery codes in a single db transaction
+func (t *twoFactorRepository) Enable(ctx context.Context, userID int64, recoveryCodeHashes []string) error {
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(model.UserTwoFactor{}).
+			Where("user_id = ?", userID).
+			Update("enabled_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": userID,
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes replace the recovery codes of the user, the old codes can no longer be used
+func (t *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int64, recoveryCodeHashes []string) error {
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": userID,
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UseStep record the time step, the update only matches an older step so a code can not be used twice
+// even by the concurrent requests
+func (t *twoFactorRepository) UseStep(ctx context.Context, userID, step int64) (bool, error) {
+	res := t.db.WithContext(ctx).Model(model.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if res.Error != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": userID,
+		}).Error(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode mark the recovery code used, the update only matches an unused code
+func (t *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	res := t.db.WithContext(ctx).Model(model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": userID,
+		}).Error(res.Error)
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// DeleteByUserID delete the two factor & the recovery codes of the user
+func (t *twoFactorRepository) DeleteByUserID(ctx context.Context, userID int64) error {
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(model.RecoveryCode{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(model.UserTwoFactor{}, "user_id = ?", userID).Error
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": userID,
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateChallenge store the challenge
+func (t *twoFactorRepository) CreateChallenge(ctx context.Context, challenge *model.TwoFactorChallenge) error {
+	if err := t.db.WithContext(ctx).Create(challenge).Error; err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":    utils.DumpIncomingContext(ctx),
+			"userID": challenge.UserID,
+		}).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// FindChallengeByTokenHash find the challenge by its hash
+func (t *twoFactorRepository) FindChallengeByTokenHash(ctx context.Context, tokenHash string) (*model.TwoFactorChallenge, error) {
+	challenge := &model.TwoFactorChallenge{}
+	err := t.db.WithContext(ctx).Take(challenge, "token_hash = ?", tokenHash).Error
+	switch err {
+	case nil:
+		return challenge, nil
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	default:
+		logrus.WithField("ctx", utils.DumpIncomingContext(ctx)).Error(err)
+		return nil, err
+	}
+}
+
+// UseChallenge mark the challenge used, the update only matches an unused challenge
+// so a challenge can not be answered twice even by the concurrent requests
+func (t *twoFactorRepository) UseChallenge(ctx context.Context, challenge *model.TwoFactorChallenge) (bool, error) {
+	now := time.Now()
+	res := t.db.WithContext(ctx).Model(model.TwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL", challenge.ID).
+		Update("used_at", now)
+	if res.Error != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":         utils.DumpIncomingContext(ctx),
+			"challengeID": challenge.ID,
+		}).Error(res.Error)
+		return false, res.Error
+	}
+	if res.RowsAffected <= 0 {
+		return false, nil
+	}
+
+	challenge.UsedAt = &now
+	return true, nil
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID int64, recoveryCodeHashes []string) error {
+	if err := tx.Delete(model.RecoveryCode{}, "user_id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	if len(recoveryCodeHashes) == 0 {
+		return nil
+	}
+
+	codes := make([]*model.RecoveryCode, 0, len(recoveryCodeHashes))
+	for _, hash := range recoveryCodeHashes {
+		codes = append(codes, &model.RecoveryCode{
+			ID:       utils.GenerateID(),
+			UserID:   userID,
+			CodeHash: hash,
+		})
+	}
+
+	return tx.Create(codes).Error
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorRepository_UseStep(t *testing.T) {
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+	initializeTest()
+
+	ctx := context.TODO()
+	repo := &twoFactorRepository{db: kit.db}
+
+	t.Run("ok", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "user_two_factors" SET "last_used_step"=\$1,"updated_at"=\$2 WHERE user_id = \$3 AND last_used_step < \$4`).
+			WithArgs(int64(100), sqlmock.AnyArg(), int64(2), int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		used, err := repo.UseStep(ctx, 2, 100)
+		require.NoError(t, err)
+		require.True(t, used)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ok - step already used", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "user_two_factors" SET "last_used_step"=\$1,"updated_at"=\$2 WHERE user_id = \$3 AND last_used_step < \$4`).
+			WithArgs(int64(100), sqlmock.AnyArg(), int64(2), int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		used, err := repo.UseStep(ctx, 2, 100)
+		require.NoError(t, err)
+		require.False(t, used)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTwoFactorRepository_UseChallenge(t *testing.T) {
+	kit, closer := initializeRepoTestKit(t)
+	defer closer()
+	mock := kit.dbmock
+	initializeTest()
+
+	ctx := context.TODO()
+	repo := &twoFactorRepository{db: kit.db}
+
+	t.Run("ok", func(t *testing.T) {
+		challenge := &model.TwoFactorChallenge{ID: 9, UserID: 2}
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "two_factor_challenges" SET "used_at"=\$1 WHERE id = \$2 AND used_at IS NULL`).
+			WithArgs(sqlmock.AnyArg(), challenge.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		used, err := repo.UseChallenge(ctx, challenge)
+		require.NoError(t, err)
+		require.True(t, used)
+		require.NotNil(t, challenge.UsedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ok - already used challenge", func(t *testing.T) {
+		challenge := &model.TwoFactorChallenge{ID: 9, UserID: 2}
+		mock.ExpectBegin()
+		mock.ExpectExec(`^UPDATE "two_factor_challenges" SET "used_at"=\$1 WHERE id = \$2 AND used_at IS NULL`).
+			WithArgs(sqlmock.AnyArg(), challenge.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		used, err := repo.UseChallenge(ctx, challenge)
+		require.NoError(t, err)
+		require.False(t, used)
+		require.Nil(t, challenge.UsedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
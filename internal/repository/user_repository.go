@@ -154,7 +154,64 @@ func (u *userRepository) IsLoginByEmailPasswordLocked(ctx context.Context, email
 		"email": email,
 	})
 
-	key := u.newLoginByEmailPasswordAttemptsCacheKeyByEmail(email)
+	return u.isLoginLocked(logger, u.newLoginByEmailPasswordAttemptsCacheKeyByEmail(email))
+}
+
+func (u *userRepository) IncrementLoginByEmailPasswordRetryAttempts(ctx context.Context, email string) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":   utils.DumpIncomingContext(ctx),
+		"email": email,
+	})
+
+	return u.incrementLoginRetryAttempts(logger, u.newLoginByEmailPasswordAttemptsCacheKeyByEmail(email))
+}
+
+// IsLoginByPINLocked check if the user is locked from logging in using PIN on the terminal,
+// a user locked on a terminal can still log in on the other terminals
+func (u *userRepository) IsLoginByPINLocked(ctx context.Context, appID, userID int64) (bool, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"appID":  appID,
+		"userID": userID,
+	})
+
+	return u.isLoginLocked(logger, u.newLoginByPINAttemptsCacheKey(appID, userID))
+}
+
+// IncrementLoginByPINRetryAttempts increment the failed PIN login attempts of the user on the terminal
+func (u *userRepository) IncrementLoginByPINRetryAttempts(ctx context.Context, appID, userID int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"appID":  appID,
+		"userID": userID,
+	})
+
+	return u.incrementLoginRetryAttempts(logger, u.newLoginByPINAttemptsCacheKey(appID, userID))
+}
+
+// IsLoginByTwoFactorLocked check if the user is locked from answering the two factor challenges
+func (u *userRepository) IsLoginByTwoFactorLocked(ctx context.Context, userID int64) (bool, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+	})
+
+	return u.isLoginLocked(logger, u.newLoginByTwoFactorAttemptsCacheKey(userID))
+}
+
+// IncrementLoginByTwoFactorRetryAttempts increment the failed two factor attempts of the user,
+// counted across the challenges so a new login does not reset them
+func (u *userRepository) IncrementLoginByTwoFactorRetryAttempts(ctx context.Context, userID int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+	})
+
+	return u.incrementLoginRetryAttempts(logger, u.newLoginByTwoFactorAttemptsCacheKey(userID))
+}
+
+// isLoginLocked check if the login attempts of the key reach the retry attempts before the lock ttl is over
+func (u *userRepository) isLoginLocked(logger *logrus.Entry, key string) (bool, error) {
 	ttl, err := u.cacheManager.GetTTL(key)
 	if err != nil {
 		logger.Error(err)
@@ -175,13 +232,7 @@ func (u *userRepository) IsLoginByEmailPasswordLocked(ctx context.Context, email
 	return false, nil
 }
 
-func (u *userRepository) IncrementLoginByEmailPasswordRetryAttempts(ctx context.Context, email string) error {
-	logger := logrus.WithFields(logrus.Fields{
-		"ctx":   utils.DumpIncomingContext(ctx),
-		"email": email,
-	})
-
-	key := u.newLoginByEmailPasswordAttemptsCacheKeyByEmail(email)
+func (u *userRepository) incrementLoginRetryAttempts(logger *logrus.Entry, key string) error {
 	if err := u.cacheManager.IncreaseCachedValueByOne(key); err != nil {
 		logger.Error(err)
 		return err
@@ -234,6 +285,71 @@ func (u *userRepository) FindPasswordByID(ctx context.Context, id int64) ([]byte
 	}
 }
 
+// FindPINByID find the hashed PIN of the user, return nil when the user has no PIN
+func (u *userRepository) FindPINByID(ctx context.Context, id int64) ([]byte, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	cacheKey := u.newPINCacheKeyByID(id)
+	if !config.DisableCaching() {
+		reply, mu, err := u.findStringValueFromCacheByKey(cacheKey)
+		defer cacher.SafeUnlock(mu)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		if mu == nil {
+			if reply == "" {
+				return nil, nil
+			}
+			return []byte(reply), nil
+		}
+	}
+
+	var pin string
+	err := u.db.WithContext(ctx).Model(model.User{}).Select("COALESCE(pin, '')").Take(&pin, "id = ?", id).Error
+	switch err {
+	case nil:
+		err := u.cacheManager.StoreWithoutBlocking(cacher.NewItem(cacheKey, pin))
+		if err != nil {
+			logger.Error(err)
+		}
+
+		if pin == "" {
+			return nil, nil
+		}
+		return []byte(pin), nil
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	default:
+		logger.Error(err)
+		return nil, err
+	}
+}
+
+// UpdatePINByID update the hashed PIN of the user
+func (u *userRepository) UpdatePINByID(ctx context.Context, userID int64, pin string) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": userID,
+	})
+
+	err := u.db.WithContext(ctx).Model(model.User{}).Where("id = ?", userID).Update("pin", pin).Error
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := u.cacheManager.DeleteByKeys([]string{u.newPINCacheKeyByID(userID)}); err != nil {
+		logger.Error(err)
+	}
+
+	return nil
+}
+
 func (u *userRepository) UpdatePasswordByID(ctx context.Context, userID int64, password string) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":    utils.DumpIncomingContext(ctx),
@@ -260,11 +376,46 @@ func (u *userRepository) UpdatePasswordByID(ctx context.Context, userID int64, p
 	return nil
 }
 
+// SearchByPage find all user ids ordered by name
+func (u *userRepository) SearchByPage(ctx context.Context, criteria model.UserSearchCriteria) (ids []int64, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":      utils.DumpIncomingContext(ctx),
+		"criteria": utils.Dump(criteria),
+	})
+
+	err = u.db.WithContext(ctx).
+		Model(model.User{}).
+		Scopes(scopesByUserSearchCriteria(criteria)...).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	if count <= 0 {
+		return nil, 0, nil
+	}
+
+	err = u.db.WithContext(ctx).
+		Model(model.User{}).
+		Scopes(scopesByUserSearchCriteria(criteria)...).
+		Scopes(scopeByPageAndLimit(criteria.Page, criteria.Size)).
+		Order("name ASC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	return ids, count, nil
+}
+
 func (u *userRepository) deleteCache(user *model.User) error {
 	return u.cacheManager.DeleteByKeys([]string{
 		u.newCacheKeyByID(user.ID),
 		u.newCacheKeyByEmail(user.Email),
 		u.newPasswordCacheKeyByID(user.ID),
+		u.newPINCacheKeyByID(user.ID),
 	})
 }
 
@@ -280,6 +431,18 @@ func (u *userRepository) newPasswordCacheKeyByID(id int64) string {
 	return fmt.Sprintf("cache:password:id:%d", id)
 }
 
+func (u *userRepository) newPINCacheKeyByID(id int64) string {
+	return fmt.Sprintf("cache:pin:id:%d", id)
+}
+
+func (u *userRepository) newLoginByPINAttemptsCacheKey(appID, userID int64) string {
+	return fmt.Sprintf("cache:login_attempts:pin:app_id:%d:user_id:%d", appID, userID)
+}
+
+func (u *userRepository) newLoginByTwoFactorAttemptsCacheKey(userID int64) string {
+	return fmt.Sprintf("cache:login_attempts:two_factor:user_id:%d", userID)
+}
+
 func (u *userRepository) newLoginByEmailPasswordAttemptsCacheKeyByEmail(email string) string {
 	return fmt.Sprintf("cache:login_attempts:email_password:user_email:%s", email)
 }
@@ -350,3 +513,26 @@ func (u *userRepository) findStringValueFromCacheByKey(key string) (reply string
 	reply = string(bt)
 	return
 }
+
+func scopesByUserSearchCriteria(criteria model.UserSearchCriteria) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if criteria.Query != "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("name ILIKE ? OR email ILIKE ?", "%"+criteria.Query+"%", "%"+criteria.Query+"%")
+		})
+	}
+
+	if criteria.Role != "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("role = ?", criteria.Role)
+		})
+	}
+
+	if criteria.Status != "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("status = ?", criteria.Status)
+		})
+	}
+
+	return scopes
+}
@@ -0,0 +1,73 @@
+// Package spreadsheet read & write the rows of the CSV and XLSX files,
+// the XLSX files are handled with the standard library so only the first sheet and the cell values are supported
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"io"
+)
+
+// content types of the written files
+const (
+	CSVContentType  = "text/csv; charset=utf-8"
+	XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ContentType the content type of the format
+func ContentType(format model.ProductFileFormat) string {
+	if format == model.ProductFileFormatXLSX {
+		return XLSXContentType
+	}
+
+	return CSVContentType
+}
+
+// ReadAll read every row of the file, the rows of an XLSX file are read from its first sheet
+// and the missing cells are read as empty values
+func ReadAll(r io.ReaderAt, size int64, format model.ProductFileFormat) ([][]string, error) {
+	switch format {
+	case model.ProductFileFormatCSV:
+		reader := csv.NewReader(io.NewSectionReader(r, 0, size))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case model.ProductFileFormatXLSX:
+		return readXLSX(r, size)
+	default:
+		return nil, model.ErrUnknownProductFileFormat
+	}
+}
+
+// Writer write the rows one by one, so a large file is never held in memory.
+// Close must be called after the last row to complete the file.
+type Writer interface {
+	Write(record []string) error
+	Close() error
+}
+
+// NewWriter create the writer of the format, every cell of an XLSX file is written as text
+// so the codes like the barcodes keep their leading zeros
+func NewWriter(w io.Writer, format model.ProductFileFormat) (Writer, error) {
+	switch format {
+	case model.ProductFileFormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case model.ProductFileFormatXLSX:
+		return newXLSXWriter(w), nil
+	default:
+		return nil, model.ErrUnknownProductFileFormat
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(record []string) error {
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,87 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndReadAll(t *testing.T) {
+	records := [][]string{
+		{"name", "sku", "barcode", "price"},
+		{"Pisang Goreng", "PISANG-GORENG", "0012345678905", "5000"},
+		{"Es Teh <Manis> & \"Dingin\"", "", "", "3000"},
+	}
+
+	for _, format := range []model.ProductFileFormat{model.ProductFileFormatCSV, model.ProductFileFormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w, err := NewWriter(buf, format)
+			require.NoError(t, err)
+			for _, record := range records {
+				require.NoError(t, w.Write(record))
+			}
+			require.NoError(t, w.Close())
+
+			got, err := ReadAll(bytes.NewReader(buf.Bytes()), int64(buf.Len()), format)
+			require.NoError(t, err)
+			require.Equal(t, records, got)
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	t.Run("xlsx with shared strings, sparse cells & numbers", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		parts := map[string]string{
+			"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+				`<sheets><sheet name="Produk" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				`<Relationship Id="rId3" Target="worksheets/produk.xml"/></Relationships>`,
+			"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+				`<si><t>name</t></si><si><t>price</t></si><si><r><t>Kopi </t></r><r><t>Susu</t></r></si></sst>`,
+			"xl/worksheets/produk.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+				`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+				`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" t="b"><v>1</v></c><c r="C3"><v>8.991234567891E+12</v></c></row>` +
+				`</sheetData></worksheet>`,
+		}
+		for name, content := range parts {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = io.WriteString(w, content)
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+
+		got, err := ReadAll(bytes.NewReader(buf.Bytes()), int64(buf.Len()), model.ProductFileFormatXLSX)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"name", "", "price"},
+			nil,
+			{"Kopi Susu", "1", "8991234567891"},
+		}, got)
+	})
+
+	t.Run("not a workbook", func(t *testing.T) {
+		content := []byte("name,price\nKopi,5000\n")
+		_, err := ReadAll(bytes.NewReader(content), int64(len(content)), model.ProductFileFormatXLSX)
+		require.ErrorIs(t, err, ErrInvalidXLSX)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := ReadAll(bytes.NewReader(nil), 0, "ODS")
+		require.ErrorIs(t, err, model.ErrUnknownProductFileFormat)
+	})
+}
+
+func TestColumnName(t *testing.T) {
+	for col, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		require.Equal(t, name, columnName(col))
+		require.Equal(t, col, columnIndex(name+"12"))
+	}
+}
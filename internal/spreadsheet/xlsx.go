@@ -0,0 +1,331 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidXLSX error when the file is not a workbook or has no sheet
+var ErrInvalidXLSX = errors.New("invalid xlsx file")
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText a plain or a rich text, the text of a rich text is split into runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) <= 0 {
+		return t.T
+	}
+
+	var sb strings.Builder
+	for _, run := range t.Runs {
+		sb.WriteString(run.T)
+	}
+
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string    `xml:"r,attr"`
+			T      string    `xml:"t,attr"`
+			V      string    `xml:"v"`
+			Inline *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidXLSX
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXMLFile(f, &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrInvalidXLSX
+	}
+
+	var sheet xlsxSheet
+	if err := decodeXMLFile(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	for _, row := range sheet.Rows {
+		// the empty rows are usually left out of the sheet, keep them so the row numbers stay the same
+		for row.R > len(records)+1 {
+			records = append(records, nil)
+		}
+
+		var record []string
+		for _, cell := range row.Cells {
+			col := len(record)
+			if cell.R != "" {
+				col = columnIndex(cell.R)
+			}
+			for len(record) < col {
+				record = append(record, "")
+			}
+
+			val, err := cellValue(cell.T, cell.V, cell.Inline, sharedStrings)
+			if err != nil {
+				return nil, err
+			}
+			record = append(record, val)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// firstSheetPath find the first sheet of the workbook through the workbook relationships
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrInvalidXLSX
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXMLFile(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) <= 0 {
+		return "", ErrInvalidXLSX
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+
+	var rels xlsxRelationships
+	if err := decodeXMLFile(relsFile, &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", ErrInvalidXLSX
+}
+
+func cellValue(cellType, value string, inline *xlsxText, sharedStrings xlsxSharedStrings) (string, error) {
+	switch cellType {
+	case "s":
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 || i >= len(sharedStrings.Items) {
+			return "", ErrInvalidXLSX
+		}
+		return sharedStrings.Items[i].String(), nil
+	case "inlineStr":
+		if inline == nil {
+			return "", nil
+		}
+		return inline.String(), nil
+	case "", "n":
+		// a large number such as a barcode may be stored in the scientific notation
+		if strings.ContainsAny(value, "eE") {
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				return strconv.FormatFloat(f, 'f', -1, 64), nil
+			}
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// columnIndex the zero based column of the cell reference, e.g. 2 for C7
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A') + 1
+	}
+
+	return col - 1
+}
+
+// columnName the letters of the zero based column, e.g. AA for 26
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+
+	return name
+}
+
+func decodeXMLFile(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return ErrInvalidXLSX
+	}
+
+	return nil
+}
+
+// the static parts of the written workbook, it has a single sheet
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+// xlsxWriter stream the rows into the sheet, which is the last part of the zip so it can be written until Close
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+	err   error
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	x := &xlsxWriter{zw: zip.NewWriter(w)}
+
+	for _, part := range xlsxStaticParts {
+		if x.err = x.writePart(part.name, part.content); x.err != nil {
+			return x
+		}
+	}
+
+	x.sheet, x.err = x.zw.Create("xl/worksheets/sheet1.xml")
+	if x.err != nil {
+		return x
+	}
+
+	_, x.err = io.WriteString(x.sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x
+}
+
+func (x *xlsxWriter) writePart(name, content string) error {
+	w, err := x.zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+func (x *xlsxWriter) Write(record []string) error {
+	if x.err != nil {
+		return x.err
+	}
+
+	x.row++
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<row r="%d">`, x.row)
+	for col, val := range record {
+		fmt.Fprintf(&sb, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(col), x.row)
+		if x.err = xml.EscapeText(&sb, []byte(val)); x.err != nil {
+			return x.err
+		}
+		sb.WriteString(`</t></is></c>`)
+	}
+	sb.WriteString(`</row>`)
+
+	_, x.err = io.WriteString(x.sheet, sb.String())
+	return x.err
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+
+	return x.zw.Close()
+}
@@ -0,0 +1,80 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHOTP(t *testing.T) {
+	// the SHA1 test vectors of RFC 6238 appendix B
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "94287082"},
+		{unix: 1111111109, code: "07081804"},
+		{unix: 1111111111, code: "14050471"},
+		{unix: 1234567890, code: "89005924"},
+		{unix: 2000000000, code: "69279037"},
+		{unix: 20000000000, code: "65353130"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.code, hotp(key, uint64(Step(time.Unix(tt.unix, 0))), 8))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	t.Run("ok", func(t *testing.T) {
+		step, ok, err := Validate(secret, "050471", now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, Step(now), step)
+	})
+
+	t.Run("ok - previous step within the skew", func(t *testing.T) {
+		code, err := Code(secret, Step(now)-1)
+		require.NoError(t, err)
+
+		step, ok, err := Validate(secret, code, now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, Step(now)-1, step)
+	})
+
+	t.Run("failed - outside the skew", func(t *testing.T) {
+		code, err := Code(secret, Step(now)-2)
+		require.NoError(t, err)
+
+		_, ok, err := Validate(secret, code, now)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("failed - invalid secret", func(t *testing.T) {
+		_, _, err := Validate("not base32!", "050471", now)
+		require.ErrorIs(t, err, ErrInvalidSecret)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	code, err := Code(secret, Step(time.Now()))
+	require.NoError(t, err)
+	require.Len(t, code, Digits)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Go Point of Sales", "budi@mail.com", "JBSWY3DPEHPK3PXP")
+	require.Equal(t, "otpauth://totp/Go%20Point%20of%20Sales:budi@mail.com?secret=JBSWY3DPEHPK3PXP&issuer=Go%20Point%20of%20Sales&algorithm=SHA1&digits=6&period=30", uri)
+}
@@ -2,36 +2,59 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/irvankadhafi/go-point-of-sales/internal/config"
 	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/qrcode"
+	"github.com/irvankadhafi/go-point-of-sales/internal/totp"
 	"github.com/irvankadhafi/go-point-of-sales/rbac"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/sirupsen/logrus"
+	"strings"
 	"time"
 )
 
+const (
+	// twoFactorChallengeTokenSize the random bytes of a two factor challenge token
+	twoFactorChallengeTokenSize = 32
+	// recoveryCodeCount the recovery codes given to the user, recoveryCodeSize random bytes each
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+	// qrCodeScale the pixels of a module of the enrollment QR code
+	qrCodeScale = 8
+)
+
 type authUsecase struct {
-	userRepo    model.UserRepository
-	sessionRepo model.SessionRepository
-	rbacRepo    model.RBACRepository
+	userRepo      model.UserRepository
+	sessionRepo   model.SessionRepository
+	rbacRepo      model.RBACRepository
+	appClientRepo model.AppClientRepository
+	twoFactorRepo model.TwoFactorRepository
 }
 
 func NewAuthUsecase(
 	userRepo model.UserRepository,
 	sessionRepo model.SessionRepository,
 	rbacRepo model.RBACRepository,
+	appClientRepo model.AppClientRepository,
+	twoFactorRepo model.TwoFactorRepository,
 ) model.AuthUsecase {
 	return &authUsecase{
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		rbacRepo:    rbacRepo,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		rbacRepo:      rbacRepo,
+		appClientRepo: appClientRepo,
+		twoFactorRepo: twoFactorRepo,
 	}
 }
 
-func (a *authUsecase) LoginByEmailPassword(ctx context.Context, req model.LoginRequest) (*model.Session, error) {
+// LoginByEmailPassword log in using email & password, a two factor challenge is returned instead of the session
+// when the user has two factor authentication enabled or its role requires it
+func (a *authUsecase) LoginByEmailPassword(ctx context.Context, req model.LoginRequest) (*model.Session, *model.TwoFactorChallenge, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":       utils.DumpIncomingContext(ctx),
 		"email":     req.Email,
@@ -41,42 +64,188 @@ func (a *authUsecase) LoginByEmailPassword(ctx context.Context, req model.LoginR
 	isLocked, err := a.userRepo.IsLoginByEmailPasswordLocked(ctx, req.Email)
 	if err != nil {
 		logger.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	if isLocked {
-		return nil, ErrLoginByEmailPasswordLocked
+		return nil, nil, ErrLoginByEmailPasswordLocked
 	}
 
 	user, err := a.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		logger.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 	if user == nil {
-		return nil, ErrNotFound
+		return nil, nil, ErrNotFound
 	}
 
 	cipherPass, err := a.userRepo.FindPasswordByID(ctx, user.ID)
 	if err != nil {
 		logger.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 	if cipherPass == nil {
 		logger.Error(err)
-		return nil, errors.New("unexpected: no password found")
+		return nil, nil, errors.New("unexpected: no password found")
 	}
 	if !helper.IsHashedStringMatch([]byte(req.PlainPassword), cipherPass) {
 		// obscure the error if the password does not match
 		if err := a.userRepo.IncrementLoginByEmailPasswordRetryAttempts(ctx, req.Email); err != nil {
 			logger.Error(err)
+			return nil, nil, err
+		}
+
+		return nil, nil, ErrUnauthorized
+	}
+
+	logger = logger.WithField("userID", user.ID)
+
+	// checked after the password so the status is only told to the owner of the user
+	if err := user.Status.Err(); err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	challenge, err := a.createTwoFactorChallenge(ctx, user, req)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+	if challenge != nil {
+		return nil, challenge, nil
+	}
+
+	session, err := a.createSession(ctx, user, req.AppID, req.UserAgent, req.IPAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, nil, nil
+}
+
+// LoginByPIN log in the user on the POS terminal using PIN
+func (a *authUsecase) LoginByPIN(ctx context.Context, req model.PINLoginRequest) (*model.Session, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"appID":     req.AppID,
+		"userID":    req.UserID,
+		"userAgent": req.UserAgent,
+	})
+
+	user, err := a.authenticatePIN(ctx, req)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return a.createSession(ctx, user, req.AppID, req.UserAgent, req.IPAddress)
+}
+
+// SwitchOperator replace the session of the requester with a session of the user on the same terminal
+func (a *authUsecase) SwitchOperator(ctx context.Context, requester *model.User, req model.PINLoginRequest) (*model.Session, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"userID":    req.UserID,
+		"userAgent": req.UserAgent,
+	})
+
+	currentSession, err := a.sessionRepo.FindByID(ctx, requester.SessionID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if currentSession == nil {
+		return nil, ErrNotFound
+	}
+
+	// the terminal is taken from the current session, so the terminal does not need its client secret again
+	req.AppID = currentSession.AppID
+	user, err := a.authenticatePIN(ctx, req)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	session, err := a.createSession(ctx, user, currentSession.AppID, req.UserAgent, req.IPAddress)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := a.sessionRepo.Delete(ctx, currentSession); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// authenticatePIN check the PIN of the user on the POS terminal, the failed attempts lock the user
+// from logging in using PIN on the terminal
+func (a *authUsecase) authenticatePIN(ctx context.Context, req model.PINLoginRequest) (*model.User, error) {
+	client, err := a.appClientRepo.FindByID(ctx, req.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || !client.IsPOSTerminal {
+		return nil, ErrNotPOSTerminal
+	}
+
+	isLocked, err := a.userRepo.IsLoginByPINLocked(ctx, req.AppID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if isLocked {
+		return nil, ErrLoginByPINLocked
+	}
+
+	user, err := a.userRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotFound
+	}
+
+	cipherPIN, err := a.userRepo.FindPINByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// obscure whether the user has no PIN or the PIN does not match
+	if cipherPIN == nil || !helper.IsHashedStringMatch([]byte(req.PlainPIN), cipherPIN) {
+		if err := a.userRepo.IncrementLoginByPINRetryAttempts(ctx, req.AppID, req.UserID); err != nil {
 			return nil, err
 		}
 
 		return nil, ErrUnauthorized
 	}
 
-	logger = logger.WithField("userID", user.ID)
+	if err := user.Status.Err(); err != nil {
+		return nil, err
+	}
+
+	// the PIN is a single factor, the users with two factor authentication have to log in using email & password
+	twoFactor, err := a.twoFactorRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.IsEnabled() || isTwoFactorRequired(user.Role) {
+		return nil, model.ErrTwoFactorRequired
+	}
+
+	return user, nil
+}
+
+// createSession create the session of the user on the app client, the oldest sessions
+// above the max active sessions are deleted
+func (a *authUsecase) createSession(ctx context.Context, user *model.User, appID int64, userAgent, ipAddress string) (*model.Session, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":    utils.DumpIncomingContext(ctx),
+		"userID": user.ID,
+		"appID":  appID,
+	})
 
 	now := time.Now()
 	session := &model.Session{
@@ -84,10 +253,11 @@ func (a *authUsecase) LoginByEmailPassword(ctx context.Context, req model.LoginR
 		UserID:                user.ID,
 		AccessTokenExpiredAt:  now.Add(config.AccessTokenDuration()),
 		RefreshTokenExpiredAt: now.Add(config.RefreshTokenDuration()),
-		AppID:                 req.AppID,
-		UserAgent:             req.UserAgent,
-		IPAddress:             req.IPAddress,
+		AppID:                 appID,
+		UserAgent:             userAgent,
+		IPAddress:             ipAddress,
 		Role:                  user.Role,
+		UserStatus:            user.Status,
 	}
 
 	accessToken, err := generateToken(user.ID, user.Role, session.AccessTokenExpiredAt)
@@ -145,6 +315,11 @@ func (a *authUsecase) AuthenticateToken(ctx context.Context, accessToken string)
 		return nil, ErrNotFound
 	}
 
+	if err := user.Status.Err(); err != nil {
+		logrus.WithField("userID", session.UserID).Error(err)
+		return nil, err
+	}
+
 	user.SessionID = session.ID
 	user.SetPermission(perm)
 
@@ -187,6 +362,11 @@ func (a *authUsecase) RefreshToken(ctx context.Context, req model.RefreshTokenRe
 		return nil, ErrNotFound
 	}
 
+	if err := user.Status.Err(); err != nil {
+		logger.WithField("userID", session.UserID).Error(err)
+		return nil, err
+	}
+
 	// old session is used to delete the old session cache
 	oldSess := *session
 
@@ -248,6 +428,429 @@ func (a *authUsecase) DeleteSessionByID(ctx context.Context, sessionID int64) er
 	return err
 }
 
+// EnrollTwoFactor start the two factor enrollment of the requester, a pending enrollment is replaced by a new secret
+func (a *authUsecase) EnrollTwoFactor(ctx context.Context, requester *model.User) (*model.TwoFactorEnrollment, error) {
+	enrollment, err := a.startTwoFactorEnrollment(ctx, requester)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":       utils.DumpIncomingContext(ctx),
+			"requester": utils.Dump(requester),
+		}).Error(err)
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// ConfirmTwoFactor enable the pending two factor of the requester with the first code of the authenticator app,
+// the recovery codes are only returned here
+func (a *authUsecase) ConfirmTwoFactor(ctx context.Context, requester *model.User, input model.TwoFactorCodeInput) ([]string, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	twoFactor, err := a.twoFactorRepo.FindByUserID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, model.ErrTwoFactorNotEnrolled
+	}
+	if twoFactor.IsEnabled() {
+		return nil, model.ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := a.verifyTwoFactorCode(ctx, twoFactor, input.Code, ""); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	recoveryCodes, err := a.enableTwoFactor(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTwoFactor turn off the two factor of the requester, not allowed when the role requires it
+func (a *authUsecase) DisableTwoFactor(ctx context.Context, requester *model.User, input model.DisableTwoFactorInput) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if isTwoFactorRequired(requester.Role) {
+		return model.ErrTwoFactorRequired
+	}
+
+	cipherPwd, err := a.userRepo.FindPasswordByID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	if cipherPwd == nil {
+		return ErrNotFound
+	}
+	if !helper.IsHashedStringMatch([]byte(input.Password), cipherPwd) {
+		return ErrPasswordMismatch
+	}
+
+	twoFactor, err := a.twoFactorRepo.FindByUserID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	if !twoFactor.IsEnabled() {
+		return model.ErrTwoFactorNotEnabled
+	}
+
+	if err := a.verifyTwoFactorCode(ctx, twoFactor, input.Code, input.RecoveryCode); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := a.twoFactorRepo.DeleteByUserID(ctx, requester.ID); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replace the recovery codes of the requester, e.g. when most of them are used
+func (a *authUsecase) RegenerateRecoveryCodes(ctx context.Context, requester *model.User, input model.TwoFactorCodeInput) ([]string, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	twoFactor, err := a.twoFactorRepo.FindByUserID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !twoFactor.IsEnabled() {
+		return nil, model.ErrTwoFactorNotEnabled
+	}
+
+	if err := a.verifyTwoFactorCode(ctx, twoFactor, input.Code, ""); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	recoveryCodes, recoveryCodeHashes, err := newRecoveryCodes()
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := a.twoFactorRepo.ReplaceRecoveryCodes(ctx, requester.ID, recoveryCodeHashes); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// EnrollTwoFactorByChallenge start the enrollment of the user logging in whose role requires two factor authentication
+func (a *authUsecase) EnrollTwoFactorByChallenge(ctx context.Context, challengeToken string) (*model.TwoFactorEnrollment, error) {
+	logger := logrus.WithField("ctx", utils.DumpIncomingContext(ctx))
+
+	challenge, err := a.findTwoFactorChallenge(ctx, challengeToken)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !challenge.Enrollment {
+		return nil, model.ErrTwoFactorAlreadyEnabled
+	}
+
+	logger = logger.WithField("userID", challenge.UserID)
+	user, err := a.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotFound
+	}
+
+	enrollment, err := a.startTwoFactorEnrollment(ctx, user)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// VerifyTwoFactor answer the challenge of the login with a code or a recovery code and create the session,
+// an enrollment challenge also enables the two factor and return the recovery codes
+func (a *authUsecase) VerifyTwoFactor(ctx context.Context, req model.VerifyTwoFactorRequest) (*model.Session, []string, error) {
+	logger := logrus.WithField("ctx", utils.DumpIncomingContext(ctx))
+
+	challenge, err := a.findTwoFactorChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	logger = logger.WithField("userID", challenge.UserID)
+	user, err := a.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrNotFound
+	}
+
+	if err := user.Status.Err(); err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	twoFactor, err := a.twoFactorRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+	switch {
+	case twoFactor == nil && challenge.Enrollment:
+		return nil, nil, model.ErrTwoFactorNotEnrolled
+	case !twoFactor.IsEnabled() && !challenge.Enrollment:
+		// the two factor is disabled after the login
+		return nil, nil, model.ErrInvalidTwoFactorChallenge
+	}
+
+	if err := a.verifyTwoFactorCode(ctx, twoFactor, req.Code, req.RecoveryCode); err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	used, err := a.twoFactorRepo.UseChallenge(ctx, challenge)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+	if !used {
+		return nil, nil, model.ErrInvalidTwoFactorChallenge
+	}
+
+	var recoveryCodes []string
+	if !twoFactor.IsEnabled() {
+		recoveryCodes, err = a.enableTwoFactor(ctx, user.ID)
+		if err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+	}
+
+	session, err := a.createSession(ctx, user, challenge.AppID, challenge.UserAgent, challenge.IPAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, recoveryCodes, nil
+}
+
+// createTwoFactorChallenge create the challenge of the login, return nil when the user can log in with the password only
+func (a *authUsecase) createTwoFactorChallenge(ctx context.Context, user *model.User, req model.LoginRequest) (*model.TwoFactorChallenge, error) {
+	twoFactor, err := a.twoFactorRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := twoFactor.IsEnabled()
+	if !enabled && !isTwoFactorRequired(user.Role) {
+		return nil, nil
+	}
+
+	token, err := helper.GenerateRandomToken(twoFactorChallengeTokenSize)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &model.TwoFactorChallenge{
+		ID:         utils.GenerateID(),
+		UserID:     user.ID,
+		AppID:      req.AppID,
+		TokenHash:  helper.HashToken(token),
+		Token:      token,
+		Enrollment: !enabled,
+		UserAgent:  req.UserAgent,
+		IPAddress:  req.IPAddress,
+		ExpiredAt:  time.Now().Add(config.TwoFactorChallengeDuration()),
+	}
+	if err := a.twoFactorRepo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// findTwoFactorChallenge find the challenge of the token, the unknown, expired & used challenges are invalid
+func (a *authUsecase) findTwoFactorChallenge(ctx context.Context, token string) (*model.TwoFactorChallenge, error) {
+	if token == "" {
+		return nil, model.ErrInvalidTwoFactorChallenge
+	}
+
+	challenge, err := a.twoFactorRepo.FindChallengeByTokenHash(ctx, helper.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || challenge.IsExpired() || challenge.UsedAt != nil {
+		return nil, model.ErrInvalidTwoFactorChallenge
+	}
+
+	return challenge, nil
+}
+
+// startTwoFactorEnrollment generate a new secret for the user along with its otpauth URI & QR code
+func (a *authUsecase) startTwoFactorEnrollment(ctx context.Context, user *model.User) (*model.TwoFactorEnrollment, error) {
+	twoFactor, err := a.twoFactorRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.IsEnabled() {
+		return nil, model.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.twoFactorRepo.SavePending(ctx, &model.UserTwoFactor{UserID: user.ID, Secret: secret}); err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(config.TwoFactorIssuer(), user.Email, secret)
+	code, err := qrcode.Encode(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, err := code.PNG(qrCodeScale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    uri,
+		QRCode: qrCode,
+	}, nil
+}
+
+// verifyTwoFactorCode check the code of the authenticator app or else the recovery code, the recovery codes
+// are only accepted once the two factor is enabled. The failed attempts lock the user from verifying the codes.
+func (a *authUsecase) verifyTwoFactorCode(ctx context.Context, twoFactor *model.UserTwoFactor, code, recoveryCode string) error {
+	isLocked, err := a.userRepo.IsLoginByTwoFactorLocked(ctx, twoFactor.UserID)
+	if err != nil {
+		return err
+	}
+	if isLocked {
+		return ErrLoginByTwoFactorLocked
+	}
+
+	var ok bool
+	switch {
+	case code != "":
+		step, valid, err := totp.Validate(twoFactor.Secret, code, time.Now())
+		if err != nil {
+			return err
+		}
+		if valid {
+			// a code already used within its time step is rejected
+			ok, err = a.twoFactorRepo.UseStep(ctx, twoFactor.UserID, step)
+			if err != nil {
+				return err
+			}
+		}
+	case recoveryCode != "" && twoFactor.IsEnabled():
+		ok, err = a.twoFactorRepo.UseRecoveryCode(ctx, twoFactor.UserID, helper.HashToken(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+	}
+
+	if !ok {
+		if err := a.userRepo.IncrementLoginByTwoFactorRetryAttempts(ctx, twoFactor.UserID); err != nil {
+			return err
+		}
+
+		return model.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// enableTwoFactor enable the two factor of the user with new recovery codes
+func (a *authUsecase) enableTwoFactor(ctx context.Context, userID int64) ([]string, error) {
+	recoveryCodes, recoveryCodeHashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.twoFactorRepo.Enable(ctx, userID, recoveryCodeHashes); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// isTwoFactorRequired check if the role must log in with two factor authentication
+func isTwoFactorRequired(role rbac.Role) bool {
+	for _, r := range config.TwoFactorRequiredRoles() {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newRecoveryCodes generate the recovery codes formatted as XXXX-XXXX along with their hashes
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := base32.StdEncoding.EncodeToString(b)
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, helper.HashToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode upper case the recovery code and remove the dash & spaces typed along with it
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToUpper(code))
+}
+
 // generateToken and check uniqueness
 func generateToken(userID int64, role rbac.Role, expTime time.Time) (string, error) {
 	rawToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -0,0 +1,481 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/totp"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthUsecase_LoginByEmailPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	mockTwoFactorRepo := mock.NewMockTwoFactorRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, twoFactorRepo: mockTwoFactorRepo}
+	req := model.LoginRequest{AppID: 7, Email: "budi@mail.com", PlainPassword: "123456"}
+	viper.Set("secret_key", "test-secret-key")
+
+	cipherPwd, err := helper.HashString(req.PlainPassword)
+	require.NoError(t, err)
+
+	expectLogin := func(user *model.User) {
+		mockUserRepo.EXPECT().IsLoginByEmailPasswordLocked(ctx, req.Email).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByEmail(ctx, req.Email).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().FindPasswordByID(ctx, user.ID).Times(1).Return([]byte(cipherPwd), nil)
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		user := &model.User{ID: 2, Email: req.Email, Role: rbac.RoleCashiers, Status: model.StatusActive}
+		expectLogin(user)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, user.ID).Times(1).Return(nil, nil)
+		mockSessionRepo.EXPECT().Create(ctx, gomock.Any()).Times(1).Return(nil)
+		mockSessionRepo.EXPECT().DeleteByUserIDAndMaxRemainderSession(ctx, user.ID, gomock.Any()).Times(1).Return(nil)
+
+		session, challenge, err := ucase.LoginByEmailPassword(ctx, req)
+		require.NoError(t, err)
+		require.Nil(t, challenge)
+		require.Equal(t, user.ID, session.UserID)
+	})
+
+	t.Run("ok - challenge when two factor is enabled", func(t *testing.T) {
+		user := &model.User{ID: 2, Email: req.Email, Role: rbac.RoleFinancialAuditor, Status: model.StatusActive}
+		enabledAt := time.Now()
+		expectLogin(user)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, user.ID).Times(1).Return(&model.UserTwoFactor{UserID: user.ID, EnabledAt: &enabledAt}, nil)
+		mockTwoFactorRepo.EXPECT().CreateChallenge(ctx, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, challenge *model.TwoFactorChallenge) error {
+			require.Equal(t, helper.HashToken(challenge.Token), challenge.TokenHash)
+			require.Equal(t, req.AppID, challenge.AppID)
+			return nil
+		})
+
+		session, challenge, err := ucase.LoginByEmailPassword(ctx, req)
+		require.NoError(t, err)
+		require.Nil(t, session)
+		require.False(t, challenge.Enrollment)
+		require.NotEmpty(t, challenge.Token)
+	})
+
+	t.Run("ok - enrollment challenge when the role requires two factor", func(t *testing.T) {
+		viper.Set("two_factor.required_roles", []string{"admin"})
+		defer viper.Set("two_factor.required_roles", nil)
+
+		user := &model.User{ID: 2, Email: req.Email, Role: rbac.RoleAdmin, Status: model.StatusActive}
+		expectLogin(user)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, user.ID).Times(1).Return(nil, nil)
+		mockTwoFactorRepo.EXPECT().CreateChallenge(ctx, gomock.Any()).Times(1).Return(nil)
+
+		session, challenge, err := ucase.LoginByEmailPassword(ctx, req)
+		require.NoError(t, err)
+		require.Nil(t, session)
+		require.True(t, challenge.Enrollment)
+	})
+
+	t.Run("failed - pending user", func(t *testing.T) {
+		expectLogin(&model.User{ID: 3, Email: req.Email, Status: model.StatusPending})
+
+		_, _, err := ucase.LoginByEmailPassword(ctx, req)
+		require.ErrorIs(t, err, model.ErrUserPending)
+	})
+
+	t.Run("failed - inactive user", func(t *testing.T) {
+		expectLogin(&model.User{ID: 4, Email: req.Email, Status: model.StatusInactive})
+
+		_, _, err := ucase.LoginByEmailPassword(ctx, req)
+		require.ErrorIs(t, err, model.ErrUserInactive)
+	})
+
+	t.Run("failed - wrong password does not tell the status", func(t *testing.T) {
+		user := &model.User{ID: 4, Email: req.Email, Status: model.StatusInactive}
+		mockUserRepo.EXPECT().IsLoginByEmailPasswordLocked(ctx, req.Email).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByEmail(ctx, req.Email).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().FindPasswordByID(ctx, user.ID).Times(1).Return([]byte(cipherPwd), nil)
+		mockUserRepo.EXPECT().IncrementLoginByEmailPasswordRetryAttempts(ctx, req.Email).Times(1).Return(nil)
+
+		_, _, err := ucase.LoginByEmailPassword(ctx, model.LoginRequest{Email: req.Email, PlainPassword: "wrong-password"})
+		require.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestAuthUsecase_AuthenticateToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	mockRBACRepo := mock.NewMockRBACRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, rbacRepo: mockRBACRepo}
+	session := &model.Session{ID: 11, UserID: 2, AccessToken: "access-token", AccessTokenExpiredAt: time.Now().Add(time.Hour)}
+
+	t.Run("ok", func(t *testing.T) {
+		mockSessionRepo.EXPECT().FindByToken(ctx, model.AccessToken, session.AccessToken).Times(1).Return(session, nil)
+		mockRBACRepo.EXPECT().LoadPermission(ctx).Times(1).Return(rbac.NewPermission(), nil)
+		mockUserRepo.EXPECT().FindByID(ctx, session.UserID).Times(1).Return(&model.User{ID: 2, Status: model.StatusActive}, nil)
+
+		user, err := ucase.AuthenticateToken(ctx, session.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, session.ID, user.SessionID)
+	})
+
+	t.Run("failed - inactive user", func(t *testing.T) {
+		mockSessionRepo.EXPECT().FindByToken(ctx, model.AccessToken, session.AccessToken).Times(1).Return(session, nil)
+		mockRBACRepo.EXPECT().LoadPermission(ctx).Times(1).Return(rbac.NewPermission(), nil)
+		mockUserRepo.EXPECT().FindByID(ctx, session.UserID).Times(1).Return(&model.User{ID: 2, Status: model.StatusInactive}, nil)
+
+		_, err := ucase.AuthenticateToken(ctx, session.AccessToken)
+		require.ErrorIs(t, err, model.ErrUserInactive)
+	})
+}
+
+func TestAuthUsecase_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo}
+	session := &model.Session{ID: 11, UserID: 2, RefreshToken: "refresh-token", RefreshTokenExpiredAt: time.Now().Add(time.Hour)}
+
+	t.Run("failed - pending user", func(t *testing.T) {
+		mockSessionRepo.EXPECT().FindByToken(ctx, model.RefreshToken, session.RefreshToken).Times(1).Return(session, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, session.UserID).Times(1).Return(&model.User{ID: 2, Status: model.StatusPending}, nil)
+
+		_, err := ucase.RefreshToken(ctx, model.RefreshTokenRequest{RefreshToken: session.RefreshToken})
+		require.ErrorIs(t, err, model.ErrUserPending)
+	})
+}
+
+func TestAuthUsecase_LoginByPIN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	mockAppClientRepo := mock.NewMockAppClientRepository(ctrl)
+	mockTwoFactorRepo := mock.NewMockTwoFactorRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, appClientRepo: mockAppClientRepo, twoFactorRepo: mockTwoFactorRepo}
+	req := model.PINLoginRequest{AppID: 7, UserID: 2, PlainPIN: "1234"}
+	terminal := &model.AppClient{ID: req.AppID, IsPOSTerminal: true}
+	user := &model.User{ID: req.UserID, Role: rbac.RoleCashiers, Status: model.StatusActive}
+	viper.Set("secret_key", "test-secret-key")
+
+	cipherPIN, err := helper.HashString(req.PlainPIN)
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		mockAppClientRepo.EXPECT().FindByID(ctx, req.AppID).Times(1).Return(terminal, nil)
+		mockUserRepo.EXPECT().IsLoginByPINLocked(ctx, req.AppID, req.UserID).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, req.UserID).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().FindPINByID(ctx, req.UserID).Times(1).Return([]byte(cipherPIN), nil)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, req.UserID).Times(1).Return(nil, nil)
+		mockSessionRepo.EXPECT().Create(ctx, gomock.Any()).Times(1).Return(nil)
+		mockSessionRepo.EXPECT().DeleteByUserIDAndMaxRemainderSession(ctx, user.ID, gomock.Any()).Times(1).Return(nil)
+
+		session, err := ucase.LoginByPIN(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, session.UserID)
+		require.Equal(t, req.AppID, session.AppID)
+	})
+
+	t.Run("failed - two factor user", func(t *testing.T) {
+		enabledAt := time.Now()
+		mockAppClientRepo.EXPECT().FindByID(ctx, req.AppID).Times(1).Return(terminal, nil)
+		mockUserRepo.EXPECT().IsLoginByPINLocked(ctx, req.AppID, req.UserID).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, req.UserID).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().FindPINByID(ctx, req.UserID).Times(1).Return([]byte(cipherPIN), nil)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, req.UserID).Times(1).Return(&model.UserTwoFactor{UserID: req.UserID, EnabledAt: &enabledAt}, nil)
+
+		_, err := ucase.LoginByPIN(ctx, req)
+		require.ErrorIs(t, err, model.ErrTwoFactorRequired)
+	})
+
+	t.Run("failed - not a POS terminal", func(t *testing.T) {
+		mockAppClientRepo.EXPECT().FindByID(ctx, req.AppID).Times(1).Return(&model.AppClient{ID: req.AppID}, nil)
+
+		_, err := ucase.LoginByPIN(ctx, req)
+		require.ErrorIs(t, err, ErrNotPOSTerminal)
+	})
+
+	t.Run("failed - locked", func(t *testing.T) {
+		mockAppClientRepo.EXPECT().FindByID(ctx, req.AppID).Times(1).Return(terminal, nil)
+		mockUserRepo.EXPECT().IsLoginByPINLocked(ctx, req.AppID, req.UserID).Times(1).Return(true, nil)
+
+		_, err := ucase.LoginByPIN(ctx, req)
+		require.ErrorIs(t, err, ErrLoginByPINLocked)
+	})
+
+	t.Run("failed - wrong PIN", func(t *testing.T) {
+		mockAppClientRepo.EXPECT().FindByID(ctx, req.AppID).Times(1).Return(terminal, nil)
+		mockUserRepo.EXPECT().IsLoginByPINLocked(ctx, req.AppID, req.UserID).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, req.UserID).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().FindPINByID(ctx, req.UserID).Times(1).Return([]byte(cipherPIN), nil)
+		mockUserRepo.EXPECT().IncrementLoginByPINRetryAttempts(ctx, req.AppID, req.UserID).Times(1).Return(nil)
+
+		_, err := ucase.LoginByPIN(ctx, model.PINLoginRequest{AppID: req.AppID, UserID: req.UserID, PlainPIN: "9999"})
+		require.ErrorIs(t, err, ErrUnauthorized)
+	})
+
+	t.Run("failed - PIN not set", func(t *testing.T) {
+		mockAppClientRepo.EXPECT().FindByID(ctx, req.AppID).Times(1).Return(terminal, nil)
+		mockUserRepo.EXPECT().IsLoginByPINLocked(ctx, req.AppID, req.UserID).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, req.UserID).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().FindPINByID(ctx, req.UserID).Times(1).Return(nil, nil)
+		mockUserRepo.EXPECT().IncrementLoginByPINRetryAttempts(ctx, req.AppID, req.UserID).Times(1).Return(nil)
+
+		_, err := ucase.LoginByPIN(ctx, req)
+		require.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestAuthUsecase_SwitchOperator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	mockAppClientRepo := mock.NewMockAppClientRepository(ctrl)
+	mockTwoFactorRepo := mock.NewMockTwoFactorRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, appClientRepo: mockAppClientRepo, twoFactorRepo: mockTwoFactorRepo}
+	requester := &model.User{ID: 2, SessionID: 11, Role: rbac.RoleCashiers, Status: model.StatusActive}
+	currentSession := &model.Session{ID: requester.SessionID, UserID: requester.ID, AppID: 7}
+	nextOperator := &model.User{ID: 3, Role: rbac.RoleCashiers, Status: model.StatusActive}
+	viper.Set("secret_key", "test-secret-key")
+
+	cipherPIN, err := helper.HashString("654321")
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		mockSessionRepo.EXPECT().FindByID(ctx, requester.SessionID).Times(1).Return(currentSession, nil)
+		mockAppClientRepo.EXPECT().FindByID(ctx, currentSession.AppID).Times(1).Return(&model.AppClient{ID: currentSession.AppID, IsPOSTerminal: true}, nil)
+		mockUserRepo.EXPECT().IsLoginByPINLocked(ctx, currentSession.AppID, nextOperator.ID).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, nextOperator.ID).Times(1).Return(nextOperator, nil)
+		mockUserRepo.EXPECT().FindPINByID(ctx, nextOperator.ID).Times(1).Return([]byte(cipherPIN), nil)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, nextOperator.ID).Times(1).Return(nil, nil)
+		mockSessionRepo.EXPECT().Create(ctx, gomock.Any()).Times(1).Return(nil)
+		mockSessionRepo.EXPECT().DeleteByUserIDAndMaxRemainderSession(ctx, nextOperator.ID, gomock.Any()).Times(1).Return(nil)
+		mockSessionRepo.EXPECT().Delete(ctx, currentSession).Times(1).Return(nil)
+
+		session, err := ucase.SwitchOperator(ctx, requester, model.PINLoginRequest{UserID: nextOperator.ID, PlainPIN: "654321"})
+		require.NoError(t, err)
+		require.Equal(t, nextOperator.ID, session.UserID)
+		require.Equal(t, currentSession.AppID, session.AppID)
+	})
+
+	t.Run("failed - inactive operator keeps the current session", func(t *testing.T) {
+		inactive := &model.User{ID: 4, Status: model.StatusInactive}
+		mockSessionRepo.EXPECT().FindByID(ctx, requester.SessionID).Times(1).Return(currentSession, nil)
+		mockAppClientRepo.EXPECT().FindByID(ctx, currentSession.AppID).Times(1).Return(&model.AppClient{ID: currentSession.AppID, IsPOSTerminal: true}, nil)
+		mockUserRepo.EXPECT().IsLoginByPINLocked(ctx, currentSession.AppID, inactive.ID).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, inactive.ID).Times(1).Return(inactive, nil)
+		mockUserRepo.EXPECT().FindPINByID(ctx, inactive.ID).Times(1).Return([]byte(cipherPIN), nil)
+
+		_, err := ucase.SwitchOperator(ctx, requester, model.PINLoginRequest{UserID: inactive.ID, PlainPIN: "654321"})
+		require.ErrorIs(t, err, model.ErrUserInactive)
+	})
+}
+
+func TestAuthUsecase_VerifyTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	mockTwoFactorRepo := mock.NewMockTwoFactorRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, twoFactorRepo: mockTwoFactorRepo}
+	viper.Set("secret_key", "test-secret-key")
+
+	user := &model.User{ID: 2, Role: rbac.RoleFinancialAuditor, Status: model.StatusActive}
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	enabled := &model.UserTwoFactor{UserID: user.ID, Secret: secret, EnabledAt: &enabledAt}
+	token := "challenge-token"
+
+	newChallenge := func(enrollment bool) *model.TwoFactorChallenge {
+		return &model.TwoFactorChallenge{ID: 9, UserID: user.ID, AppID: 7, TokenHash: helper.HashToken(token), Enrollment: enrollment, ExpiredAt: time.Now().Add(time.Minute)}
+	}
+	expectChallenge := func(challenge *model.TwoFactorChallenge, twoFactor *model.UserTwoFactor) {
+		mockTwoFactorRepo.EXPECT().FindChallengeByTokenHash(ctx, helper.HashToken(token)).Times(1).Return(challenge, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, user.ID).Times(1).Return(twoFactor, nil)
+		mockUserRepo.EXPECT().IsLoginByTwoFactorLocked(ctx, user.ID).Times(1).Return(false, nil)
+	}
+	expectSession := func() {
+		mockSessionRepo.EXPECT().Create(ctx, gomock.Any()).Times(1).Return(nil)
+		mockSessionRepo.EXPECT().DeleteByUserIDAndMaxRemainderSession(ctx, user.ID, gomock.Any()).Times(1).Return(nil)
+	}
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		challenge := newChallenge(false)
+		expectChallenge(challenge, enabled)
+		mockTwoFactorRepo.EXPECT().UseStep(ctx, user.ID, gomock.Any()).Times(1).Return(true, nil)
+		mockTwoFactorRepo.EXPECT().UseChallenge(ctx, challenge).Times(1).Return(true, nil)
+		expectSession()
+
+		session, recoveryCodes, err := ucase.VerifyTwoFactor(ctx, model.VerifyTwoFactorRequest{ChallengeToken: token, Code: code})
+		require.NoError(t, err)
+		require.Equal(t, challenge.AppID, session.AppID)
+		require.Empty(t, recoveryCodes)
+	})
+
+	t.Run("ok - recovery code", func(t *testing.T) {
+		challenge := newChallenge(false)
+		expectChallenge(challenge, enabled)
+		mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, user.ID, helper.HashToken("ABCDEFGH")).Times(1).Return(true, nil)
+		mockTwoFactorRepo.EXPECT().UseChallenge(ctx, challenge).Times(1).Return(true, nil)
+		expectSession()
+
+		_, _, err := ucase.VerifyTwoFactor(ctx, model.VerifyTwoFactorRequest{ChallengeToken: token, RecoveryCode: "abcd-efgh"})
+		require.NoError(t, err)
+	})
+
+	t.Run("ok - enrollment enables the two factor", func(t *testing.T) {
+		challenge := newChallenge(true)
+		expectChallenge(challenge, &model.UserTwoFactor{UserID: user.ID, Secret: secret})
+		mockTwoFactorRepo.EXPECT().UseStep(ctx, user.ID, gomock.Any()).Times(1).Return(true, nil)
+		mockTwoFactorRepo.EXPECT().UseChallenge(ctx, challenge).Times(1).Return(true, nil)
+		mockTwoFactorRepo.EXPECT().Enable(ctx, user.ID, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ int64, hashes []string) error {
+			require.Len(t, hashes, recoveryCodeCount)
+			return nil
+		})
+		expectSession()
+
+		_, recoveryCodes, err := ucase.VerifyTwoFactor(ctx, model.VerifyTwoFactorRequest{ChallengeToken: token, Code: code})
+		require.NoError(t, err)
+		require.Len(t, recoveryCodes, recoveryCodeCount)
+	})
+
+	t.Run("failed - code already used", func(t *testing.T) {
+		expectChallenge(newChallenge(false), enabled)
+		mockTwoFactorRepo.EXPECT().UseStep(ctx, user.ID, gomock.Any()).Times(1).Return(false, nil)
+		mockUserRepo.EXPECT().IncrementLoginByTwoFactorRetryAttempts(ctx, user.ID).Times(1).Return(nil)
+
+		_, _, err := ucase.VerifyTwoFactor(ctx, model.VerifyTwoFactorRequest{ChallengeToken: token, Code: code})
+		require.ErrorIs(t, err, model.ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("failed - recovery code before the enrollment is confirmed", func(t *testing.T) {
+		expectChallenge(newChallenge(true), &model.UserTwoFactor{UserID: user.ID, Secret: secret})
+		mockUserRepo.EXPECT().IncrementLoginByTwoFactorRetryAttempts(ctx, user.ID).Times(1).Return(nil)
+
+		_, _, err := ucase.VerifyTwoFactor(ctx, model.VerifyTwoFactorRequest{ChallengeToken: token, RecoveryCode: "ABCD-EFGH"})
+		require.ErrorIs(t, err, model.ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("failed - locked", func(t *testing.T) {
+		mockTwoFactorRepo.EXPECT().FindChallengeByTokenHash(ctx, helper.HashToken(token)).Times(1).Return(newChallenge(false), nil)
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, user.ID).Times(1).Return(enabled, nil)
+		mockUserRepo.EXPECT().IsLoginByTwoFactorLocked(ctx, user.ID).Times(1).Return(true, nil)
+
+		_, _, err := ucase.VerifyTwoFactor(ctx, model.VerifyTwoFactorRequest{ChallengeToken: token, Code: code})
+		require.ErrorIs(t, err, ErrLoginByTwoFactorLocked)
+	})
+
+	t.Run("failed - expired challenge", func(t *testing.T) {
+		challenge := newChallenge(false)
+		challenge.ExpiredAt = time.Now().Add(-time.Second)
+		mockTwoFactorRepo.EXPECT().FindChallengeByTokenHash(ctx, helper.HashToken(token)).Times(1).Return(challenge, nil)
+
+		_, _, err := ucase.VerifyTwoFactor(ctx, model.VerifyTwoFactorRequest{ChallengeToken: token, Code: code})
+		require.ErrorIs(t, err, model.ErrInvalidTwoFactorChallenge)
+	})
+}
+
+func TestAuthUsecase_ConfirmTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockTwoFactorRepo := mock.NewMockTwoFactorRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, twoFactorRepo: mockTwoFactorRepo}
+	requester := newUserWithRole(1, rbac.RoleAdmin)
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, requester.ID).Times(1).Return(&model.UserTwoFactor{UserID: requester.ID, Secret: secret}, nil)
+		mockUserRepo.EXPECT().IsLoginByTwoFactorLocked(ctx, requester.ID).Times(1).Return(false, nil)
+		mockTwoFactorRepo.EXPECT().UseStep(ctx, requester.ID, gomock.Any()).Times(1).Return(true, nil)
+		mockTwoFactorRepo.EXPECT().Enable(ctx, requester.ID, gomock.Any()).Times(1).Return(nil)
+
+		recoveryCodes, err := ucase.ConfirmTwoFactor(ctx, requester, model.TwoFactorCodeInput{Code: code})
+		require.NoError(t, err)
+		require.Len(t, recoveryCodes, recoveryCodeCount)
+		for _, recoveryCode := range recoveryCodes {
+			require.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}$`, recoveryCode)
+		}
+	})
+
+	t.Run("failed - not enrolled", func(t *testing.T) {
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, requester.ID).Times(1).Return(nil, nil)
+
+		_, err := ucase.ConfirmTwoFactor(ctx, requester, model.TwoFactorCodeInput{Code: code})
+		require.ErrorIs(t, err, model.ErrTwoFactorNotEnrolled)
+	})
+
+	t.Run("failed - invalid code format", func(t *testing.T) {
+		_, err := ucase.ConfirmTwoFactor(ctx, requester, model.TwoFactorCodeInput{Code: "12ab56"})
+		require.Error(t, err)
+	})
+}
+
+func TestAuthUsecase_DisableTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockTwoFactorRepo := mock.NewMockTwoFactorRepository(ctrl)
+	ucase := authUsecase{userRepo: mockUserRepo, twoFactorRepo: mockTwoFactorRepo}
+	requester := newUserWithRole(1, rbac.RoleAdmin)
+	input := model.DisableTwoFactorInput{Password: "123456", RecoveryCode: "ABCD-EFGH"}
+	enabledAt := time.Now()
+
+	cipherPwd, err := helper.HashString(input.Password)
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindPasswordByID(ctx, requester.ID).Times(1).Return([]byte(cipherPwd), nil)
+		mockTwoFactorRepo.EXPECT().FindByUserID(ctx, requester.ID).Times(1).Return(&model.UserTwoFactor{UserID: requester.ID, EnabledAt: &enabledAt}, nil)
+		mockUserRepo.EXPECT().IsLoginByTwoFactorLocked(ctx, requester.ID).Times(1).Return(false, nil)
+		mockTwoFactorRepo.EXPECT().UseRecoveryCode(ctx, requester.ID, helper.HashToken("ABCDEFGH")).Times(1).Return(true, nil)
+		mockTwoFactorRepo.EXPECT().DeleteByUserID(ctx, requester.ID).Times(1).Return(nil)
+
+		require.NoError(t, ucase.DisableTwoFactor(ctx, requester, input))
+	})
+
+	t.Run("failed - required for the role", func(t *testing.T) {
+		viper.Set("two_factor.required_roles", []string{"ADMIN"})
+		defer viper.Set("two_factor.required_roles", nil)
+
+		require.ErrorIs(t, ucase.DisableTwoFactor(ctx, requester, input), model.ErrTwoFactorRequired)
+	})
+
+	t.Run("failed - neither a code nor a recovery code", func(t *testing.T) {
+		require.Error(t, ucase.DisableTwoFactor(ctx, requester, model.DisableTwoFactorInput{Password: input.Password}))
+	})
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"github.com/gosimple/slug"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type categoryUsecase struct {
+	categoryRepo model.CategoryRepository
+}
+
+// NewCategoryUsecase instantiate a new category usecase
+func NewCategoryUsecase(categoryRepo model.CategoryRepository) model.CategoryUsecase {
+	return &categoryUsecase{
+		categoryRepo: categoryRepo,
+	}
+}
+
+// FindByID find category by specific id along with its subcategories
+func (c *categoryUsecase) FindByID(ctx context.Context, requester *model.User, id int64) (*model.Category, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"requester":  utils.Dump(requester),
+		"categoryID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceCategory, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	category, err := c.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	categories, err := c.categoryRepo.FindAll(ctx)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	for _, node := range model.AnyCategories(categories).Tree() {
+		if found := findCategoryInTree(node, category.ID); found != nil {
+			return found, nil
+		}
+	}
+
+	return category, nil
+}
+
+// FindTree find the root categories with their subcategories nested
+func (c *categoryUsecase) FindTree(ctx context.Context, requester *model.User) (model.AnyCategories, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if !requester.HasAccess(rbac.ResourceCategory, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	categories, err := c.categoryRepo.FindAll(ctx)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return model.AnyCategories(categories).Tree(), nil
+}
+
+// Create category from input, the slug is prefixed with the parent slug so the same name can be used under different parents
+func (c *categoryUsecase) Create(ctx context.Context, requester *model.User, input model.CreateCategoryInput) (*model.Category, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceCategory, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	category := &model.Category{
+		ID:          utils.GenerateID(),
+		ParentID:    input.ParentID,
+		Name:        input.Name,
+		Description: input.Description,
+		SortOrder:   input.SortOrder,
+	}
+
+	if err := c.setParentAndSlug(ctx, category); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := c.categoryRepo.Create(ctx, requester.ID, category); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// UpdateByID update category with id, the category can not be moved under itself or one of its subcategories
+func (c *categoryUsecase) UpdateByID(ctx context.Context, requester *model.User, id int64, input model.UpdateCategoryInput) (*model.Category, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"requester":  utils.Dump(requester),
+		"categoryID": id,
+		"input":      utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceCategory, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	oldCategory, err := c.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if input.ParentID > 0 && input.ParentID != oldCategory.ParentID {
+		categories, err := c.categoryRepo.FindAll(ctx)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		if input.ParentID == id || model.AnyCategories(categories).HasDescendant(id, input.ParentID) {
+			return nil, model.ErrInvalidCategoryParent
+		}
+	}
+
+	updatedCategory := *oldCategory
+	updatedCategory.Children = nil
+	updatedCategory.ParentID = input.ParentID
+	updatedCategory.Name = input.Name
+	updatedCategory.Description = input.Description
+	updatedCategory.SortOrder = input.SortOrder
+
+	if err := c.setParentAndSlug(ctx, &updatedCategory); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := c.categoryRepo.Update(ctx, requester.ID, &updatedCategory); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &updatedCategory, nil
+}
+
+// DeleteByID delete category by id, only an empty category can be deleted
+func (c *categoryUsecase) DeleteByID(ctx context.Context, requester *model.User, id int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"requester":  utils.Dump(requester),
+		"categoryID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceCategory, rbac.ActionDeleteAny) {
+		return ErrPermissionDenied
+	}
+
+	category, err := c.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := c.categoryRepo.Delete(ctx, requester.ID, category); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// setParentAndSlug make sure the parent exists and generate the slug, return ErrAlreadyExist when the slug is taken
+func (c *categoryUsecase) setParentAndSlug(ctx context.Context, category *model.Category) error {
+	category.Slug = slug.Make(category.Name)
+	if category.ParentID > 0 {
+		parent, err := c.categoryRepo.FindByID(ctx, category.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return model.ErrInvalidCategoryParent
+		}
+
+		category.Slug = slug.Make(parent.Slug + " " + category.Name)
+	}
+
+	existingCategory, err := c.categoryRepo.FindBySlug(ctx, category.Slug)
+	if err != nil {
+		return err
+	}
+	if existingCategory != nil && existingCategory.ID != category.ID {
+		return ErrAlreadyExist
+	}
+
+	return nil
+}
+
+func (c *categoryUsecase) findByID(ctx context.Context, id int64) (*model.Category, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	category, err := c.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if category == nil {
+		return nil, ErrNotFound
+	}
+
+	return category, nil
+}
+
+// findCategoryInTree find the category node with its nested subcategories
+func findCategoryInTree(node *model.Category, id int64) *model.Category {
+	if node.ID == id {
+		return node
+	}
+
+	for _, child := range node.Children {
+		if found := findCategoryInTree(child, id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
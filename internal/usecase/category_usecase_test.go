@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+// minuman > kopi > kopi susu, makanan
+var testCategories = []*model.Category{
+	{ID: 1, Name: "Minuman", Slug: "minuman", SortOrder: 2},
+	{ID: 2, ParentID: 1, Name: "Kopi", Slug: "minuman-kopi"},
+	{ID: 3, ParentID: 2, Name: "Kopi Susu", Slug: "minuman-kopi-kopi-susu"},
+	{ID: 4, Name: "Makanan", Slug: "makanan", SortOrder: 1},
+}
+
+func TestCategoryUsecase_FindTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockCategoryRepo := mock.NewMockCategoryRepository(ctrl)
+	ucase := categoryUsecase{categoryRepo: mockCategoryRepo}
+
+	mockCategoryRepo.EXPECT().FindAll(ctx).Times(1).Return(testCategories, nil)
+
+	res, err := ucase.FindTree(ctx, newUserWithRole(111, rbac.RoleProductManager))
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	// ordered by the sort order
+	require.Equal(t, "Makanan", res[0].Name)
+	require.Equal(t, "Minuman", res[1].Name)
+	require.Equal(t, "Kopi", res[1].Children[0].Name)
+	require.Equal(t, "Kopi Susu", res[1].Children[0].Children[0].Name)
+	// the cached categories are never mutated
+	require.Nil(t, testCategories[0].Children)
+}
+
+func TestCategoryUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockCategoryRepo := mock.NewMockCategoryRepository(ctrl)
+	ucase := categoryUsecase{categoryRepo: mockCategoryRepo}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+
+	t.Run("ok - subcategory slug is prefixed with the parent slug", func(t *testing.T) {
+		mockCategoryRepo.EXPECT().FindByID(ctx, int64(2)).Times(1).Return(testCategories[1], nil)
+		mockCategoryRepo.EXPECT().FindBySlug(ctx, "minuman-kopi-es-kopi").Times(1).Return(nil, nil)
+		mockCategoryRepo.EXPECT().Create(ctx, manager.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, manager, model.CreateCategoryInput{ParentID: 2, Name: "Es Kopi"})
+		require.NoError(t, err)
+		require.Equal(t, "minuman-kopi-es-kopi", res.Slug)
+		require.Equal(t, int64(2), res.ParentID)
+	})
+
+	t.Run("failed - parent not found", func(t *testing.T) {
+		mockCategoryRepo.EXPECT().FindByID(ctx, int64(999)).Times(1).Return(nil, nil)
+
+		res, err := ucase.Create(ctx, manager, model.CreateCategoryInput{ParentID: 999, Name: "Es Kopi"})
+		require.ErrorIs(t, err, model.ErrInvalidCategoryParent)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - already exist", func(t *testing.T) {
+		mockCategoryRepo.EXPECT().FindBySlug(ctx, "makanan").Times(1).Return(testCategories[3], nil)
+
+		res, err := ucase.Create(ctx, manager, model.CreateCategoryInput{Name: "Makanan"})
+		require.ErrorIs(t, err, ErrAlreadyExist)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - cashier can not create category", func(t *testing.T) {
+		res, err := ucase.Create(ctx, newUserWithRole(222, rbac.RoleCashiers), model.CreateCategoryInput{Name: "Makanan"})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestCategoryUsecase_UpdateByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockCategoryRepo := mock.NewMockCategoryRepository(ctrl)
+	ucase := categoryUsecase{categoryRepo: mockCategoryRepo}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+
+	t.Run("ok - move to another parent", func(t *testing.T) {
+		mockCategoryRepo.EXPECT().FindByID(ctx, int64(2)).Times(1).Return(testCategories[1], nil)
+		mockCategoryRepo.EXPECT().FindAll(ctx).Times(1).Return(testCategories, nil)
+		mockCategoryRepo.EXPECT().FindByID(ctx, int64(4)).Times(1).Return(testCategories[3], nil)
+		mockCategoryRepo.EXPECT().FindBySlug(ctx, "makanan-kopi").Times(1).Return(nil, nil)
+		mockCategoryRepo.EXPECT().Update(ctx, manager.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.UpdateByID(ctx, manager, 2, model.UpdateCategoryInput{ParentID: 4, Name: "Kopi"})
+		require.NoError(t, err)
+		require.Equal(t, int64(4), res.ParentID)
+		require.Equal(t, "makanan-kopi", res.Slug)
+	})
+
+	t.Run("ok - move to the root", func(t *testing.T) {
+		mockCategoryRepo.EXPECT().FindByID(ctx, int64(2)).Times(1).Return(testCategories[1], nil)
+		mockCategoryRepo.EXPECT().FindBySlug(ctx, "kopi").Times(1).Return(nil, nil)
+		mockCategoryRepo.EXPECT().Update(ctx, manager.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.UpdateByID(ctx, manager, 2, model.UpdateCategoryInput{Name: "Kopi"})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), res.ParentID)
+	})
+
+	t.Run("failed - move under its own subcategory", func(t *testing.T) {
+		mockCategoryRepo.EXPECT().FindByID(ctx, int64(1)).Times(1).Return(testCategories[0], nil)
+		mockCategoryRepo.EXPECT().FindAll(ctx).Times(1).Return(testCategories, nil)
+
+		res, err := ucase.UpdateByID(ctx, manager, 1, model.UpdateCategoryInput{ParentID: 3, Name: "Minuman"})
+		require.ErrorIs(t, err, model.ErrInvalidCategoryParent)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - move under itself", func(t *testing.T) {
+		mockCategoryRepo.EXPECT().FindByID(ctx, int64(1)).Times(1).Return(testCategories[0], nil)
+		mockCategoryRepo.EXPECT().FindAll(ctx).Times(1).Return(testCategories, nil)
+
+		res, err := ucase.UpdateByID(ctx, manager, 1, model.UpdateCategoryInput{ParentID: 1, Name: "Minuman"})
+		require.ErrorIs(t, err, model.ErrInvalidCategoryParent)
+		require.Nil(t, res)
+	})
+}
@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+)
+
+// newUserWithRole build a user of the role with the built in rbac permission,
+// so the tests check the same permission as production
+func newUserWithRole(id int64, role rbac.Role) *model.User {
+	perm := rbac.NewPermission()
+	rbac.TraversePermission(func(role rbac.Role, rsc rbac.Resource, act rbac.Action) {
+		perm.Add(role, rsc, act)
+	})
+
+	user := &model.User{ID: id, Role: role}
+	user.SetPermission(perm)
+	return user
+}
@@ -8,9 +8,15 @@ var (
 	ErrFailedPrecondition         = errors.New("precondition failed")
 	ErrPasswordMismatch           = errors.New("password mismatch")
 	ErrLoginByEmailPasswordLocked = errors.New("user is locked from logging in using email and password")
+	ErrLoginByPINLocked           = errors.New("user is locked from logging in using PIN on the terminal")
+	ErrNotPOSTerminal             = errors.New("app client is not a POS terminal")
+	ErrLoginByTwoFactorLocked     = errors.New("user is locked from verifying the two factor codes")
 	ErrUnauthorized               = errors.New("unauthorized")
 	ErrAccessTokenExpired         = errors.New("access token expired")
 	ErrRefreshTokenExpired        = errors.New("refresh token expired")
 
 	ErrAlreadyExist = errors.New("already exist")
+
+	ErrVoidWindowExpired      = errors.New("transaction can no longer be voided")
+	ErrManualDiscountExceeded = errors.New("manual discount exceeds the allowed maximum")
 )
@@ -4,21 +4,34 @@ import (
 	"context"
 	"errors"
 	"github.com/gosimple/slug"
+	"github.com/irvankadhafi/go-point-of-sales/internal/barcode"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/label"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/rbac"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
 	"sync"
 )
 
 type productUsecase struct {
-	productRepo model.ProductRepository
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+	categoryRepo       model.CategoryRepository
 }
 
 // NewProductUsecase instantiate a new product usecase
-func NewProductUsecase(productRepo model.ProductRepository) model.ProductUsecase {
+func NewProductUsecase(
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	categoryRepo model.CategoryRepository,
+) model.ProductUsecase {
 	return &productUsecase{
-		productRepo: productRepo,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+		categoryRepo:       categoryRepo,
 	}
 }
 
@@ -43,7 +56,7 @@ func (p *productUsecase) FindByID(ctx context.Context, requester *model.User, id
 	return product, nil
 }
 
-// Create product from input
+// Create product from input along with its variants
 func (p *productUsecase) Create(ctx context.Context, requester *model.User, input model.CreateProductInput) (*model.Product, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":       utils.DumpIncomingContext(ctx),
@@ -60,13 +73,45 @@ func (p *productUsecase) Create(ctx context.Context, requester *model.User, inpu
 		return nil, err
 	}
 
+	taxCategory, err := parseTaxCategory(input.TaxCategory)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.validateCategory(ctx, input.CategoryID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	product := &model.Product{
-		ID:          utils.GenerateID(),
-		Name:        input.Name,
-		Slug:        slug.Make(input.Name),
-		Price:       input.Price,
-		Description: input.Description,
-		Quantity:    input.Quantity,
+		ID:              utils.GenerateID(),
+		Name:            input.Name,
+		Slug:            slug.Make(input.Name),
+		SKU:             input.SKU,
+		Barcode:         input.Barcode,
+		CategoryID:      input.CategoryID,
+		Price:           input.Price,
+		CostPrice:       input.CostPrice,
+		Description:     input.Description,
+		Quantity:        input.Quantity,
+		TaxCategory:     taxCategory,
+		MinStock:        input.MinStock,
+		ReorderQuantity: input.ReorderQuantity,
+		Options:         input.Options,
+	}
+	if product.SKU == "" {
+		product.SKU = strings.ToUpper(product.Slug)
+	}
+
+	if err := p.setVariants(product, input.Variants); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.validateCodes(ctx, product); err != nil {
+		logger.Error(err)
+		return nil, err
 	}
 
 	existingProduct, err := p.productRepo.FindBySlug(ctx, product.Slug)
@@ -86,6 +131,39 @@ func (p *productUsecase) Create(ctx context.Context, requester *model.User, inpu
 	return product, nil
 }
 
+// FindByCode find the product of a scanned sku or barcode along with the scanned variant,
+// allowed for the requester who can view the products or sell them
+func (p *productUsecase) FindByCode(ctx context.Context, requester *model.User, code string) (*model.Product, *model.ProductVariant, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"code":      code,
+	})
+
+	// the cashiers scan the products while selling, so they may look up a code without viewing the catalog
+	if !requester.HasAccess(rbac.ResourceProduct, rbac.ActionViewAny) &&
+		!requester.HasAccess(rbac.ResourceTransaction, rbac.ActionCreateAny) {
+		return nil, nil, ErrPermissionDenied
+	}
+
+	product, variant, err := findProductByCode(ctx, p.productRepo, p.productVariantRepo, code)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	if product.HasVariants() {
+		variants, err := p.productVariantRepo.FindByProductID(ctx, product.ID)
+		if err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+		product.Variants = variants
+	}
+
+	return product, variant, nil
+}
+
 // Search product with given search criteria
 func (p *productUsecase) Search(ctx context.Context, requester *model.User, criteria model.ProductSearchCriteria) (products model.AnyProducts, count int64, err error) {
 	logger := logrus.WithFields(logrus.Fields{
@@ -117,7 +195,39 @@ func (p *productUsecase) Search(ctx context.Context, requester *model.User, crit
 	return
 }
 
-// UpdateByID update product with id
+// SearchLowStock find the products which stock has reached their minimum stock level, the lowest first
+func (p *productUsecase) SearchLowStock(ctx context.Context, requester *model.User, criteria model.LowStockSearchCriteria) (products model.AnyProducts, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	productIDs, count, err := p.productRepo.SearchLowStockByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(productIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	products = p.findAllByIDs(ctx, productIDs)
+	if len(products) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// UpdateByID update product with id, the variants missing from the input are removed.
+// The stock is kept, it is changed through the stock movements and the stock opname.
 func (p *productUsecase) UpdateByID(ctx context.Context, requester *model.User, id int64, input model.UpdateProductInput) (*model.Product, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":       utils.DumpIncomingContext(ctx),
@@ -141,10 +251,26 @@ func (p *productUsecase) UpdateByID(ctx context.Context, requester *model.User,
 		return nil, err
 	}
 
+	taxCategory, err := parseTaxCategory(input.TaxCategory)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.validateCategory(ctx, input.CategoryID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	updatedProduct := *oldProduct
+	updatedProduct.TaxCategory = taxCategory
+	updatedProduct.CategoryID = input.CategoryID
 	updatedProduct.Description = input.Description
 	updatedProduct.Price = input.Price
-	updatedProduct.Quantity = input.Quantity
+	updatedProduct.MinStock = input.MinStock
+	updatedProduct.ReorderQuantity = input.ReorderQuantity
+	updatedProduct.Options = input.Options
+	updatedProduct.Barcode = input.Barcode
 
 	// validate if product with same name is exists
 	if input.Name != oldProduct.Name {
@@ -161,6 +287,30 @@ func (p *productUsecase) UpdateByID(ctx context.Context, requester *model.User,
 		}
 	}
 
+	// keep the sku when it is not given, since it may already be printed on the labels
+	switch {
+	case input.SKU != "":
+		updatedProduct.SKU = input.SKU
+	case updatedProduct.SKU == "":
+		updatedProduct.SKU = strings.ToUpper(updatedProduct.Slug)
+	}
+
+	// the stock is kept by the repository, the variant inputs carry no quantity
+	variants := make([]model.CreateProductVariantInput, 0, len(input.Variants))
+	for _, variant := range input.Variants {
+		variants = append(variants, model.CreateProductVariantInput{ProductVariantInput: variant})
+	}
+
+	if err := p.setVariants(&updatedProduct, variants); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.validateCodes(ctx, &updatedProduct); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	if err := p.productRepo.Update(ctx, requester.ID, &updatedProduct); err != nil {
 		logger.Error(err)
 		return nil, err
@@ -169,7 +319,41 @@ func (p *productUsecase) UpdateByID(ctx context.Context, requester *model.User,
 	return &updatedProduct, nil
 }
 
-//DeleteByID delete product by id
+// UpdateCostPriceByID correct the moving average cost price of the product manually
+func (p *productUsecase) UpdateCostPriceByID(ctx context.Context, requester *model.User, id int64, input model.UpdateProductCostPriceInput) (*model.Product, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"productID": id,
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceProduct, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	oldProduct, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	updatedProduct := *oldProduct
+	updatedProduct.CostPrice = input.CostPrice
+	if err := p.productRepo.UpdateCostPrice(ctx, requester.ID, &updatedProduct); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &updatedProduct, nil
+}
+
+// DeleteByID delete product by id
 func (p *productUsecase) DeleteByID(ctx context.Context, requester *model.User, id int64) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":       utils.DumpIncomingContext(ctx),
@@ -195,6 +379,322 @@ func (p *productUsecase) DeleteByID(ctx context.Context, requester *model.User,
 	return nil
 }
 
+// PrintLabels render the shelf labels of the products, a product with variants prints the copies
+// for every variant unless the variant is chosen. The label shows the barcode, or the sku when the product has no barcode,
+// or the id when the sku can not be printed as Code128.
+func (p *productUsecase) PrintLabels(ctx context.Context, requester *model.User, input model.PrintLabelsInput) (*model.LabelSheet, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceProduct, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	var items []label.Item
+	for _, in := range input.Items {
+		product, err := p.findByID(ctx, in.ProductID)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		var labels []label.Item
+		switch {
+		case in.VariantID > 0:
+			variant := model.AnyProductVariants(product.Variants).FindByID(in.VariantID)
+			if variant == nil {
+				return nil, model.ErrUnknownProductVariant
+			}
+			labels = append(labels, newVariantLabel(product, variant))
+		case product.HasVariants():
+			for _, variant := range product.Variants {
+				labels = append(labels, newVariantLabel(product, variant))
+			}
+		default:
+			labels = append(labels, label.Item{
+				Name:  product.Name,
+				Price: product.Price,
+				Code:  labelCode(product.Barcode, product.SKU, product.ID),
+			})
+		}
+
+		for i := int64(0); i < in.Copies; i++ {
+			items = append(items, labels...)
+		}
+		if len(items) > model.MaxLabelsPerPrint {
+			return nil, model.ErrTooManyLabels
+		}
+	}
+
+	sheet, err := label.Render(input.Format, input.Layout, items)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return sheet, nil
+}
+
+// Import create or update the products of the imported rows in batches, each batch is stored in a single db transaction.
+// A row is matched to an existing product by its sku or by the slug of its name, an existing product only takes
+// the columns of the file and keeps its stock & cost price. The failed rows are reported in the result,
+// so does every row of a failed batch. Nothing is stored on a dry run.
+func (p *productUsecase) Import(ctx context.Context, requester *model.User, input model.ImportProductsInput) (*model.ProductImportResult, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"rows":      len(input.Rows),
+		"dryRun":    input.DryRun,
+	})
+
+	if !requester.HasAccess(rbac.ResourceProduct, rbac.ActionCreateAny) ||
+		!requester.HasAccess(rbac.ResourceProduct, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	result := &model.ProductImportResult{DryRun: input.DryRun, Total: len(input.Rows)}
+	addResult := func(imports []*model.ProductImport) {
+		for _, in := range imports {
+			if in.IsNew {
+				result.Created++
+				continue
+			}
+			result.Updated++
+		}
+	}
+
+	batchSize := config.ProductImportBatchSize()
+	batch := make([]*model.ProductImport, 0, batchSize)
+	storeBatch := func() {
+		if len(batch) <= 0 {
+			return
+		}
+		defer func() { batch = batch[:0] }()
+
+		if input.DryRun {
+			addResult(batch)
+			return
+		}
+
+		if err := p.productRepo.Import(ctx, requester.ID, batch); err != nil {
+			logger.Error(err)
+			for _, in := range batch {
+				result.Errors = append(result.Errors, model.NewProductImportRowError(in.Line, model.ErrProductImportBatchFailed))
+			}
+			return
+		}
+		addResult(batch)
+	}
+
+	// the products, names & codes taken by the previous rows, so no two rows of the file point to the same product
+	taken := make(map[string]bool)
+	for _, row := range input.Rows {
+		in, err := p.newProductImport(ctx, input, row)
+		if err == nil {
+			err = takeProductImport(taken, in)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, model.NewProductImportRowError(row.Line, err))
+			continue
+		}
+
+		batch = append(batch, in)
+		if len(batch) >= batchSize {
+			storeBatch()
+		}
+	}
+	storeBatch()
+
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Line < result.Errors[j].Line
+	})
+
+	return result, nil
+}
+
+// Export call fn with every product of the catalog ordered by id, the products are streamed from the repository
+func (p *productUsecase) Export(ctx context.Context, requester *model.User, fn func(product *model.Product) error) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if !requester.HasAccess(rbac.ResourceProduct, rbac.ActionViewAny) {
+		return ErrPermissionDenied
+	}
+
+	if err := p.productRepo.StreamAll(ctx, fn); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// newProductImport build the product of the imported row, either a new product or the existing product it points to
+func (p *productUsecase) newProductImport(ctx context.Context, input model.ImportProductsInput, row *model.ProductImportRow) (*model.ProductImport, error) {
+	if row.Err != nil {
+		return nil, row.Err
+	}
+
+	productSlug := slug.Make(row.Input.Name)
+	existingProduct, err := p.findImportedProduct(ctx, row.Input.SKU, productSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := row.Validate(existingProduct == nil); err != nil {
+		return nil, err
+	}
+
+	taxCategory, err := parseTaxCategory(row.Input.TaxCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.validateCategory(ctx, row.Input.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if existingProduct == nil {
+		product := &model.Product{
+			ID:              utils.GenerateID(),
+			Name:            row.Input.Name,
+			Slug:            productSlug,
+			SKU:             row.Input.SKU,
+			Barcode:         row.Input.Barcode,
+			CategoryID:      row.Input.CategoryID,
+			Price:           row.Input.Price,
+			CostPrice:       row.Input.CostPrice,
+			Description:     row.Input.Description,
+			Quantity:        row.Input.Quantity,
+			TaxCategory:     taxCategory,
+			MinStock:        row.Input.MinStock,
+			ReorderQuantity: row.Input.ReorderQuantity,
+		}
+		if product.SKU == "" {
+			product.SKU = strings.ToUpper(product.Slug)
+		}
+
+		if err := p.validateCodes(ctx, product); err != nil {
+			return nil, err
+		}
+
+		return &model.ProductImport{Line: row.Line, Product: product, IsNew: true}, nil
+	}
+
+	if existingProduct.HasVariants() {
+		return nil, model.ErrProductImportHasVariants
+	}
+
+	// the stock & cost price are kept by the repository
+	product := *existingProduct
+	if product.Name != row.Input.Name {
+		product.Name = row.Input.Name
+		product.Slug = productSlug
+
+		sameSlugProduct, err := p.productRepo.FindBySlug(ctx, product.Slug)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if sameSlugProduct != nil && sameSlugProduct.ID != product.ID {
+			return nil, model.ErrProductImportNameAlreadyExist
+		}
+	}
+
+	// keep the sku when it is not given, since it may already be printed on the labels
+	if row.Input.SKU != "" {
+		product.SKU = row.Input.SKU
+	}
+	if input.HasColumn("barcode") {
+		product.Barcode = row.Input.Barcode
+	}
+	if input.HasColumn("description") {
+		product.Description = row.Input.Description
+	}
+	if input.HasColumn("price") {
+		product.Price = row.Input.Price
+	}
+	if input.HasColumn("min_stock") {
+		product.MinStock = row.Input.MinStock
+	}
+	if input.HasColumn("reorder_quantity") {
+		product.ReorderQuantity = row.Input.ReorderQuantity
+	}
+	if input.HasColumn("tax_category") {
+		product.TaxCategory = taxCategory
+	}
+	if input.HasColumn("category_id") {
+		product.CategoryID = row.Input.CategoryID
+	}
+
+	if err := p.validateCodes(ctx, &product); err != nil {
+		return nil, err
+	}
+
+	return &model.ProductImport{Line: row.Line, Product: &product}, nil
+}
+
+// findImportedProduct find the existing product of the imported row by its sku, or by its slug when the sku is not given
+// or not used by any product. Return nil when the row is a new product.
+func (p *productUsecase) findImportedProduct(ctx context.Context, sku, productSlug string) (*model.Product, error) {
+	if sku != "" {
+		product, err := p.productRepo.FindByCode(ctx, sku)
+		if err != nil {
+			return nil, err
+		}
+		// the code may be the barcode of another product, such row fails on the duplicate code instead
+		if product != nil && product.SKU == sku {
+			return product, nil
+		}
+	}
+
+	if productSlug == "" {
+		return nil, nil
+	}
+
+	product, err := p.productRepo.FindBySlug(ctx, productSlug)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// takeProductImport mark the product, slug & codes of the import as taken,
+// fail when one of them is already taken by a previous row of the same file
+func takeProductImport(taken map[string]bool, in *model.ProductImport) error {
+	keys := []string{"slug:" + in.Product.Slug}
+	if !in.IsNew {
+		keys = append(keys, "id:"+utils.Int64ToString(in.Product.ID))
+	}
+	for _, code := range []string{in.Product.SKU, in.Product.Barcode} {
+		if code != "" {
+			keys = append(keys, "code:"+code)
+		}
+	}
+
+	for _, key := range keys {
+		if taken[key] {
+			return model.ErrDuplicateProductImportRow
+		}
+	}
+
+	for _, key := range keys {
+		taken[key] = true
+	}
+
+	return nil
+}
+
 func (p *productUsecase) findByID(ctx context.Context, id int64) (*model.Product, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx": utils.DumpIncomingContext(ctx),
@@ -210,6 +710,15 @@ func (p *productUsecase) findByID(ctx context.Context, id int64) (*model.Product
 		return nil, ErrNotFound
 	}
 
+	if product.HasVariants() {
+		variants, err := p.productVariantRepo.FindByProductID(ctx, product.ID)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		product.Variants = variants
+	}
+
 	return product, nil
 }
 
@@ -263,3 +772,190 @@ func (p *productUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model
 
 	return products
 }
+
+// setVariants build the product variants from the input, an input with id updates the existing variant of the product.
+// The price of the product becomes the lowest variant price and the quantity becomes the total variants stock.
+func (p *productUsecase) setVariants(product *model.Product, inputs []model.CreateProductVariantInput) error {
+	if !product.HasVariants() {
+		product.Variants = nil
+		return nil
+	}
+
+	existingVariants := model.AnyProductVariants(product.Variants)
+	variants := make([]*model.ProductVariant, 0, len(inputs))
+	var quantity int64
+	for i, input := range inputs {
+		variant := &model.ProductVariant{
+			ID:        input.ID,
+			ProductID: product.ID,
+			Name:      product.Options.VariantName(input.Options),
+			SKU:       input.SKU,
+			Barcode:   input.Barcode,
+			Options:   input.Options,
+			Price:     input.Price,
+			Quantity:  input.Quantity,
+		}
+
+		switch {
+		case variant.ID <= 0:
+			variant.ID = utils.GenerateID()
+		case existingVariants.FindByID(variant.ID) == nil:
+			return model.ErrUnknownProductVariant
+		}
+
+		if variant.SKU == "" {
+			variant.SKU = strings.ToUpper(slug.Make(product.Slug + " " + variant.Name))
+		}
+
+		if i == 0 || variant.Price < product.Price {
+			product.Price = variant.Price
+		}
+		quantity += variant.Quantity
+		variants = append(variants, variant)
+	}
+
+	product.Variants = variants
+	product.Quantity = quantity
+
+	return nil
+}
+
+// validateCodes make sure every sku & barcode of the product and its variants points to a single item,
+// both among the product itself and among the stored products & variants
+func (p *productUsecase) validateCodes(ctx context.Context, product *model.Product) error {
+	owners := make(map[string]model.ProductVariantKey)
+	addCode := func(code string, owner model.ProductVariantKey) error {
+		if code == "" {
+			return nil
+		}
+		if other, ok := owners[code]; ok && other != owner {
+			return model.ErrDuplicateProductCode
+		}
+		owners[code] = owner
+		return nil
+	}
+
+	for _, code := range []string{product.SKU, product.Barcode} {
+		if err := addCode(code, model.ProductVariantKey{ProductID: product.ID}); err != nil {
+			return err
+		}
+	}
+	for _, variant := range product.Variants {
+		for _, code := range []string{variant.SKU, variant.Barcode} {
+			if err := addCode(code, model.ProductVariantKey{ProductID: product.ID, VariantID: variant.ID}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for code, owner := range owners {
+		existingProduct, err := p.productRepo.FindByCode(ctx, code)
+		if err != nil {
+			return err
+		}
+		if existingProduct != nil && (existingProduct.ID != owner.ProductID || owner.VariantID > 0) {
+			return model.ErrDuplicateProductCode
+		}
+
+		existingVariant, err := p.productVariantRepo.FindByCode(ctx, code)
+		if err != nil {
+			return err
+		}
+		if existingVariant != nil && existingVariant.ID != owner.VariantID {
+			return model.ErrDuplicateProductCode
+		}
+	}
+
+	return nil
+}
+
+// validateCategory make sure the category exists, zero means the product is not categorized
+func (p *productUsecase) validateCategory(ctx context.Context, categoryID int64) error {
+	if categoryID <= 0 {
+		return nil
+	}
+
+	category, err := p.categoryRepo.FindByID(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return model.ErrUnknownCategory
+	}
+
+	return nil
+}
+
+// parseTaxCategory default the empty category to STANDARD and make sure the category has a configured rate
+func parseTaxCategory(category model.TaxCategory) (model.TaxCategory, error) {
+	category = model.TaxCategory(strings.ToUpper(string(category)))
+	if category == "" {
+		category = model.TaxCategoryStandard
+	}
+
+	if !newTaxSetting().HasCategory(category) {
+		return "", model.ErrUnknownTaxCategory
+	}
+
+	return category, nil
+}
+
+func newVariantLabel(product *model.Product, variant *model.ProductVariant) label.Item {
+	return label.Item{
+		Name:  product.Name + " (" + variant.Name + ")",
+		Price: variant.Price,
+		Code:  labelCode(variant.Barcode, variant.SKU, variant.ID),
+	}
+}
+
+// labelCode the code printed on the label, the barcode is preferred over the sku and the id is the last resort
+func labelCode(code, sku string, id int64) string {
+	if code != "" {
+		return code
+	}
+	if sku != "" && barcode.Validate(sku) == nil {
+		return sku
+	}
+
+	return utils.Int64ToString(id)
+}
+
+// findProductByCode find the product of a scanned code, the product codes are looked up before the variant codes.
+// The variant is nil when the code belongs to the product itself.
+func findProductByCode(
+	ctx context.Context,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	code string,
+) (*model.Product, *model.ProductVariant, error) {
+	code = barcode.Normalize(code)
+	if code == "" {
+		return nil, nil, ErrNotFound
+	}
+
+	product, err := productRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, nil, err
+	}
+	if product != nil {
+		return product, nil, nil
+	}
+
+	variant, err := productVariantRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, nil, err
+	}
+	if variant == nil {
+		return nil, nil, ErrNotFound
+	}
+
+	product, err = productRepo.FindByID(ctx, variant.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if product == nil {
+		return nil, nil, ErrNotFound
+	}
+
+	return product, variant, nil
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductUsecase_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockProductVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	ucase := productUsecase{productRepo: mockProductRepo, productVariantRepo: mockProductVariantRepo}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+
+	existingProduct := &model.Product{
+		ID:          222,
+		Name:        "Pisang Goreng",
+		Slug:        "pisang-goreng",
+		SKU:         "PISANG-GORENG",
+		Barcode:     "8991234567891",
+		Description: "Pisang goreng gurih",
+		Price:       6000,
+		CostPrice:   4000,
+		Quantity:    20,
+		TaxCategory: model.TaxCategoryStandard,
+	}
+	mockProductRepo.EXPECT().FindByCode(ctx, gomock.Any()).AnyTimes().DoAndReturn(func(_ context.Context, code string) (*model.Product, error) {
+		if existingProduct.HasCode(code) {
+			return existingProduct, nil
+		}
+		return nil, nil
+	})
+	mockProductRepo.EXPECT().FindBySlug(ctx, gomock.Any()).AnyTimes().DoAndReturn(func(_ context.Context, slug string) (*model.Product, error) {
+		if slug == existingProduct.Slug {
+			return existingProduct, nil
+		}
+		return nil, nil
+	})
+	mockProductVariantRepo.EXPECT().FindByCode(ctx, gomock.Any()).AnyTimes().Return(nil, nil)
+
+	newInput := func(t *testing.T) model.ImportProductsInput {
+		input, err := model.ParseProductImportRecords([][]string{
+			{"Name", "SKU", "Price", "Quantity"},
+			{"Kopi Susu", "", "18000", "12"},
+			{"Pisang Goreng Keju", "PISANG-GORENG", "7000", ""},
+			{"Es Teh", "", "abc", "5"},
+			{"Kopi Susu", "KOPI-SUSU-2", "19000", "3"},
+			{"Teh Tawar", "", "-1", "5"},
+		})
+		require.NoError(t, err)
+		return input
+	}
+
+	expectedErrors := []*model.ProductImportRowError{
+		{Line: 4, Error: `price: "abc" is not a whole number`},
+		{Line: 5, Error: model.ErrDuplicateProductImportRow.Error()},
+		{Line: 6, Error: "price: failed on the 'gte' tag"},
+	}
+
+	t.Run("ok - dry run", func(t *testing.T) {
+		input := newInput(t)
+		input.DryRun = true
+
+		res, err := ucase.Import(ctx, manager, input)
+		require.NoError(t, err)
+		require.Equal(t, &model.ProductImportResult{
+			DryRun:  true,
+			Total:   5,
+			Created: 1,
+			Updated: 1,
+			Errors:  expectedErrors,
+		}, res)
+	})
+
+	t.Run("ok - the existing product keeps the columns missing from the file", func(t *testing.T) {
+		mockProductRepo.EXPECT().Import(ctx, manager.ID, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ int64, imports []*model.ProductImport) error {
+			require.Len(t, imports, 2)
+
+			require.True(t, imports[0].IsNew)
+			require.Equal(t, 2, imports[0].Line)
+			require.Equal(t, "KOPI-SUSU", imports[0].Product.SKU)
+			require.Equal(t, int64(12), imports[0].Product.Quantity)
+			require.Equal(t, model.TaxCategoryStandard, imports[0].Product.TaxCategory)
+
+			updated := imports[1].Product
+			require.False(t, imports[1].IsNew)
+			require.Equal(t, existingProduct.ID, updated.ID)
+			require.Equal(t, "pisang-goreng-keju", updated.Slug)
+			require.Equal(t, int64(7000), updated.Price)
+			require.Equal(t, existingProduct.Barcode, updated.Barcode)
+			require.Equal(t, existingProduct.Description, updated.Description)
+			return nil
+		})
+
+		res, err := ucase.Import(ctx, manager, newInput(t))
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Created)
+		require.Equal(t, 1, res.Updated)
+		require.Equal(t, expectedErrors, res.Errors)
+		// the stored product is never mutated
+		require.Equal(t, "Pisang Goreng", existingProduct.Name)
+	})
+
+	t.Run("ok - the rows of a failed batch are reported", func(t *testing.T) {
+		viper.Set("product_import.batch_size", 1)
+		defer viper.Set("product_import.batch_size", 0)
+
+		gomock.InOrder(
+			mockProductRepo.EXPECT().Import(ctx, manager.ID, gomock.Len(1)).Times(1).Return(nil),
+			mockProductRepo.EXPECT().Import(ctx, manager.ID, gomock.Len(1)).Times(1).Return(errors.New("db error")),
+		)
+
+		res, err := ucase.Import(ctx, manager, newInput(t))
+		require.NoError(t, err)
+		require.Equal(t, 1, res.Created)
+		require.Equal(t, 0, res.Updated)
+		require.Len(t, res.Errors, 4)
+		require.Equal(t, &model.ProductImportRowError{Line: 3, Error: model.ErrProductImportBatchFailed.Error()}, res.Errors[0])
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		_, err := ucase.Import(ctx, newUserWithRole(111, rbac.RoleCashiers), newInput(t))
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+}
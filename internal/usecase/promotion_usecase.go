@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+type promotionUsecase struct {
+	promotionRepo model.PromotionRepository
+	productRepo   model.ProductRepository
+}
+
+// NewPromotionUsecase instantiate a new promotion usecase
+func NewPromotionUsecase(promotionRepo model.PromotionRepository, productRepo model.ProductRepository) model.PromotionUsecase {
+	return &promotionUsecase{
+		promotionRepo: promotionRepo,
+		productRepo:   productRepo,
+	}
+}
+
+// FindByID find promotion by specific id
+func (p *promotionUsecase) FindByID(ctx context.Context, requester *model.User, id int64) (*model.Promotion, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":         utils.DumpIncomingContext(ctx),
+		"requester":   utils.Dump(requester),
+		"promotionID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourcePromotion, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	promotion, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// Search promotion with given search criteria
+func (p *promotionUsecase) Search(ctx context.Context, requester *model.User, criteria model.PromotionSearchCriteria) (promotions model.AnyPromotions, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourcePromotion, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	promotionIDs, count, err := p.promotionRepo.SearchByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(promotionIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	promotions = p.findAllByIDs(ctx, promotionIDs)
+	if len(promotions) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// Create promotion from input
+func (p *promotionUsecase) Create(ctx context.Context, requester *model.User, input model.CreatePromotionInput) (*model.Promotion, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourcePromotion, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.ValidateAndFormat(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.validateProduct(ctx, input.ProductID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	promotion := &model.Promotion{ID: utils.GenerateID()}
+	setPromotionFromInput(promotion, input)
+
+	if err := p.promotionRepo.Create(ctx, requester.ID, promotion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// UpdateByID update promotion with id
+func (p *promotionUsecase) UpdateByID(ctx context.Context, requester *model.User, id int64, input model.UpdatePromotionInput) (*model.Promotion, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":         utils.DumpIncomingContext(ctx),
+		"requester":   utils.Dump(requester),
+		"promotionID": id,
+		"input":       utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourcePromotion, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.ValidateAndFormat(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	oldPromotion, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.validateProduct(ctx, input.ProductID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	updatedPromotion := *oldPromotion
+	setPromotionFromInput(&updatedPromotion, input)
+
+	if err := p.promotionRepo.Update(ctx, requester.ID, &updatedPromotion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &updatedPromotion, nil
+}
+
+// DeleteByID delete promotion by id
+func (p *promotionUsecase) DeleteByID(ctx context.Context, requester *model.User, id int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":         utils.DumpIncomingContext(ctx),
+		"requester":   utils.Dump(requester),
+		"promotionID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourcePromotion, rbac.ActionDeleteAny) {
+		return ErrPermissionDenied
+	}
+
+	promotion, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := p.promotionRepo.Delete(ctx, requester.ID, promotion); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// validateProduct make sure the target product of the promotion exists
+func (p *promotionUsecase) validateProduct(ctx context.Context, productID int64) error {
+	if productID <= 0 {
+		return nil
+	}
+
+	product, err := p.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *promotionUsecase) findByID(ctx context.Context, id int64) (*model.Promotion, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	promotion, err := p.promotionRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if promotion == nil {
+		return nil, ErrNotFound
+	}
+
+	return promotion, nil
+}
+
+// findAllByIDs find all promotions with IDs
+func (p *promotionUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model.Promotion {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.Promotion, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			promotion, err := p.findByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- promotion
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.Promotion{}
+	for promotion := range c {
+		if promotion != nil {
+			rs[promotion.ID] = promotion
+		}
+	}
+
+	// sort promotions based on the order of received ids
+	var promotions []*model.Promotion
+	for _, id := range ids {
+		if promotion, ok := rs[id]; ok {
+			promotions = append(promotions, promotion)
+		}
+	}
+
+	return promotions
+}
+
+func setPromotionFromInput(promotion *model.Promotion, input model.CreatePromotionInput) {
+	promotion.Name = input.Name
+	promotion.Type = input.Type
+	promotion.ProductID = input.ProductID
+	promotion.Value = input.Value
+	promotion.BuyQuantity = input.BuyQuantity
+	promotion.GetQuantity = input.GetQuantity
+	promotion.MinimumSpend = input.MinimumSpend
+	promotion.StartAt = input.StartAt
+	promotion.EndAt = input.EndAt
+	promotion.DailyStartTime = input.DailyStartTime
+	promotion.DailyEndTime = input.DailyEndTime
+	promotion.IsActive = input.IsActive
+}
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+type purchaseOrderUsecase struct {
+	purchaseOrderRepo  model.PurchaseOrderRepository
+	supplierRepo       model.SupplierRepository
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+}
+
+// NewPurchaseOrderUsecase instantiate a new purchase order usecase
+func NewPurchaseOrderUsecase(
+	purchaseOrderRepo model.PurchaseOrderRepository,
+	supplierRepo model.SupplierRepository,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+) model.PurchaseOrderUsecase {
+	return &purchaseOrderUsecase{
+		purchaseOrderRepo:  purchaseOrderRepo,
+		supplierRepo:       supplierRepo,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+	}
+}
+
+// FindByID find purchase order by specific id along with its items & goods receipts
+func (p *purchaseOrderUsecase) FindByID(ctx context.Context, requester *model.User, id int64) (*model.PurchaseOrder, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":             utils.DumpIncomingContext(ctx),
+		"requester":       utils.Dump(requester),
+		"purchaseOrderID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourcePurchaseOrder, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	order, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// Search purchase order with given search criteria
+func (p *purchaseOrderUsecase) Search(ctx context.Context, requester *model.User, criteria model.PurchaseOrderSearchCriteria) (orders model.AnyPurchaseOrders, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourcePurchaseOrder, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	orderIDs, count, err := p.purchaseOrderRepo.SearchByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(orderIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	orders = p.findAllByIDs(ctx, orderIDs)
+	if len(orders) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// Create a draft purchase order, the items can be changed until it is sent to the supplier
+func (p *purchaseOrderUsecase) Create(ctx context.Context, requester *model.User, input model.CreatePurchaseOrderInput) (*model.PurchaseOrder, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourcePurchaseOrder, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	order := &model.PurchaseOrder{ID: utils.GenerateID()}
+	if err := p.setPurchaseOrderFromInput(ctx, order, input); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.purchaseOrderRepo.Create(ctx, requester.ID, order); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// UpdateByID replace the supplier, note & items of a draft purchase order
+func (p *purchaseOrderUsecase) UpdateByID(ctx context.Context, requester *model.User, id int64, input model.UpdatePurchaseOrderInput) (*model.PurchaseOrder, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":             utils.DumpIncomingContext(ctx),
+		"requester":       utils.Dump(requester),
+		"purchaseOrderID": id,
+		"input":           utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourcePurchaseOrder, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	oldOrder, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !oldOrder.IsDraft() {
+		return nil, model.ErrInvalidPurchaseOrderStatus
+	}
+
+	updatedOrder := *oldOrder
+	if err := p.setPurchaseOrderFromInput(ctx, &updatedOrder, input); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := p.purchaseOrderRepo.Update(ctx, requester.ID, &updatedOrder); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &updatedOrder, nil
+}
+
+// Send mark the draft purchase order as sent to the supplier, its goods can be received afterwards
+func (p *purchaseOrderUsecase) Send(ctx context.Context, requester *model.User, id int64) (*model.PurchaseOrder, error) {
+	return p.updateStatusByID(ctx, requester, id, model.PurchaseOrderStatusSent)
+}
+
+// Close the purchase order, the items which are not received yet are given up
+func (p *purchaseOrderUsecase) Close(ctx context.Context, requester *model.User, id int64) (*model.PurchaseOrder, error) {
+	return p.updateStatusByID(ctx, requester, id, model.PurchaseOrderStatusClosed)
+}
+
+// Receive record a goods receipt of the purchase order, the received goods are added to the stock
+// and the cost price of the received products is averaged
+func (p *purchaseOrderUsecase) Receive(ctx context.Context, requester *model.User, id int64, input model.ReceivePurchaseOrderInput) (*model.PurchaseOrder, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":             utils.DumpIncomingContext(ctx),
+		"requester":       utils.Dump(requester),
+		"purchaseOrderID": id,
+		"input":           utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourcePurchaseOrder, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	order, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !order.Status.CanReceive() {
+		return nil, model.ErrInvalidPurchaseOrderStatus
+	}
+
+	receipt := &model.GoodsReceipt{
+		ID:   utils.GenerateID(),
+		Note: input.Note,
+	}
+	for _, item := range input.Items {
+		receipt.Items = append(receipt.Items, &model.GoodsReceiptItem{
+			PurchaseOrderItemID: item.PurchaseOrderItemID,
+			Quantity:            item.Quantity,
+		})
+	}
+
+	if err := p.purchaseOrderRepo.Receive(ctx, requester.ID, order, receipt); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (p *purchaseOrderUsecase) updateStatusByID(ctx context.Context, requester *model.User, id int64, status model.PurchaseOrderStatus) (*model.PurchaseOrder, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":             utils.DumpIncomingContext(ctx),
+		"requester":       utils.Dump(requester),
+		"purchaseOrderID": id,
+		"status":          status,
+	})
+
+	if !requester.HasAccess(rbac.ResourcePurchaseOrder, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	order, err := p.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(status) {
+		return nil, model.ErrInvalidPurchaseOrderStatus
+	}
+
+	if err := p.purchaseOrderRepo.UpdateStatus(ctx, requester.ID, order, status); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// setPurchaseOrderFromInput set the supplier & items of the input, the supplier and the ordered products must exist
+func (p *purchaseOrderUsecase) setPurchaseOrderFromInput(ctx context.Context, order *model.PurchaseOrder, input model.CreatePurchaseOrderInput) error {
+	supplier, err := p.supplierRepo.FindByID(ctx, input.SupplierID)
+	if err != nil {
+		return err
+	}
+	if supplier == nil {
+		return model.ErrUnknownSupplier
+	}
+
+	items := make([]*model.PurchaseOrderItem, 0, len(input.Items))
+	for _, item := range input.Items {
+		if _, err := findStockedProduct(ctx, p.productRepo, p.productVariantRepo, item.ProductID, item.VariantID); err != nil {
+			return err
+		}
+
+		items = append(items, &model.PurchaseOrderItem{
+			ID:        utils.GenerateID(),
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			CostPrice: item.CostPrice,
+		})
+	}
+
+	order.SupplierID = supplier.ID
+	order.Note = input.Note
+	order.Items = items
+	order.CalculateTotalCost()
+
+	return nil
+}
+
+func (p *purchaseOrderUsecase) findByID(ctx context.Context, id int64) (*model.PurchaseOrder, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	order, err := p.purchaseOrderRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrNotFound
+	}
+
+	return order, nil
+}
+
+// findAllByIDs find all purchase orders with IDs
+func (p *purchaseOrderUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model.PurchaseOrder {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.PurchaseOrder, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			order, err := p.findByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- order
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.PurchaseOrder{}
+	for order := range c {
+		if order != nil {
+			rs[order.ID] = order
+		}
+	}
+
+	// sort purchase orders based on the order of received ids
+	var orders []*model.PurchaseOrder
+	for _, id := range ids {
+		if order, ok := rs[id]; ok {
+			orders = append(orders, order)
+		}
+	}
+
+	return orders
+}
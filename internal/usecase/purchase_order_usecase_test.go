@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchaseOrderUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockPurchaseOrderRepo := mock.NewMockPurchaseOrderRepository(ctrl)
+	mockSupplierRepo := mock.NewMockSupplierRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockProductVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	ucase := purchaseOrderUsecase{
+		purchaseOrderRepo:  mockPurchaseOrderRepo,
+		supplierRepo:       mockSupplierRepo,
+		productRepo:        mockProductRepo,
+		productVariantRepo: mockProductVariantRepo,
+	}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+	supplier := &model.Supplier{ID: 555, Name: "CV Sumber Rejeki"}
+	product := &model.Product{ID: 222, Name: "Pisang Goreng"}
+
+	t.Run("ok", func(t *testing.T) {
+		input := model.CreatePurchaseOrderInput{
+			SupplierID: supplier.ID,
+			Items:      []model.PurchaseOrderItemInput{{ProductID: product.ID, Quantity: 24, CostPrice: 3000}},
+		}
+
+		mockSupplierRepo.EXPECT().FindByID(ctx, supplier.ID).Times(1).Return(supplier, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockPurchaseOrderRepo.EXPECT().Create(ctx, manager.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, manager, input)
+		require.NoError(t, err)
+		require.Equal(t, supplier.ID, res.SupplierID)
+		require.Equal(t, int64(72000), res.TotalCost)
+		require.Len(t, res.Items, 1)
+	})
+
+	t.Run("failed - unknown supplier", func(t *testing.T) {
+		mockSupplierRepo.EXPECT().FindByID(ctx, int64(666)).Times(1).Return(nil, nil)
+
+		res, err := ucase.Create(ctx, manager, model.CreatePurchaseOrderInput{
+			SupplierID: 666,
+			Items:      []model.PurchaseOrderItemInput{{ProductID: product.ID, Quantity: 24, CostPrice: 3000}},
+		})
+		require.ErrorIs(t, err, model.ErrUnknownSupplier)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.Create(ctx, newUserWithRole(111, rbac.RoleCashiers), model.CreatePurchaseOrderInput{
+			SupplierID: supplier.ID,
+			Items:      []model.PurchaseOrderItemInput{{ProductID: product.ID, Quantity: 24, CostPrice: 3000}},
+		})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestPurchaseOrderUsecase_Receive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockPurchaseOrderRepo := mock.NewMockPurchaseOrderRepository(ctrl)
+	ucase := purchaseOrderUsecase{purchaseOrderRepo: mockPurchaseOrderRepo}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+	input := model.ReceivePurchaseOrderInput{
+		Note:  "surat jalan 0042",
+		Items: []model.GoodsReceiptItemInput{{PurchaseOrderItemID: 701, Quantity: 12}},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		order := &model.PurchaseOrder{ID: 777, Status: model.PurchaseOrderStatusSent}
+
+		mockPurchaseOrderRepo.EXPECT().FindByID(ctx, order.ID).Times(1).Return(order, nil)
+		mockPurchaseOrderRepo.EXPECT().Receive(ctx, manager.ID, order, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ int64, _ *model.PurchaseOrder, receipt *model.GoodsReceipt) error {
+				require.Equal(t, input.Note, receipt.Note)
+				require.Equal(t, []*model.GoodsReceiptItem{{PurchaseOrderItemID: 701, Quantity: 12}}, receipt.Items)
+				return nil
+			})
+
+		res, err := ucase.Receive(ctx, manager, order.ID, input)
+		require.NoError(t, err)
+		require.Equal(t, order, res)
+	})
+
+	t.Run("failed - draft purchase order", func(t *testing.T) {
+		mockPurchaseOrderRepo.EXPECT().FindByID(ctx, int64(888)).Times(1).
+			Return(&model.PurchaseOrder{ID: 888, Status: model.PurchaseOrderStatusDraft}, nil)
+
+		res, err := ucase.Receive(ctx, manager, 888, input)
+		require.ErrorIs(t, err, model.ErrInvalidPurchaseOrderStatus)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - purchase order item received twice", func(t *testing.T) {
+		res, err := ucase.Receive(ctx, manager, 777, model.ReceivePurchaseOrderInput{
+			Items: []model.GoodsReceiptItemInput{{PurchaseOrderItemID: 701, Quantity: 1}, {PurchaseOrderItemID: 701, Quantity: 2}},
+		})
+		require.ErrorIs(t, err, model.ErrDuplicateProductVariant)
+		require.Nil(t, res)
+	})
+}
+
+func TestPurchaseOrderUsecase_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockPurchaseOrderRepo := mock.NewMockPurchaseOrderRepository(ctrl)
+	ucase := purchaseOrderUsecase{purchaseOrderRepo: mockPurchaseOrderRepo}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+
+	t.Run("ok", func(t *testing.T) {
+		order := &model.PurchaseOrder{ID: 777, Status: model.PurchaseOrderStatusDraft}
+
+		mockPurchaseOrderRepo.EXPECT().FindByID(ctx, order.ID).Times(1).Return(order, nil)
+		mockPurchaseOrderRepo.EXPECT().UpdateStatus(ctx, manager.ID, order, model.PurchaseOrderStatusSent).Times(1).Return(nil)
+
+		res, err := ucase.Send(ctx, manager, order.ID)
+		require.NoError(t, err)
+		require.Equal(t, order, res)
+	})
+
+	t.Run("failed - already received", func(t *testing.T) {
+		mockPurchaseOrderRepo.EXPECT().FindByID(ctx, int64(888)).Times(1).
+			Return(&model.PurchaseOrder{ID: 888, Status: model.PurchaseOrderStatusReceived}, nil)
+
+		res, err := ucase.Send(ctx, manager, 888)
+		require.ErrorIs(t, err, model.ErrInvalidPurchaseOrderStatus)
+		require.Nil(t, res)
+	})
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+type reportUsecase struct {
+	reportRepo model.ReportRepository
+}
+
+// NewReportUsecase instantiate a new report usecase
+func NewReportUsecase(reportRepo model.ReportRepository) model.ReportUsecase {
+	return &reportUsecase{
+		reportRepo: reportRepo,
+	}
+}
+
+// GenerateXReport aggregate the sales since the last Z report until now without closing the period
+func (r *reportUsecase) GenerateXReport(ctx context.Context, requester *model.User) (*model.SalesReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if !requester.HasAccess(rbac.ResourceReport, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	var startAt time.Time
+	lastZReport, err := r.reportRepo.FindLastZReport(ctx)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if lastZReport != nil {
+		startAt = lastZReport.PeriodEnd
+	}
+
+	report, err := r.reportRepo.Aggregate(ctx, startAt, time.Now())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	report.Type = model.ReportTypeX
+	return report, nil
+}
+
+// CreateZReport close the period since the last Z report and store it as the next numbered Z report
+func (r *reportUsecase) CreateZReport(ctx context.Context, requester *model.User) (*model.SalesReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if !requester.HasAccess(rbac.ResourceReport, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	report := &model.SalesReport{ID: utils.GenerateID()}
+	if err := r.reportRepo.CreateZReport(ctx, requester.ID, report); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// FindZReportByID find Z report by specific id
+func (r *reportUsecase) FindZReportByID(ctx context.Context, requester *model.User, id int64) (*model.SalesReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"zReportID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceReport, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	report, err := r.findZReportByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// SearchZReports search Z reports ordered by the newest number
+func (r *reportUsecase) SearchZReports(ctx context.Context, requester *model.User, criteria model.ZReportSearchCriteria) (reports model.AnySalesReports, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceReport, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	reportIDs, count, err := r.reportRepo.SearchZReportsByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(reportIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	reports = r.findAllZReportsByIDs(ctx, reportIDs)
+	if len(reports) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// GenerateProfitReport aggregate the gross profit & margin of the sales made within the date range of the criteria,
+// a missing end date ends the period now
+func (r *reportUsecase) GenerateProfitReport(ctx context.Context, requester *model.User, criteria model.ProfitReportCriteria) (*model.ProfitReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceReport, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := criteria.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	var startAt time.Time
+	endAt := time.Now()
+	start, end, err := criteria.DateRange()
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if start != nil {
+		startAt = *start
+	}
+	if end != nil {
+		endAt = *end
+	}
+
+	report, err := r.reportRepo.AggregateProfit(ctx, criteria.GroupBy, startAt, endAt)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *reportUsecase) findZReportByID(ctx context.Context, id int64) (*model.SalesReport, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	report, err := r.reportRepo.FindZReportByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if report == nil {
+		return nil, ErrNotFound
+	}
+
+	return report, nil
+}
+
+// findAllZReportsByIDs find all Z reports with IDs
+func (r *reportUsecase) findAllZReportsByIDs(ctx context.Context, ids []int64) []*model.SalesReport {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.SalesReport, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			report, err := r.findZReportByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- report
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.SalesReport{}
+	for report := range c {
+		if report != nil {
+			rs[report.ID] = report
+		}
+	}
+
+	// sort reports based on the order of received ids
+	var reports []*model.SalesReport
+	for _, id := range ids {
+		if report, ok := rs[id]; ok {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports
+}
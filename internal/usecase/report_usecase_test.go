@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportUsecase_GenerateXReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockReportRepo := mock.NewMockReportRepository(ctrl)
+	ucase := reportUsecase{reportRepo: mockReportRepo}
+	auditor := newUserWithRole(111, rbac.RoleFinancialAuditor)
+
+	t.Run("ok - since the last Z report", func(t *testing.T) {
+		lastPeriodEnd := time.Date(2023, 9, 24, 22, 0, 0, 0, time.UTC)
+		mockReportRepo.EXPECT().FindLastZReport(ctx).Times(1).Return(&model.SalesReport{ID: 1, Number: 11, PeriodEnd: lastPeriodEnd}, nil)
+		mockReportRepo.EXPECT().Aggregate(ctx, lastPeriodEnd, gomock.Any()).Times(1).
+			Return(&model.SalesReport{PeriodStart: lastPeriodEnd, Summary: &model.SalesSummary{TransactionCount: 3}}, nil)
+
+		res, err := ucase.GenerateXReport(ctx, auditor)
+		require.NoError(t, err)
+		require.Equal(t, model.ReportTypeX, res.Type)
+		require.Equal(t, int64(0), res.Number)
+		require.Equal(t, int64(3), res.Summary.TransactionCount)
+	})
+
+	t.Run("ok - without any Z report", func(t *testing.T) {
+		mockReportRepo.EXPECT().FindLastZReport(ctx).Times(1).Return(nil, nil)
+		mockReportRepo.EXPECT().Aggregate(ctx, time.Time{}, gomock.Any()).Times(1).
+			Return(&model.SalesReport{Summary: &model.SalesSummary{}}, nil)
+
+		res, err := ucase.GenerateXReport(ctx, auditor)
+		require.NoError(t, err)
+		require.Equal(t, model.ReportTypeX, res.Type)
+	})
+
+	t.Run("failed - cashier can not see the report", func(t *testing.T) {
+		res, err := ucase.GenerateXReport(ctx, newUserWithRole(222, rbac.RoleCashiers))
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestReportUsecase_CreateZReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockReportRepo := mock.NewMockReportRepository(ctrl)
+	ucase := reportUsecase{reportRepo: mockReportRepo}
+	auditor := newUserWithRole(111, rbac.RoleFinancialAuditor)
+
+	t.Run("ok", func(t *testing.T) {
+		mockReportRepo.EXPECT().CreateZReport(ctx, auditor.ID, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, userID int64, report *model.SalesReport) error {
+				require.NotZero(t, report.ID)
+				report.Type = model.ReportTypeZ
+				report.Number = 12
+				report.CreatedBy = userID
+				return nil
+			})
+
+		res, err := ucase.CreateZReport(ctx, auditor)
+		require.NoError(t, err)
+		require.Equal(t, int64(12), res.Number)
+		require.Equal(t, auditor.ID, res.CreatedBy)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.CreateZReport(ctx, newUserWithRole(222, rbac.RoleProductManager))
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestReportUsecase_GenerateProfitReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockReportRepo := mock.NewMockReportRepository(ctrl)
+	ucase := reportUsecase{reportRepo: mockReportRepo}
+	auditor := newUserWithRole(111, rbac.RoleFinancialAuditor)
+
+	t.Run("ok - within the date range", func(t *testing.T) {
+		// the dates are on western indonesian time and the end date is inclusive
+		startAt := time.Date(2023, 8, 31, 17, 0, 0, 0, time.UTC)
+		endAt := time.Date(2023, 9, 30, 17, 0, 0, 0, time.UTC)
+		report := &model.ProfitReport{GroupBy: model.ProfitReportGroupCategory, PeriodStart: startAt, PeriodEnd: endAt}
+		mockReportRepo.EXPECT().AggregateProfit(ctx, model.ProfitReportGroupCategory, gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ model.ProfitReportGroup, start, end time.Time) (*model.ProfitReport, error) {
+				require.True(t, startAt.Equal(start))
+				require.True(t, endAt.Equal(end))
+				return report, nil
+			})
+
+		res, err := ucase.GenerateProfitReport(ctx, auditor, model.ProfitReportCriteria{
+			GroupBy:   model.ProfitReportGroupCategory,
+			StartDate: "2023-09-01",
+			EndDate:   "2023-09-30",
+		})
+		require.NoError(t, err)
+		require.Equal(t, report, res)
+	})
+
+	t.Run("ok - without date range", func(t *testing.T) {
+		mockReportRepo.EXPECT().AggregateProfit(ctx, model.ProfitReportGroupDay, time.Time{}, gomock.Any()).Times(1).
+			Return(&model.ProfitReport{GroupBy: model.ProfitReportGroupDay}, nil)
+
+		res, err := ucase.GenerateProfitReport(ctx, auditor, model.ProfitReportCriteria{GroupBy: model.ProfitReportGroupDay})
+		require.NoError(t, err)
+		require.Equal(t, model.ProfitReportGroupDay, res.GroupBy)
+	})
+
+	t.Run("failed - unknown group", func(t *testing.T) {
+		res, err := ucase.GenerateProfitReport(ctx, auditor, model.ProfitReportCriteria{GroupBy: "SUPPLIER"})
+		require.ErrorIs(t, err, model.ErrUnknownProfitReportGroup)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - end date before start date", func(t *testing.T) {
+		res, err := ucase.GenerateProfitReport(ctx, auditor, model.ProfitReportCriteria{
+			GroupBy:   model.ProfitReportGroupProduct,
+			StartDate: "2023-09-30",
+			EndDate:   "2023-09-01",
+		})
+		require.ErrorIs(t, err, model.ErrInvalidDateRange)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - cashier can not see the cost", func(t *testing.T) {
+		res, err := ucase.GenerateProfitReport(ctx, newUserWithRole(222, rbac.RoleCashiers), model.ProfitReportCriteria{
+			GroupBy: model.ProfitReportGroupProduct,
+		})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
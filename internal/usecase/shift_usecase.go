@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+type shiftUsecase struct {
+	shiftRepo model.ShiftRepository
+}
+
+// NewShiftUsecase instantiate a new shift usecase
+func NewShiftUsecase(shiftRepo model.ShiftRepository) model.ShiftUsecase {
+	return &shiftUsecase{
+		shiftRepo: shiftRepo,
+	}
+}
+
+// FindByID find shift by specific id
+func (s *shiftUsecase) FindByID(ctx context.Context, requester *model.User, id int64) (*model.Shift, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"shiftID":   id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceShift, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	shift, err := s.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// FindCurrent find the open shift of the requester
+func (s *shiftUsecase) FindCurrent(ctx context.Context, requester *model.User) (*model.Shift, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if !requester.HasAccess(rbac.ResourceShift, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	shift, err := s.shiftRepo.FindOpenByCashierID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if shift == nil {
+		return nil, ErrNotFound
+	}
+
+	return shift, nil
+}
+
+// Search shift with given search criteria
+func (s *shiftUsecase) Search(ctx context.Context, requester *model.User, criteria model.ShiftSearchCriteria) (shifts model.AnyShifts, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceShift, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	shiftIDs, count, err := s.shiftRepo.SearchByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(shiftIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	shifts = s.findAllByIDs(ctx, shiftIDs)
+	if len(shifts) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// Open a new shift for the requester with the opening float counted in the drawer
+func (s *shiftUsecase) Open(ctx context.Context, requester *model.User, input model.OpenShiftInput) (*model.Shift, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceShift, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	shift := &model.Shift{
+		ID:           utils.GenerateID(),
+		CashierID:    requester.ID,
+		OpeningFloat: input.OpeningFloat,
+		OpeningNote:  input.Note,
+	}
+
+	if err := s.shiftRepo.Open(ctx, requester.ID, shift); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// AddCashMovement record a cash in or cash out of the requester open shift
+func (s *shiftUsecase) AddCashMovement(ctx context.Context, requester *model.User, id int64, input model.CashMovementInput) (*model.CashMovement, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"shiftID":   id,
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceShift, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	shift, err := s.findOwnOpenShift(ctx, requester, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	movement := &model.CashMovement{
+		ID:      utils.GenerateID(),
+		ShiftID: shift.ID,
+		Type:    input.Type,
+		Amount:  input.Amount,
+		Reason:  input.Reason,
+	}
+
+	if err := s.shiftRepo.CreateCashMovement(ctx, requester.ID, movement); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// Close the requester open shift, the expected cash & variance are computed against the counted closing cash
+func (s *shiftUsecase) Close(ctx context.Context, requester *model.User, id int64, input model.CloseShiftInput) (*model.Shift, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"shiftID":   id,
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceShift, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	shift, err := s.findOwnOpenShift(ctx, requester, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	closedShift := *shift
+	closedShift.ClosingNote = input.Note
+	if err := s.shiftRepo.Close(ctx, requester.ID, &closedShift, input.ClosingCash); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &closedShift, nil
+}
+
+// findOwnOpenShift find the shift and make sure it is still open and belongs to the requester
+func (s *shiftUsecase) findOwnOpenShift(ctx context.Context, requester *model.User, id int64) (*model.Shift, error) {
+	shift, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if shift.CashierID != requester.ID {
+		return nil, ErrPermissionDenied
+	}
+
+	if !shift.IsOpen() {
+		return nil, model.ErrShiftClosed
+	}
+
+	return shift, nil
+}
+
+func (s *shiftUsecase) findByID(ctx context.Context, id int64) (*model.Shift, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	shift, err := s.shiftRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if shift == nil {
+		return nil, ErrNotFound
+	}
+
+	return shift, nil
+}
+
+// findAllByIDs find all shifts with IDs
+func (s *shiftUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model.Shift {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.Shift, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			shift, err := s.findByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- shift
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.Shift{}
+	for shift := range c {
+		if shift != nil {
+			rs[shift.ID] = shift
+		}
+	}
+
+	// sort shifts based on the order of received ids
+	var shifts []*model.Shift
+	for _, id := range ids {
+		if shift, ok := rs[id]; ok {
+			shifts = append(shifts, shift)
+		}
+	}
+
+	return shifts
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShiftUsecase_Open(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := shiftUsecase{shiftRepo: mockShiftRepo}
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+
+	t.Run("ok", func(t *testing.T) {
+		mockShiftRepo.EXPECT().Open(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Open(ctx, cashier, model.OpenShiftInput{OpeningFloat: 200000})
+		require.NoError(t, err)
+		require.Equal(t, cashier.ID, res.CashierID)
+		require.Equal(t, int64(200000), res.OpeningFloat)
+	})
+
+	t.Run("failed - already open", func(t *testing.T) {
+		mockShiftRepo.EXPECT().Open(ctx, cashier.ID, gomock.Any()).Times(1).Return(model.ErrShiftAlreadyOpen)
+
+		res, err := ucase.Open(ctx, cashier, model.OpenShiftInput{OpeningFloat: 200000})
+		require.ErrorIs(t, err, model.ErrShiftAlreadyOpen)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - negative opening float", func(t *testing.T) {
+		res, err := ucase.Open(ctx, cashier, model.OpenShiftInput{OpeningFloat: -1})
+		require.Error(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.Open(ctx, newUserWithRole(222, rbac.RoleFinancialAuditor), model.OpenShiftInput{})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestShiftUsecase_AddCashMovement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := shiftUsecase{shiftRepo: mockShiftRepo}
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	shift := &model.Shift{ID: 555, CashierID: cashier.ID, Status: model.ShiftStatusOpen}
+	input := model.CashMovementInput{Type: model.CashMovementTypeOut, Amount: 500000, Reason: "setor ke brankas"}
+
+	t.Run("ok", func(t *testing.T) {
+		mockShiftRepo.EXPECT().FindByID(ctx, shift.ID).Times(1).Return(shift, nil)
+		mockShiftRepo.EXPECT().CreateCashMovement(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.AddCashMovement(ctx, cashier, shift.ID, input)
+		require.NoError(t, err)
+		require.Equal(t, shift.ID, res.ShiftID)
+		require.Equal(t, model.CashMovementTypeOut, res.Type)
+	})
+
+	t.Run("failed - shift of another cashier", func(t *testing.T) {
+		mockShiftRepo.EXPECT().FindByID(ctx, shift.ID).Times(1).Return(shift, nil)
+
+		res, err := ucase.AddCashMovement(ctx, newUserWithRole(222, rbac.RoleCashiers), shift.ID, input)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - shift closed", func(t *testing.T) {
+		closed := *shift
+		closed.Status = model.ShiftStatusClosed
+		mockShiftRepo.EXPECT().FindByID(ctx, shift.ID).Times(1).Return(&closed, nil)
+
+		res, err := ucase.AddCashMovement(ctx, cashier, shift.ID, input)
+		require.ErrorIs(t, err, model.ErrShiftClosed)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - invalid type", func(t *testing.T) {
+		res, err := ucase.AddCashMovement(ctx, cashier, shift.ID, model.CashMovementInput{Type: "SAFE", Amount: 1, Reason: "x"})
+		require.Error(t, err)
+		require.Nil(t, res)
+	})
+}
+
+func TestShiftUsecase_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := shiftUsecase{shiftRepo: mockShiftRepo}
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	shift := &model.Shift{ID: 555, CashierID: cashier.ID, Status: model.ShiftStatusOpen, OpeningFloat: 200000}
+
+	t.Run("ok - short drawer", func(t *testing.T) {
+		mockShiftRepo.EXPECT().FindByID(ctx, shift.ID).Times(1).Return(shift, nil)
+		mockShiftRepo.EXPECT().Close(ctx, cashier.ID, gomock.Any(), int64(745000)).Times(1).
+			DoAndReturn(func(_ context.Context, _ int64, s *model.Shift, closingCash int64) error {
+				s.Reconcile(1050000, 0, 500000, closingCash, time.Now())
+				return nil
+			})
+
+		res, err := ucase.Close(ctx, cashier, shift.ID, model.CloseShiftInput{ClosingCash: 745000, Note: "kurang"})
+		require.NoError(t, err)
+		require.Equal(t, model.ShiftStatusClosed, res.Status)
+		require.Equal(t, int64(750000), res.ExpectedCash)
+		require.Equal(t, int64(-5000), res.Variance)
+		require.Equal(t, "kurang", res.ClosingNote)
+		require.NotNil(t, res.ClosedAt)
+		// the cached shift is never mutated
+		require.True(t, shift.IsOpen())
+	})
+
+	t.Run("failed - not found", func(t *testing.T) {
+		mockShiftRepo.EXPECT().FindByID(ctx, int64(999)).Times(1).Return(nil, nil)
+
+		res, err := ucase.Close(ctx, cashier, 999, model.CloseShiftInput{ClosingCash: 0})
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Nil(t, res)
+	})
+}
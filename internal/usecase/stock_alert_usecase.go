@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// lowStockCheckBatchSize the number of low stock products loaded on every page of the check
+const lowStockCheckBatchSize = 100
+
+type stockAlertUsecase struct {
+	productRepo    model.ProductRepository
+	stockAlertRepo model.StockAlertRepository
+	notifier       model.StockAlertNotifier
+}
+
+// NewStockAlertUsecase instantiate a new stock alert usecase
+func NewStockAlertUsecase(
+	productRepo model.ProductRepository,
+	stockAlertRepo model.StockAlertRepository,
+	notifier model.StockAlertNotifier,
+) model.StockAlertUsecase {
+	return &stockAlertUsecase{
+		productRepo:    productRepo,
+		stockAlertRepo: stockAlertRepo,
+		notifier:       notifier,
+	}
+}
+
+// NotifyLowStock send an alert for every low stock product which is not alerted within the repeat interval.
+// A product is only marked as alerted once its alert is sent, so a failed alert is sent again on the next check.
+func (s *stockAlertUsecase) NotifyLowStock(ctx context.Context) (int, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+	})
+
+	var productIDs []int64
+	criteria := model.LowStockSearchCriteria{Page: 1, Size: lowStockCheckBatchSize}
+	for {
+		ids, count, err := s.productRepo.SearchLowStockByPage(ctx, criteria)
+		if err != nil {
+			logger.Error(err)
+			return 0, err
+		}
+
+		productIDs = append(productIDs, ids...)
+		if len(ids) < criteria.Size || int64(len(productIDs)) >= count {
+			break
+		}
+		criteria.Page++
+	}
+
+	var sent int
+	for _, id := range productIDs {
+		alerted, err := s.stockAlertRepo.IsAlerted(ctx, id)
+		if err != nil {
+			logger.Error(err)
+			return sent, err
+		}
+		if alerted {
+			continue
+		}
+
+		product, err := s.productRepo.FindByID(ctx, id)
+		if err != nil {
+			logger.Error(err)
+			return sent, err
+		}
+		// the stock may be restocked since the search
+		if product == nil || !product.IsLowStock() {
+			continue
+		}
+
+		if err := s.notifier.Notify(ctx, model.NewLowStockAlert(product, time.Now())); err != nil {
+			logger.WithField("productID", id).Error(err)
+			continue
+		}
+		sent++
+
+		if err := s.stockAlertRepo.MarkAlerted(ctx, id); err != nil {
+			logger.Error(err)
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockAlertUsecase_NotifyLowStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockStockAlertRepo := mock.NewMockStockAlertRepository(ctrl)
+	mockNotifier := mock.NewMockStockAlertNotifier(ctrl)
+	ucase := stockAlertUsecase{
+		productRepo:    mockProductRepo,
+		stockAlertRepo: mockStockAlertRepo,
+		notifier:       mockNotifier,
+	}
+	criteria := model.LowStockSearchCriteria{Page: 1, Size: lowStockCheckBatchSize}
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", SKU: "PISANG-GORENG", Quantity: 3, MinStock: 5, ReorderQuantity: 20}
+
+	t.Run("ok", func(t *testing.T) {
+		mockProductRepo.EXPECT().SearchLowStockByPage(ctx, criteria).Times(1).Return([]int64{product.ID}, int64(1), nil)
+		mockStockAlertRepo.EXPECT().IsAlerted(ctx, product.ID).Times(1).Return(false, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockNotifier.EXPECT().Notify(ctx, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, alert *model.LowStockAlert) error {
+			require.Equal(t, product.ID, alert.ProductID)
+			require.Equal(t, product.Quantity, alert.Quantity)
+			require.Equal(t, product.ReorderQuantity, alert.ReorderQuantity)
+			return nil
+		})
+		mockStockAlertRepo.EXPECT().MarkAlerted(ctx, product.ID).Times(1).Return(nil)
+
+		sent, err := ucase.NotifyLowStock(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, sent)
+	})
+
+	t.Run("ok - already alerted product is skipped", func(t *testing.T) {
+		mockProductRepo.EXPECT().SearchLowStockByPage(ctx, criteria).Times(1).Return([]int64{product.ID}, int64(1), nil)
+		mockStockAlertRepo.EXPECT().IsAlerted(ctx, product.ID).Times(1).Return(true, nil)
+
+		sent, err := ucase.NotifyLowStock(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, sent)
+	})
+
+	t.Run("ok - restocked product is skipped", func(t *testing.T) {
+		restocked := *product
+		restocked.Quantity = 30
+
+		mockProductRepo.EXPECT().SearchLowStockByPage(ctx, criteria).Times(1).Return([]int64{product.ID}, int64(1), nil)
+		mockStockAlertRepo.EXPECT().IsAlerted(ctx, product.ID).Times(1).Return(false, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(&restocked, nil)
+
+		sent, err := ucase.NotifyLowStock(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, sent)
+	})
+
+	t.Run("ok - failed alert is not marked", func(t *testing.T) {
+		mockProductRepo.EXPECT().SearchLowStockByPage(ctx, criteria).Times(1).Return([]int64{product.ID}, int64(1), nil)
+		mockStockAlertRepo.EXPECT().IsAlerted(ctx, product.ID).Times(1).Return(false, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockNotifier.EXPECT().Notify(ctx, gomock.Any()).Times(1).Return(errors.New("webhook down"))
+
+		sent, err := ucase.NotifyLowStock(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, sent)
+	})
+
+	t.Run("failed - search error", func(t *testing.T) {
+		mockProductRepo.EXPECT().SearchLowStockByPage(ctx, criteria).Times(1).Return(nil, int64(0), errors.New("db error"))
+
+		_, err := ucase.NotifyLowStock(ctx)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type stockMovementUsecase struct {
+	stockMovementRepo  model.StockMovementRepository
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+}
+
+// NewStockMovementUsecase instantiate a new stock movement usecase
+func NewStockMovementUsecase(
+	stockMovementRepo model.StockMovementRepository,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+) model.StockMovementUsecase {
+	return &stockMovementUsecase{
+		stockMovementRepo:  stockMovementRepo,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+	}
+}
+
+// FindStockCard find the stock card of a product or one of its variants, the period default to the current month
+func (s *stockMovementUsecase) FindStockCard(ctx context.Context, requester *model.User, criteria model.StockCardCriteria) (*model.StockCard, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	criteria.SetDefaultValue()
+	if _, _, err := criteria.DateRange(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.findProductByID(ctx, criteria.ProductID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if criteria.VariantID > 0 {
+		if _, err := s.findVariant(ctx, product, criteria.VariantID); err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+	}
+
+	card, err := s.stockMovementRepo.FindStockCard(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// Create post a waste or transfer movement, the stock of the product & variant is changed along with the ledger
+func (s *stockMovementUsecase) Create(ctx context.Context, requester *model.User, productID int64, input model.CreateStockMovementInput) (*model.StockMovement, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"productID": productID,
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	product, err := findStockedProduct(ctx, s.productRepo, s.productVariantRepo, productID, input.VariantID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	movement := &model.StockMovement{
+		ID:            utils.GenerateID(),
+		ProductID:     product.ID,
+		VariantID:     input.VariantID,
+		Type:          input.Type,
+		Quantity:      input.Quantity,
+		ReferenceType: model.StockReferenceManual,
+		Note:          input.Note,
+		CreatedBy:     requester.ID,
+	}
+
+	if err := s.productRepo.PostStockMovements(ctx, []*model.StockMovement{movement}); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+func (s *stockMovementUsecase) findProductByID(ctx context.Context, id int64) (*model.Product, error) {
+	product, err := s.productRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrNotFound
+	}
+
+	return product, nil
+}
+
+func (s *stockMovementUsecase) findVariant(ctx context.Context, product *model.Product, variantID int64) (*model.ProductVariant, error) {
+	variant, err := s.productVariantRepo.FindByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant == nil || variant.ProductID != product.ID {
+		return nil, model.ErrUnknownProductVariant
+	}
+
+	return variant, nil
+}
+
+// findStockedProduct find the product of a stock line, the variant is required for a product with variants
+// since such product is stocked per variant, and must belong to the product
+func findStockedProduct(
+	ctx context.Context,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	productID, variantID int64,
+) (*model.Product, error) {
+	product, err := productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrNotFound
+	}
+
+	switch {
+	case product.HasVariants():
+		if variantID <= 0 {
+			return nil, model.ErrProductVariantRequired
+		}
+		variant, err := productVariantRepo.FindByID(ctx, variantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant == nil || variant.ProductID != product.ID {
+			return nil, model.ErrUnknownProductVariant
+		}
+	case variantID > 0:
+		return nil, model.ErrUnknownProductVariant
+	}
+
+	return product, nil
+}
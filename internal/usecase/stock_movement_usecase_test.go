@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockMovementUsecase_FindStockCard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockStockMovementRepo := mock.NewMockStockMovementRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockProductVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	ucase := stockMovementUsecase{
+		stockMovementRepo:  mockStockMovementRepo,
+		productRepo:        mockProductRepo,
+		productVariantRepo: mockProductVariantRepo,
+	}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", Quantity: 8}
+
+	t.Run("ok - default to the current month", func(t *testing.T) {
+		card := &model.StockCard{ProductID: product.ID, OpeningBalance: 10, TotalOut: 2, ClosingBalance: 8}
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockStockMovementRepo.EXPECT().FindStockCard(ctx, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, criteria model.StockCardCriteria) (*model.StockCard, error) {
+				require.NotEmpty(t, criteria.StartDate)
+				require.NotEmpty(t, criteria.EndDate)
+				return card, nil
+			})
+
+		res, err := ucase.FindStockCard(ctx, manager, model.StockCardCriteria{ProductID: product.ID})
+		require.NoError(t, err)
+		require.Equal(t, card, res)
+	})
+
+	t.Run("failed - invalid date range", func(t *testing.T) {
+		res, err := ucase.FindStockCard(ctx, manager, model.StockCardCriteria{
+			ProductID: product.ID,
+			StartDate: "2023-09-30",
+			EndDate:   "2023-09-01",
+		})
+		require.ErrorIs(t, err, model.ErrInvalidDateRange)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - variant of another product", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductVariantRepo.EXPECT().FindByID(ctx, int64(301)).Times(1).Return(&model.ProductVariant{ID: 301, ProductID: 999}, nil)
+
+		res, err := ucase.FindStockCard(ctx, manager, model.StockCardCriteria{ProductID: product.ID, VariantID: 301})
+		require.ErrorIs(t, err, model.ErrUnknownProductVariant)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - product not found", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(nil, nil)
+
+		res, err := ucase.FindStockCard(ctx, manager, model.StockCardCriteria{ProductID: product.ID})
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.FindStockCard(ctx, newUserWithRole(333, rbac.RoleCashiers), model.StockCardCriteria{ProductID: product.ID})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestStockMovementUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockProductVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	ucase := stockMovementUsecase{
+		productRepo:        mockProductRepo,
+		productVariantRepo: mockProductVariantRepo,
+	}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", Quantity: 8}
+	variantProduct := &model.Product{
+		ID:       223,
+		Name:     "Es Teh",
+		Options:  []model.ProductOption{{Name: "Ukuran", Values: []string{"S", "L"}}},
+		Variants: []*model.ProductVariant{{ID: 301, ProductID: 223}},
+	}
+
+	t.Run("ok - waste", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductRepo.EXPECT().PostStockMovements(ctx, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, movements []*model.StockMovement) error {
+				require.Len(t, movements, 1)
+				require.Equal(t, model.StockMovementTypeWaste, movements[0].Type)
+				require.Equal(t, int64(-2), movements[0].Quantity)
+				require.Equal(t, model.StockReferenceManual, movements[0].ReferenceType)
+				require.Equal(t, manager.ID, movements[0].CreatedBy)
+				return nil
+			})
+
+		res, err := ucase.Create(ctx, manager, product.ID, model.CreateStockMovementInput{
+			Type:     model.StockMovementTypeWaste,
+			Quantity: -2,
+			Note:     "basi",
+		})
+		require.NoError(t, err)
+		require.Equal(t, product.ID, res.ProductID)
+		require.Equal(t, "basi", res.Note)
+	})
+
+	t.Run("ok - transfer in of a variant", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, variantProduct.ID).Times(1).Return(variantProduct, nil)
+		mockProductVariantRepo.EXPECT().FindByID(ctx, int64(301)).Times(1).Return(variantProduct.Variants[0], nil)
+		mockProductRepo.EXPECT().PostStockMovements(ctx, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, manager, variantProduct.ID, model.CreateStockMovementInput{
+			VariantID: 301,
+			Type:      model.StockMovementTypeTransfer,
+			Quantity:  5,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(301), res.VariantID)
+	})
+
+	t.Run("failed - positive waste", func(t *testing.T) {
+		res, err := ucase.Create(ctx, manager, product.ID, model.CreateStockMovementInput{
+			Type:     model.StockMovementTypeWaste,
+			Quantity: 2,
+		})
+		require.ErrorIs(t, err, model.ErrInvalidStockMovement)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - sale is not a manual movement", func(t *testing.T) {
+		res, err := ucase.Create(ctx, manager, product.ID, model.CreateStockMovementInput{
+			Type:     model.StockMovementTypeSale,
+			Quantity: -2,
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - variant required", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, variantProduct.ID).Times(1).Return(variantProduct, nil)
+
+		res, err := ucase.Create(ctx, manager, variantProduct.ID, model.CreateStockMovementInput{
+			Type:     model.StockMovementTypeWaste,
+			Quantity: -1,
+		})
+		require.ErrorIs(t, err, model.ErrProductVariantRequired)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - insufficient stock", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductRepo.EXPECT().PostStockMovements(ctx, gomock.Any()).Times(1).Return(model.ErrInsufficientStock)
+
+		res, err := ucase.Create(ctx, manager, product.ID, model.CreateStockMovementInput{
+			Type:     model.StockMovementTypeWaste,
+			Quantity: -20,
+		})
+		require.ErrorIs(t, err, model.ErrInsufficientStock)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.Create(ctx, newUserWithRole(333, rbac.RoleFinancialAuditor), product.ID, model.CreateStockMovementInput{
+			Type:     model.StockMovementTypeWaste,
+			Quantity: -1,
+		})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+type stockOpnameUsecase struct {
+	stockOpnameRepo    model.StockOpnameRepository
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+}
+
+// NewStockOpnameUsecase instantiate a new stock opname usecase
+func NewStockOpnameUsecase(
+	stockOpnameRepo model.StockOpnameRepository,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+) model.StockOpnameUsecase {
+	return &stockOpnameUsecase{
+		stockOpnameRepo:    stockOpnameRepo,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+	}
+}
+
+// FindByID find stock opname by specific id along with its counts
+func (s *stockOpnameUsecase) FindByID(ctx context.Context, requester *model.User, id int64) (*model.StockOpname, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"requester":     utils.Dump(requester),
+		"stockOpnameID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	opname, err := s.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return opname, nil
+}
+
+// Search stock opname with given search criteria
+func (s *stockOpnameUsecase) Search(ctx context.Context, requester *model.User, criteria model.StockOpnameSearchCriteria) (opnames model.AnyStockOpnames, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	opnameIDs, count, err := s.stockOpnameRepo.SearchByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(opnameIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	opnames = s.findAllByIDs(ctx, opnameIDs)
+	if len(opnames) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// Start a new stock opname, the products can be counted by many users until it is approved
+func (s *stockOpnameUsecase) Start(ctx context.Context, requester *model.User, input model.StartStockOpnameInput) (*model.StockOpname, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	opname := &model.StockOpname{
+		ID:   utils.GenerateID(),
+		Note: input.Note,
+	}
+
+	if err := s.stockOpnameRepo.Create(ctx, requester.ID, opname); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return opname, nil
+}
+
+// SubmitCounts store the quantities counted by the requester, a product may be counted partially over many submissions
+// and the counts of other users on the same product are added up
+func (s *stockOpnameUsecase) SubmitCounts(ctx context.Context, requester *model.User, id int64, input model.SubmitStockOpnameCountsInput) (*model.StockOpname, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"requester":     utils.Dump(requester),
+		"stockOpnameID": id,
+		"input":         utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	opname, err := s.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !opname.IsOpen() {
+		return nil, model.ErrStockOpnameNotOpen
+	}
+
+	counts := make([]*model.StockOpnameCount, 0, len(input.Counts))
+	for _, count := range input.Counts {
+		if _, err := findStockedProduct(ctx, s.productRepo, s.productVariantRepo, count.ProductID, count.VariantID); err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		counts = append(counts, &model.StockOpnameCount{
+			ProductID: count.ProductID,
+			VariantID: count.VariantID,
+			Quantity:  count.Quantity,
+		})
+	}
+
+	if err := s.stockOpnameRepo.SaveCounts(ctx, requester.ID, opname.ID, counts); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return s.findByID(ctx, opname.ID)
+}
+
+// Approve post the variances of the counted products as adjustment movements,
+// the products which are not counted keep their stock
+func (s *stockOpnameUsecase) Approve(ctx context.Context, requester *model.User, id int64) (*model.StockOpname, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"requester":     utils.Dump(requester),
+		"stockOpnameID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceStock, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	opname, err := s.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !opname.IsOpen() {
+		return nil, model.ErrStockOpnameNotOpen
+	}
+
+	if err := s.stockOpnameRepo.Approve(ctx, requester.ID, opname); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return opname, nil
+}
+
+func (s *stockOpnameUsecase) findByID(ctx context.Context, id int64) (*model.StockOpname, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	opname, err := s.stockOpnameRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if opname == nil {
+		return nil, ErrNotFound
+	}
+
+	return opname, nil
+}
+
+// findAllByIDs find all stock opnames with IDs
+func (s *stockOpnameUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model.StockOpname {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.StockOpname, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			opname, err := s.findByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- opname
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.StockOpname{}
+	for opname := range c {
+		if opname != nil {
+			rs[opname.ID] = opname
+		}
+	}
+
+	// sort stock opnames based on the order of received ids
+	var opnames []*model.StockOpname
+	for _, id := range ids {
+		if opname, ok := rs[id]; ok {
+			opnames = append(opnames, opname)
+		}
+	}
+
+	return opnames
+}
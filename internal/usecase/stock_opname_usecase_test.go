@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockOpnameUsecase_SubmitCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockStockOpnameRepo := mock.NewMockStockOpnameRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockProductVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	ucase := stockOpnameUsecase{
+		stockOpnameRepo:    mockStockOpnameRepo,
+		productRepo:        mockProductRepo,
+		productVariantRepo: mockProductVariantRepo,
+	}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+	opname := &model.StockOpname{ID: 555, Status: model.StockOpnameStatusOpen}
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", Quantity: 8}
+	variantProduct := &model.Product{ID: 333, Name: "Es Teh", Options: model.ProductOptions{{Name: "Ukuran", Values: []string{"S"}}}}
+
+	t.Run("ok", func(t *testing.T) {
+		input := model.SubmitStockOpnameCountsInput{Counts: []model.StockOpnameCountInput{
+			{ProductID: product.ID, Quantity: 5},
+			{ProductID: variantProduct.ID, VariantID: 301, Quantity: 2},
+		}}
+
+		mockStockOpnameRepo.EXPECT().FindByID(ctx, opname.ID).Times(1).Return(opname, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, variantProduct.ID).Times(1).Return(variantProduct, nil)
+		mockProductVariantRepo.EXPECT().FindByID(ctx, int64(301)).Times(1).Return(&model.ProductVariant{ID: 301, ProductID: variantProduct.ID}, nil)
+		mockStockOpnameRepo.EXPECT().SaveCounts(ctx, manager.ID, opname.ID, []*model.StockOpnameCount{
+			{ProductID: product.ID, Quantity: 5},
+			{ProductID: variantProduct.ID, VariantID: 301, Quantity: 2},
+		}).Times(1).Return(nil)
+		mockStockOpnameRepo.EXPECT().FindByID(ctx, opname.ID).Times(1).Return(opname, nil)
+
+		res, err := ucase.SubmitCounts(ctx, manager, opname.ID, input)
+		require.NoError(t, err)
+		require.Equal(t, opname, res)
+	})
+
+	t.Run("failed - variant is required", func(t *testing.T) {
+		mockStockOpnameRepo.EXPECT().FindByID(ctx, opname.ID).Times(1).Return(opname, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, variantProduct.ID).Times(1).Return(variantProduct, nil)
+
+		res, err := ucase.SubmitCounts(ctx, manager, opname.ID, model.SubmitStockOpnameCountsInput{
+			Counts: []model.StockOpnameCountInput{{ProductID: variantProduct.ID, Quantity: 2}},
+		})
+		require.ErrorIs(t, err, model.ErrProductVariantRequired)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - product counted twice", func(t *testing.T) {
+		res, err := ucase.SubmitCounts(ctx, manager, opname.ID, model.SubmitStockOpnameCountsInput{
+			Counts: []model.StockOpnameCountInput{{ProductID: product.ID, Quantity: 2}, {ProductID: product.ID, Quantity: 3}},
+		})
+		require.ErrorIs(t, err, model.ErrDuplicateProductVariant)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - already approved", func(t *testing.T) {
+		mockStockOpnameRepo.EXPECT().FindByID(ctx, int64(666)).Times(1).
+			Return(&model.StockOpname{ID: 666, Status: model.StockOpnameStatusApproved}, nil)
+
+		res, err := ucase.SubmitCounts(ctx, manager, 666, model.SubmitStockOpnameCountsInput{
+			Counts: []model.StockOpnameCountInput{{ProductID: product.ID, Quantity: 2}},
+		})
+		require.ErrorIs(t, err, model.ErrStockOpnameNotOpen)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.SubmitCounts(ctx, newUserWithRole(333, rbac.RoleCashiers), opname.ID, model.SubmitStockOpnameCountsInput{
+			Counts: []model.StockOpnameCountInput{{ProductID: product.ID, Quantity: 2}},
+		})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestStockOpnameUsecase_Approve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockStockOpnameRepo := mock.NewMockStockOpnameRepository(ctrl)
+	ucase := stockOpnameUsecase{
+		stockOpnameRepo: mockStockOpnameRepo,
+	}
+	manager := newUserWithRole(111, rbac.RoleProductManager)
+
+	t.Run("ok", func(t *testing.T) {
+		opname := &model.StockOpname{ID: 555, Status: model.StockOpnameStatusOpen}
+		mockStockOpnameRepo.EXPECT().FindByID(ctx, opname.ID).Times(1).Return(opname, nil)
+		mockStockOpnameRepo.EXPECT().Approve(ctx, manager.ID, opname).Times(1).Return(nil)
+
+		res, err := ucase.Approve(ctx, manager, opname.ID)
+		require.NoError(t, err)
+		require.Equal(t, opname, res)
+	})
+
+	t.Run("failed - already approved", func(t *testing.T) {
+		opname := &model.StockOpname{ID: 666, Status: model.StockOpnameStatusApproved}
+		mockStockOpnameRepo.EXPECT().FindByID(ctx, opname.ID).Times(1).Return(opname, nil)
+
+		res, err := ucase.Approve(ctx, manager, opname.ID)
+		require.ErrorIs(t, err, model.ErrStockOpnameNotOpen)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - not found", func(t *testing.T) {
+		mockStockOpnameRepo.EXPECT().FindByID(ctx, int64(777)).Times(1).Return(nil, nil)
+
+		res, err := ucase.Approve(ctx, manager, 777)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - auditor can not approve", func(t *testing.T) {
+		res, err := ucase.Approve(ctx, newUserWithRole(333, rbac.RoleFinancialAuditor), 555)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/irvankadhafi/go-point-of-sales/utils"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+type supplierUsecase struct {
+	supplierRepo model.SupplierRepository
+}
+
+// NewSupplierUsecase instantiate a new supplier usecase
+func NewSupplierUsecase(supplierRepo model.SupplierRepository) model.SupplierUsecase {
+	return &supplierUsecase{
+		supplierRepo: supplierRepo,
+	}
+}
+
+// FindByID find supplier by specific id
+func (s *supplierUsecase) FindByID(ctx context.Context, requester *model.User, id int64) (*model.Supplier, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"requester":  utils.Dump(requester),
+		"supplierID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceSupplier, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	supplier, err := s.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+// Search supplier with given search criteria
+func (s *supplierUsecase) Search(ctx context.Context, requester *model.User, criteria model.SupplierSearchCriteria) (suppliers model.AnySuppliers, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceSupplier, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	supplierIDs, count, err := s.supplierRepo.SearchByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(supplierIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	suppliers = s.findAllByIDs(ctx, supplierIDs)
+	if len(suppliers) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// Create supplier from input
+func (s *supplierUsecase) Create(ctx context.Context, requester *model.User, input model.CreateSupplierInput) (*model.Supplier, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceSupplier, rbac.ActionCreateAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	supplier := &model.Supplier{ID: utils.GenerateID()}
+	setSupplierFromInput(supplier, input)
+
+	if err := s.supplierRepo.Create(ctx, requester.ID, supplier); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+// UpdateByID update supplier with id
+func (s *supplierUsecase) UpdateByID(ctx context.Context, requester *model.User, id int64, input model.UpdateSupplierInput) (*model.Supplier, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"requester":  utils.Dump(requester),
+		"supplierID": id,
+		"input":      utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceSupplier, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	oldSupplier, err := s.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	updatedSupplier := *oldSupplier
+	setSupplierFromInput(&updatedSupplier, input)
+
+	if err := s.supplierRepo.Update(ctx, requester.ID, &updatedSupplier); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &updatedSupplier, nil
+}
+
+// DeleteByID delete supplier by id
+func (s *supplierUsecase) DeleteByID(ctx context.Context, requester *model.User, id int64) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":        utils.DumpIncomingContext(ctx),
+		"requester":  utils.Dump(requester),
+		"supplierID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceSupplier, rbac.ActionDeleteAny) {
+		return ErrPermissionDenied
+	}
+
+	supplier, err := s.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.supplierRepo.Delete(ctx, requester.ID, supplier); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *supplierUsecase) findByID(ctx context.Context, id int64) (*model.Supplier, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	supplier, err := s.supplierRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, ErrNotFound
+	}
+
+	return supplier, nil
+}
+
+// findAllByIDs find all suppliers with IDs
+func (s *supplierUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model.Supplier {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.Supplier, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			supplier, err := s.findByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- supplier
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.Supplier{}
+	for supplier := range c {
+		if supplier != nil {
+			rs[supplier.ID] = supplier
+		}
+	}
+
+	// sort suppliers based on the order of received ids
+	var suppliers []*model.Supplier
+	for _, id := range ids {
+		if supplier, ok := rs[id]; ok {
+			suppliers = append(suppliers, supplier)
+		}
+	}
+
+	return suppliers
+}
+
+func setSupplierFromInput(supplier *model.Supplier, input model.CreateSupplierInput) {
+	supplier.Name = input.Name
+	supplier.ContactName = input.ContactName
+	supplier.Phone = input.Phone
+	supplier.Email = input.Email
+	supplier.Address = input.Address
+	supplier.Note = input.Note
+}
@@ -2,27 +2,110 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/receipt"
 	"github.com/irvankadhafi/go-point-of-sales/rbac"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/sirupsen/logrus"
 	"sync"
+	"time"
 )
 
 type transactionUsecase struct {
-	transactionRepo model.TransactionRepository
-	productRepo     model.ProductRepository
+	transactionRepo    model.TransactionRepository
+	productRepo        model.ProductRepository
+	productVariantRepo model.ProductVariantRepository
+	refundRepo         model.RefundRepository
+	promotionRepo      model.PromotionRepository
+	userRepo           model.UserRepository
+	shiftRepo          model.ShiftRepository
 }
 
 // NewTransactionUsecase instantiate a new transaction usecase
-func NewTransactionUsecase(transactionRepo model.TransactionRepository, productRepo model.ProductRepository) model.TransactionUsecase {
+func NewTransactionUsecase(
+	transactionRepo model.TransactionRepository,
+	productRepo model.ProductRepository,
+	productVariantRepo model.ProductVariantRepository,
+	refundRepo model.RefundRepository,
+	promotionRepo model.PromotionRepository,
+	userRepo model.UserRepository,
+	shiftRepo model.ShiftRepository,
+) model.TransactionUsecase {
 	return &transactionUsecase{
-		transactionRepo: transactionRepo,
-		productRepo:     productRepo,
+		transactionRepo:    transactionRepo,
+		productRepo:        productRepo,
+		productVariantRepo: productVariantRepo,
+		refundRepo:         refundRepo,
+		promotionRepo:      promotionRepo,
+		userRepo:           userRepo,
+		shiftRepo:          shiftRepo,
 	}
 }
 
+// FindByID find transaction by specific id
+func (t *transactionUsecase) FindByID(ctx context.Context, requester *model.User, id int64) (*model.Transaction, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"requester":     utils.Dump(requester),
+		"transactionID": id,
+	})
+
+	if !requester.HasAccess(rbac.ResourceTransaction, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	transaction, err := t.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Search transaction with given search criteria
+func (t *transactionUsecase) Search(ctx context.Context, requester *model.User, criteria model.TransactionSearchCriteria) (transactions model.AnyTransactions, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceTransaction, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	if err = criteria.Validate(); err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+
+	transactionIDs, count, err := t.transactionRepo.SearchByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(transactionIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	transactions = t.findAllByIDs(ctx, transactionIDs)
+	if len(transactions) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// Create a transaction, the stock is decreased atomically along with the transaction creation.
+// A product with variants is sold per variant using the variant price.
+// The transaction is linked to the open shift of the requester.
+// The active promotions are applied first, then the manual discount which is limited by the requester role.
+// The tax & service charge are calculated on the discounted lines using the configured tax setting.
+// The payments must cover the total price and the change is given from the cash part only.
 func (t *transactionUsecase) Create(ctx context.Context, requester *model.User, input model.CreateTransactionInput) (*model.Transaction, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx":       utils.DumpIncomingContext(ctx),
@@ -34,70 +117,130 @@ func (t *transactionUsecase) Create(ctx context.Context, requester *model.User,
 		return nil, ErrPermissionDenied
 	}
 
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	newTransaction := &model.Transaction{
-		ID:         utils.GenerateID(),
-		AmountPaid: input.AmountPaid,
-		CreatedBy:  requester.ID,
+		ID:        utils.GenerateID(),
+		CreatedBy: requester.ID,
 	}
 
-	// Calculate total price and check product stock
-	var (
-		totalAmount int64
-		wg          sync.WaitGroup
-	)
+	// link the sale to the open shift of the cashier, selling without one is blocked when it is required
+	shift, err := t.shiftRepo.FindOpenByCashierID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	switch {
+	case shift != nil:
+		newTransaction.ShiftID = shift.ID
+	case config.TransactionRequireOpenShift():
+		return nil, model.ErrShiftNotOpen
+	}
 
-	errCh := make(chan error, len(input.TransactionDetails))
-	transactionDetails := make([]*model.TransactionDetail, len(input.TransactionDetails))
-	for i, detail := range input.TransactionDetails {
-		wg.Add(1)
+	if err := t.resolveBarcodes(ctx, &input); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
 
-		go func(i int, detail model.TransactionDetail) {
-			defer wg.Done()
+	// Snapshot the products & variants, the stock itself is checked & decreased by the repository
+	details := input.MergedTransactionDetails()
+	transactionDetails := make([]*model.TransactionDetail, 0, len(details))
+	for _, detail := range details {
+		product, err := t.productRepo.FindByID(ctx, detail.ProductID)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		if product == nil {
+			return nil, ErrNotFound
+		}
 
-			product, err := t.productRepo.FindByID(ctx, detail.ProductID)
+		transactionDetail := &model.TransactionDetail{
+			TransactionID: newTransaction.ID,
+			ProductID:     detail.ProductID,
+			ProductName:   product.Name,
+			ProductSlug:   product.Slug,
+			UnitPrice:     product.Price,
+			UnitCost:      product.UnitCost(),
+			Quantity:      detail.Quantity,
+			TaxCategory:   product.TaxCategory,
+		}
+
+		switch {
+		case product.HasVariants():
+			variant, err := t.findVariant(ctx, product, detail.VariantID)
 			if err != nil {
-				errCh <- err
-				return
-			}
-			if product == nil {
-				errCh <- ErrNotFound
-				return
+				logger.Error(err)
+				return nil, err
 			}
 
-			if product.Quantity < detail.Quantity {
-				errCh <- errors.New("insufficient stock for one or more products")
-				return
-			}
+			transactionDetail.VariantID = variant.ID
+			transactionDetail.VariantName = variant.Name
+			transactionDetail.SKU = variant.SKU
+			transactionDetail.UnitPrice = variant.Price
+		case detail.VariantID > 0:
+			return nil, model.ErrUnknownProductVariant
+		}
 
-			// Calculate subtotal for transaction detail
-			subtotal := product.Price * detail.Quantity
-			totalAmount += subtotal
+		transactionDetail.Subtotal = transactionDetail.UnitPrice * detail.Quantity
+		transactionDetails = append(transactionDetails, transactionDetail)
+	}
 
-			// Reduce product stock
-			product.Quantity -= detail.Quantity
-			if err := t.productRepo.Update(ctx, requester.ID, product); err != nil {
-				errCh <- err
-				return
-			}
+	promotions, err := t.promotionRepo.FindAllActive(ctx)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	cartDiscount, appliedPromotions := model.AnyPromotions(promotions).Apply(transactionDetails, time.Now())
 
-			detail.TransactionID = newTransaction.ID
-			detail.Subtotal = subtotal
-			transactionDetails[i] = &detail
-		}(i, detail)
+	var subtotal, lineDiscount int64
+	for _, detail := range transactionDetails {
+		subtotal += detail.Subtotal
+		lineDiscount += detail.Discount
 	}
+	subtotal -= cartDiscount
+
+	if !requester.CanGiveManualDiscount(input.ManualDiscount, subtotal) {
+		return nil, ErrManualDiscountExceeded
+	}
+
+	taxSetting := newTaxSetting()
+	calc := taxSetting.Calculate(transactionDetails, cartDiscount+input.ManualDiscount)
+	totalAmount := calc.TotalPrice
 
-	go func() {
-		wg.Wait()
-		close(errCh)
-	}()
+	payments := input.Payments()
+	transactionPayments := make([]*model.TransactionPayment, 0, len(payments))
+	for _, payment := range payments {
+		transactionPayments = append(transactionPayments, &model.TransactionPayment{
+			TransactionID: newTransaction.ID,
+			Method:        payment.Method,
+			Amount:        payment.Amount,
+			Reference:     payment.Reference,
+		})
+	}
 
-	for err := range errCh {
+	amountPaid, change, err := model.AnyTransactionPayments(transactionPayments).CalculateChange(totalAmount)
+	if err != nil {
+		logger.Error(err)
 		return nil, err
 	}
 
 	newTransaction.TotalPrice = totalAmount
-	newTransaction.Change = newTransaction.AmountPaid - newTransaction.TotalPrice
+	newTransaction.Discount = lineDiscount + cartDiscount + input.ManualDiscount
+	newTransaction.ManualDiscount = input.ManualDiscount
+	newTransaction.TaxBase = calc.TaxBase
+	newTransaction.TaxAmount = calc.TaxAmount
+	newTransaction.ServiceCharge = calc.ServiceCharge
+	newTransaction.PriceIncludeTax = taxSetting.PriceIncludeTax
+	newTransaction.AmountPaid = amountPaid
+	newTransaction.Change = change
 	newTransaction.TransactionDetails = transactionDetails
+	newTransaction.TransactionPayments = transactionPayments
+	newTransaction.TransactionPromotions = appliedPromotions
 
 	// Save the transaction to the repository
 	if err := t.transactionRepo.Create(ctx, requester.ID, newTransaction); err != nil {
@@ -107,3 +250,358 @@ func (t *transactionUsecase) Create(ctx context.Context, requester *model.User,
 
 	return newTransaction, nil
 }
+
+// Void cancel the whole transaction and restock all of its products, only allowed when the transaction has no refund yet.
+// A transaction of a shift can only be voided while the shift is open, by its cashier or an approver,
+// otherwise the void is only allowed within the void window.
+func (t *transactionUsecase) Void(ctx context.Context, requester *model.User, id int64, input model.VoidTransactionInput) (*model.Refund, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"requester":     utils.Dump(requester),
+		"transactionID": id,
+		"input":         utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceTransaction, rbac.ActionDeleteAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	transaction, err := t.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := t.checkVoidable(ctx, requester, transaction); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	refund := &model.Refund{
+		ID:            utils.GenerateID(),
+		TransactionID: transaction.ID,
+		Type:          model.RefundTypeVoid,
+		TotalAmount:   transaction.TotalPrice,
+		ShiftID:       transaction.ShiftID,
+		CashAmount:    transaction.RefundCashAmount(transaction.TotalPrice),
+		Reason:        input.Reason,
+		ApprovedBy:    requester.ID,
+		CreatedAt:     time.Now(),
+	}
+	for _, detail := range transaction.TransactionDetails {
+		refund.RefundDetails = append(refund.RefundDetails, &model.RefundDetail{
+			RefundID:      refund.ID,
+			TransactionID: transaction.ID,
+			ProductID:     detail.ProductID,
+			VariantID:     detail.VariantID,
+			Quantity:      detail.Quantity,
+			Subtotal:      detail.Subtotal,
+		})
+	}
+
+	if err := t.refundRepo.Create(ctx, requester.ID, refund); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// checkVoidable make sure the shift of the transaction is still open and belongs to the requester,
+// unless the requester can approve the void of any transaction. The shift is checked again under lock
+// when the void is stored, so it can not be closed meanwhile
+func (t *transactionUsecase) checkVoidable(ctx context.Context, requester *model.User, transaction *model.Transaction) error {
+	if transaction.ShiftID <= 0 {
+		if time.Since(transaction.CreatedAt) > config.TransactionVoidWindow() {
+			return ErrVoidWindowExpired
+		}
+
+		return nil
+	}
+
+	shift, err := t.shiftRepo.FindByID(ctx, transaction.ShiftID)
+	if err != nil {
+		return err
+	}
+
+	if shift == nil || !shift.IsOpen() {
+		return model.ErrShiftClosed
+	}
+
+	if shift.CashierID != requester.ID && !requester.HasAccess(rbac.ResourceTransaction, rbac.ActionApproveAny) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// Refund return some lines of the transaction and restock the returned products,
+// the refunded amount is prorated from the share of the line in the total price when the transaction was made
+func (t *transactionUsecase) Refund(ctx context.Context, requester *model.User, id int64, input model.RefundTransactionInput) (*model.Refund, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"requester":     utils.Dump(requester),
+		"transactionID": id,
+		"input":         utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceTransaction, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	transaction, err := t.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// the refund is paid from the drawer of the requester open shift, if any
+	shift, err := t.shiftRepo.FindOpenByCashierID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	soldDetails := make(map[model.ProductVariantKey]*model.TransactionDetail, len(transaction.TransactionDetails))
+	for _, detail := range transaction.TransactionDetails {
+		soldDetails[detail.Key()] = detail
+	}
+
+	refund := &model.Refund{
+		ID:            utils.GenerateID(),
+		TransactionID: transaction.ID,
+		Type:          model.RefundTypeRefund,
+		Reason:        input.Reason,
+		ApprovedBy:    requester.ID,
+		CreatedAt:     time.Now(),
+	}
+	if shift != nil {
+		refund.ShiftID = shift.ID
+	}
+
+	// merge the lines of the same product & variant, the remaining quantity itself is checked by the repository
+	quantities := make(map[model.ProductVariantKey]int64)
+	var keys []model.ProductVariantKey
+	for _, detail := range input.RefundDetails {
+		key := model.ProductVariantKey{ProductID: detail.ProductID, VariantID: detail.VariantID}
+		if _, ok := quantities[key]; !ok {
+			keys = append(keys, key)
+		}
+		quantities[key] += detail.Quantity
+	}
+
+	for _, key := range keys {
+		sold, ok := soldDetails[key]
+		if !ok || quantities[key] > sold.Quantity {
+			return nil, model.ErrRefundQuantityExceeded
+		}
+
+		subtotal := transaction.RefundAmount(sold, quantities[key])
+		refund.TotalAmount += subtotal
+		refund.RefundDetails = append(refund.RefundDetails, &model.RefundDetail{
+			RefundID:      refund.ID,
+			TransactionID: transaction.ID,
+			ProductID:     key.ProductID,
+			VariantID:     key.VariantID,
+			Quantity:      quantities[key],
+			Subtotal:      subtotal,
+		})
+	}
+	refund.CashAmount = transaction.RefundCashAmount(refund.TotalAmount)
+
+	if err := t.refundRepo.Create(ctx, requester.ID, refund); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// PrintReceipt render the receipt of the transaction on the requested format, TEXT_80 when empty.
+// Every print is recorded so a print after the first one is marked & audited as reprint.
+func (t *transactionUsecase) PrintReceipt(ctx context.Context, requester *model.User, id int64, format model.ReceiptFormat) (*model.Receipt, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":           utils.DumpIncomingContext(ctx),
+		"requester":     utils.Dump(requester),
+		"transactionID": id,
+		"format":        format,
+	})
+
+	if !requester.HasAccess(rbac.ResourceTransaction, rbac.ActionViewAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if format == "" {
+		format = model.ReceiptFormatText80
+	}
+	if !format.IsValid() {
+		return nil, model.ErrUnknownReceiptFormat
+	}
+
+	transaction, err := t.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	var cashierName string
+	cashier, err := t.userRepo.FindByID(ctx, transaction.CreatedBy)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if cashier != nil {
+		cashierName = cashier.Name
+	}
+
+	printCount, err := t.transactionRepo.RecordReceiptPrint(ctx, requester.ID, transaction)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return receipt.Render(format, receipt.Data{
+		Transaction: transaction,
+		CashierName: cashierName,
+		Setting: model.ReceiptSetting{
+			Header: config.ReceiptHeader(),
+			Footer: config.ReceiptFooter(),
+		},
+		PrintCount: printCount,
+	})
+}
+
+func (t *transactionUsecase) findByID(ctx context.Context, id int64) (*model.Transaction, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"id":  id,
+	})
+
+	transaction, err := t.transactionRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, ErrNotFound
+	}
+
+	refunds, err := t.refundRepo.FindByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	transaction.Refunds = refunds
+
+	return transaction, nil
+}
+
+// resolveBarcodes set the product & variant of the lines given by a scanned barcode or sku
+func (t *transactionUsecase) resolveBarcodes(ctx context.Context, input *model.CreateTransactionInput) error {
+	for i, detail := range input.TransactionDetails {
+		if detail.Barcode == "" {
+			continue
+		}
+
+		product, variant, err := findProductByCode(ctx, t.productRepo, t.productVariantRepo, detail.Barcode)
+		if err != nil {
+			return err
+		}
+
+		input.TransactionDetails[i].ProductID = product.ID
+		input.TransactionDetails[i].VariantID = 0
+		if variant != nil {
+			input.TransactionDetails[i].VariantID = variant.ID
+		}
+	}
+
+	return nil
+}
+
+// findVariant find the chosen variant of a product with variants
+func (t *transactionUsecase) findVariant(ctx context.Context, product *model.Product, variantID int64) (*model.ProductVariant, error) {
+	if variantID <= 0 {
+		return nil, model.ErrProductVariantRequired
+	}
+
+	variant, err := t.productVariantRepo.FindByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant == nil || variant.ProductID != product.ID {
+		return nil, model.ErrUnknownProductVariant
+	}
+
+	return variant, nil
+}
+
+// findAllByIDs find all transactions with IDs
+func (t *transactionUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model.Transaction {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.Transaction, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			transaction, err := t.findByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- transaction
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.Transaction{}
+	for transaction := range c {
+		if transaction != nil {
+			rs[transaction.ID] = transaction
+		}
+	}
+
+	// sort transactions based on the order of received ids
+	var transactions []*model.Transaction
+	for _, id := range ids {
+		if transaction, ok := rs[id]; ok {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	return transactions
+}
+
+// newTaxSetting build the tax setting from the config
+func newTaxSetting() model.TaxSetting {
+	rates := make(map[model.TaxCategory]float64)
+	for category, rate := range config.TaxRates() {
+		rates[model.TaxCategory(category)] = rate
+	}
+
+	return model.TaxSetting{
+		Rates:             rates,
+		ServiceChargeRate: config.ServiceChargeRate(),
+		PriceIncludeTax:   config.PriceIncludeTax(),
+	}
+}
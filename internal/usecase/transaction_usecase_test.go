@@ -0,0 +1,927 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// setTaxConfig override the tax config during the test
+func setTaxConfig(t *testing.T, standardRate, serviceChargeRate float64, priceIncludeTax bool) {
+	viper.Set("tax.rates.standard", standardRate)
+	viper.Set("tax.service_charge_rate", serviceChargeRate)
+	viper.Set("tax.price_include_tax", priceIncludeTax)
+
+	t.Cleanup(func() {
+		viper.Set("tax.rates.standard", config.DefaultPPNRate)
+		viper.Set("tax.service_charge_rate", 0)
+		viper.Set("tax.price_include_tax", false)
+	})
+}
+
+func TestTransactionUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	setTaxConfig(t, 0, 0, false)
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockPromotionRepo := mock.NewMockPromotionRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		productRepo:     mockProductRepo,
+		promotionRepo:   mockPromotionRepo,
+		shiftRepo:       mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).AnyTimes().Return(nil, nil)
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", Slug: "pisang-goreng", Price: 5000, CostPrice: 3500, Quantity: 10}
+	mockPromotionRepo.EXPECT().FindAllActive(ctx).AnyTimes().Return(nil, nil)
+
+	t.Run("ok - merge duplicated lines", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ int64, transaction *model.Transaction) error {
+				require.Len(t, transaction.TransactionDetails, 1)
+				require.Equal(t, int64(3), transaction.TransactionDetails[0].Quantity)
+				require.Equal(t, product.Name, transaction.TransactionDetails[0].ProductName)
+				require.Equal(t, product.Price, transaction.TransactionDetails[0].UnitPrice)
+				require.Equal(t, &product.CostPrice, transaction.TransactionDetails[0].UnitCost)
+				return nil
+			})
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{
+				{ProductID: product.ID, Quantity: 1},
+				{ProductID: product.ID, Quantity: 2},
+			},
+			AmountPaid: 20000,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(15000), res.TotalPrice)
+		require.Equal(t, int64(5000), res.Change)
+	})
+
+	t.Run("ok - unknown cost is not snapshotted as zero", func(t *testing.T) {
+		uncosted := &model.Product{ID: 223, Name: "Es Teh", Slug: "es-teh", Price: 3000, Quantity: 10}
+		mockProductRepo.EXPECT().FindByID(ctx, uncosted.ID).Times(1).Return(uncosted, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ int64, transaction *model.Transaction) error {
+				require.Len(t, transaction.TransactionDetails, 1)
+				require.Nil(t, transaction.TransactionDetails[0].UnitCost)
+				return nil
+			})
+
+		_, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: uncosted.ID, Quantity: 1}},
+			AmountPaid:         3000,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("ok - split tender, change from cash only", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 3}},
+			TransactionPayments: []model.TransactionPayment{
+				{Method: model.PaymentMethodQRIS, Amount: 10000, Reference: "QR-001"},
+				{Method: model.PaymentMethodCash, Amount: 10000},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(20000), res.AmountPaid)
+		require.Equal(t, int64(5000), res.Change)
+		require.Len(t, res.TransactionPayments, 2)
+	})
+
+	t.Run("failed - underpayment", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 3}},
+			AmountPaid:         10000,
+		})
+		require.ErrorIs(t, err, model.ErrInsufficientPayment)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - non cash overpayment", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 1}},
+			TransactionPayments: []model.TransactionPayment{
+				{Method: model.PaymentMethodDebitCard, Amount: 10000},
+			},
+		})
+		require.ErrorIs(t, err, model.ErrNonCashOverpayment)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - invalid payment method", func(t *testing.T) {
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 1}},
+			TransactionPayments: []model.TransactionPayment{
+				{Method: "CHEQUE", Amount: 5000},
+			},
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - invalid quantity", func(t *testing.T) {
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 0}},
+			AmountPaid:         20000,
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - product not found", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, int64(999)).Times(1).Return(nil, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: 999, Quantity: 1}},
+			AmountPaid:         20000,
+		})
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.Create(ctx, newUserWithRole(333, rbac.RoleFinancialAuditor), model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 1}},
+		})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestTransactionUsecase_Create_Discount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	setTaxConfig(t, 0, 0, false)
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockPromotionRepo := mock.NewMockPromotionRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		productRepo:     mockProductRepo,
+		promotionRepo:   mockPromotionRepo,
+		shiftRepo:       mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).AnyTimes().Return(nil, nil)
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", Slug: "pisang-goreng", Price: 5000, Quantity: 10}
+	now := time.Now()
+	promotions := []*model.Promotion{
+		{
+			ID:          1,
+			Name:        "Beli 2 Gratis 1",
+			Type:        model.PromotionTypeBuyXGetY,
+			ProductID:   product.ID,
+			BuyQuantity: 2,
+			GetQuantity: 1,
+			StartAt:     now.Add(-time.Hour),
+			EndAt:       now.Add(time.Hour),
+			IsActive:    true,
+		},
+		{
+			ID:        2,
+			Name:      "Diskon 10%",
+			Type:      model.PromotionTypeLinePercentage,
+			ProductID: product.ID,
+			Value:     10,
+			StartAt:   now.Add(-time.Hour),
+			EndAt:     now.Add(time.Hour),
+			IsActive:  true,
+		},
+		{
+			ID:           3,
+			Name:         "Potongan Belanja",
+			Type:         model.PromotionTypeCartFixed,
+			Value:        1000,
+			MinimumSpend: 10000,
+			StartAt:      now.Add(-time.Hour),
+			EndAt:        now.Add(time.Hour),
+			IsActive:     true,
+		},
+		{
+			ID:       4,
+			Name:     "Promo Kadaluarsa",
+			Type:     model.PromotionTypeCartPercentage,
+			Value:    50,
+			StartAt:  now.Add(-2 * time.Hour),
+			EndAt:    now.Add(-time.Hour),
+			IsActive: true,
+		},
+	}
+
+	t.Run("ok - best line promotion and cart promotion are applied", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockPromotionRepo.EXPECT().FindAllActive(ctx).Times(1).Return(promotions, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 3}},
+			AmountPaid:         20000,
+		})
+		require.NoError(t, err)
+		// 15.000 - 5.000 (buy 2 get 1) - 1.000 (cart)
+		require.Equal(t, int64(9000), res.TotalPrice)
+		require.Equal(t, int64(6000), res.Discount)
+		require.Equal(t, int64(5000), res.TransactionDetails[0].Discount)
+		require.Len(t, res.TransactionPromotions, 2)
+		require.Equal(t, int64(1), res.TransactionPromotions[0].PromotionID)
+		require.Equal(t, int64(3), res.TransactionPromotions[1].PromotionID)
+	})
+
+	t.Run("ok - manual discount within the role limit", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockPromotionRepo.EXPECT().FindAllActive(ctx).Times(1).Return(nil, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 2}},
+			ManualDiscount:     1000,
+			AmountPaid:         9000,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(9000), res.TotalPrice)
+		require.Equal(t, int64(1000), res.ManualDiscount)
+	})
+
+	t.Run("failed - manual discount exceeds the role limit", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockPromotionRepo.EXPECT().FindAllActive(ctx).Times(1).Return(nil, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 2}},
+			ManualDiscount:     1001,
+			AmountPaid:         10000,
+		})
+		require.ErrorIs(t, err, ErrManualDiscountExceeded)
+		require.Nil(t, res)
+	})
+}
+
+func TestTransactionUsecase_Create_Tax(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockPromotionRepo := mock.NewMockPromotionRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		productRepo:     mockProductRepo,
+		promotionRepo:   mockPromotionRepo,
+		shiftRepo:       mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).AnyTimes().Return(nil, nil)
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", Slug: "pisang-goreng", Price: 5000, Quantity: 10, TaxCategory: model.TaxCategoryStandard}
+	exemptProduct := &model.Product{ID: 333, Name: "Beras", Slug: "beras", Price: 10000, Quantity: 10, TaxCategory: model.TaxCategoryExempt}
+	mockPromotionRepo.EXPECT().FindAllActive(ctx).AnyTimes().Return(nil, nil)
+
+	input := model.CreateTransactionInput{
+		TransactionDetails: []model.TransactionDetail{
+			{ProductID: product.ID, Quantity: 2},
+			{ProductID: exemptProduct.ID, Quantity: 1},
+		},
+		AmountPaid: 25000,
+	}
+
+	t.Run("ok - tax exclusive with service charge", func(t *testing.T) {
+		setTaxConfig(t, 11, 5, false)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, exemptProduct.ID).Times(1).Return(exemptProduct, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, cashier, input)
+		require.NoError(t, err)
+		// service charge 5% of 20.000, the PPN covers the taxable line & the service charge only
+		require.Equal(t, int64(1000), res.ServiceCharge)
+		require.Equal(t, int64(11000), res.TaxBase)
+		require.Equal(t, int64(1210), res.TaxAmount)
+		require.Equal(t, int64(22210), res.TotalPrice)
+		require.Equal(t, int64(2790), res.Change)
+		require.False(t, res.PriceIncludeTax)
+		require.Equal(t, model.TaxCategoryExempt, res.TransactionDetails[1].TaxCategory)
+	})
+
+	t.Run("ok - tax inclusive", func(t *testing.T) {
+		setTaxConfig(t, 11, 0, true)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, exemptProduct.ID).Times(1).Return(exemptProduct, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, cashier, input)
+		require.NoError(t, err)
+		// the PPN is extracted from the 10.000 taxable line
+		require.Equal(t, int64(9009), res.TaxBase)
+		require.Equal(t, int64(991), res.TaxAmount)
+		require.Equal(t, int64(20000), res.TotalPrice)
+		require.True(t, res.PriceIncludeTax)
+	})
+}
+
+func TestTransactionUsecase_Create_Shift(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	setTaxConfig(t, 0, 0, false)
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockPromotionRepo := mock.NewMockPromotionRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		productRepo:     mockProductRepo,
+		promotionRepo:   mockPromotionRepo,
+		shiftRepo:       mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	product := &model.Product{ID: 222, Name: "Pisang Goreng", Slug: "pisang-goreng", Price: 5000, Quantity: 10}
+	shift := &model.Shift{ID: 555, CashierID: cashier.ID, Status: model.ShiftStatusOpen}
+	mockPromotionRepo.EXPECT().FindAllActive(ctx).AnyTimes().Return(nil, nil)
+	input := model.CreateTransactionInput{
+		TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 1}},
+		AmountPaid:         5000,
+	}
+
+	t.Run("ok - linked to the open shift", func(t *testing.T) {
+		mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).Times(1).Return(shift, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, cashier, input)
+		require.NoError(t, err)
+		require.Equal(t, shift.ID, res.ShiftID)
+	})
+
+	t.Run("ok - no open shift when it is not required", func(t *testing.T) {
+		mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).Times(1).Return(nil, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Create(ctx, cashier, input)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), res.ShiftID)
+	})
+
+	t.Run("failed - no open shift when it is required", func(t *testing.T) {
+		viper.Set("transaction.require_open_shift", true)
+		defer viper.Set("transaction.require_open_shift", false)
+		mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).Times(1).Return(nil, nil)
+
+		res, err := ucase.Create(ctx, cashier, input)
+		require.ErrorIs(t, err, model.ErrShiftNotOpen)
+		require.Nil(t, res)
+	})
+}
+
+func TestTransactionUsecase_Void(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockRefundRepo := mock.NewMockRefundRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		refundRepo:      mockRefundRepo,
+		shiftRepo:       mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	transaction := &model.Transaction{
+		ID:         444,
+		TotalPrice: 15000,
+		CreatedAt:  time.Now(),
+		TransactionDetails: []*model.TransactionDetail{
+			{TransactionID: 444, ProductID: 222, Quantity: 3, Subtotal: 15000},
+		},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(transaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockRefundRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Void(ctx, cashier, transaction.ID, model.VoidTransactionInput{Reason: "salah input"})
+		require.NoError(t, err)
+		require.Equal(t, model.RefundTypeVoid, res.Type)
+		require.Equal(t, transaction.TotalPrice, res.TotalAmount)
+		require.Equal(t, cashier.ID, res.ApprovedBy)
+		require.Len(t, res.RefundDetails, 1)
+		require.Equal(t, int64(3), res.RefundDetails[0].Quantity)
+	})
+
+	t.Run("failed - void window expired", func(t *testing.T) {
+		old := *transaction
+		old.CreatedAt = time.Now().Add(-48 * time.Hour)
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(&old, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+
+		res, err := ucase.Void(ctx, cashier, transaction.ID, model.VoidTransactionInput{Reason: "salah input"})
+		require.ErrorIs(t, err, ErrVoidWindowExpired)
+		require.Nil(t, res)
+	})
+
+	// a split tender sale of the cashier shift, made before the void window
+	shiftTransaction := *transaction
+	shiftTransaction.ShiftID = 555
+	shiftTransaction.CreatedAt = time.Now().Add(-48 * time.Hour)
+	shiftTransaction.TransactionPayments = []*model.TransactionPayment{
+		{TransactionID: 444, Method: model.PaymentMethodCash, Amount: 5000},
+		{TransactionID: 444, Method: model.PaymentMethodDebitCard, Amount: 10000},
+	}
+	openShift := &model.Shift{ID: shiftTransaction.ShiftID, CashierID: cashier.ID, Status: model.ShiftStatusOpen}
+
+	t.Run("ok - open shift, regardless of the void window", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(&shiftTransaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockShiftRepo.EXPECT().FindByID(ctx, shiftTransaction.ShiftID).Times(1).Return(openShift, nil)
+		mockRefundRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Void(ctx, cashier, transaction.ID, model.VoidTransactionInput{Reason: "salah input"})
+		require.NoError(t, err)
+		require.Equal(t, shiftTransaction.ShiftID, res.ShiftID)
+		require.Equal(t, int64(15000), res.TotalAmount)
+		require.Equal(t, int64(5000), res.CashAmount)
+	})
+
+	t.Run("ok - admin approve the void on the shift of a cashier", func(t *testing.T) {
+		admin := newUserWithRole(999, rbac.RoleAdmin)
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(&shiftTransaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockShiftRepo.EXPECT().FindByID(ctx, shiftTransaction.ShiftID).Times(1).Return(openShift, nil)
+		mockRefundRepo.EXPECT().Create(ctx, admin.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Void(ctx, admin, transaction.ID, model.VoidTransactionInput{Reason: "salah input"})
+		require.NoError(t, err)
+		require.Equal(t, admin.ID, res.ApprovedBy)
+	})
+
+	t.Run("failed - shift already closed", func(t *testing.T) {
+		closedShift := *openShift
+		closedShift.Status = model.ShiftStatusClosed
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(&shiftTransaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockShiftRepo.EXPECT().FindByID(ctx, shiftTransaction.ShiftID).Times(1).Return(&closedShift, nil)
+
+		res, err := ucase.Void(ctx, cashier, transaction.ID, model.VoidTransactionInput{Reason: "salah input"})
+		require.ErrorIs(t, err, model.ErrShiftClosed)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - shift of another cashier", func(t *testing.T) {
+		otherCashier := newUserWithRole(666, rbac.RoleCashiers)
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(&shiftTransaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockShiftRepo.EXPECT().FindByID(ctx, shiftTransaction.ShiftID).Times(1).Return(openShift, nil)
+
+		res, err := ucase.Void(ctx, otherCashier, transaction.ID, model.VoidTransactionInput{Reason: "salah input"})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - missing reason", func(t *testing.T) {
+		res, err := ucase.Void(ctx, cashier, transaction.ID, model.VoidTransactionInput{})
+		require.Error(t, err)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		res, err := ucase.Void(ctx, newUserWithRole(333, rbac.RoleFinancialAuditor), transaction.ID, model.VoidTransactionInput{Reason: "salah input"})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Nil(t, res)
+	})
+}
+
+func TestTransactionUsecase_Refund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockRefundRepo := mock.NewMockRefundRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		refundRepo:      mockRefundRepo,
+		shiftRepo:       mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	transaction := &model.Transaction{
+		ID:         444,
+		TotalPrice: 15000,
+		AmountPaid: 20000,
+		Change:     5000,
+		CreatedAt:  time.Now(),
+		TransactionDetails: []*model.TransactionDetail{
+			{TransactionID: 444, ProductID: 222, Quantity: 3, Subtotal: 15000},
+		},
+		TransactionPayments: []*model.TransactionPayment{
+			{TransactionID: 444, Method: model.PaymentMethodCash, Amount: 20000},
+		},
+	}
+
+	t.Run("ok - partial refund", func(t *testing.T) {
+		shift := &model.Shift{ID: 555, CashierID: cashier.ID, Status: model.ShiftStatusOpen}
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(transaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).Times(1).Return(shift, nil)
+		mockRefundRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+		res, err := ucase.Refund(ctx, cashier, transaction.ID, model.RefundTransactionInput{
+			Reason:        "produk rusak",
+			RefundDetails: []model.RefundDetailInput{{ProductID: 222, Quantity: 2}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.RefundTypeRefund, res.Type)
+		require.Equal(t, int64(10000), res.TotalAmount)
+		require.Equal(t, shift.ID, res.ShiftID)
+		require.Equal(t, int64(10000), res.CashAmount)
+	})
+
+	t.Run("failed - product is not part of the transaction", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(transaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).Times(1).Return(nil, nil)
+
+		res, err := ucase.Refund(ctx, cashier, transaction.ID, model.RefundTransactionInput{
+			Reason:        "produk rusak",
+			RefundDetails: []model.RefundDetailInput{{ProductID: 999, Quantity: 1}},
+		})
+		require.ErrorIs(t, err, model.ErrRefundQuantityExceeded)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - quantity exceeded", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(transaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).Times(1).Return(nil, nil)
+
+		res, err := ucase.Refund(ctx, cashier, transaction.ID, model.RefundTransactionInput{
+			Reason: "produk rusak",
+			RefundDetails: []model.RefundDetailInput{
+				{ProductID: 222, Quantity: 2},
+				{ProductID: 222, Quantity: 2},
+			},
+		})
+		require.ErrorIs(t, err, model.ErrRefundQuantityExceeded)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - transaction not found", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().FindByID(ctx, int64(999)).Times(1).Return(nil, nil)
+
+		res, err := ucase.Refund(ctx, cashier, 999, model.RefundTransactionInput{
+			Reason:        "produk rusak",
+			RefundDetails: []model.RefundDetailInput{{ProductID: 222, Quantity: 1}},
+		})
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Nil(t, res)
+	})
+}
+
+func TestTransactionUsecase_Refund_Rounding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockRefundRepo := mock.NewMockRefundRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		refundRepo:      mockRefundRepo,
+		shiftRepo:       mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+
+	// refund every unit one refund at a time, each refund sees the previous ones
+	refundAll := func(t *testing.T, transaction *model.Transaction, inputs ...model.RefundDetailInput) (total int64) {
+		var refunds []*model.Refund
+		for _, input := range inputs {
+			mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(transaction, nil)
+			mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(refunds, nil)
+			mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).Times(1).Return(nil, nil)
+			mockRefundRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).Return(nil)
+
+			res, err := ucase.Refund(ctx, cashier, transaction.ID, model.RefundTransactionInput{
+				Reason:        "produk rusak",
+				RefundDetails: []model.RefundDetailInput{input},
+			})
+			require.NoError(t, err)
+
+			refunds = append(refunds, res)
+			total += res.TotalAmount
+		}
+
+		return total
+	}
+
+	t.Run("ok - partial refunds of a line add up to its share", func(t *testing.T) {
+		transaction := &model.Transaction{
+			ID:         444,
+			TotalPrice: 10000,
+			TransactionDetails: []*model.TransactionDetail{
+				{TransactionID: 444, ProductID: 222, Quantity: 3, Subtotal: 10000},
+			},
+		}
+
+		total := refundAll(t, transaction,
+			model.RefundDetailInput{ProductID: 222, Quantity: 1},
+			model.RefundDetailInput{ProductID: 222, Quantity: 1},
+			model.RefundDetailInput{ProductID: 222, Quantity: 1},
+		)
+		require.Equal(t, int64(10000), total)
+	})
+
+	t.Run("ok - full refund of the lines add up to the total price", func(t *testing.T) {
+		// the cart discount & tax make the prorated shares fractional
+		transaction := &model.Transaction{
+			ID:         445,
+			TotalPrice: 24419,
+			TransactionDetails: []*model.TransactionDetail{
+				{TransactionID: 445, ProductID: 222, Quantity: 3, Subtotal: 10000},
+				{TransactionID: 445, ProductID: 333, Quantity: 7, Subtotal: 13333},
+			},
+		}
+
+		total := refundAll(t, transaction,
+			model.RefundDetailInput{ProductID: 222, Quantity: 2},
+			model.RefundDetailInput{ProductID: 333, Quantity: 3},
+			model.RefundDetailInput{ProductID: 222, Quantity: 1},
+			model.RefundDetailInput{ProductID: 333, Quantity: 4},
+		)
+		require.Equal(t, transaction.TotalPrice, total)
+	})
+}
+
+func TestTransactionUsecase_PrintReceipt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockRefundRepo := mock.NewMockRefundRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo: mockTransactionRepo,
+		refundRepo:      mockRefundRepo,
+		userRepo:        mockUserRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	cashier.Name = "Budi"
+	transaction := &model.Transaction{
+		ID:         444,
+		TotalPrice: 15000,
+		AmountPaid: 20000,
+		Change:     5000,
+		CreatedBy:  cashier.ID,
+		CreatedAt:  time.Now(),
+		TransactionDetails: []*model.TransactionDetail{
+			{TransactionID: 444, ProductID: 222, ProductName: "Pisang Goreng", UnitPrice: 5000, Quantity: 3, Subtotal: 15000},
+		},
+	}
+
+	t.Run("ok - reprint", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().FindByID(ctx, transaction.ID).Times(1).Return(transaction, nil)
+		mockRefundRepo.EXPECT().FindByTransactionID(ctx, transaction.ID).Times(1).Return(nil, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, cashier.ID).Times(1).Return(cashier, nil)
+		mockTransactionRepo.EXPECT().RecordReceiptPrint(ctx, cashier.ID, transaction).Times(1).Return(int64(2), nil)
+
+		res, err := ucase.PrintReceipt(ctx, cashier, transaction.ID, model.ReceiptFormatText58)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), res.PrintCount)
+		require.Equal(t, "text/plain; charset=utf-8", res.ContentType)
+		require.Contains(t, string(res.Content), "Budi")
+		require.Contains(t, string(res.Content), "CETAK ULANG #1")
+	})
+
+	t.Run("failed - unknown format", func(t *testing.T) {
+		res, err := ucase.PrintReceipt(ctx, cashier, transaction.ID, "DOCX")
+		require.ErrorIs(t, err, model.ErrUnknownReceiptFormat)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - not found", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().FindByID(ctx, int64(999)).Times(1).Return(nil, nil)
+
+		res, err := ucase.PrintReceipt(ctx, cashier, 999, model.ReceiptFormatPDF)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Nil(t, res)
+	})
+}
+
+func TestTransactionUsecase_Create_Variant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	setTaxConfig(t, 0, 0, false)
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockProductVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	mockPromotionRepo := mock.NewMockPromotionRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo:    mockTransactionRepo,
+		productRepo:        mockProductRepo,
+		productVariantRepo: mockProductVariantRepo,
+		promotionRepo:      mockPromotionRepo,
+		shiftRepo:          mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).AnyTimes().Return(nil, nil)
+	mockPromotionRepo.EXPECT().FindAllActive(ctx).AnyTimes().Return(nil, nil)
+
+	product := &model.Product{
+		ID:      222,
+		Name:    "Es Teh",
+		Slug:    "es-teh",
+		Price:   5000,
+		Options: model.ProductOptions{{Name: "Ukuran", Values: []string{"S", "L"}}},
+	}
+	small := &model.ProductVariant{ID: 301, ProductID: product.ID, Name: "S", SKU: "ES-TEH-S", Price: 5000, Quantity: 10}
+	large := &model.ProductVariant{ID: 302, ProductID: product.ID, Name: "L", SKU: "ES-TEH-L", Price: 8000, Quantity: 10}
+
+	t.Run("ok - priced & merged per variant", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(2).Return(product, nil)
+		mockProductVariantRepo.EXPECT().FindByID(ctx, small.ID).Times(1).Return(small, nil)
+		mockProductVariantRepo.EXPECT().FindByID(ctx, large.ID).Times(1).Return(large, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ int64, transaction *model.Transaction) error {
+				require.Len(t, transaction.TransactionDetails, 2)
+				require.Equal(t, small.ID, transaction.TransactionDetails[0].VariantID)
+				require.Equal(t, int64(1), transaction.TransactionDetails[0].Quantity)
+				require.Equal(t, large.ID, transaction.TransactionDetails[1].VariantID)
+				require.Equal(t, "ES-TEH-L", transaction.TransactionDetails[1].SKU)
+				require.Equal(t, int64(3), transaction.TransactionDetails[1].Quantity)
+				require.Equal(t, int64(24000), transaction.TransactionDetails[1].Subtotal)
+				require.Equal(t, "Es Teh (L)", transaction.TransactionDetails[1].DisplayName())
+				return nil
+			})
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{
+				{ProductID: product.ID, VariantID: large.ID, Quantity: 1},
+				{ProductID: product.ID, VariantID: small.ID, Quantity: 1},
+				{ProductID: product.ID, VariantID: large.ID, Quantity: 2},
+			},
+			AmountPaid: 30000,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(29000), res.TotalPrice)
+	})
+
+	t.Run("failed - variant is not chosen", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, Quantity: 1}},
+			AmountPaid:         5000,
+		})
+		require.ErrorIs(t, err, model.ErrProductVariantRequired)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - variant of another product", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductVariantRepo.EXPECT().FindByID(ctx, int64(999)).Times(1).
+			Return(&model.ProductVariant{ID: 999, ProductID: 333, Price: 1000}, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: product.ID, VariantID: 999, Quantity: 1}},
+			AmountPaid:         5000,
+		})
+		require.ErrorIs(t, err, model.ErrUnknownProductVariant)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - variant of a product without variants", func(t *testing.T) {
+		plain := &model.Product{ID: 444, Name: "Pisang Goreng", Price: 5000, Quantity: 10}
+		mockProductRepo.EXPECT().FindByID(ctx, plain.ID).Times(1).Return(plain, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{ProductID: plain.ID, VariantID: small.ID, Quantity: 1}},
+			AmountPaid:         5000,
+		})
+		require.ErrorIs(t, err, model.ErrUnknownProductVariant)
+		require.Nil(t, res)
+	})
+}
+
+func TestTransactionUsecase_Create_Barcode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	setTaxConfig(t, 0, 0, false)
+
+	ctx := context.TODO()
+	mockTransactionRepo := mock.NewMockTransactionRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockProductVariantRepo := mock.NewMockProductVariantRepository(ctrl)
+	mockPromotionRepo := mock.NewMockPromotionRepository(ctrl)
+	mockShiftRepo := mock.NewMockShiftRepository(ctrl)
+	ucase := transactionUsecase{
+		transactionRepo:    mockTransactionRepo,
+		productRepo:        mockProductRepo,
+		productVariantRepo: mockProductVariantRepo,
+		promotionRepo:      mockPromotionRepo,
+		shiftRepo:          mockShiftRepo,
+	}
+
+	cashier := newUserWithRole(111, rbac.RoleCashiers)
+	mockShiftRepo.EXPECT().FindOpenByCashierID(ctx, cashier.ID).AnyTimes().Return(nil, nil)
+	mockPromotionRepo.EXPECT().FindAllActive(ctx).AnyTimes().Return(nil, nil)
+
+	product := &model.Product{ID: 111, Name: "Pisang Goreng", SKU: "PISANG-GORENG", Barcode: "8991234567891", Price: 5000, Quantity: 10}
+	teh := &model.Product{
+		ID:      222,
+		Name:    "Es Teh",
+		Options: model.ProductOptions{{Name: "Ukuran", Values: []string{"S", "L"}}},
+	}
+	large := &model.ProductVariant{ID: 302, ProductID: teh.ID, Name: "L", SKU: "ES-TEH-L", Barcode: "036000291452", Price: 8000, Quantity: 10}
+
+	t.Run("ok - scanned product & variant are merged with the chosen lines", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByCode(ctx, product.Barcode).Times(1).Return(product, nil)
+		mockProductRepo.EXPECT().FindByCode(ctx, large.Barcode).Times(1).Return(nil, nil)
+		mockProductVariantRepo.EXPECT().FindByCode(ctx, large.Barcode).Times(1).Return(large, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, teh.ID).Times(2).Return(teh, nil)
+		mockProductRepo.EXPECT().FindByID(ctx, product.ID).Times(1).Return(product, nil)
+		mockProductVariantRepo.EXPECT().FindByID(ctx, large.ID).Times(1).Return(large, nil)
+		mockTransactionRepo.EXPECT().Create(ctx, cashier.ID, gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, _ int64, transaction *model.Transaction) error {
+				require.Len(t, transaction.TransactionDetails, 2)
+				require.Equal(t, product.ID, transaction.TransactionDetails[0].ProductID)
+				require.Equal(t, int64(3), transaction.TransactionDetails[0].Quantity)
+				require.Equal(t, large.ID, transaction.TransactionDetails[1].VariantID)
+				require.Equal(t, int64(1), transaction.TransactionDetails[1].Quantity)
+				return nil
+			})
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{
+				{Barcode: product.Barcode + "\r\n", Quantity: 1},
+				{Barcode: large.Barcode, Quantity: 1},
+				{ProductID: product.ID, Quantity: 2},
+			},
+			AmountPaid: 30000,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(23000), res.TotalPrice)
+	})
+
+	t.Run("failed - unknown barcode", func(t *testing.T) {
+		mockProductRepo.EXPECT().FindByCode(ctx, "8990000000007").Times(1).Return(nil, nil)
+		mockProductVariantRepo.EXPECT().FindByCode(ctx, "8990000000007").Times(1).Return(nil, nil)
+
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{Barcode: "8990000000007", Quantity: 1}},
+			AmountPaid:         5000,
+		})
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Nil(t, res)
+	})
+
+	t.Run("failed - neither product nor barcode", func(t *testing.T) {
+		res, err := ucase.Create(ctx, cashier, model.CreateTransactionInput{
+			TransactionDetails: []model.TransactionDetail{{Quantity: 1}},
+			AmountPaid:         5000,
+		})
+		require.Error(t, err)
+		require.Nil(t, res)
+	})
+}
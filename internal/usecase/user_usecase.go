@@ -2,21 +2,40 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"github.com/irvankadhafi/go-point-of-sales/internal/config"
 	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
 	"github.com/irvankadhafi/go-point-of-sales/internal/model"
 	"github.com/irvankadhafi/go-point-of-sales/rbac"
 	"github.com/irvankadhafi/go-point-of-sales/utils"
 	"github.com/sirupsen/logrus"
+	"net/url"
+	"sync"
+	"time"
 )
 
+// passwordResetTokenSize the random bytes of a password reset token
+const passwordResetTokenSize = 32
+
 type userUsecase struct {
-	userRepo model.UserRepository
+	userRepo               model.UserRepository
+	sessionRepo            model.SessionRepository
+	passwordResetTokenRepo model.PasswordResetTokenRepository
+	mailer                 model.Mailer
 }
 
 // NewUserUsecase :nodoc:
-func NewUserUsecase(userRepo model.UserRepository) model.UserUsecase {
+func NewUserUsecase(
+	userRepo model.UserRepository,
+	sessionRepo model.SessionRepository,
+	passwordResetTokenRepo model.PasswordResetTokenRepository,
+	mailer model.Mailer,
+) model.UserUsecase {
 	return &userUsecase{
-		userRepo: userRepo,
+		userRepo:               userRepo,
+		sessionRepo:            sessionRepo,
+		passwordResetTokenRepo: passwordResetTokenRepo,
+		mailer:                 mailer,
 	}
 }
 
@@ -61,6 +80,15 @@ func (u *userUsecase) Create(ctx context.Context, requester *model.User, input m
 		return nil, err
 	}
 
+	existingUser, err := u.userRepo.FindByEmail(ctx, input.Email)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, ErrAlreadyExist
+	}
+
 	cipherPwd, err := helper.HashString(input.Password)
 	if err != nil {
 		logger.Error(err)
@@ -72,7 +100,8 @@ func (u *userUsecase) Create(ctx context.Context, requester *model.User, input m
 		Name:     input.Name,
 		Email:    input.Email,
 		Password: cipherPwd,
-		Role:     rbac.RoleAdmin,
+		Role:     input.Role,
+		Status:   model.StatusActive,
 	}
 
 	if err := u.userRepo.Create(ctx, requester.ID, user); err != nil {
@@ -169,6 +198,293 @@ func (u *userUsecase) UpdateProfile(ctx context.Context, requester *model.User,
 	return user, nil
 }
 
+// Search user with given search criteria
+func (u *userUsecase) Search(ctx context.Context, requester *model.User, criteria model.UserSearchCriteria) (users model.AnyUsers, count int64, err error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"criteria":  utils.Dump(criteria),
+	})
+
+	if !requester.HasAccess(rbac.ResourceUser, rbac.ActionViewAny) {
+		err = ErrPermissionDenied
+		return
+	}
+
+	userIDs, count, err := u.userRepo.SearchByPage(ctx, criteria)
+	if err != nil {
+		logger.Error(err)
+		return nil, 0, err
+	}
+	if len(userIDs) <= 0 || count <= 0 {
+		return nil, 0, err
+	}
+
+	users = u.findAllByIDs(ctx, userIDs)
+	if len(users) <= 0 {
+		err = ErrNotFound
+		return
+	}
+
+	return
+}
+
+// ChangeRoleByID change the role of the user with id
+func (u *userUsecase) ChangeRoleByID(ctx context.Context, requester *model.User, id int64, input model.ChangeUserRoleInput) (*model.User, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"userID":    id,
+		"input":     utils.Dump(input),
+	})
+
+	if !requester.HasAccess(rbac.ResourceUser, rbac.ActionChangeRole) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := input.ValidateAndFormat(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if id == requester.ID {
+		return nil, model.ErrUserSelfChange
+	}
+
+	user, err := u.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if user.Role == input.Role {
+		return user, nil
+	}
+
+	user.Role = input.Role
+	user, err = u.userRepo.Update(ctx, requester.ID, user)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// the role is cached along with the sessions, so the user must log in again to use the new role
+	if err := u.sessionRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ActivateByID activate the user with id
+func (u *userUsecase) ActivateByID(ctx context.Context, requester *model.User, id int64) (*model.User, error) {
+	return u.updateStatusByID(ctx, requester, id, model.StatusActive)
+}
+
+// DeactivateByID deactivate the user with id
+func (u *userUsecase) DeactivateByID(ctx context.Context, requester *model.User, id int64) (*model.User, error) {
+	return u.updateStatusByID(ctx, requester, id, model.StatusInactive)
+}
+
+// updateStatusByID update the status of the user and revoke their sessions
+func (u *userUsecase) updateStatusByID(ctx context.Context, requester *model.User, id int64, status model.UserStatus) (*model.User, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+		"userID":    id,
+		"status":    status,
+	})
+
+	if !requester.HasAccess(rbac.ResourceUser, rbac.ActionEditAny) {
+		return nil, ErrPermissionDenied
+	}
+
+	if id == requester.ID {
+		return nil, model.ErrUserSelfChange
+	}
+
+	user, err := u.findByID(ctx, id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if user.Status == status {
+		return user, nil
+	}
+
+	user.Status = status
+	user, err = u.userRepo.Update(ctx, requester.ID, user)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// the status is cached along with the sessions, revoke them so the new status takes effect right away
+	if err := u.sessionRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ForgotPassword mail a password reset token to the user with the email, the mail is sent in the background
+func (u *userUsecase) ForgotPassword(ctx context.Context, input model.ForgotPasswordInput) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":   utils.DumpIncomingContext(ctx),
+		"email": input.Email,
+	})
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	user, err := u.userRepo.FindByEmail(ctx, input.Email)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	// obscure whether the email is registered or the user can log in,
+	// the token is stored & mailed off the request so both cases are answered in the same time
+	if user == nil || user.Status != model.StatusActive {
+		logger.Warn("password reset is requested for a missing or not active user")
+		return nil
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), config.MailerSendTimeout())
+		defer cancel()
+
+		// a failure is only logged, so a mailer outage does not tell which emails are registered either
+		if err := u.mailPasswordResetToken(ctx, user); err != nil {
+			logger.Error(err)
+		}
+	}()
+
+	return nil
+}
+
+// mailPasswordResetToken store a new password reset token of the user and mail it
+func (u *userUsecase) mailPasswordResetToken(ctx context.Context, user *model.User) error {
+	token, err := helper.GenerateRandomToken(passwordResetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	resetToken := &model.PasswordResetToken{
+		ID:        utils.GenerateID(),
+		UserID:    user.ID,
+		TokenHash: helper.HashToken(token),
+		ExpiredAt: time.Now().Add(config.PasswordResetTokenDuration()),
+	}
+	if err := u.passwordResetTokenRepo.Create(ctx, resetToken); err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, newPasswordResetMail(user, token, resetToken.ExpiredAt))
+}
+
+// ResetPassword reset the password of the owner of the token
+func (u *userUsecase) ResetPassword(ctx context.Context, input model.ResetPasswordInput) error {
+	logger := logrus.WithField("ctx", utils.DumpIncomingContext(ctx))
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	resetToken, err := u.passwordResetTokenRepo.FindByTokenHash(ctx, helper.HashToken(input.Token))
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	if resetToken == nil || resetToken.UsedAt != nil || resetToken.IsExpired() {
+		return model.ErrInvalidPasswordResetToken
+	}
+
+	logger = logger.WithField("userID", resetToken.UserID)
+
+	user, err := u.findByID(ctx, resetToken.UserID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	if err := user.Status.Err(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	cipherPwd, err := helper.HashString(input.NewPassword)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	used, err := u.passwordResetTokenRepo.Use(ctx, resetToken)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	if !used {
+		return model.ErrInvalidPasswordResetToken
+	}
+
+	if err := u.userRepo.UpdatePasswordByID(ctx, user.ID, cipherPwd); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := u.sessionRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ChangePIN set the PIN of the requester after checking their password
+func (u *userUsecase) ChangePIN(ctx context.Context, requester *model.User, input model.ChangePINInput) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx":       utils.DumpIncomingContext(ctx),
+		"requester": utils.Dump(requester),
+	})
+
+	if err := input.Validate(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	cipherPwd, err := u.userRepo.FindPasswordByID(ctx, requester.ID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	if cipherPwd == nil {
+		return ErrNotFound
+	}
+
+	if !helper.IsHashedStringMatch([]byte(input.Password), cipherPwd) {
+		return ErrPasswordMismatch
+	}
+
+	cipherPIN, err := helper.HashString(input.PIN)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := u.userRepo.UpdatePINByID(ctx, requester.ID, cipherPIN); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
 func (u *userUsecase) findByID(ctx context.Context, id int64) (*model.User, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"ctx": utils.DumpIncomingContext(ctx),
@@ -186,3 +502,64 @@ func (u *userUsecase) findByID(ctx context.Context, id int64) (*model.User, erro
 
 	return user, nil
 }
+
+func (u *userUsecase) findAllByIDs(ctx context.Context, ids []int64) []*model.User {
+	logger := logrus.WithFields(logrus.Fields{
+		"ctx": utils.DumpIncomingContext(ctx),
+		"ids": ids,
+	})
+
+	var wg sync.WaitGroup
+	c := make(chan *model.User, len(ids))
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			user, err := u.findByID(ctx, id)
+			if err != nil {
+				logger.Error(err)
+				return
+			}
+			c <- user
+		}(id)
+	}
+	wg.Wait()
+	close(c)
+
+	if len(c) <= 0 {
+		return nil
+	}
+
+	rs := map[int64]*model.User{}
+	for user := range c {
+		if user != nil {
+			rs[user.ID] = user
+		}
+	}
+
+	// sort users based on the order of received ids
+	var users []*model.User
+	for _, id := range ids {
+		if user, ok := rs[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
+
+// newPasswordResetMail the email of the password reset token, the token is only known by the email owner
+func newPasswordResetMail(user *model.User, token string, expiredAt time.Time) *model.Mail {
+	link := config.PasswordResetURL() + "?" + url.Values{"token": {token}}.Encode()
+
+	return &model.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"We received a request to reset your password. Open the link below to choose a new password:\n\n"+
+			"%s\n\n"+
+			"The link can be used once and expires at %s. If you did not request it, you can ignore this email.\n",
+			user.Name, link, expiredAt.Format(time.RFC1123)),
+	}
+}
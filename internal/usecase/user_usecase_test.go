@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/irvankadhafi/go-point-of-sales/internal/helper"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model"
+	"github.com/irvankadhafi/go-point-of-sales/internal/model/mock"
+	"github.com/irvankadhafi/go-point-of-sales/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	ucase := userUsecase{userRepo: mockUserRepo}
+	admin := newUserWithRole(1, rbac.RoleAdmin)
+	input := model.CreateUserInput{
+		Name:                 "Budi Santoso",
+		Email:                "budi@mail.com",
+		Password:             "123456",
+		PasswordConfirmation: "123456",
+		Role:                 "cashier",
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(nil, nil)
+		mockUserRepo.EXPECT().Create(ctx, admin.ID, gomock.Any()).Times(1).Return(nil)
+
+		user, err := ucase.Create(ctx, admin, input)
+		require.NoError(t, err)
+		require.Equal(t, rbac.RoleCashiers, user.Role)
+		require.Equal(t, model.StatusActive, user.Status)
+		require.NotEqual(t, input.Password, user.Password)
+	})
+
+	t.Run("failed - email already exist", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(&model.User{ID: 2, Email: input.Email}, nil)
+
+		_, err := ucase.Create(ctx, admin, input)
+		require.ErrorIs(t, err, ErrAlreadyExist)
+	})
+
+	t.Run("failed - internal service role is not assignable", func(t *testing.T) {
+		in := input
+		in.Role = rbac.RoleInternalService
+
+		_, err := ucase.Create(ctx, admin, in)
+		require.ErrorIs(t, err, model.ErrUnknownRole)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		_, err := ucase.Create(ctx, newUserWithRole(111, rbac.RoleCashiers), input)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+}
+
+func TestUserUsecase_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	ucase := userUsecase{userRepo: mockUserRepo}
+	admin := newUserWithRole(1, rbac.RoleAdmin)
+	criteria := model.UserSearchCriteria{Query: "budi", Role: rbac.RoleCashiers, Page: 1, Size: 10}
+
+	t.Run("ok", func(t *testing.T) {
+		mockUserRepo.EXPECT().SearchByPage(ctx, criteria).Times(1).Return([]int64{3, 2}, int64(2), nil)
+		mockUserRepo.EXPECT().FindByID(gomock.Any(), int64(2)).Times(1).Return(&model.User{ID: 2, Name: "Budi"}, nil)
+		mockUserRepo.EXPECT().FindByID(gomock.Any(), int64(3)).Times(1).Return(&model.User{ID: 3, Name: "Budiman"}, nil)
+
+		users, count, err := ucase.Search(ctx, admin, criteria)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+		require.Len(t, users, 2)
+		require.Equal(t, int64(3), users[0].ID)
+		require.Equal(t, int64(2), users[1].ID)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		_, _, err := ucase.Search(ctx, newUserWithRole(111, rbac.RoleCashiers), criteria)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+}
+
+func TestUserUsecase_ChangeRoleByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	ucase := userUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo}
+	admin := newUserWithRole(1, rbac.RoleAdmin)
+	input := model.ChangeUserRoleInput{Role: rbac.RoleProductManager}
+
+	t.Run("ok - sessions are revoked", func(t *testing.T) {
+		user := &model.User{ID: 2, Role: rbac.RoleCashiers, Status: model.StatusActive}
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().Update(ctx, admin.ID, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ int64, u *model.User) (*model.User, error) {
+			require.Equal(t, rbac.RoleProductManager, u.Role)
+			return u, nil
+		})
+		mockSessionRepo.EXPECT().DeleteByUserID(ctx, user.ID).Times(1).Return(nil)
+
+		res, err := ucase.ChangeRoleByID(ctx, admin, user.ID, input)
+		require.NoError(t, err)
+		require.Equal(t, rbac.RoleProductManager, res.Role)
+	})
+
+	t.Run("ok - same role is left as is", func(t *testing.T) {
+		user := &model.User{ID: 2, Role: rbac.RoleProductManager, Status: model.StatusActive}
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+
+		_, err := ucase.ChangeRoleByID(ctx, admin, user.ID, input)
+		require.NoError(t, err)
+	})
+
+	t.Run("failed - own role", func(t *testing.T) {
+		_, err := ucase.ChangeRoleByID(ctx, admin, admin.ID, input)
+		require.ErrorIs(t, err, model.ErrUserSelfChange)
+	})
+
+	t.Run("failed - not found", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByID(ctx, int64(9)).Times(1).Return(nil, nil)
+
+		_, err := ucase.ChangeRoleByID(ctx, admin, 9, input)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("failed - without change role permission", func(t *testing.T) {
+		_, err := ucase.ChangeRoleByID(ctx, newUserWithRole(5, rbac.RoleProductManager), 2, input)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+}
+
+func TestUserUsecase_UpdateStatusByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	ucase := userUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo}
+	admin := newUserWithRole(1, rbac.RoleAdmin)
+
+	t.Run("ok - deactivate revokes the sessions", func(t *testing.T) {
+		user := &model.User{ID: 2, Role: rbac.RoleCashiers, Status: model.StatusActive}
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().Update(ctx, admin.ID, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ int64, u *model.User) (*model.User, error) {
+			return u, nil
+		})
+		mockSessionRepo.EXPECT().DeleteByUserID(ctx, user.ID).Times(1).Return(nil)
+
+		res, err := ucase.DeactivateByID(ctx, admin, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.StatusInactive, res.Status)
+	})
+
+	t.Run("ok - activate revokes the sessions", func(t *testing.T) {
+		user := &model.User{ID: 2, Role: rbac.RoleCashiers, Status: model.StatusPending}
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+		mockUserRepo.EXPECT().Update(ctx, admin.ID, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ int64, u *model.User) (*model.User, error) {
+			return u, nil
+		})
+		mockSessionRepo.EXPECT().DeleteByUserID(ctx, user.ID).Times(1).Return(nil)
+
+		res, err := ucase.ActivateByID(ctx, admin, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.StatusActive, res.Status)
+	})
+
+	t.Run("failed - deactivate own user", func(t *testing.T) {
+		_, err := ucase.DeactivateByID(ctx, admin, admin.ID)
+		require.ErrorIs(t, err, model.ErrUserSelfChange)
+	})
+
+	t.Run("failed - permission denied", func(t *testing.T) {
+		_, err := ucase.DeactivateByID(ctx, newUserWithRole(111, rbac.RoleCashiers), 2)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+}
+
+func TestUserUsecase_ForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockPasswordResetTokenRepo := mock.NewMockPasswordResetTokenRepository(ctrl)
+	mockMailer := mock.NewMockMailer(ctrl)
+	ucase := userUsecase{userRepo: mockUserRepo, passwordResetTokenRepo: mockPasswordResetTokenRepo, mailer: mockMailer}
+	input := model.ForgotPasswordInput{Email: "budi@mail.com"}
+
+	t.Run("ok - the mailed token is stored hashed", func(t *testing.T) {
+		user := &model.User{ID: 2, Name: "Budi", Email: input.Email, Status: model.StatusActive}
+		var stored *model.PasswordResetToken
+		sent := make(chan *model.Mail, 1)
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(user, nil)
+		mockPasswordResetTokenRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, token *model.PasswordResetToken) error {
+			stored = token
+			return nil
+		})
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, mail *model.Mail) error {
+			sent <- mail
+			return nil
+		})
+
+		require.NoError(t, ucase.ForgotPassword(ctx, input))
+
+		mail := <-sent
+		require.Equal(t, user.Email, mail.To)
+		token := mail.Body[strings.Index(mail.Body, "token=")+len("token="):]
+		token = token[:strings.Index(token, "\n")]
+		require.Equal(t, helper.HashToken(token), stored.TokenHash)
+		require.NotContains(t, mail.Body, stored.TokenHash)
+		require.Equal(t, user.ID, stored.UserID)
+		require.True(t, stored.ExpiredAt.After(time.Now()))
+	})
+
+	t.Run("ok - returns before the mail is sent", func(t *testing.T) {
+		release := make(chan struct{})
+		done := make(chan struct{})
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(&model.User{ID: 2, Email: input.Email, Status: model.StatusActive}, nil)
+		mockPasswordResetTokenRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ *model.Mail) error {
+			defer close(done)
+			<-release // a slow SMTP server
+			return nil
+		})
+
+		returned := make(chan error, 1)
+		go func() {
+			returned <- ucase.ForgotPassword(ctx, input)
+		}()
+
+		select {
+		case err := <-returned:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("forgot password waits for the mailer")
+		}
+
+		close(release)
+		<-done
+	})
+
+	t.Run("ok - unknown email is not told", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(nil, nil)
+
+		require.NoError(t, ucase.ForgotPassword(ctx, input))
+	})
+
+	t.Run("ok - inactive user is not mailed", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(&model.User{ID: 2, Status: model.StatusInactive}, nil)
+
+		require.NoError(t, ucase.ForgotPassword(ctx, input))
+	})
+
+	t.Run("ok - failed mail is not told apart from an unknown email", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(nil, nil)
+		unknownErr := ucase.ForgotPassword(ctx, input)
+
+		done := make(chan struct{})
+		mockUserRepo.EXPECT().FindByEmail(ctx, input.Email).Times(1).Return(&model.User{ID: 2, Email: input.Email, Status: model.StatusActive}, nil)
+		mockPasswordResetTokenRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+		mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ *model.Mail) error {
+			defer close(done)
+			return errors.New("smtp: connection refused")
+		})
+		failedMailErr := ucase.ForgotPassword(ctx, input)
+		<-done
+
+		require.NoError(t, unknownErr)
+		require.Equal(t, unknownErr, failedMailErr)
+	})
+}
+
+func TestUserUsecase_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+	mockPasswordResetTokenRepo := mock.NewMockPasswordResetTokenRepository(ctrl)
+	ucase := userUsecase{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, passwordResetTokenRepo: mockPasswordResetTokenRepo}
+	input := model.ResetPasswordInput{Token: "reset-token", NewPassword: "654321", NewPasswordConfirmation: "654321"}
+	tokenHash := helper.HashToken(input.Token)
+	user := &model.User{ID: 2, Status: model.StatusActive}
+
+	t.Run("ok - sessions are revoked", func(t *testing.T) {
+		resetToken := &model.PasswordResetToken{ID: 7, UserID: user.ID, TokenHash: tokenHash, ExpiredAt: time.Now().Add(time.Hour)}
+		mockPasswordResetTokenRepo.EXPECT().FindByTokenHash(ctx, tokenHash).Times(1).Return(resetToken, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+		mockPasswordResetTokenRepo.EXPECT().Use(ctx, resetToken).Times(1).Return(true, nil)
+		mockUserRepo.EXPECT().UpdatePasswordByID(ctx, user.ID, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ int64, cipherPwd string) error {
+			require.True(t, helper.IsHashedStringMatch([]byte(input.NewPassword), []byte(cipherPwd)))
+			return nil
+		})
+		mockSessionRepo.EXPECT().DeleteByUserID(ctx, user.ID).Times(1).Return(nil)
+
+		require.NoError(t, ucase.ResetPassword(ctx, input))
+	})
+
+	t.Run("failed - expired token", func(t *testing.T) {
+		resetToken := &model.PasswordResetToken{ID: 7, UserID: user.ID, TokenHash: tokenHash, ExpiredAt: time.Now().Add(-time.Minute)}
+		mockPasswordResetTokenRepo.EXPECT().FindByTokenHash(ctx, tokenHash).Times(1).Return(resetToken, nil)
+
+		require.ErrorIs(t, ucase.ResetPassword(ctx, input), model.ErrInvalidPasswordResetToken)
+	})
+
+	t.Run("failed - token used by a concurrent reset", func(t *testing.T) {
+		resetToken := &model.PasswordResetToken{ID: 7, UserID: user.ID, TokenHash: tokenHash, ExpiredAt: time.Now().Add(time.Hour)}
+		mockPasswordResetTokenRepo.EXPECT().FindByTokenHash(ctx, tokenHash).Times(1).Return(resetToken, nil)
+		mockUserRepo.EXPECT().FindByID(ctx, user.ID).Times(1).Return(user, nil)
+		mockPasswordResetTokenRepo.EXPECT().Use(ctx, resetToken).Times(1).Return(false, nil)
+
+		require.ErrorIs(t, ucase.ResetPassword(ctx, input), model.ErrInvalidPasswordResetToken)
+	})
+
+	t.Run("failed - unknown token", func(t *testing.T) {
+		mockPasswordResetTokenRepo.EXPECT().FindByTokenHash(ctx, tokenHash).Times(1).Return(nil, nil)
+
+		require.ErrorIs(t, ucase.ResetPassword(ctx, input), model.ErrInvalidPasswordResetToken)
+	})
+}
+
+func TestUserUsecase_ChangePIN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	ucase := userUsecase{userRepo: mockUserRepo}
+	requester := newUserWithRole(111, rbac.RoleCashiers)
+	input := model.ChangePINInput{Password: "123456", PIN: "2468", PINConfirmation: "2468"}
+
+	cipherPwd, err := helper.HashString(input.Password)
+	require.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindPasswordByID(ctx, requester.ID).Times(1).Return([]byte(cipherPwd), nil)
+		mockUserRepo.EXPECT().UpdatePINByID(ctx, requester.ID, gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ int64, cipherPIN string) error {
+			require.True(t, helper.IsHashedStringMatch([]byte(input.PIN), []byte(cipherPIN)))
+			return nil
+		})
+
+		require.NoError(t, ucase.ChangePIN(ctx, requester, input))
+	})
+
+	t.Run("failed - wrong password", func(t *testing.T) {
+		mockUserRepo.EXPECT().FindPasswordByID(ctx, requester.ID).Times(1).Return([]byte(cipherPwd), nil)
+
+		err := ucase.ChangePIN(ctx, requester, model.ChangePINInput{Password: "wrong-password", PIN: "2468", PINConfirmation: "2468"})
+		require.ErrorIs(t, err, ErrPasswordMismatch)
+	})
+
+	t.Run("failed - invalid PIN", func(t *testing.T) {
+		for _, pin := range []string{"12", "1234567", "12ab"} {
+			err := ucase.ChangePIN(ctx, requester, model.ChangePINInput{Password: input.Password, PIN: pin, PINConfirmation: pin})
+			require.Error(t, err)
+		}
+	})
+}
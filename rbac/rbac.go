@@ -17,9 +17,16 @@ type Resource string
 
 // Resource constants
 const (
-	ResourceUser        Resource = "user"
-	ResourceProduct     Resource = "product"
-	ResourceTransaction Resource = "transaction"
+	ResourceUser          Resource = "user"
+	ResourceProduct       Resource = "product"
+	ResourceTransaction   Resource = "transaction"
+	ResourcePromotion     Resource = "promotion"
+	ResourceShift         Resource = "shift"
+	ResourceReport        Resource = "report"
+	ResourceCategory      Resource = "category"
+	ResourceStock         Resource = "stock"
+	ResourceSupplier      Resource = "supplier"
+	ResourcePurchaseOrder Resource = "purchase_order"
 )
 
 // Action is an action
@@ -32,6 +39,7 @@ const (
 	ActionEditAny    Action = "edit_any"
 	ActionDeleteAny  Action = "delete_any"
 	ActionChangeRole Action = "change_role"
+	ActionApproveAny Action = "approve_any"
 )
 
 // TraversePermission traverse the built in permission
@@ -54,14 +62,63 @@ var _permissions = map[resourceAction][]Role{
 	{ResourceUser, ActionViewAny}:   {RoleAdmin},
 	{ResourceUser, ActionEditAny}:   {RoleAdmin},
 	{ResourceUser, ActionDeleteAny}: {RoleAdmin},
+	// change the role of another user
+	{ResourceUser, ActionChangeRole}: {RoleAdmin},
 
 	{ResourceProduct, ActionCreateAny}: {RoleAdmin, RoleProductManager},
 	{ResourceProduct, ActionViewAny}:   {RoleAdmin, RoleProductManager, RoleFinancialAuditor},
 	{ResourceProduct, ActionEditAny}:   {RoleAdmin, RoleProductManager},
 	{ResourceProduct, ActionDeleteAny}: {RoleAdmin, RoleProductManager},
 
-	{ResourceTransaction, ActionCreateAny}: {RoleAdmin, RoleCashiers},
-	{ResourceTransaction, ActionViewAny}:   {RoleAdmin, RoleCashiers, RoleFinancialAuditor},
-	{ResourceTransaction, ActionEditAny}:   {RoleAdmin, RoleCashiers},
-	{ResourceTransaction, ActionDeleteAny}: {RoleAdmin, RoleCashiers},
+	{ResourceTransaction, ActionCreateAny}:  {RoleAdmin, RoleCashiers},
+	{ResourceTransaction, ActionViewAny}:    {RoleAdmin, RoleCashiers, RoleFinancialAuditor},
+	{ResourceTransaction, ActionEditAny}:    {RoleAdmin, RoleCashiers},
+	{ResourceTransaction, ActionDeleteAny}:  {RoleAdmin, RoleCashiers},
+	{ResourceTransaction, ActionApproveAny}: {RoleAdmin}, // void a transaction on the shift of another cashier
+
+	{ResourcePromotion, ActionCreateAny}: {RoleAdmin, RoleProductManager},
+	{ResourcePromotion, ActionViewAny}:   {RoleAdmin, RoleProductManager, RoleCashiers, RoleFinancialAuditor},
+	{ResourcePromotion, ActionEditAny}:   {RoleAdmin, RoleProductManager},
+	{ResourcePromotion, ActionDeleteAny}: {RoleAdmin, RoleProductManager},
+
+	{ResourceShift, ActionCreateAny}: {RoleCashiers},
+	{ResourceShift, ActionViewAny}:   {RoleAdmin, RoleCashiers, RoleFinancialAuditor},
+	{ResourceShift, ActionEditAny}:   {RoleCashiers},
+
+	{ResourceReport, ActionCreateAny}: {RoleAdmin, RoleFinancialAuditor},
+	{ResourceReport, ActionViewAny}:   {RoleAdmin, RoleFinancialAuditor},
+
+	{ResourceCategory, ActionCreateAny}: {RoleAdmin, RoleProductManager},
+	{ResourceCategory, ActionViewAny}:   {RoleAdmin, RoleProductManager, RoleCashiers, RoleFinancialAuditor},
+	{ResourceCategory, ActionEditAny}:   {RoleAdmin, RoleProductManager},
+	{ResourceCategory, ActionDeleteAny}: {RoleAdmin, RoleProductManager},
+
+	{ResourceStock, ActionCreateAny}: {RoleAdmin, RoleProductManager},
+	{ResourceStock, ActionViewAny}:   {RoleAdmin, RoleProductManager, RoleFinancialAuditor},
+	{ResourceStock, ActionEditAny}:   {RoleAdmin, RoleProductManager},
+
+	{ResourceSupplier, ActionCreateAny}: {RoleAdmin, RoleProductManager},
+	{ResourceSupplier, ActionViewAny}:   {RoleAdmin, RoleProductManager, RoleFinancialAuditor},
+	{ResourceSupplier, ActionEditAny}:   {RoleAdmin, RoleProductManager},
+	{ResourceSupplier, ActionDeleteAny}: {RoleAdmin, RoleProductManager},
+
+	{ResourcePurchaseOrder, ActionCreateAny}: {RoleAdmin, RoleProductManager},
+	{ResourcePurchaseOrder, ActionViewAny}:   {RoleAdmin, RoleProductManager, RoleFinancialAuditor},
+	{ResourcePurchaseOrder, ActionEditAny}:   {RoleAdmin, RoleProductManager},
+}
+
+// describe the maximum manual discount in percent of the cart total the Role can give here,
+// the role which is not listed can not give any manual discount
+var _maxManualDiscountPercents = map[Role]int64{
+	RoleAdmin:    100,
+	RoleCashiers: 10,
+}
+
+// MaxManualDiscountPercent get the maximum manual discount in percent the role can give
+func MaxManualDiscountPercent(role Role) int64 {
+	if role == RoleInternalService {
+		role = RoleAdmin
+	}
+
+	return _maxManualDiscountPercents[role]
 }
@@ -30,3 +30,10 @@ func TestRole_HasAccess(t *testing.T) {
 		require.False(t, hasAccess)
 	})
 }
+
+func TestMaxManualDiscountPercent(t *testing.T) {
+	require.Equal(t, int64(100), MaxManualDiscountPercent(RoleAdmin))
+	require.Equal(t, int64(100), MaxManualDiscountPercent(RoleInternalService))
+	require.Equal(t, int64(10), MaxManualDiscountPercent(RoleCashiers))
+	require.Equal(t, int64(0), MaxManualDiscountPercent(RoleFinancialAuditor))
+}
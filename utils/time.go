@@ -15,3 +15,21 @@ func FormatToWesternIndonesianTime(layout string, t *time.Time) string {
 	}
 	return monday.Format(t.In(location), layout, monday.LocaleIdID)
 }
+
+// ParseWesternIndonesianDate parse the value using layout on western indonesian time
+func ParseWesternIndonesianDate(layout, value string) (time.Time, error) {
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(layout, value, location)
+}
+
+// WesternIndonesianLocation the western indonesian time location
+func WesternIndonesianLocation() *time.Location {
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return location
+}